@@ -52,7 +52,7 @@ func main() {
 	defer rdb.Close()
 
 	// Initialize and start server
-	srv := server.New(cfg.Server, db, rdb, log)
+	srv := server.New(cfg, db, rdb, log)
 	
 	// Graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
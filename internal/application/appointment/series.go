@@ -0,0 +1,345 @@
+package appointment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/presentation/http/dto"
+)
+
+// CreateAppointmentSeries expands the request's recurrence rule into a linked series of
+// appointments. Occurrences that overlap existing bookings are returned as skipped when
+// SkipConflicts is set; otherwise they abort the series with a SeriesConflictError.
+func (s *Service) CreateAppointmentSeries(ctx *fiber.Ctx, appointmentData *dto.CreateAppointmentRequest) (*appointment.Series, []*appointment.Appointment, []appointment.OccurrenceConflict, error) {
+	if appointmentData.Recurrence == nil {
+		return nil, nil, nil, fmt.Errorf("recurrence rule is required for a series")
+	}
+
+	template, err := s.newAppointmentFromRequest(appointmentData)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := template.ValidateTimeRange(); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.checkRoom(ctx.Context(), template); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.applyNoShowPolicy(ctx.Context(), template); err != nil {
+		return nil, nil, nil, err
+	}
+
+	rule, err := recurrenceRuleFromRequest(appointmentData.Recurrence)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := rule.Validate(template.Date); err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	series := &appointment.Series{
+		OrganizationID: template.OrganizationID,
+		PatientID:      template.PatientID,
+		DoctorID:       template.DoctorID,
+		Rule:           rule,
+		StartDate:      template.Date,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	dates := rule.Occurrences(template.Date)
+	occurrences := make([]*appointment.Appointment, len(dates))
+	for i, date := range dates {
+		occurrence := *template
+		occurrence.Date = date
+		occurrences[i] = &occurrence
+	}
+
+	var skipped []appointment.OccurrenceConflict
+	created := make([]*appointment.Appointment, 0, len(occurrences))
+	err = s.transactor.WithinTx(ctx.Context(), func(ctx context.Context) error {
+		skipped, err = s.appointmentRepo.CreateSeries(ctx, series, occurrences, appointmentData.SkipConflicts)
+		if err != nil {
+			return err
+		}
+
+		for _, occurrence := range occurrences {
+			if occurrence.ID == "" {
+				continue
+			}
+			created = append(created, occurrence)
+			if err := s.publishScheduled(ctx, occurrence, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return series, created, skipped, nil
+}
+
+// GetAppointmentSeries returns a series and all of its occurrences
+func (s *Service) GetAppointmentSeries(ctx *fiber.Ctx, seriesID string) (*appointment.Series, []*appointment.Appointment, error) {
+	series, err := s.appointmentRepo.GetSeriesByID(ctx.Context(), seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	occurrences, err := s.appointmentRepo.GetSeriesAppointments(ctx.Context(), seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return series, occurrences, nil
+}
+
+// UpdateSeriesOccurrences applies an edit to this occurrence, this and the following
+// occurrences, or the whole series. A "following" edit splits the series in two so
+// the earlier occurrences keep their original rule.
+func (s *Service) UpdateSeriesOccurrences(ctx *fiber.Ctx, appointmentID string, scope appointment.SeriesScope, changes *dto.UpdateAppointmentRequest) ([]*appointment.Appointment, error) {
+	if changes.Date != nil && scope != appointment.ScopeThis {
+		return nil, appointment.ErrSeriesDateChange
+	}
+
+	target, series, selected, err := s.selectOccurrences(ctx, appointmentID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.RoomID != nil {
+		moved := *target
+		moved.RoomID = changes.RoomID
+		if err := s.checkRoom(ctx.Context(), &moved); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	updated := make([]*appointment.Appointment, 0, len(selected))
+	for _, occurrence := range selected {
+		// Past and closed occurrences keep their details but still move with a split
+		if scope != appointment.ScopeThis && !occurrence.Status.IsEditable() {
+			continue
+		}
+		if err := applyAppointmentChanges(occurrence, changes); err != nil {
+			return nil, err
+		}
+		if err := occurrence.ValidateTimeRange(); err != nil {
+			return nil, err
+		}
+		occurrence.UpdatedAt = now
+		updated = append(updated, occurrence)
+	}
+
+	// A split re-links every following occurrence, not just the edited ones
+	toSave := updated
+	var split *appointment.SeriesSplit
+	if scope == appointment.ScopeFollowing && series != nil && !sameDay(target.Date, series.StartDate) {
+		split = splitSeries(series, target.Date, len(selected), now)
+		toSave = selected
+	}
+
+	err = s.transactor.WithinTx(ctx.Context(), func(ctx context.Context) error {
+		if err := s.appointmentRepo.UpdateOccurrences(ctx, toSave, split); err != nil {
+			return err
+		}
+		if changes.Date == nil && changes.StartTime == nil {
+			return nil
+		}
+
+		for _, occurrence := range updated {
+			if err := s.publishScheduled(ctx, occurrence, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// CancelSeriesOccurrences cancels this occurrence, this and the following occurrences,
+// or every open occurrence of the series. Occurrences the lifecycle no longer allows
+// to be cancelled are left alone. Cancelling the following occurrences also ends the
+// series' rule before the selected date.
+func (s *Service) CancelSeriesOccurrences(ctx *fiber.Ctx, appointmentID string, scope appointment.SeriesScope, reason *string) ([]*appointment.Appointment, error) {
+	target, series, selected, err := s.selectOccurrences(ctx, appointmentID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := actorFromContext(ctx)
+	cancelled := make([]*appointment.Appointment, 0, len(selected))
+	changes := make([]*appointment.StatusChange, 0, len(selected))
+	for _, occurrence := range selected {
+		change, err := occurrence.Transition(appointment.StatusCancelled, actorID, reason)
+		if err != nil {
+			// A single occurrence must report why it cannot be cancelled
+			if scope == appointment.ScopeThis {
+				return nil, err
+			}
+			continue
+		}
+		changes = append(changes, change)
+		cancelled = append(cancelled, occurrence)
+	}
+
+	err = s.transactor.WithinTx(ctx.Context(), func(ctx context.Context) error {
+		if len(changes) > 0 {
+			if err := s.appointmentRepo.ChangeStatus(ctx, changes...); err != nil {
+				return err
+			}
+			for i, occurrence := range cancelled {
+				occurrence.Status = changes[i].ToStatus
+				occurrence.UpdatedAt = changes[i].ChangedAt
+				if err := s.publishStatusChanged(ctx, occurrence, changes[i]); err != nil {
+					return err
+				}
+				if err := s.publishCancelled(ctx, occurrence, changes[i], false); err != nil {
+					return err
+				}
+			}
+		}
+
+		if scope == appointment.ScopeFollowing && series != nil && !sameDay(target.Date, series.StartDate) {
+			truncated := *series
+			truncated.Rule = series.Rule.EndBefore(target.Date)
+			truncated.UpdatedAt = time.Now()
+			return s.appointmentRepo.UpdateOccurrences(ctx, nil, &appointment.SeriesSplit{Original: &truncated})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
+
+// selectOccurrences loads the target appointment and the occurrences a scope covers
+func (s *Service) selectOccurrences(ctx *fiber.Ctx, appointmentID string, scope appointment.SeriesScope) (*appointment.Appointment, *appointment.Series, []*appointment.Appointment, error) {
+	target, err := s.appointmentRepo.GetByID(ctx.Context(), appointmentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if target.SeriesID == nil {
+		if scope != appointment.ScopeThis {
+			return nil, nil, nil, appointment.ErrNotInSeries
+		}
+		return target, nil, []*appointment.Appointment{target}, nil
+	}
+
+	if scope == appointment.ScopeThis {
+		return target, nil, []*appointment.Appointment{target}, nil
+	}
+
+	series, err := s.appointmentRepo.GetSeriesByID(ctx.Context(), *target.SeriesID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	occurrences, err := s.appointmentRepo.GetSeriesAppointments(ctx.Context(), series.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if scope == appointment.ScopeAll {
+		return target, series, occurrences, nil
+	}
+
+	following := make([]*appointment.Appointment, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		if !occurrence.Date.Before(target.Date) {
+			following = append(following, occurrence)
+		}
+	}
+
+	return target, series, following, nil
+}
+
+// splitSeries ends the original series before date and starts a successor series
+// with the same rule covering the moved occurrences
+func splitSeries(series *appointment.Series, date time.Time, moved int, now time.Time) *appointment.SeriesSplit {
+	original := *series
+	original.Rule = series.Rule.EndBefore(date)
+	original.UpdatedAt = now
+
+	successor := *series
+	successor.ID = ""
+	successor.StartDate = date
+	successor.CreatedAt = now
+	successor.UpdatedAt = now
+	if series.Rule.Count != nil {
+		remaining := moved
+		successor.Rule.Count = &remaining
+	}
+
+	return &appointment.SeriesSplit{Original: &original, Successor: &successor}
+}
+
+func recurrenceRuleFromRequest(req *dto.RecurrenceRequest) (appointment.RecurrenceRule, error) {
+	rule := appointment.RecurrenceRule{
+		Frequency: appointment.RecurrenceFrequency(req.Frequency),
+		Interval:  req.Interval,
+		Count:     req.Count,
+	}
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+
+	if req.Until != nil {
+		until, err := parseAppointmentDate(*req.Until)
+		if err != nil {
+			return appointment.RecurrenceRule{}, fmt.Errorf("invalid recurrence until: %w", err)
+		}
+		rule.Until = &until
+	}
+
+	return rule, nil
+}
+
+// applyAppointmentChanges copies the fields present in an update request onto an appointment
+func applyAppointmentChanges(apt *appointment.Appointment, changes *dto.UpdateAppointmentRequest) error {
+	if changes.Date != nil {
+		date, err := parseAppointmentDate(*changes.Date)
+		if err != nil {
+			return err
+		}
+		apt.Date = date
+	}
+	if changes.StartTime != nil {
+		apt.StartTime = *changes.StartTime
+	}
+	if changes.EndTime != nil {
+		apt.EndTime = *changes.EndTime
+	}
+	if changes.Duration != nil {
+		apt.Duration = *changes.Duration
+	}
+	if changes.Type != nil {
+		apt.Type = *changes.Type
+	}
+	if changes.Notes != nil {
+		apt.Notes = changes.Notes
+	}
+	if changes.RoomID != nil {
+		apt.RoomID = changes.RoomID
+	}
+	return nil
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
@@ -7,19 +7,40 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"medika-backend/internal/application/shared/events"
 	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/noshow"
+	"medika-backend/internal/domain/room"
 	"medika-backend/internal/presentation/http/dto"
 	"medika-backend/pkg/logger"
 )
 
+// RoomRepository interface for dependency injection
+type RoomRepository interface {
+	GetByID(ctx context.Context, id string) (*room.Room, error)
+}
+
+// NoShowPolicy decides what a patient with a history of no-shows has to do for a new appointment
+type NoShowPolicy interface {
+	RequirementFor(ctx context.Context, organizationID, patientID string) (noshow.Requirement, error)
+}
+
 type Service struct {
 	appointmentRepo appointment.Repository
+	roomRepo        RoomRepository
+	noShowPolicy    NoShowPolicy
+	eventBus        events.Bus
+	transactor      events.Transactor
 	logger          logger.Logger
 }
 
-func NewService(appointmentRepo appointment.Repository, logger logger.Logger) *Service {
+func NewService(appointmentRepo appointment.Repository, roomRepo RoomRepository, noShowPolicy NoShowPolicy, eventBus events.Bus, transactor events.Transactor, logger logger.Logger) *Service {
 	return &Service{
 		appointmentRepo: appointmentRepo,
+		roomRepo:        roomRepo,
+		noShowPolicy:    noShowPolicy,
+		eventBus:        eventBus,
+		transactor:      transactor,
 		logger:          logger,
 	}
 }
@@ -69,20 +90,326 @@ func (s *Service) GetAppointmentByID(ctx *fiber.Ctx, appointmentID string) (*app
 }
 
 func (s *Service) CreateAppointment(ctx *fiber.Ctx, appointmentData *dto.CreateAppointmentRequest) (*appointment.Appointment, error) {
-	// Parse the date from string to time.Time
-	// Try ISO format first, then fallback to date-only format
-	var date time.Time
-	var err error
-	
-	// Try parsing as ISO format (e.g., "2025-09-09T00:00:00.000Z")
-	date, err = time.Parse(time.RFC3339, appointmentData.Date)
+	apt, err := s.newAppointmentFromRequest(appointmentData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apt.ValidateTimeRange(); err != nil {
+		return nil, err
+	}
+	if err := s.checkRoom(ctx.Context(), apt); err != nil {
+		return nil, err
+	}
+	if err := s.applyNoShowPolicy(ctx.Context(), apt); err != nil {
+		return nil, err
+	}
+
+	// Create appointment in repository; overlapping bookings come back as *appointment.ConflictError
+	err = s.transactor.WithinTx(ctx.Context(), func(ctx context.Context) error {
+		if err := s.appointmentRepo.Create(ctx, apt); err != nil {
+			return fmt.Errorf("failed to create appointment: %w", err)
+		}
+		return s.publishScheduled(ctx, apt, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apt, nil
+}
+
+// UpdateAppointment applies the fields present in the request. The new time window is
+// checked for conflicts and an active queue entry is adjusted to the new date.
+func (s *Service) UpdateAppointment(ctx *fiber.Ctx, appointmentID string, appointmentData *dto.UpdateAppointmentRequest) (*appointment.Appointment, error) {
+	apt, err := s.appointmentRepo.GetByID(ctx.Context(), appointmentID)
 	if err != nil {
-		// Fallback to date-only format (e.g., "2025-09-09")
-		date, err = time.Parse("2006-01-02", appointmentData.Date)
-		if err != nil {
-			return nil, fmt.Errorf("invalid date format, expected ISO format or YYYY-MM-DD: %w", err)
+		return nil, err
+	}
+
+	if !apt.Status.IsEditable() {
+		return nil, appointment.ErrAppointmentClosed
+	}
+
+	previousDate, previousStart := apt.Date, apt.StartTime
+	if err := applyAppointmentChanges(apt, appointmentData); err != nil {
+		return nil, err
+	}
+	if err := apt.ValidateTimeRange(); err != nil {
+		return nil, err
+	}
+	if appointmentData.RoomID != nil {
+		if err := s.checkRoom(ctx.Context(), apt); err != nil {
+			return nil, err
 		}
 	}
+	apt.UpdatedAt = time.Now()
+
+	err = s.transactor.WithinTx(ctx.Context(), func(ctx context.Context) error {
+		if err := s.appointmentRepo.Update(ctx, apt); err != nil {
+			return err
+		}
+		if apt.Date.Equal(previousDate) && apt.StartTime == previousStart {
+			return nil
+		}
+		return s.publishScheduled(ctx, apt, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apt, nil
+}
+
+// RescheduleAppointment moves an appointment to a new slot. The original is cancelled
+// with the given reason and the returned appointment links back to it.
+func (s *Service) RescheduleAppointment(ctx *fiber.Ctx, appointmentID string, req *dto.RescheduleAppointmentRequest) (*appointment.Appointment, error) {
+	original, err := s.appointmentRepo.GetByID(ctx.Context(), appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !original.Status.IsEditable() {
+		return nil, appointment.ErrAppointmentClosed
+	}
+
+	date, err := parseAppointmentDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replacement := *original
+	replacement.ID = ""
+	replacement.RescheduledFromID = &original.ID
+	replacement.Date = date
+	replacement.StartTime = req.StartTime
+	replacement.EndTime = req.EndTime
+	replacement.CreatedAt = now
+	replacement.UpdatedAt = now
+	if req.Duration != nil {
+		replacement.Duration = *req.Duration
+	}
+	if req.RoomID != nil {
+		replacement.RoomID = req.RoomID
+	}
+	if err := replacement.ValidateTimeRange(); err != nil {
+		return nil, err
+	}
+	if err := s.checkRoom(ctx.Context(), &replacement); err != nil {
+		return nil, err
+	}
+
+	reason := req.Reason
+	if reason == nil {
+		defaultReason := "Rescheduled"
+		reason = &defaultReason
+	}
+
+	cancellation, err := original.Transition(appointment.StatusCancelled, actorFromContext(ctx), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx.Context(), func(ctx context.Context) error {
+		if err := s.appointmentRepo.Reschedule(ctx, cancellation, &replacement); err != nil {
+			return err
+		}
+
+		original.Status = cancellation.ToStatus
+		if err := s.publishStatusChanged(ctx, original, cancellation); err != nil {
+			return err
+		}
+		if err := s.publishCancelled(ctx, original, cancellation, true); err != nil {
+			return err
+		}
+		return s.publishScheduled(ctx, &replacement, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &replacement, nil
+}
+
+func (s *Service) DeleteAppointment(ctx *fiber.Ctx, appointmentID string) error {
+	return s.appointmentRepo.Delete(ctx.Context(), appointmentID)
+}
+
+// UpdateAppointmentStatus moves an appointment through its lifecycle, recording the
+// acting user and reason in the status history
+func (s *Service) UpdateAppointmentStatus(ctx *fiber.Ctx, appointmentID string, status string, reason *string) (*appointment.Appointment, error) {
+	return s.changeStatus(ctx.Context(), appointmentID, appointment.AppointmentStatus(status), actorFromContext(ctx), reason)
+}
+
+// GetAppointmentHistory returns the status transitions of an appointment, oldest first
+func (s *Service) GetAppointmentHistory(ctx *fiber.Ctx, appointmentID string) ([]*appointment.StatusChange, error) {
+	if _, err := s.appointmentRepo.GetByID(ctx.Context(), appointmentID); err != nil {
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetStatusHistory(ctx.Context(), appointmentID)
+}
+
+func (s *Service) changeStatus(ctx context.Context, appointmentID string, status appointment.AppointmentStatus, actorID, reason *string) (*appointment.Appointment, error) {
+	apt, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := apt.Transition(status, actorID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.appointmentRepo.ChangeStatus(ctx, change); err != nil {
+			return err
+		}
+
+		apt.Status = change.ToStatus
+		apt.UpdatedAt = change.ChangedAt
+
+		if err := s.publishStatusChanged(ctx, apt, change); err != nil {
+			return err
+		}
+		if apt.Status != appointment.StatusCancelled {
+			return nil
+		}
+		return s.publishCancelled(ctx, apt, change, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apt, nil
+}
+
+// publishCancelled announces a freed slot. Like the other publish methods it is
+// called in the transaction that stores the change, so the event is published if
+// and only if the change is stored.
+func (s *Service) publishCancelled(ctx context.Context, apt *appointment.Appointment, change *appointment.StatusChange, rescheduled bool) error {
+	event := appointment.AppointmentCancelledEvent{
+		Appointment: *apt,
+		ActorID:     change.ActorID,
+		Reason:      change.Reason,
+		Rescheduled: rescheduled,
+		CancelledAt: change.ChangedAt,
+	}
+
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Error(ctx, "Failed to publish appointment cancelled event", "error", err, "appointmentID", apt.ID)
+		return err
+	}
+	return nil
+}
+
+// publishStatusChanged announces a status change
+func (s *Service) publishStatusChanged(ctx context.Context, apt *appointment.Appointment, change *appointment.StatusChange) error {
+	event := appointment.AppointmentStatusChangedEvent{
+		Appointment: *apt,
+		Change:      *change,
+	}
+
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Error(ctx, "Failed to publish appointment status changed event", "error", err, "appointmentID", apt.ID)
+		return err
+	}
+	return nil
+}
+
+// RecordDeposit records that the deposit required to confirm an appointment has been paid
+func (s *Service) RecordDeposit(ctx *fiber.Ctx, appointmentID string) (*appointment.Appointment, error) {
+	apt, err := s.appointmentRepo.GetByID(ctx.Context(), appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apt.DepositRequired {
+		return nil, appointment.ErrDepositNotRequired
+	}
+	if apt.DepositPaidAt != nil {
+		return apt, nil
+	}
+	if !apt.Status.IsEditable() {
+		return nil, appointment.ErrAppointmentClosed
+	}
+
+	now := time.Now()
+	if err := s.appointmentRepo.MarkDepositPaid(ctx.Context(), appointmentID, now); err != nil {
+		return nil, err
+	}
+
+	apt.DepositPaidAt = &now
+	apt.UpdatedAt = now
+	return apt, nil
+}
+
+// applyNoShowPolicy flags a new appointment according to the patient's no-show
+// history. A deposit has to be paid before the appointment can be confirmed, and
+// like a required confirmation it has to happen before the organization's deadline.
+func (s *Service) applyNoShowPolicy(ctx context.Context, apt *appointment.Appointment) error {
+	requirement, err := s.noShowPolicy.RequirementFor(ctx, apt.OrganizationID, apt.PatientID)
+	if err != nil {
+		return err
+	}
+
+	switch requirement {
+	case noshow.RequirementDeposit:
+		apt.DepositRequired = true
+		apt.ConfirmationRequired = true
+	case noshow.RequirementConfirmation:
+		apt.ConfirmationRequired = true
+	}
+
+	return nil
+}
+
+// checkRoom verifies that the appointment's room, if any, can be booked by its
+// organization. Whether the room has capacity left is checked when saving.
+func (s *Service) checkRoom(ctx context.Context, apt *appointment.Appointment) error {
+	if apt.RoomID == nil || *apt.RoomID == "" {
+		return nil
+	}
+
+	rm, err := s.roomRepo.GetByID(ctx, *apt.RoomID)
+	if err != nil {
+		return err
+	}
+
+	return rm.CheckBookable(apt.OrganizationID)
+}
+
+// publishScheduled announces a booked or moved appointment
+func (s *Service) publishScheduled(ctx context.Context, apt *appointment.Appointment, rescheduled bool) error {
+	event := appointment.AppointmentScheduledEvent{
+		Appointment: *apt,
+		Rescheduled: rescheduled,
+		ScheduledAt: time.Now(),
+	}
+
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Error(ctx, "Failed to publish appointment scheduled event", "error", err, "appointmentID", apt.ID)
+		return err
+	}
+	return nil
+}
+
+// actorFromContext returns the authenticated user, if any, for audit records
+func actorFromContext(ctx *fiber.Ctx) *string {
+	userID, ok := ctx.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return nil
+	}
+	return &userID
+}
+
+// newAppointmentFromRequest builds a pending appointment from a create request
+func (s *Service) newAppointmentFromRequest(appointmentData *dto.CreateAppointmentRequest) (*appointment.Appointment, error) {
+	date, err := parseAppointmentDate(appointmentData.Date)
+	if err != nil {
+		return nil, err
+	}
 
 	// Organization ID is required for appointments
 	// It should come from the request (set by the staff member creating the appointment)
@@ -92,7 +419,7 @@ func (s *Service) CreateAppointment(ctx *fiber.Ctx, appointmentData *dto.CreateA
 	}
 
 	// Create domain entity from DTO
-	apt := &appointment.Appointment{
+	return &appointment.Appointment{
 		PatientID:      appointmentData.PatientID,
 		DoctorID:       appointmentData.DoctorID,
 		OrganizationID: organizationID,
@@ -106,29 +433,21 @@ func (s *Service) CreateAppointment(ctx *fiber.Ctx, appointmentData *dto.CreateA
 		Notes:          appointmentData.Notes,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
-	}
-
-	// Create appointment in repository
-	if err := s.appointmentRepo.Create(ctx.Context(), apt); err != nil {
-		return nil, fmt.Errorf("failed to create appointment: %w", err)
-	}
-
-	return apt, nil
+	}, nil
 }
 
-func (s *Service) UpdateAppointment(ctx *fiber.Ctx, appointmentID string, appointmentData *dto.UpdateAppointmentRequest) (*appointment.Appointment, error) {
-	// For now, return error - implement update logic later
-	return nil, fmt.Errorf("not implemented yet")
-}
-
-func (s *Service) DeleteAppointment(ctx *fiber.Ctx, appointmentID string) error {
-	return s.appointmentRepo.Delete(ctx.Context(), appointmentID)
-}
+// parseAppointmentDate accepts ISO timestamps (e.g. "2025-09-09T00:00:00.000Z") or plain dates ("2025-09-09")
+func parseAppointmentDate(value string) (time.Time, error) {
+	date, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return date, nil
+	}
 
-func (s *Service) UpdateAppointmentStatus(ctx *fiber.Ctx, appointmentID string, status string) (*appointment.Appointment, error) {
-	// Convert string status to AppointmentStatus and update
-	// For now, return error - implement status update logic later
-	return nil, fmt.Errorf("not implemented yet")
+	date, err = time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, expected ISO format or YYYY-MM-DD: %w", err)
+	}
+	return date, nil
 }
 
 // Additional methods for future use
@@ -144,6 +463,9 @@ func (s *Service) UpdateAppointmentDirect(ctx context.Context, apt *appointment.
 	return s.appointmentRepo.Update(ctx, apt)
 }
 
+// UpdateAppointmentStatusDirect changes the status on behalf of the system; the
+// transition rules still apply
 func (s *Service) UpdateAppointmentStatusDirect(ctx context.Context, id string, status appointment.AppointmentStatus) error {
-	return s.appointmentRepo.UpdateStatus(ctx, id, status)
+	_, err := s.changeStatus(ctx, id, status, nil, nil)
+	return err
 }
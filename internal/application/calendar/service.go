@@ -0,0 +1,361 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/calendar"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/domain/user"
+	"medika-backend/pkg/ical"
+	"medika-backend/pkg/logger"
+)
+
+const (
+	prodID = "-//Medika//Appointments//EN"
+
+	// feedLimit caps the number of appointments published in a feed; the most
+	// recent ones are kept
+	feedLimit = 500
+
+	tokenBytes = 32
+)
+
+// Repository interfaces for dependency injection
+type AppointmentRepository interface {
+	GetByID(ctx context.Context, id string) (*appointment.Appointment, error)
+	GetByPatient(ctx context.Context, patientID string, limit, offset int) ([]*appointment.Appointment, error)
+	GetByDoctor(ctx context.Context, doctorID string, limit, offset int) ([]*appointment.Appointment, error)
+}
+
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*organization.Organization, error)
+}
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id shared.UserID) (*user.User, error)
+}
+
+// Caller is the authenticated user managing feed tokens
+type Caller struct {
+	UserID         string
+	Role           string
+	OrganizationID string
+}
+
+// Service issues calendar feed tokens and renders appointments as iCalendar
+// documents
+type Service struct {
+	calendarRepo    calendar.Repository
+	appointmentRepo AppointmentRepository
+	orgRepo         OrganizationRepository
+	userRepo        UserRepository
+	logger          logger.Logger
+}
+
+func NewService(
+	calendarRepo calendar.Repository,
+	appointmentRepo AppointmentRepository,
+	orgRepo OrganizationRepository,
+	userRepo UserRepository,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		calendarRepo:    calendarRepo,
+		appointmentRepo: appointmentRepo,
+		orgRepo:         orgRepo,
+		userRepo:        userRepo,
+		logger:          logger,
+	}
+}
+
+// CreateFeedToken issues a feed token for a doctor or patient. The returned
+// secret is not stored and cannot be recovered later.
+func (s *Service) CreateFeedToken(ctx context.Context, caller Caller, ownerType calendar.OwnerType, ownerID string, label *string) (*calendar.FeedToken, string, error) {
+	owner, err := s.checkOwner(ctx, ownerType, ownerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := authorize(caller, owner); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	token := &calendar.FeedToken{
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		TokenHash: hashSecret(secret),
+		Label:     label,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.calendarRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+func (s *Service) GetFeedTokens(ctx context.Context, caller Caller, ownerType calendar.OwnerType, ownerID string) ([]*calendar.FeedToken, error) {
+	owner, err := s.checkOwner(ctx, ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(caller, owner); err != nil {
+		return nil, err
+	}
+
+	return s.calendarRepo.GetByOwner(ctx, ownerType, ownerID)
+}
+
+// RevokeFeedToken stops a feed token from working. Calendar apps subscribed
+// with it get a 404 from then on.
+func (s *Service) RevokeFeedToken(ctx context.Context, caller Caller, id string) error {
+	token, err := s.calendarRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	owner, err := s.checkOwner(ctx, token.OwnerType, token.OwnerID)
+	if err != nil {
+		return err
+	}
+	if err := authorize(caller, owner); err != nil {
+		return err
+	}
+
+	return s.calendarRepo.Revoke(ctx, id, time.Now())
+}
+
+// GetFeed renders the subscribable feed a secret grants access to. Unknown and
+// revoked secrets both yield calendar.ErrFeedNotFound.
+func (s *Service) GetFeed(ctx context.Context, secret string) (*ical.Calendar, error) {
+	token, err := s.calendarRepo.GetByHash(ctx, hashSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	if token.IsRevoked() {
+		return nil, calendar.ErrFeedNotFound
+	}
+
+	var appointments []*appointment.Appointment
+	switch token.OwnerType {
+	case calendar.OwnerTypeDoctor:
+		appointments, err = s.appointmentRepo.GetByDoctor(ctx, token.OwnerID, feedLimit, 0)
+	case calendar.OwnerTypePatient:
+		appointments, err = s.appointmentRepo.GetByPatient(ctx, token.OwnerID, feedLimit, 0)
+	default:
+		return nil, calendar.ErrInvalidOwner
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.calendarRepo.MarkUsed(ctx, token.ID, time.Now()); err != nil {
+		s.logger.Warn(ctx, "Failed to record calendar feed access", "error", err, "tokenID", token.ID)
+	}
+
+	return &ical.Calendar{
+		ProdID: prodID,
+		Method: ical.MethodPublish,
+		Name:   "Medika - " + s.userName(ctx, token.OwnerID, map[string]string{}),
+		Events: s.buildEvents(ctx, appointments, token.OwnerType),
+	}, nil
+}
+
+// GetAppointmentCalendar renders a single appointment for download. Cancelled
+// appointments are sent as a cancellation so that calendar apps remove them.
+func (s *Service) GetAppointmentCalendar(ctx context.Context, id string) (*ical.Calendar, error) {
+	apt, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	method := ical.MethodRequest
+	if apt.Status == appointment.StatusCancelled {
+		method = ical.MethodCancel
+	}
+
+	return &ical.Calendar{
+		ProdID: prodID,
+		Method: method,
+		Events: s.buildEvents(ctx, []*appointment.Appointment{apt}, calendar.OwnerTypePatient),
+	}, nil
+}
+
+// buildEvents converts appointments into events. The counterpart's name goes into
+// the summary: the patient for a doctor's feed and the doctor otherwise.
+func (s *Service) buildEvents(ctx context.Context, appointments []*appointment.Appointment, viewer calendar.OwnerType) []*ical.Event {
+	now := time.Now()
+	names := make(map[string]string)
+	locations := make(map[string]*time.Location)
+	events := make([]*ical.Event, 0, len(appointments))
+
+	for _, apt := range appointments {
+		loc := s.location(ctx, apt.OrganizationID, locations)
+		start, err := appointmentTime(apt.Date, apt.StartTime, loc)
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping appointment with invalid start time", "error", err, "appointmentID", apt.ID)
+			continue
+		}
+		end, err := appointmentTime(apt.Date, apt.EndTime, loc)
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping appointment with invalid end time", "error", err, "appointmentID", apt.ID)
+			continue
+		}
+
+		counterpartID := apt.DoctorID
+		if viewer == calendar.OwnerTypeDoctor {
+			counterpartID = apt.PatientID
+		}
+
+		events = append(events, &ical.Event{
+			UID: apt.ID + "@medika",
+			// Every change, e.g. of status or times, is a revision calendar apps have to pick up
+			Sequence:     apt.Revision,
+			Stamp:        now,
+			Start:        start,
+			End:          end,
+			Summary:      fmt.Sprintf("%s with %s", typeLabel(apt.Type), s.userName(ctx, counterpartID, names)),
+			Description:  fmt.Sprintf("Status: %s\nAppointment ID: %s", strings.ReplaceAll(string(apt.Status), "_", " "), apt.ID),
+			Status:       eventStatus(apt.Status),
+			Created:      apt.CreatedAt,
+			LastModified: apt.UpdatedAt,
+		})
+	}
+
+	return events
+}
+
+// location looks up the time zone of an organization's appointments, caching it in
+// locations. The server's zone is used when a lookup fails.
+func (s *Service) location(ctx context.Context, organizationID string, locations map[string]*time.Location) *time.Location {
+	if loc, ok := locations[organizationID]; ok {
+		return loc
+	}
+
+	loc := time.Local
+	if org, err := s.orgRepo.GetByID(ctx, organizationID); err == nil {
+		loc = org.Location()
+	} else {
+		s.logger.Warn(ctx, "Failed to look up organization for calendar", "error", err, "organizationID", organizationID)
+	}
+
+	locations[organizationID] = loc
+	return loc
+}
+
+// userName looks up a user's display name, caching it in names. Feeds are still
+// served when a lookup fails.
+func (s *Service) userName(ctx context.Context, id string, names map[string]string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+
+	name := "unknown"
+	if userID, err := shared.NewUserIDFromString(id); err == nil {
+		if u, err := s.userRepo.FindByID(ctx, userID); err == nil {
+			name = u.Name().String()
+		} else {
+			s.logger.Warn(ctx, "Failed to look up user for calendar", "error", err, "userID", id)
+		}
+	}
+
+	names[id] = name
+	return name
+}
+
+// checkOwner returns the user whose feeds are managed, who must have ownerType's role
+func (s *Service) checkOwner(ctx context.Context, ownerType calendar.OwnerType, ownerID string) (*user.User, error) {
+	if !ownerType.IsValid() {
+		return nil, calendar.ErrInvalidOwner
+	}
+
+	userID, err := shared.NewUserIDFromString(ownerID)
+	if err != nil {
+		return nil, calendar.ErrInvalidOwner
+	}
+
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, calendar.ErrInvalidOwner
+	}
+
+	if string(u.Role()) != string(ownerType) {
+		return nil, calendar.ErrInvalidOwner
+	}
+
+	return u, nil
+}
+
+// authorize lets users manage their own feeds, and admins those of the users of their
+// organization. A feed exposes its owner's appointments to anyone holding its URL.
+func authorize(caller Caller, owner *user.User) error {
+	if caller.UserID != "" && caller.UserID == owner.ID().String() {
+		return nil
+	}
+
+	if caller.Role == string(user.RoleAdmin) && caller.OrganizationID != "" &&
+		owner.OrganizationID() != nil && owner.OrganizationID().String() == caller.OrganizationID {
+		return nil
+	}
+
+	return calendar.ErrForbidden
+}
+
+func eventStatus(status appointment.AppointmentStatus) ical.Status {
+	switch status {
+	case appointment.StatusPending:
+		return ical.StatusTentative
+	case appointment.StatusCancelled:
+		return ical.StatusCancelled
+	default:
+		return ical.StatusConfirmed
+	}
+}
+
+func typeLabel(appointmentType string) string {
+	label := strings.ReplaceAll(appointmentType, "_", " ")
+	if label == "" {
+		return "Appointment"
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// appointmentTime combines an appointment date and a wall-clock time of day in the
+// organization's time zone, loc. Events are written in UTC, so calendar apps show it
+// in their user's zone.
+func appointmentTime(date time.Time, timeOfDay string, loc *time.Location) (time.Time, error) {
+	offset, err := shared.ParseTimeOfDay(timeOfDay)
+	if err != nil {
+		return time.Time{}, err
+	}
+	year, month, day := date.Date()
+	return time.Date(year, month, day, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, loc), nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
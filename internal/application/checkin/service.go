@@ -0,0 +1,234 @@
+package checkin
+
+import (
+	"context"
+	"time"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/checkin"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/pkg/logger"
+)
+
+// AppointmentRepository interface for dependency injection
+type AppointmentRepository interface {
+	GetByID(ctx context.Context, id string) (*appointment.Appointment, error)
+}
+
+// OrganizationRepository interface for dependency injection
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*organization.Organization, error)
+}
+
+// Service checks patients in on arrival, turning their appointment into a place in
+// their doctor's queue, and books walk-ins who arrive without one
+type Service struct {
+	checkinRepo      checkin.Repository
+	appointmentRepo  AppointmentRepository
+	organizationRepo OrganizationRepository
+	queueRepo        queue.Repository
+	window           checkin.ArrivalWindow
+	eventBus         events.Bus
+	transactor       events.Transactor
+	logger           logger.Logger
+}
+
+func NewService(
+	checkinRepo checkin.Repository,
+	appointmentRepo AppointmentRepository,
+	organizationRepo OrganizationRepository,
+	queueRepo queue.Repository,
+	window checkin.ArrivalWindow,
+	eventBus events.Bus,
+	transactor events.Transactor,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		checkinRepo:      checkinRepo,
+		appointmentRepo:  appointmentRepo,
+		organizationRepo: organizationRepo,
+		queueRepo:        queueRepo,
+		window:           window,
+		eventBus:         eventBus,
+		transactor:       transactor,
+		logger:           logger,
+	}
+}
+
+// CheckIn checks in the appointment with the given ID or, without one, the given
+// check-in code, and queues the patient for their doctor. organizationID is the
+// checking-in staff member's organization, whose appointments alone they can check in,
+// or empty for a patient checking in with their code. triageLevel may be zero for the
+// default level.
+func (s *Service) CheckIn(ctx context.Context, organizationID, appointmentID, code string, triageLevel queue.TriageLevel, actorID *string) (*checkin.Result, error) {
+	var apt *appointment.Appointment
+	var err error
+	if appointmentID != "" {
+		if apt, err = s.appointmentRepo.GetByID(ctx, appointmentID); err != nil {
+			return nil, checkin.ErrAppointmentNotFound
+		}
+	} else {
+		if apt, err = s.checkinRepo.GetAppointmentByCode(ctx, checkin.NormalizeCode(code)); err != nil {
+			return nil, err
+		}
+	}
+	if organizationID != "" && apt.OrganizationID != organizationID {
+		if appointmentID != "" {
+			return nil, checkin.ErrAppointmentNotFound
+		}
+		return nil, checkin.ErrCodeNotFound
+	}
+
+	if apt.Status == appointment.StatusCheckedIn {
+		return nil, checkin.ErrAlreadyCheckedIn
+	}
+	org, err := s.organizationRepo.GetByID(ctx, apt.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.window.Check(apt, org.Location(), time.Now()); err != nil {
+		return nil, err
+	}
+
+	entry, err := newEntry(apt, triageLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := apt.Transition(appointment.StatusCheckedIn, actorID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *checkin.Result
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := s.checkinRepo.CheckIn(ctx, change, entry, actorID)
+		if err != nil {
+			return err
+		}
+
+		apt.Status = change.ToStatus
+		apt.UpdatedAt = change.ChangedAt
+
+		result, err = s.result(ctx, apt, entry, created)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RegisterWalkIn books apt for a patient who arrived without an appointment, at the
+// first time from now, in its organization's time zone, that their doctor and they
+// are free, checks it in and queues the patient. Duration defaults to
+// queue.DefaultConsultationMinutes.
+func (s *Service) RegisterWalkIn(ctx context.Context, apt *appointment.Appointment, triageLevel queue.TriageLevel, actorID *string) (*checkin.Result, error) {
+	org, err := s.organizationRepo.GetByID(ctx, apt.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	loc := org.Location()
+
+	// Walk-ins start on the next whole minute, as booked times have no seconds
+	now := time.Now().In(loc)
+	start := now.Truncate(time.Minute).Add(time.Minute)
+	year, month, day := now.Date()
+	if start.Day() != day {
+		return nil, checkin.ErrNoWalkInSlot
+	}
+
+	apt.Date = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	apt.StartTime = start.Format("15:04")
+	apt.Status = appointment.StatusCheckedIn
+	apt.WalkIn = true
+	apt.CreatedAt = now
+	apt.UpdatedAt = now
+	if apt.Duration == 0 {
+		apt.Duration = queue.DefaultConsultationMinutes
+	}
+
+	entry, err := newEntry(apt, triageLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *checkin.Result
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkinRepo.CreateWalkIn(ctx, apt, entry, actorID); err != nil {
+			return err
+		}
+
+		result, err = s.result(ctx, apt, entry, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// newEntry builds the entry a checked-in patient joins their doctor's queue with
+func newEntry(apt *appointment.Appointment, triageLevel queue.TriageLevel) (*queue.PatientQueue, error) {
+	if triageLevel == 0 {
+		triageLevel = queue.DefaultTriageLevel
+	}
+	if !triageLevel.IsValid() {
+		return nil, queue.ErrInvalidTriageLevel
+	}
+
+	return &queue.PatientQueue{
+		AppointmentID:  apt.ID,
+		OrganizationID: apt.OrganizationID,
+		ScopeType:      queue.ScopeDoctor,
+		ScopeID:        apt.DoctorID,
+		TriageLevel:    triageLevel,
+		Status:         queue.QueueStatusWaiting,
+	}, nil
+}
+
+// result re-reads the entry for its rank and estimated wait, and announces it if it
+// was created. It runs in the transaction that checked the patient in.
+func (s *Service) result(ctx context.Context, apt *appointment.Appointment, entry *queue.PatientQueue, created bool) (*checkin.Result, error) {
+	ranked, err := s.queueRepo.GetByID(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		if ranked.TriageLevel != queue.DefaultTriageLevel {
+			if err := s.publish(ctx, queue.EventPositionsChanged, ranked.OrganizationID, ranked.Scope(), nil); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.publish(ctx, queue.EventCreated, ranked.OrganizationID, ranked.Scope(), ranked); err != nil {
+			return nil, err
+		}
+	}
+
+	return &checkin.Result{Appointment: apt, Entry: ranked}, nil
+}
+
+// publish broadcasts a queue change; entry is nil for changes to the queue as a whole
+func (s *Service) publish(ctx context.Context, eventType, organizationID string, scope queue.Scope, entry *queue.PatientQueue) error {
+	event := queue.QueueEvent{
+		Type:           eventType,
+		OrganizationID: organizationID,
+		Scope:          scope,
+		OccurredAt:     time.Now(),
+	}
+	if entry != nil {
+		snapshot := *entry
+		event.Entry = &snapshot
+	}
+
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Error(ctx, "Failed to publish queue event", "error", err, "type", eventType, "organizationID", organizationID)
+		return err
+	}
+	return nil
+}
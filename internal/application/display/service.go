@@ -0,0 +1,128 @@
+package display
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"medika-backend/internal/domain/display"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/pkg/logger"
+)
+
+const tokenBytes = 32
+
+// OrganizationRepository interface for dependency injection
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*organization.Organization, error)
+}
+
+// Service issues display tokens and builds the waiting-room boards they grant
+// access to
+type Service struct {
+	displayRepo      display.Repository
+	organizationRepo OrganizationRepository
+	logger           logger.Logger
+}
+
+func NewService(
+	displayRepo display.Repository,
+	organizationRepo OrganizationRepository,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		displayRepo:      displayRepo,
+		organizationRepo: organizationRepo,
+		logger:           logger,
+	}
+}
+
+// CreateToken issues a display token for an organization's board, or for the board
+// of one of its queues. The returned secret is not stored and cannot be recovered
+// later.
+func (s *Service) CreateToken(ctx context.Context, organizationID string, scope *queue.Scope, label *string) (*display.Token, string, error) {
+	if _, err := s.organizationRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, "", display.ErrOrganizationNotFound
+	}
+	if scope != nil && !scope.IsValid() {
+		return nil, "", queue.ErrInvalidScope
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	token := &display.Token{
+		OrganizationID: organizationID,
+		Scope:          scope,
+		TokenHash:      hashSecret(secret),
+		Label:          label,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.displayRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+func (s *Service) GetTokens(ctx context.Context, organizationID string) ([]*display.Token, error) {
+	return s.displayRepo.GetByOrganization(ctx, organizationID)
+}
+
+// RevokeToken stops a display token of an organization from working; screens using
+// it get a 404 from then on. Other organizations' tokens are not found.
+func (s *Service) RevokeToken(ctx context.Context, organizationID, id string) error {
+	token, err := s.displayRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if token.OrganizationID != organizationID {
+		return display.ErrTokenNotFound
+	}
+	return s.displayRepo.Revoke(ctx, id, time.Now())
+}
+
+// GetBoard returns the board a secret grants access to. Unknown and revoked secrets
+// both yield display.ErrTokenNotFound.
+func (s *Service) GetBoard(ctx context.Context, secret string) (*display.Board, error) {
+	token, err := s.displayRepo.GetByHash(ctx, hashSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	if token.IsRevoked() {
+		return nil, display.ErrTokenNotFound
+	}
+
+	board, err := s.displayRepo.GetBoard(ctx, token.OrganizationID, token.Scope, display.DefaultNextUpPerQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.displayRepo.MarkUsed(ctx, token.ID, time.Now()); err != nil {
+		s.logger.Warn(ctx, "Failed to record display token access", "error", err, "tokenID", token.ID)
+	}
+
+	return board, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate display token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,188 @@
+package noshow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/noshow"
+	"medika-backend/pkg/logger"
+)
+
+const (
+	noShowReason      = "Not attended within the grace period"
+	unconfirmedReason = "Not confirmed before the confirmation deadline"
+)
+
+// AppointmentRepository interface for dependency injection
+type AppointmentRepository interface {
+	FindOverdue(ctx context.Context, now time.Time, defaultGrace time.Duration, limit int) ([]*appointment.Appointment, error)
+	FindUnconfirmed(ctx context.Context, now time.Time, limit int) ([]*appointment.Appointment, error)
+	ChangeStatus(ctx context.Context, changes ...*appointment.StatusChange) error
+}
+
+// Service applies organizations' no-show policies: it marks missed appointments,
+// keeps per-patient counts and decides what patients with a history of no-shows
+// have to do before a new appointment is secured
+type Service struct {
+	noShowRepo      noshow.Repository
+	appointmentRepo AppointmentRepository
+	eventBus        events.Bus
+	transactor      events.Transactor
+	gracePeriod     time.Duration
+	batchSize       int
+	logger          logger.Logger
+}
+
+func NewService(
+	noShowRepo noshow.Repository,
+	appointmentRepo AppointmentRepository,
+	eventBus events.Bus,
+	transactor events.Transactor,
+	gracePeriod time.Duration,
+	batchSize int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		noShowRepo:      noShowRepo,
+		appointmentRepo: appointmentRepo,
+		eventBus:        eventBus,
+		transactor:      transactor,
+		gracePeriod:     gracePeriod,
+		batchSize:       batchSize,
+		logger:          logger,
+	}
+}
+
+// GetPolicy returns the organization's policy, or the default policy if none was saved
+func (s *Service) GetPolicy(ctx context.Context, organizationID string) (*noshow.Policy, error) {
+	policy, err := s.noShowRepo.GetPolicy(ctx, organizationID)
+	if errors.Is(err, noshow.ErrPolicyNotFound) {
+		return noshow.DefaultPolicy(organizationID), nil
+	}
+	return policy, err
+}
+
+// UpdatePolicy replaces the organization's policy. It applies to appointments booked
+// from now on.
+func (s *Service) UpdatePolicy(ctx context.Context, policy *noshow.Policy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	return s.noShowRepo.SavePolicy(ctx, policy)
+}
+
+// GetPatientRecord returns a patient's no-show count at an organization together with
+// what the organization's policy currently requires from them
+func (s *Service) GetPatientRecord(ctx context.Context, organizationID, patientID string) (*noshow.PatientRecord, noshow.Requirement, error) {
+	policy, err := s.GetPolicy(ctx, organizationID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	record, err := s.noShowRepo.GetPatientRecord(ctx, organizationID, patientID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return record, policy.RequirementFor(record.NoShowCount), nil
+}
+
+// RequirementFor returns what the patient has to do for a new appointment at the organization
+func (s *Service) RequirementFor(ctx context.Context, organizationID, patientID string) (noshow.Requirement, error) {
+	_, requirement, err := s.GetPatientRecord(ctx, organizationID, patientID)
+	return requirement, err
+}
+
+// MarkNoShows marks confirmed appointments that ended more than the grace period ago
+// as no-show. The repository takes them out of the queue and counts them against the
+// patient.
+func (s *Service) MarkNoShows(ctx context.Context) error {
+	overdue, err := s.appointmentRepo.FindOverdue(ctx, time.Now(), s.gracePeriod, s.batchSize)
+	if err != nil {
+		return err
+	}
+
+	reason := noShowReason
+	marked := 0
+	for _, apt := range overdue {
+		change, err := apt.Transition(appointment.StatusNoShow, nil, &reason)
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping overdue appointment", "appointmentID", apt.ID, "error", err)
+			continue
+		}
+
+		if err := s.appointmentRepo.ChangeStatus(ctx, change); err != nil {
+			if errors.Is(err, appointment.ErrStatusChanged) {
+				// Checked in or cancelled since it was loaded
+				continue
+			}
+			return err
+		}
+		marked++
+	}
+
+	if marked > 0 {
+		s.logger.Info(ctx, "Marked appointments as no-show", "count", marked)
+	}
+
+	return nil
+}
+
+// EnforceConfirmations cancels appointments that had to be confirmed, or paid for,
+// and were not by the organization's deadline. The freed slots are offered to the
+// waitlist like any other cancellation.
+func (s *Service) EnforceConfirmations(ctx context.Context) error {
+	unconfirmed, err := s.appointmentRepo.FindUnconfirmed(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		return err
+	}
+
+	reason := unconfirmedReason
+	for _, apt := range unconfirmed {
+		change, err := apt.Transition(appointment.StatusCancelled, nil, &reason)
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping unconfirmed appointment", "appointmentID", apt.ID, "error", err)
+			continue
+		}
+
+		err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.appointmentRepo.ChangeStatus(ctx, change); err != nil {
+				return err
+			}
+
+			apt.Status = change.ToStatus
+			apt.UpdatedAt = change.ChangedAt
+
+			event := appointment.AppointmentCancelledEvent{
+				Appointment: *apt,
+				Reason:      change.Reason,
+				CancelledAt: change.ChangedAt,
+			}
+			if err := s.eventBus.Publish(ctx, event); err != nil {
+				s.logger.Error(ctx, "Failed to publish appointment cancelled event", "error", err, "appointmentID", apt.ID)
+				return err
+			}
+			return nil
+		})
+		if errors.Is(err, appointment.ErrStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsValidationError reports whether err is caused by an invalid policy
+func IsValidationError(err error) bool {
+	return errors.Is(err, noshow.ErrInvalidGracePeriod) ||
+		errors.Is(err, noshow.ErrInvalidThreshold) ||
+		errors.Is(err, noshow.ErrInvalidLeadTime) ||
+		errors.Is(err, noshow.ErrInvalidDeposit)
+}
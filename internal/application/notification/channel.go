@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"context"
+)
+
+// Channel delivers notifications outside the app, e.g. by email. Implementations live
+// in the infrastructure layer and are registered with the Service by name.
+type Channel interface {
+	// Name is the channel as listed in a notification's channels, e.g. "email"
+	Name() string
+	// Send delivers msg to its recipient. A failed delivery is attempted again later
+	// unless the error wraps notification.ErrUndeliverable.
+	Send(ctx context.Context, msg Message) error
+}
+
+// Message is a notification addressed to its recipient on one channel
+type Message struct {
+	DeliveryID     string                 `json:"deliveryId"`
+	NotificationID string                 `json:"notificationId"`
+	Channel        string                 `json:"channel"`
+	Type           string                 `json:"type"`
+	Priority       string                 `json:"priority"`
+	Title          string                 `json:"title"`
+	Body           string                 `json:"body"`
+	HTML           string                 `json:"html,omitempty"` // HTML alternative to Body, for email
+	Data           map[string]interface{} `json:"data,omitempty"`
+	Recipient      Recipient              `json:"recipient"`
+}
+
+// Recipient is who a message goes to and how they can be reached
+type Recipient struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Phone  string `json:"phone,omitempty"`
+}
@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"medika-backend/internal/domain/notification"
+)
+
+// GetPreferences returns a user's notification preferences, or the defaults if they
+// have not saved any
+func (s *Service) GetPreferences(ctx context.Context, userID string) (*notification.Preferences, error) {
+	preferences, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if errors.Is(err, notification.ErrPreferencesNotFound) {
+		return notification.DefaultPreferences(userID, s.defaultTimezone), nil
+	}
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get notification preferences", "error", err, "user_id", userID)
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// UpdatePreferences validates and saves a user's notification preferences
+func (s *Service) UpdatePreferences(ctx context.Context, preferences *notification.Preferences) error {
+	if err := preferences.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.notificationRepo.SavePreferences(ctx, preferences); err != nil {
+		s.logger.Error(ctx, "Failed to save notification preferences", "error", err, "user_id", preferences.UserID)
+		return err
+	}
+
+	s.logger.Info(ctx, "Notification preferences updated", "user_id", preferences.UserID)
+	return nil
+}
+
+// IsPreferencesError reports whether err comes from invalid preferences rather than a
+// server failure
+func IsPreferencesError(err error) bool {
+	return errors.Is(err, notification.ErrInvalidTimezone) ||
+		errors.Is(err, notification.ErrInvalidQuietHours) ||
+		errors.Is(err, notification.ErrInvalidPreference)
+}
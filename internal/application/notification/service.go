@@ -2,20 +2,50 @@ package notification
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"medika-backend/internal/domain/notification"
 	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/domain/user"
 	"medika-backend/pkg/logger"
 )
 
+// UserRepository interface for dependency injection
+type UserRepository interface {
+	FindByID(ctx context.Context, id shared.UserID) (*user.User, error)
+}
+
 type Service struct {
-	notificationRepo notification.Repository
-	logger           logger.Logger
+	notificationRepo  notification.Repository
+	userRepo          UserRepository
+	channels          map[string]Channel
+	retry             notification.RetryPolicy
+	defaultLocale     notification.Locale
+	defaultTimezone   string
+	dispatchBatchSize int
+	logger            logger.Logger
 }
 
-func NewService(notificationRepo notification.Repository, logger logger.Logger) *Service {
+// NewService creates the notification service. Notifications are delivered on the
+// given channels, by name, with failed deliveries retried as retry says. Templates are
+// rendered in defaultLocale unless a notice asks for another; quiet hours of users who
+// have not chosen a timezone are in defaultTimezone.
+func NewService(notificationRepo notification.Repository, userRepo UserRepository, channels []Channel, retry notification.RetryPolicy, defaultLocale notification.Locale, defaultTimezone string, dispatchBatchSize int, logger logger.Logger) *Service {
+	byName := make(map[string]Channel, len(channels))
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+
 	return &Service{
-		notificationRepo: notificationRepo,
-		logger:           logger,
+		notificationRepo:  notificationRepo,
+		userRepo:          userRepo,
+		channels:          byName,
+		retry:             retry,
+		defaultLocale:     defaultLocale,
+		defaultTimezone:   defaultTimezone,
+		dispatchBatchSize: dispatchBatchSize,
+		logger:            logger,
 	}
 }
 
@@ -134,3 +164,147 @@ func (s *Service) DeleteNotification(ctx context.Context, notificationID notific
 	s.logger.Info(ctx, "Successfully deleted notification", "notification_id", notificationID.String())
 	return nil
 }
+
+// DispatchDue delivers scheduled notifications whose time has come. Claiming a
+// notification stamps sent_at, which also makes it visible in the user's inbox.
+func (s *Service) DispatchDue(ctx context.Context) error {
+	for {
+		due, err := s.notificationRepo.ClaimDue(ctx, time.Now(), s.dispatchBatchSize)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to claim due notifications", "error", err)
+			return err
+		}
+
+		for _, notif := range due {
+			s.logger.Info(ctx, "Dispatched scheduled notification", "notification_id", notif.ID().String(), "user_id", notif.UserID().String())
+		}
+
+		if len(due) == 0 || len(due) < s.dispatchBatchSize {
+			return nil
+		}
+	}
+}
+
+// DeliverPending attempts the deliveries that are due on external channels, recording
+// each outcome. Failed deliveries are retried with backoff until the retry policy's
+// attempts are used up.
+func (s *Service) DeliverPending(ctx context.Context) error {
+	for {
+		claimed, err := s.notificationRepo.ClaimDeliveries(ctx, time.Now(), s.dispatchBatchSize)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to claim notification deliveries", "error", err)
+			return err
+		}
+
+		for _, delivery := range claimed {
+			s.deliver(ctx, delivery)
+		}
+
+		if len(claimed) == 0 || len(claimed) < s.dispatchBatchSize {
+			return nil
+		}
+	}
+}
+
+// GetDeliveries returns a notification's deliveries on external channels
+func (s *Service) GetDeliveries(ctx context.Context, notificationID notification.NotificationID) ([]*notification.Delivery, error) {
+	return s.notificationRepo.FindDeliveries(ctx, notificationID)
+}
+
+func (s *Service) deliver(ctx context.Context, delivery *notification.Delivery) {
+	now := time.Now()
+
+	notif, err := s.notificationRepo.FindByID(ctx, delivery.NotificationID)
+	if err != nil {
+		err = fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	held := false
+	if err == nil {
+		held, err = s.holdBack(ctx, delivery, notif, now)
+	}
+	if err == nil && !held {
+		err = s.send(ctx, delivery, notif)
+	}
+
+	switch {
+	case held:
+		s.logger.Debug(ctx, "Notification delivery held back by preferences", "delivery_id", delivery.ID, "channel", delivery.Channel, "status", string(delivery.Status))
+	case err != nil:
+		delivery.Fail(err, now, s.retry)
+		s.logger.Warn(ctx, "Notification delivery failed", "error", err, "delivery_id", delivery.ID, "channel", delivery.Channel, "attempts", delivery.Attempts, "status", string(delivery.Status))
+	default:
+		delivery.Succeed(now)
+	}
+
+	if err := s.notificationRepo.RecordDelivery(ctx, delivery); err != nil {
+		// The lease runs out and the delivery is attempted again
+		s.logger.Error(ctx, "Failed to record notification delivery", "error", err, "delivery_id", delivery.ID)
+	}
+}
+
+// holdBack applies the recipient's preferences to a delivery: it is skipped if they
+// switched the channel off for its type, and deferred during their quiet hours unless
+// it is urgent. It reports whether the delivery was held back.
+func (s *Service) holdBack(ctx context.Context, delivery *notification.Delivery, notif *notification.Notification, now time.Time) (bool, error) {
+	preferences, err := s.GetPreferences(ctx, notif.UserID().String())
+	if err != nil {
+		return false, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	if !preferences.Allows(notif.Type(), delivery.Channel) {
+		delivery.Skip(fmt.Sprintf("recipient turned off %s notifications by %s", notif.Type(), delivery.Channel), now)
+		return true, nil
+	}
+
+	if notification.IsUrgent(notif.Type(), notif.Priority()) {
+		return false, nil
+	}
+	if until, quiet := preferences.QuietUntil(now); quiet {
+		delivery.Defer(until, now)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *Service) send(ctx context.Context, delivery *notification.Delivery, notif *notification.Notification) error {
+	channel, ok := s.channels[delivery.Channel]
+	if !ok {
+		return fmt.Errorf("%w: channel %q is not configured", notification.ErrUndeliverable, delivery.Channel)
+	}
+
+	recipient, err := s.userRepo.FindByID(ctx, notif.UserID())
+	if err != nil {
+		return fmt.Errorf("failed to get recipient: %w", err)
+	}
+
+	body := notif.Message()
+	if delivery.Channel == notification.ChannelSMS && notif.SMSBody() != "" {
+		body = notif.SMSBody()
+	}
+
+	msg := Message{
+		DeliveryID:     delivery.ID,
+		NotificationID: notif.ID().String(),
+		Channel:        delivery.Channel,
+		Type:           string(notif.Type()),
+		Priority:       string(notif.Priority()),
+		Title:          notif.Title(),
+		Body:           body,
+		Data:           notif.Data(),
+		Recipient: Recipient{
+			UserID: recipient.ID().String(),
+			Name:   recipient.Name().String(),
+			Email:  recipient.Email().String(),
+		},
+	}
+	if delivery.Channel == notification.ChannelEmail {
+		msg.HTML = notif.EmailHTML()
+	}
+	if phone := recipient.Phone(); phone != nil {
+		msg.Recipient.Phone = phone.String()
+	}
+
+	return channel.Send(ctx, msg)
+}
@@ -0,0 +1,132 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"medika-backend/internal/domain/notification"
+)
+
+// Notify renders the notice's template and creates the notification
+func (s *Service) Notify(ctx context.Context, notice notification.Notice) (*notification.Notification, error) {
+	rendered, err := s.Render(ctx, notice.OrganizationID, notice.Template, notice.Locale, notice.Vars)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to render notification template", "error", err, "template", string(notice.Template))
+		return nil, err
+	}
+
+	notif := notification.NewTemplatedNotification(notice.UserID, rendered, notice.Type, notice.Priority, notice.Channels, notice.Data)
+	if err := s.notificationRepo.Create(ctx, notif); err != nil {
+		s.logger.Error(ctx, "Failed to create notification", "error", err, "user_id", notice.UserID.String())
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Successfully created notification", "notification_id", notif.ID().String(), "user_id", notice.UserID.String(), "template", string(notice.Template))
+	return notif, nil
+}
+
+// Render fills in the template of an event with vars. The organization's override is
+// used if it has one. An unsupported or empty locale falls back to the default.
+func (s *Service) Render(ctx context.Context, organizationID string, key notification.TemplateKey, locale notification.Locale, vars map[string]string) (*notification.Rendered, error) {
+	tmpl, err := s.resolveTemplate(ctx, organizationID, key, locale)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.Render(vars)
+}
+
+// GetTemplates returns the templates in effect for an organization: its overrides, and
+// the built-in templates for every event and locale it has not overridden
+func (s *Service) GetTemplates(ctx context.Context, organizationID string) ([]*notification.Template, error) {
+	overrides, err := s.notificationRepo.FindTemplates(ctx, organizationID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get notification templates", "error", err, "organizationID", organizationID)
+		return nil, err
+	}
+
+	overridden := make(map[notification.TemplateKey]map[notification.Locale]*notification.Template)
+	for _, tmpl := range overrides {
+		if overridden[tmpl.Key] == nil {
+			overridden[tmpl.Key] = make(map[notification.Locale]*notification.Template)
+		}
+		overridden[tmpl.Key][tmpl.Locale] = tmpl
+	}
+
+	var templates []*notification.Template
+	for _, key := range notification.TemplateKeys() {
+		for _, locale := range notification.Locales() {
+			if tmpl, ok := overridden[key][locale]; ok {
+				templates = append(templates, tmpl)
+			} else if tmpl, ok := notification.BuiltinTemplate(key, locale); ok {
+				templates = append(templates, tmpl)
+			}
+		}
+	}
+
+	return templates, nil
+}
+
+// SaveTemplate validates and saves an organization's override of a template
+func (s *Service) SaveTemplate(ctx context.Context, tmpl *notification.Template) error {
+	if err := tmpl.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.notificationRepo.SaveTemplate(ctx, tmpl); err != nil {
+		s.logger.Error(ctx, "Failed to save notification template", "error", err, "organizationID", *tmpl.OrganizationID, "key", string(tmpl.Key))
+		return err
+	}
+
+	s.logger.Info(ctx, "Notification template saved", "organizationID", *tmpl.OrganizationID, "key", string(tmpl.Key), "locale", string(tmpl.Locale))
+	return nil
+}
+
+// DeleteTemplate removes an organization's override, restoring the built-in template
+func (s *Service) DeleteTemplate(ctx context.Context, organizationID string, key notification.TemplateKey, locale notification.Locale) error {
+	if err := s.notificationRepo.DeleteTemplate(ctx, organizationID, key, locale); err != nil {
+		if !errors.Is(err, notification.ErrTemplateNotFound) {
+			s.logger.Error(ctx, "Failed to delete notification template", "error", err, "organizationID", organizationID, "key", string(key))
+		}
+		return err
+	}
+
+	s.logger.Info(ctx, "Notification template reset to built-in", "organizationID", organizationID, "key", string(key), "locale", string(locale))
+	return nil
+}
+
+func (s *Service) resolveTemplate(ctx context.Context, organizationID string, key notification.TemplateKey, locale notification.Locale) (*notification.Template, error) {
+	if !key.IsValid() {
+		return nil, notification.ErrUnknownTemplate
+	}
+
+	locales := []notification.Locale{s.defaultLocale}
+	if locale.IsValid() && locale != s.defaultLocale {
+		locales = []notification.Locale{locale, s.defaultLocale}
+	}
+
+	for _, locale := range locales {
+		if organizationID != "" {
+			tmpl, err := s.notificationRepo.GetTemplate(ctx, organizationID, key, locale)
+			if err == nil {
+				return tmpl, nil
+			}
+			if !errors.Is(err, notification.ErrTemplateNotFound) {
+				return nil, err
+			}
+		}
+		if tmpl, ok := notification.BuiltinTemplate(key, locale); ok {
+			return tmpl, nil
+		}
+	}
+
+	return nil, notification.ErrTemplateNotFound
+}
+
+// IsTemplateError reports whether err comes from an invalid template rather than a
+// server failure
+func IsTemplateError(err error) bool {
+	return errors.Is(err, notification.ErrUnknownTemplate) ||
+		errors.Is(err, notification.ErrUnsupportedLocale) ||
+		errors.Is(err, notification.ErrInvalidTemplate) ||
+		errors.Is(err, notification.ErrTemplateIncomplete)
+}
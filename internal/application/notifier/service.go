@@ -0,0 +1,234 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/notification"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/domain/user"
+	"medika-backend/pkg/logger"
+)
+
+// NotificationService interface for dependency injection
+type NotificationService interface {
+	Notify(ctx context.Context, notice notification.Notice) (*notification.Notification, error)
+}
+
+type AppointmentRepository interface {
+	GetByID(ctx context.Context, id string) (*appointment.Appointment, error)
+}
+
+type QueueRepository interface {
+	GetNextInQueue(ctx context.Context, organizationID string, scope queue.Scope) (*queue.PatientQueue, error)
+}
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id shared.UserID) (*user.User, error)
+}
+
+// Service turns domain events into notifications for the people they concern: the
+// doctor of a new booking, the patient of a confirmed or cancelled appointment, the
+// patient called from a queue and the one who is next, and new users.
+type Service struct {
+	notificationService NotificationService
+	appointmentRepo     AppointmentRepository
+	queueRepo           QueueRepository
+	userRepo            UserRepository
+	channels            []string
+	logger              logger.Logger
+}
+
+// NewService creates the notifier. Notifications go out on the given channels, subject
+// to each recipient's preferences.
+func NewService(
+	notificationService NotificationService,
+	appointmentRepo AppointmentRepository,
+	queueRepo QueueRepository,
+	userRepo UserRepository,
+	channels []string,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		notificationService: notificationService,
+		appointmentRepo:     appointmentRepo,
+		queueRepo:           queueRepo,
+		userRepo:            userRepo,
+		channels:            channels,
+		logger:              logger,
+	}
+}
+
+// Handle implements events.Handler for appointment.scheduled, appointment.status_changed,
+// appointment.cancelled, queue.called and user.created events
+func (s *Service) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case appointment.AppointmentScheduledEvent:
+		if e.Rescheduled {
+			return nil
+		}
+		return s.notifyBooked(ctx, &e.Appointment)
+	case appointment.AppointmentStatusChangedEvent:
+		if e.Change.ToStatus != appointment.StatusConfirmed {
+			return nil
+		}
+		return s.notifyConfirmed(ctx, &e.Appointment)
+	case appointment.AppointmentCancelledEvent:
+		// A moved appointment is not a cancellation to the patient, nor is their own
+		if e.Rescheduled || (e.ActorID != nil && *e.ActorID == e.Appointment.PatientID) {
+			return nil
+		}
+		return s.notifyCancelled(ctx, &e.Appointment)
+	case queue.QueueEvent:
+		if e.Type != queue.EventCalled || e.Entry == nil {
+			return nil
+		}
+		return s.notifyCalled(ctx, e)
+	case user.UserCreatedEvent:
+		return s.notifyWelcome(ctx, e)
+	}
+	return fmt.Errorf("unexpected event type %q", event.EventType())
+}
+
+// notifyBooked tells the doctor about a new booking
+func (s *Service) notifyBooked(ctx context.Context, apt *appointment.Appointment) error {
+	patient, err := s.findUser(ctx, apt.PatientID)
+	if err != nil {
+		return err
+	}
+
+	return s.notify(ctx, apt.DoctorID, apt.OrganizationID, notification.TemplateAppointmentBooked, map[string]string{
+		"patient_name": patient.Name().String(),
+		"date":         apt.Date.Format("2006-01-02"),
+		"time":         apt.StartTime,
+	}, notification.NotificationTypeAppointment, notification.PriorityMedium, appointmentData(apt))
+}
+
+// notifyConfirmed tells the patient their appointment is confirmed
+func (s *Service) notifyConfirmed(ctx context.Context, apt *appointment.Appointment) error {
+	doctor, err := s.findUser(ctx, apt.DoctorID)
+	if err != nil {
+		return err
+	}
+
+	return s.notify(ctx, apt.PatientID, apt.OrganizationID, notification.TemplateAppointmentConfirmed, map[string]string{
+		"doctor_name": doctor.Name().String(),
+		"date":        apt.Date.Format("2006-01-02"),
+		"time":        apt.StartTime,
+	}, notification.NotificationTypeAppointment, notification.PriorityMedium, appointmentData(apt))
+}
+
+// notifyCancelled tells the patient their appointment was cancelled
+func (s *Service) notifyCancelled(ctx context.Context, apt *appointment.Appointment) error {
+	return s.notify(ctx, apt.PatientID, apt.OrganizationID, notification.TemplateAppointmentCancelled, map[string]string{
+		"date": apt.Date.Format("2006-01-02"),
+		"time": apt.StartTime,
+	}, notification.NotificationTypeAppointment, notification.PriorityHigh, appointmentData(apt))
+}
+
+// notifyCalled tells the called patient it is their turn, and the patient now at the
+// front of the queue that they are next
+func (s *Service) notifyCalled(ctx context.Context, e queue.QueueEvent) error {
+	calledErr := s.notifyQueueEntry(ctx, e.Entry, notification.TemplateQueueCalled)
+
+	next, err := s.queueRepo.GetNextInQueue(ctx, e.OrganizationID, e.Scope)
+	if errors.Is(err, queue.ErrQueueEmpty) {
+		return calledErr
+	}
+	if err != nil {
+		return errors.Join(calledErr, err)
+	}
+
+	return errors.Join(calledErr, s.notifyQueueEntry(ctx, next, notification.TemplateQueueUpNext))
+}
+
+func (s *Service) notifyQueueEntry(ctx context.Context, entry *queue.PatientQueue, key notification.TemplateKey) error {
+	apt, err := s.appointmentRepo.GetByID(ctx, entry.AppointmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get appointment of queue entry %s: %w", entry.ID, err)
+	}
+
+	ticket := entry.TicketNumber
+	if ticket == "" {
+		ticket = "#" + strconv.Itoa(entry.Position)
+	}
+
+	return s.notify(ctx, apt.PatientID, entry.OrganizationID, key, map[string]string{
+		"ticket_number": ticket,
+	}, notification.NotificationTypeAppointment, notification.PriorityHigh, map[string]interface{}{
+		"queue_id":        entry.ID,
+		"appointment_id":  entry.AppointmentID,
+		"organization_id": entry.OrganizationID,
+		"scope_type":      string(entry.ScopeType),
+		"scope_id":        entry.ScopeID,
+		"ticket_number":   entry.TicketNumber,
+	})
+}
+
+// notifyWelcome greets a new user
+func (s *Service) notifyWelcome(ctx context.Context, e user.UserCreatedEvent) error {
+	newUser, err := s.userRepo.FindByID(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user %s: %w", e.UserID.String(), err)
+	}
+
+	organizationID := ""
+	if e.OrganizationID != nil {
+		organizationID = e.OrganizationID.String()
+	}
+
+	return s.notify(ctx, e.UserID.String(), organizationID, notification.TemplateWelcome, map[string]string{
+		"name": newUser.Name().String(),
+	}, notification.NotificationTypeSystem, notification.PriorityLow, nil)
+}
+
+func (s *Service) notify(ctx context.Context, userID, organizationID string, key notification.TemplateKey, vars map[string]string, notificationType notification.NotificationType, priority notification.Priority, data map[string]interface{}) error {
+	recipientID, err := shared.NewUserIDFromString(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.notificationService.Notify(ctx, notification.Notice{
+		UserID:         recipientID,
+		OrganizationID: organizationID,
+		Template:       key,
+		Vars:           vars,
+		Type:           notificationType,
+		Priority:       priority,
+		Channels:       s.channels,
+		Data:           data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", key, err)
+	}
+	return nil
+}
+
+func (s *Service) findUser(ctx context.Context, id string) (*user.User, error) {
+	userID, err := shared.NewUserIDFromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", id, err)
+	}
+	return found, nil
+}
+
+func appointmentData(apt *appointment.Appointment) map[string]interface{} {
+	return map[string]interface{}{
+		"appointment_id":  apt.ID,
+		"organization_id": apt.OrganizationID,
+		"patient_id":      apt.PatientID,
+		"doctor_id":       apt.DoctorID,
+		"date":            apt.Date.Format("2006-01-02"),
+		"start_time":      apt.StartTime,
+	}
+}
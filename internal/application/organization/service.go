@@ -2,9 +2,10 @@ package organization
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 
 	"medika-backend/internal/domain/organization"
 	"medika-backend/internal/presentation/http/dto"
@@ -37,13 +38,63 @@ func (s *Service) GetOrganizationByID(ctx *fiber.Ctx, organizationID string) (*o
 }
 
 func (s *Service) CreateOrganization(ctx *fiber.Ctx, orgData *dto.CreateOrganizationRequest) (*organization.Organization, error) {
-	// For now, return error - implement conversion logic later
-	return nil, fmt.Errorf("not implemented yet")
+	now := time.Now()
+	org := &organization.Organization{
+		ID:        uuid.New().String(),
+		Name:      orgData.Name,
+		Type:      orgData.Type,
+		Address:   orgData.Address,
+		Phone:     orgData.Phone,
+		Email:     orgData.Email,
+		Website:   orgData.Website,
+		Timezone:  orgData.Timezone,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.orgRepo.Create(ctx.Context(), org); err != nil {
+		return nil, err
+	}
+	return org, nil
 }
 
 func (s *Service) UpdateOrganization(ctx *fiber.Ctx, organizationID string, orgData *dto.UpdateOrganizationRequest) (*organization.Organization, error) {
-	// For now, return error - implement update logic later
-	return nil, fmt.Errorf("not implemented yet")
+	org, err := s.orgRepo.GetByID(ctx.Context(), organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if orgData.Name != nil {
+		org.Name = *orgData.Name
+	}
+	if orgData.Type != nil {
+		org.Type = *orgData.Type
+	}
+	if orgData.Address != nil {
+		org.Address = *orgData.Address
+	}
+	if orgData.Phone != nil {
+		org.Phone = *orgData.Phone
+	}
+	if orgData.Email != nil {
+		org.Email = *orgData.Email
+	}
+	if orgData.Website != nil {
+		org.Website = orgData.Website
+	}
+	if orgData.Status != nil {
+		org.IsActive = *orgData.Status == "active"
+	}
+	if orgData.Timezone != nil {
+		org.Timezone = *orgData.Timezone
+	}
+	org.UpdatedAt = time.Now()
+
+	if err := s.orgRepo.Update(ctx.Context(), org); err != nil {
+		return nil, err
+	}
+	return org, nil
 }
 
 func (s *Service) DeleteOrganization(ctx *fiber.Ctx, organizationID string) error {
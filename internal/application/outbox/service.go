@@ -0,0 +1,223 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/outbox"
+	"medika-backend/pkg/logger"
+)
+
+// Decoder rebuilds an event from its stored payload
+type Decoder func(payload []byte) (events.Event, error)
+
+// DecoderFor returns the Decoder of events of type E, which are stored as their JSON
+// encoding
+func DecoderFor[E events.Event]() Decoder {
+	return func(payload []byte) (events.Event, error) {
+		var event E
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+}
+
+// Service is the application's event bus. Publish stores an event in the outbox, in
+// the transaction ctx carries if any, and the relay hands it to the handlers subscribed
+// to its type. A failed event is published again only to the handlers that have not
+// handled it yet. Handlers still get an event more than once if the relay stops before
+// recording that they did, so they have to tolerate repeats.
+type Service struct {
+	outboxRepo  outbox.Repository
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+	decoders    map[string]Decoder
+	retry       outbox.RetryPolicy
+	batchSize   int
+	wake        chan struct{}
+	logger      logger.Logger
+}
+
+// subscriber is a handler together with the name its deliveries are recorded under
+type subscriber struct {
+	name    string
+	handler events.Handler
+}
+
+func NewService(outboxRepo outbox.Repository, decoders map[string]Decoder, retry outbox.RetryPolicy, batchSize int, logger logger.Logger) *Service {
+	return &Service{
+		outboxRepo:  outboxRepo,
+		subscribers: make(map[string][]subscriber),
+		decoders:    decoders,
+		retry:       retry,
+		batchSize:   batchSize,
+		wake:        make(chan struct{}, 1),
+		logger:      logger,
+	}
+}
+
+// Publish stores event in the outbox. Inside a transaction it is relayed only once the
+// transaction commits.
+func (s *Service) Publish(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.EventType(), err)
+	}
+
+	msg := outbox.NewMessage(event.EventType(), payload, time.Now())
+	msg.OrganizationID, _ = event.EventData()["organization_id"].(string)
+	if err := s.outboxRepo.Append(ctx, msg); err != nil {
+		return err
+	}
+
+	s.Wake()
+	return nil
+}
+
+// Subscribe registers handler for relayed events of eventType. Deliveries are recorded
+// under the handler's type, which stays the same across restarts, so a type can
+// subscribe to an event type only once.
+func (s *Service) Subscribe(ctx context.Context, eventType string, handler events.Handler) error {
+	if handler == nil {
+		return fmt.Errorf("nil handler for %s", eventType)
+	}
+	name := fmt.Sprintf("%T", handler)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subscribers[eventType] {
+		if sub.name == name {
+			return fmt.Errorf("%s is already subscribed to %s", name, eventType)
+		}
+	}
+	s.subscribers[eventType] = append(s.subscribers[eventType], subscriber{name: name, handler: handler})
+	return nil
+}
+
+// Wake makes the relay run soon, e.g. after a transaction with events committed
+func (s *Service) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Woken receives whenever the relay should run early
+func (s *Service) Woken() <-chan struct{} {
+	return s.wake
+}
+
+// Relay publishes due events, oldest first, until none are left. A failed event is
+// published again after the retry policy's backoff and moved to the dead letters once
+// its attempts are used up.
+func (s *Service) Relay(ctx context.Context) error {
+	for {
+		claimed, err := s.outboxRepo.Claim(ctx, time.Now(), s.batchSize)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to claim outbox messages", "error", err)
+			return err
+		}
+
+		for _, msg := range claimed {
+			s.relay(ctx, msg)
+		}
+
+		if len(claimed) < s.batchSize {
+			return nil
+		}
+	}
+}
+
+func (s *Service) relay(ctx context.Context, msg *outbox.Message) {
+	err := s.dispatch(ctx, msg)
+	if err == nil {
+		// Should this fail, the event is published again once its lease runs out
+		if err := s.outboxRepo.Delete(ctx, msg.ID); err != nil {
+			s.logger.Error(ctx, "Failed to remove published event from outbox", "error", err, "message_id", msg.ID, "type", msg.EventType)
+		}
+		return
+	}
+
+	now := time.Now()
+	if !msg.Fail(err, now, s.retry) {
+		s.logger.Warn(ctx, "Event handling failed", "error", err, "message_id", msg.ID, "type", msg.EventType, "attempts", msg.Attempts, "next_attempt_at", msg.NextAttemptAt)
+		if err := s.outboxRepo.Retry(ctx, msg); err != nil {
+			s.logger.Error(ctx, "Failed to record outbox attempt", "error", err, "message_id", msg.ID)
+		}
+		return
+	}
+
+	s.logger.Error(ctx, "Giving up on event", "error", err, "message_id", msg.ID, "type", msg.EventType, "attempts", msg.Attempts)
+	if err := s.outboxRepo.Bury(ctx, msg, now); err != nil {
+		s.logger.Error(ctx, "Failed to move event to dead letters", "error", err, "message_id", msg.ID)
+	}
+}
+
+func (s *Service) dispatch(ctx context.Context, msg *outbox.Message) error {
+	decode, ok := s.decoders[msg.EventType]
+	if !ok {
+		return fmt.Errorf("%w: unknown event type %q", outbox.ErrUndecodable, msg.EventType)
+	}
+
+	event, err := decode(msg.Payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", outbox.ErrUndecodable, err)
+	}
+
+	s.mu.RLock()
+	subscribers := slices.Clone(s.subscribers[msg.EventType])
+	s.mu.RUnlock()
+
+	// Every subscriber runs even if an earlier one fails, and those that succeed are
+	// skipped when the event is published again
+	var errs []error
+	for _, sub := range subscribers {
+		if msg.IsDeliveredTo(sub.name) {
+			continue
+		}
+		if err := events.Handle(ctx, sub.handler, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		msg.MarkDelivered(sub.name)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("handling %s: %w", msg.EventType, errors.Join(errs...))
+	}
+	return nil
+}
+
+// GetDeadLetters returns the events about the organization that the relay gave up on,
+// most recent first, optionally only those of one type
+func (s *Service) GetDeadLetters(ctx context.Context, organizationID, eventType string, limit, offset int) ([]*outbox.DeadLetter, error) {
+	return s.outboxRepo.FindDeadLetters(ctx, organizationID, eventType, limit, offset)
+}
+
+func (s *Service) CountDeadLetters(ctx context.Context, organizationID, eventType string) (int, error) {
+	return s.outboxRepo.CountDeadLetters(ctx, organizationID, eventType)
+}
+
+// Replay puts a dead letter of the organization back in the outbox with fresh attempts.
+// It goes back to the dead letters if it fails again.
+func (s *Service) Replay(ctx context.Context, organizationID, id string) (*outbox.Message, error) {
+	msg, err := s.outboxRepo.Replay(ctx, organizationID, id, time.Now())
+	if err != nil {
+		if !errors.Is(err, outbox.ErrDeadLetterNotFound) {
+			s.logger.Error(ctx, "Failed to replay dead letter", "error", err, "message_id", id)
+		}
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Dead letter replayed", "message_id", msg.ID, "type", msg.EventType)
+	s.Wake()
+	return msg, nil
+}
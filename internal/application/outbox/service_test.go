@@ -0,0 +1,178 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/outbox"
+)
+
+type testEvent struct {
+	Name string `json:"name"`
+}
+
+func (testEvent) EventType() string                   { return "test.happened" }
+func (e testEvent) EventData() map[string]interface{} { return map[string]interface{}{"name": e.Name} }
+
+// countingHandler succeeds every time
+type countingHandler struct{ calls int }
+
+func (h *countingHandler) Handle(ctx context.Context, event events.Event) error {
+	h.calls++
+	return nil
+}
+
+// flakyHandler fails its first failures calls
+type flakyHandler struct {
+	calls    int
+	failures int
+}
+
+func (h *flakyHandler) Handle(ctx context.Context, event events.Event) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("delivery failed")
+	}
+	return nil
+}
+
+// memoryRepository keeps the outbox in memory
+type memoryRepository struct {
+	messages    []*outbox.Message
+	deadLetters []*outbox.DeadLetter
+}
+
+func (r *memoryRepository) Append(ctx context.Context, msg *outbox.Message) error {
+	msg.ID = msg.EventType + "-" + time.Now().Format(time.RFC3339Nano)
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func (r *memoryRepository) Claim(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error) {
+	var claimed []*outbox.Message
+	for _, msg := range r.messages {
+		if len(claimed) < limit && !msg.NextAttemptAt.After(now) {
+			msg.Attempts++
+			msg.NextAttemptAt = now.Add(outbox.Lease)
+			claimed = append(claimed, msg)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, id string) error {
+	r.messages = slices.DeleteFunc(r.messages, func(msg *outbox.Message) bool { return msg.ID == id })
+	return nil
+}
+
+func (r *memoryRepository) Retry(ctx context.Context, msg *outbox.Message) error {
+	return nil
+}
+
+func (r *memoryRepository) Bury(ctx context.Context, msg *outbox.Message, at time.Time) error {
+	r.deadLetters = append(r.deadLetters, &outbox.DeadLetter{
+		ID:             msg.ID,
+		EventType:      msg.EventType,
+		OrganizationID: msg.OrganizationID,
+		Payload:        msg.Payload,
+		Attempts:       msg.Attempts,
+		Delivered:      msg.Delivered,
+		LastError:      *msg.LastError,
+		CreatedAt:      msg.CreatedAt,
+		FailedAt:       at,
+	})
+	return r.Delete(ctx, msg.ID)
+}
+
+func (r *memoryRepository) FindDeadLetters(ctx context.Context, organizationID, eventType string, limit, offset int) ([]*outbox.DeadLetter, error) {
+	return r.deadLetters, nil
+}
+
+func (r *memoryRepository) CountDeadLetters(ctx context.Context, organizationID, eventType string) (int, error) {
+	return len(r.deadLetters), nil
+}
+
+func (r *memoryRepository) Replay(ctx context.Context, organizationID, id string, at time.Time) (*outbox.Message, error) {
+	return nil, outbox.ErrDeadLetterNotFound
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(ctx context.Context, msg string, fields ...interface{}) {}
+func (nopLogger) Info(ctx context.Context, msg string, fields ...interface{})  {}
+func (nopLogger) Warn(ctx context.Context, msg string, fields ...interface{})  {}
+func (nopLogger) Error(ctx context.Context, msg string, fields ...interface{}) {}
+func (nopLogger) Fatal(ctx context.Context, msg string, fields ...interface{}) {}
+
+func TestServiceRelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		wantFlaky  int
+		wantBuried bool
+	}{
+		{name: "delivered at once", failures: 0, wantFlaky: 1},
+		{name: "retried until it succeeds", failures: 2, wantFlaky: 3},
+		{name: "buried once attempts are used up", failures: 10, wantFlaky: 3, wantBuried: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := &memoryRepository{}
+			decoders := map[string]Decoder{"test.happened": DecoderFor[testEvent]()}
+			// No backoff, so every relay run retries what failed in the one before
+			service := NewService(repo, decoders, outbox.RetryPolicy{MaxAttempts: 3}, 10, nopLogger{})
+
+			steady := &countingHandler{}
+			flaky := &flakyHandler{failures: tt.failures}
+			if err := service.Subscribe(ctx, "test.happened", steady); err != nil {
+				t.Fatal(err)
+			}
+			if err := service.Subscribe(ctx, "test.happened", flaky); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := service.Publish(ctx, testEvent{Name: "checked in"}); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 5; i++ {
+				if err := service.Relay(ctx); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if steady.calls != 1 {
+				t.Errorf("steady handler got the event %d times, want once", steady.calls)
+			}
+			if flaky.calls != tt.wantFlaky {
+				t.Errorf("flaky handler got the event %d times, want %d", flaky.calls, tt.wantFlaky)
+			}
+			if len(repo.messages) != 0 {
+				t.Errorf("%d messages left in the outbox, want none", len(repo.messages))
+			}
+			if buried := len(repo.deadLetters) == 1; buried != tt.wantBuried {
+				t.Fatalf("buried = %v, want %v", buried, tt.wantBuried)
+			}
+			if tt.wantBuried && !slices.Equal(repo.deadLetters[0].Delivered, []string{"*outbox.countingHandler"}) {
+				t.Errorf("dead letter delivered to %v, want only the steady handler", repo.deadLetters[0].Delivered)
+			}
+		})
+	}
+}
+
+func TestServiceSubscribeTwice(t *testing.T) {
+	service := NewService(&memoryRepository{}, nil, outbox.RetryPolicy{MaxAttempts: 1}, 10, nopLogger{})
+	handler := &countingHandler{}
+
+	if err := service.Subscribe(context.Background(), "test.happened", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Subscribe(context.Background(), "test.happened", handler); err == nil {
+		t.Error("subscribing a handler type twice to one event type succeeded")
+	}
+}
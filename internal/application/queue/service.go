@@ -2,21 +2,67 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/organization"
 	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/domain/room"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/domain/user"
 	"medika-backend/pkg/logger"
 )
 
+// AppointmentRepository interface for dependency injection
+type AppointmentRepository interface {
+	GetByID(ctx context.Context, id string) (*appointment.Appointment, error)
+}
+
+// OrganizationRepository interface for dependency injection
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*organization.Organization, error)
+}
+
+// RoomRepository interface for dependency injection
+type RoomRepository interface {
+	GetByID(ctx context.Context, id string) (*room.Room, error)
+}
+
+// UserRepository interface for dependency injection
+type UserRepository interface {
+	FindByID(ctx context.Context, id shared.UserID) (*user.User, error)
+}
+
 type Service struct {
-	queueRepo queue.Repository
-	logger    logger.Logger
+	queueRepo        queue.Repository
+	appointmentRepo  AppointmentRepository
+	organizationRepo OrganizationRepository
+	roomRepo         RoomRepository
+	userRepo         UserRepository
+	eventBus         events.Bus
+	transactor       events.Transactor
+	closeTime        string
+	leftoverAction   queue.LeftoverAction
+	logger           logger.Logger
 }
 
-func NewService(queueRepo queue.Repository, logger logger.Logger) *Service {
+// NewService creates the queue service. closeTime and leftoverAction make up the
+// closing policy of organizations that have not saved their own.
+func NewService(queueRepo queue.Repository, appointmentRepo AppointmentRepository, organizationRepo OrganizationRepository, roomRepo RoomRepository, userRepo UserRepository, eventBus events.Bus, transactor events.Transactor, closeTime string, leftoverAction queue.LeftoverAction, logger logger.Logger) *Service {
 	return &Service{
-		queueRepo: queueRepo,
-		logger:    logger,
+		queueRepo:        queueRepo,
+		appointmentRepo:  appointmentRepo,
+		organizationRepo: organizationRepo,
+		roomRepo:         roomRepo,
+		userRepo:         userRepo,
+		eventBus:         eventBus,
+		transactor:       transactor,
+		closeTime:        closeTime,
+		leftoverAction:   leftoverAction,
+		logger:           logger,
 	}
 }
 
@@ -30,24 +76,82 @@ func (s *Service) CountQueuesByOrganization(ctx context.Context, organizationID
 	return s.queueRepo.CountByOrganization(ctx, organizationID)
 }
 
-// CreateQueue creates a new patient queue entry
-func (s *Service) CreateQueue(ctx context.Context, q *queue.PatientQueue) error {
-	// Set initial status
-	q.Status = queue.QueueStatusWaiting
-	
-	// Get next position for the organization
-	nextQueue, err := s.queueRepo.GetNextInQueue(ctx, q.OrganizationID)
+// GetQueuesByScope retrieves the entries of one doctor's, room's or service point's queue
+func (s *Service) GetQueuesByScope(ctx context.Context, organizationID string, scope queue.Scope, limit, offset int) ([]*queue.PatientQueue, error) {
+	if !scope.IsValid() {
+		return nil, queue.ErrInvalidScope
+	}
+	return s.queueRepo.GetByScope(ctx, organizationID, scope, limit, offset)
+}
+
+// CountQueuesByScope counts the entries of one queue
+func (s *Service) CountQueuesByScope(ctx context.Context, organizationID string, scope queue.Scope) (int, error) {
+	if !scope.IsValid() {
+		return 0, queue.ErrInvalidScope
+	}
+	return s.queueRepo.CountByScope(ctx, organizationID, scope)
+}
+
+// CreateQueue adds a patient to the end of a queue. Without an explicit scope the
+// patient joins the queue of their appointment's doctor. actorID is the user making
+// the change, if known, here and in the other methods changing an entry's status.
+func (s *Service) CreateQueue(ctx context.Context, q *queue.PatientQueue, actorID *string) error {
+	apt, err := s.appointmentRepo.GetByID(ctx, q.AppointmentID)
 	if err != nil {
-		// If no existing queues, start with position 1
-		q.Position = 1
-	} else {
-		q.Position = nextQueue.Position + 1
+		return fmt.Errorf("failed to get appointment for queue: %w", err)
 	}
-	
-	// Estimate wait time (15 minutes per position)
-	q.EstimatedWaitTime = q.Position * 15
-	
-	return s.queueRepo.Create(ctx, q)
+	if apt.OrganizationID != q.OrganizationID {
+		return fmt.Errorf("appointment belongs to another organization")
+	}
+
+	if q.ScopeType == "" {
+		q.ScopeType = queue.ScopeDoctor
+	}
+	if q.ScopeID == "" {
+		switch q.ScopeType {
+		case queue.ScopeDoctor:
+			q.ScopeID = apt.DoctorID
+		case queue.ScopeRoom:
+			if apt.RoomID != nil {
+				q.ScopeID = *apt.RoomID
+			}
+		}
+	}
+	if !q.Scope().IsValid() {
+		return queue.ErrInvalidScope
+	}
+
+	if q.TriageLevel == 0 {
+		q.TriageLevel = queue.DefaultTriageLevel
+	}
+	if !q.TriageLevel.IsValid() {
+		return queue.ErrInvalidTriageLevel
+	}
+
+	// Set initial status
+	q.Status = queue.QueueStatusWaiting
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.queueRepo.Create(ctx, q, actorID); err != nil {
+			return err
+		}
+
+		// Re-read the entry for its rank, ahead of those already waiting if more urgent,
+		// and its estimated wait
+		ranked, err := s.queueRepo.GetByID(ctx, q.ID)
+		if err != nil {
+			return err
+		}
+		*q = *ranked
+
+		if q.TriageLevel != queue.DefaultTriageLevel {
+			if err := s.publish(ctx, queue.EventPositionsChanged, q.OrganizationID, q.Scope(), nil); err != nil {
+				return err
+			}
+		}
+
+		return s.publish(ctx, queue.EventCreated, q.OrganizationID, q.Scope(), q)
+	})
 }
 
 // GetQueue retrieves a specific queue by ID
@@ -71,85 +175,470 @@ func (s *Service) GetPatientQueue(ctx context.Context, patientID string) (*queue
 }
 
 // UpdateQueue updates an existing queue
-func (s *Service) UpdateQueue(ctx context.Context, q *queue.PatientQueue) error {
-	// If status changed to completed or cancelled, update positions
-	if q.Status == queue.QueueStatusCompleted || q.Status == queue.QueueStatusCancelled {
-		if err := s.queueRepo.UpdatePosition(ctx, q.OrganizationID); err != nil {
-			s.logger.Error(ctx, "Failed to update queue positions", "error", err)
+func (s *Service) UpdateQueue(ctx context.Context, q *queue.PatientQueue, actorID *string) error {
+	q.SetStatus(q.Status, time.Now())
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.queueRepo.Update(ctx, q, actorID); err != nil {
+			return err
 		}
-	}
-	
-	return s.queueRepo.Update(ctx, q)
+
+		// Re-read the entry for its new position and estimated wait
+		if updated, err := s.queueRepo.GetByID(ctx, q.ID); err == nil {
+			*q = *updated
+		}
+
+		switch q.Status {
+		case queue.QueueStatusCalled:
+			return s.publish(ctx, queue.EventCalled, q.OrganizationID, q.Scope(), q)
+		case queue.QueueStatusInProgress:
+			return s.publish(ctx, queue.EventStarted, q.OrganizationID, q.Scope(), q)
+		case queue.QueueStatusCompleted:
+			return s.publish(ctx, queue.EventCompleted, q.OrganizationID, q.Scope(), q)
+		default:
+			return s.publish(ctx, queue.EventPositionsChanged, q.OrganizationID, q.Scope(), nil)
+		}
+	})
 }
 
-// DeleteQueue removes a queue entry
-func (s *Service) DeleteQueue(ctx context.Context, id string) error {
-	// Get queue to update positions after deletion
+// CancelQueue takes a patient who has not been seen yet out of their queue. The entry
+// is kept, cancelled, so its history stays; everyone behind moves up.
+func (s *Service) CancelQueue(ctx context.Context, id string, actorID *string) (*queue.PatientQueue, error) {
 	q, err := s.queueRepo.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get queue for deletion: %w", err)
+		return nil, err
 	}
-	
-	// Delete the queue
-	if err := s.queueRepo.Delete(ctx, id); err != nil {
-		return err
+	if err := q.Cancel(time.Now()); err != nil {
+		return nil, err
 	}
-	
-	// Update positions for the organization
-	if err := s.queueRepo.UpdatePosition(ctx, q.OrganizationID); err != nil {
-		s.logger.Error(ctx, "Failed to update queue positions after deletion", "error", err)
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.queueRepo.Update(ctx, q, actorID); err != nil {
+			return err
+		}
+		return s.publish(ctx, queue.EventPositionsChanged, q.OrganizationID, q.Scope(), nil)
+	})
+	if err != nil {
+		return nil, err
 	}
-	
-	return nil
+
+	return q, nil
 }
 
-// CallNextPatient calls the next patient in the queue
-func (s *Service) CallNextPatient(ctx context.Context, organizationID string) (*queue.PatientQueue, error) {
-	nextQueue, err := s.queueRepo.GetNextInQueue(ctx, organizationID)
-	if err != nil {
-		return nil, fmt.Errorf("no patients in queue: %w", err)
+// CallNextPatient calls the patient at the front of a doctor's, room's or service
+// point's queue
+func (s *Service) CallNextPatient(ctx context.Context, organizationID string, scope queue.Scope, actorID *string) (*queue.PatientQueue, error) {
+	if !scope.IsValid() {
+		return nil, queue.ErrInvalidScope
 	}
-	
-	// Update status to called
-	nextQueue.Status = queue.QueueStatusCalled
-	if err := s.queueRepo.Update(ctx, nextQueue); err != nil {
-		return nil, fmt.Errorf("failed to update queue status: %w", err)
+
+	var nextQueue *queue.PatientQueue
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		nextQueue, err = s.queueRepo.CallNext(ctx, organizationID, scope, time.Now(), actorID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.publish(ctx, queue.EventCalled, organizationID, scope, nextQueue); err != nil {
+			return err
+		}
+		return s.publish(ctx, queue.EventPositionsChanged, organizationID, scope, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
-	
+
 	return nextQueue, nil
 }
 
-// StartPatientConsultation starts consultation for a patient
-func (s *Service) StartPatientConsultation(ctx context.Context, queueID string) (*queue.PatientQueue, error) {
+// RetriageQueue moves a waiting patient to another triage level, recording who did it
+// and why, and re-ranks their queue
+func (s *Service) RetriageQueue(ctx context.Context, queueID string, level queue.TriageLevel, reason string, actorID *string) (*queue.PatientQueue, error) {
 	q, err := s.queueRepo.GetByID(ctx, queueID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get queue: %w", err)
+		return nil, err
 	}
-	
-	q.Status = queue.QueueStatusInProgress
-	if err := s.queueRepo.Update(ctx, q); err != nil {
-		return nil, fmt.Errorf("failed to update queue status: %w", err)
+
+	change, err := q.Retriage(level, reason, actorID, time.Now())
+	if err != nil {
+		return nil, err
 	}
-	
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.queueRepo.Retriage(ctx, change); err != nil {
+			return err
+		}
+
+		// Re-read the entry for its new position
+		var err error
+		q, err = s.queueRepo.GetByID(ctx, queueID)
+		if err != nil {
+			return err
+		}
+
+		return s.publish(ctx, queue.EventPositionsChanged, q.OrganizationID, q.Scope(), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return q, nil
 }
 
+// GetTriageHistory returns the triage changes of a queue entry, oldest first
+func (s *Service) GetTriageHistory(ctx context.Context, queueID string) ([]*queue.TriageChange, error) {
+	if _, err := s.queueRepo.GetByID(ctx, queueID); err != nil {
+		return nil, err
+	}
+	return s.queueRepo.GetTriageHistory(ctx, queueID)
+}
+
+// GetStatusHistory returns the status changes of a queue entry from the queue event
+// log, oldest first
+func (s *Service) GetStatusHistory(ctx context.Context, queueID string) ([]*queue.StatusChange, error) {
+	if _, err := s.queueRepo.GetByID(ctx, queueID); err != nil {
+		return nil, err
+	}
+	return s.queueRepo.GetStatusHistory(ctx, queueID)
+}
+
+// StartPatientConsultation starts consultation for a patient
+func (s *Service) StartPatientConsultation(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error) {
+	return s.changeEntry(ctx, queueID, actorID, queue.EventStarted, func(q *queue.PatientQueue, at time.Time) error {
+		return q.Start(at)
+	})
+}
+
 // CompletePatientConsultation completes consultation for a patient
-func (s *Service) CompletePatientConsultation(ctx context.Context, queueID string) (*queue.PatientQueue, error) {
+func (s *Service) CompletePatientConsultation(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error) {
+	return s.changeEntry(ctx, queueID, actorID, queue.EventCompleted, func(q *queue.PatientQueue, at time.Time) error {
+		return q.Complete(at)
+	})
+}
+
+// SkipPatient marks a called patient who did not answer as skipped
+func (s *Service) SkipPatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error) {
+	return s.changeEntry(ctx, queueID, actorID, queue.EventSkipped, func(q *queue.PatientQueue, at time.Time) error {
+		return q.Skip(at)
+	})
+}
+
+// RecallPatient calls a skipped patient again
+func (s *Service) RecallPatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error) {
+	return s.changeEntry(ctx, queueID, actorID, queue.EventCalled, func(q *queue.PatientQueue, at time.Time) error {
+		return q.Recall(at)
+	})
+}
+
+// HoldPatient takes a patient out of the line while they are away, e.g. for a lab draw
+func (s *Service) HoldPatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error) {
+	return s.changeEntry(ctx, queueID, actorID, queue.EventHeld, func(q *queue.PatientQueue, at time.Time) error {
+		return q.Hold(at)
+	})
+}
+
+// ReleasePatient returns a patient on hold to their place in the line
+func (s *Service) ReleasePatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error) {
+	return s.changeEntry(ctx, queueID, actorID, queue.EventPositionsChanged, func(q *queue.PatientQueue, at time.Time) error {
+		return q.Release(at)
+	})
+}
+
+// TransferPatient moves a patient to another queue of the organization, keeping their
+// priority
+func (s *Service) TransferPatient(ctx context.Context, queueID string, to queue.Scope, actorID *string) (*queue.PatientQueue, error) {
+	var q *queue.PatientQueue
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		q, err = s.changeEntry(ctx, queueID, actorID, queue.EventTransferred, func(q *queue.PatientQueue, at time.Time) error {
+			if err := s.checkScope(ctx, q.OrganizationID, to); err != nil {
+				return err
+			}
+			return q.Transfer(to, at)
+		})
+		if err != nil {
+			return err
+		}
+
+		// changeEntry told the queue the patient left; tell the one they joined
+		return s.publish(ctx, queue.EventTransferred, q.OrganizationID, to, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// checkScope makes sure scope is a queue of the organization: one of its rooms or
+// doctors, or a service point it has queued patients at before
+func (s *Service) checkScope(ctx context.Context, organizationID string, scope queue.Scope) error {
+	switch scope.Type {
+	case queue.ScopeRoom:
+		rm, err := s.roomRepo.GetByID(ctx, scope.ID)
+		if errors.Is(err, room.ErrRoomNotFound) {
+			return fmt.Errorf("%w: room %s not found", queue.ErrUnknownScope, scope.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get room: %w", err)
+		}
+		if rm.OrganizationID != organizationID {
+			return fmt.Errorf("%w: room %s belongs to another organization", queue.ErrUnknownScope, scope.ID)
+		}
+
+	case queue.ScopeDoctor:
+		id, err := shared.NewUserIDFromString(scope.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", queue.ErrUnknownScope, err)
+		}
+		doctor, err := s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%w: doctor %s: %v", queue.ErrUnknownScope, scope.ID, err)
+		}
+		if doctor.Role() != user.RoleDoctor || doctor.OrganizationID() == nil || doctor.OrganizationID().String() != organizationID {
+			return fmt.Errorf("%w: %s is not a doctor of the organization", queue.ErrUnknownScope, scope.ID)
+		}
+
+	case queue.ScopeServicePoint:
+		// Service points are only named by the queues kept for them
+		count, err := s.queueRepo.CountByScope(ctx, organizationID, scope)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: service point %s has no queue", queue.ErrUnknownScope, scope.ID)
+		}
+
+	default:
+		return queue.ErrInvalidScope
+	}
+
+	return nil
+}
+
+// changeEntry applies change to an entry, stores it and announces it as eventType on
+// the queue the entry was in
+func (s *Service) changeEntry(ctx context.Context, queueID string, actorID *string, eventType string, change func(q *queue.PatientQueue, at time.Time) error) (*queue.PatientQueue, error) {
 	q, err := s.queueRepo.GetByID(ctx, queueID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get queue: %w", err)
+		return nil, err
 	}
-	
-	q.Status = queue.QueueStatusCompleted
-	if err := s.queueRepo.Update(ctx, q); err != nil {
-		return nil, fmt.Errorf("failed to update queue status: %w", err)
+
+	scope := q.Scope()
+	if err := change(q, time.Now()); err != nil {
+		return nil, err
 	}
-	
-	// Update positions for the organization
-	if err := s.queueRepo.UpdatePosition(ctx, q.OrganizationID); err != nil {
-		s.logger.Error(ctx, "Failed to update queue positions", "error", err)
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.queueRepo.Update(ctx, q, actorID); err != nil {
+			return err
+		}
+
+		// Re-read the entry for its new position and estimated wait
+		if updated, err := s.queueRepo.GetByID(ctx, queueID); err == nil {
+			q = updated
+		}
+
+		if eventType == queue.EventPositionsChanged {
+			return s.publish(ctx, eventType, q.OrganizationID, scope, nil)
+		}
+		return s.publish(ctx, eventType, q.OrganizationID, scope, q)
+	})
+	if err != nil {
+		return nil, err
 	}
-	
+
 	return q, nil
 }
+
+// GetQueueState returns whether a queue is paused
+func (s *Service) GetQueueState(ctx context.Context, organizationID string, scope queue.Scope) (*queue.State, error) {
+	if !scope.IsValid() {
+		return nil, queue.ErrInvalidScope
+	}
+	return s.queueRepo.GetState(ctx, organizationID, scope)
+}
+
+// PauseQueue stops patients being called from a queue, e.g. during a doctor's break
+func (s *Service) PauseQueue(ctx context.Context, organizationID string, scope queue.Scope, reason, actorID *string) (*queue.State, error) {
+	return s.setQueueState(ctx, organizationID, scope, true, reason, actorID)
+}
+
+// ResumeQueue lets patients be called from a paused queue again
+func (s *Service) ResumeQueue(ctx context.Context, organizationID string, scope queue.Scope, actorID *string) (*queue.State, error) {
+	return s.setQueueState(ctx, organizationID, scope, false, nil, actorID)
+}
+
+func (s *Service) setQueueState(ctx context.Context, organizationID string, scope queue.Scope, paused bool, reason, actorID *string) (*queue.State, error) {
+	if !scope.IsValid() {
+		return nil, queue.ErrInvalidScope
+	}
+
+	state := &queue.State{
+		OrganizationID: organizationID,
+		Scope:          scope,
+		Paused:         paused,
+		Reason:         reason,
+		ChangedBy:      actorID,
+	}
+	eventType := queue.EventResumed
+	if paused {
+		eventType = queue.EventPaused
+	}
+
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.queueRepo.SetState(ctx, state); err != nil {
+			return err
+		}
+		return s.publish(ctx, eventType, organizationID, scope, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// GetClosingPolicy returns the organization's closing policy, or the default policy if
+// none was saved
+func (s *Service) GetClosingPolicy(ctx context.Context, organizationID string) (*queue.ClosingPolicy, error) {
+	policy, err := s.queueRepo.GetClosingPolicy(ctx, organizationID)
+	if errors.Is(err, queue.ErrClosingPolicyNotFound) {
+		return queue.DefaultClosingPolicy(organizationID, s.closeTime, s.leftoverAction), nil
+	}
+	return policy, err
+}
+
+// UpdateClosingPolicy replaces the organization's closing policy. It applies from the
+// next day to be closed on.
+func (s *Service) UpdateClosingPolicy(ctx context.Context, policy *queue.ClosingPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	return s.queueRepo.SaveClosingPolicy(ctx, policy)
+}
+
+// GetClosures returns the summaries of an organization's closed queue days, latest first
+func (s *Service) GetClosures(ctx context.Context, organizationID string, limit, offset int) ([]*queue.Closure, error) {
+	return s.queueRepo.GetClosures(ctx, organizationID, limit, offset)
+}
+
+// CloseQueueDays closes the queue day of every organization whose close time has
+// passed, cancelling or carrying over the patients still waiting as its policy says.
+// Days are those of the organization's time zone. Yesterday is closed too if it was
+// missed, e.g. while the server was down.
+func (s *Service) CloseQueueDays(ctx context.Context) error {
+	now := time.Now()
+	year, month, date := now.Date()
+	locations := make(map[string]*time.Location)
+
+	// An organization's today is at most a day away from the server's, so its
+	// yesterday and today are among these dates
+	for offset := -2; offset <= 1; offset++ {
+		organizationIDs, err := s.queueRepo.FindUnclosed(ctx, time.Date(year, month, date+offset, 0, 0, 0, 0, time.Local))
+		if err != nil {
+			return err
+		}
+
+		for _, organizationID := range organizationIDs {
+			loc, ok := locations[organizationID]
+			if !ok {
+				org, err := s.organizationRepo.GetByID(ctx, organizationID)
+				if err != nil {
+					return err
+				}
+				loc = org.Location()
+				locations[organizationID] = loc
+			}
+
+			day := time.Date(year, month, date+offset, 0, 0, 0, 0, loc)
+			localYear, localMonth, localDate := now.In(loc).Date()
+			today := time.Date(localYear, localMonth, localDate, 0, 0, 0, 0, loc)
+			if day.After(today) || day.Before(today.AddDate(0, 0, -1)) {
+				continue
+			}
+
+			if err := s.closeDay(ctx, organizationID, day, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) closeDay(ctx context.Context, organizationID string, day, now time.Time) error {
+	policy, err := s.GetClosingPolicy(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled {
+		return nil
+	}
+
+	closesAt, err := policy.ClosesAt(day)
+	if err != nil {
+		s.logger.Warn(ctx, "Skipping invalid queue closing policy", "organizationID", organizationID, "error", err)
+		return nil
+	}
+	if now.Before(closesAt) {
+		return nil
+	}
+
+	closure := &queue.Closure{
+		OrganizationID: organizationID,
+		Day:            day,
+		LeftoverAction: policy.LeftoverAction,
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		scopes, err := s.queueRepo.CloseDay(ctx, closure)
+		if err != nil {
+			return err
+		}
+
+		for _, scope := range scopes {
+			if err := s.publish(ctx, queue.EventPositionsChanged, organizationID, scope, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, queue.ErrAlreadyClosed) {
+		// Closed by another instance in the meantime
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info(ctx, "Closed queue day", "organizationID", organizationID, "day", day.Format("2006-01-02"), "leftoverCancelled", closure.LeftoverCancelled, "carriedOver", closure.CarriedOver)
+	return nil
+}
+
+// IsClosingPolicyError reports whether err is caused by an invalid closing policy
+func IsClosingPolicyError(err error) bool {
+	return errors.Is(err, queue.ErrInvalidCloseTime) || errors.Is(err, queue.ErrInvalidLeftoverAction)
+}
+
+// publish broadcasts a queue change; entry is nil for changes to the queue as a whole.
+// It is called in the transaction that stores the change, so the event is published
+// if and only if the change is stored.
+func (s *Service) publish(ctx context.Context, eventType, organizationID string, scope queue.Scope, entry *queue.PatientQueue) error {
+	event := queue.QueueEvent{
+		Type:           eventType,
+		OrganizationID: organizationID,
+		Scope:          scope,
+		OccurredAt:     time.Now(),
+	}
+	if entry != nil {
+		snapshot := *entry
+		event.Entry = &snapshot
+	}
+
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Error(ctx, "Failed to publish queue event", "error", err, "type", eventType, "organizationID", organizationID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/notification"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/pkg/logger"
+)
+
+// NotificationRepository interface for dependency injection
+type NotificationRepository interface {
+	ReplaceScheduled(ctx context.Context, appointmentID string, scheduled []*notification.Notification) error
+}
+
+// OrganizationRepository interface for dependency injection
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*organization.Organization, error)
+}
+
+// TemplateRenderer interface for dependency injection
+type TemplateRenderer interface {
+	Render(ctx context.Context, organizationID string, key notification.TemplateKey, locale notification.Locale, vars map[string]string) (*notification.Rendered, error)
+}
+
+// Service keeps a patient's appointment reminders in line with the appointment. It
+// schedules one notification per configured offset before the start and replaces
+// them whenever the appointment moves; cancelled appointments lose their reminders.
+type Service struct {
+	notificationRepo NotificationRepository
+	organizationRepo OrganizationRepository
+	templates        TemplateRenderer
+	offsets          []time.Duration
+	channels         []string
+	logger           logger.Logger
+}
+
+func NewService(notificationRepo NotificationRepository, organizationRepo OrganizationRepository, templates TemplateRenderer, offsets []time.Duration, channels []string, logger logger.Logger) *Service {
+	return &Service{
+		notificationRepo: notificationRepo,
+		organizationRepo: organizationRepo,
+		templates:        templates,
+		offsets:          offsets,
+		channels:         channels,
+		logger:           logger,
+	}
+}
+
+// Handle implements events.Handler for appointment.scheduled and appointment.cancelled events
+func (s *Service) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case appointment.AppointmentScheduledEvent:
+		return s.Schedule(ctx, &e.Appointment)
+	case appointment.AppointmentCancelledEvent:
+		return s.Cancel(ctx, e.Appointment.ID)
+	default:
+		return fmt.Errorf("unexpected event type %q", event.EventType())
+	}
+}
+
+// Schedule replaces the appointment's pending reminders with fresh ones for its
+// current date and time. Reminders whose time has already passed are skipped.
+func (s *Service) Schedule(ctx context.Context, apt *appointment.Appointment) error {
+	if !apt.Status.IsEditable() {
+		return s.Cancel(ctx, apt.ID)
+	}
+
+	org, err := s.organizationRepo.GetByID(ctx, apt.OrganizationID)
+	if err != nil {
+		return err
+	}
+	startsAt, err := apt.StartsAt(org.Location())
+	if err != nil {
+		return err
+	}
+
+	patientID, err := shared.NewUserIDFromString(apt.PatientID)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := s.templates.Render(ctx, apt.OrganizationID, notification.TemplateAppointmentReminder, "", map[string]string{
+		"date": apt.Date.Format("2006-01-02"),
+		"time": apt.StartTime,
+	})
+	if err != nil {
+		s.logger.Error(ctx, "Failed to render appointment reminder", "error", err, "appointmentID", apt.ID)
+		return err
+	}
+
+	now := time.Now()
+	reminders := make([]*notification.Notification, 0, len(s.offsets))
+	for _, offset := range s.offsets {
+		at := startsAt.Add(-offset)
+		if !at.After(now) {
+			continue
+		}
+
+		reminder := notification.NewTemplatedNotification(
+			patientID,
+			rendered,
+			notification.NotificationTypeAppointment,
+			notification.PriorityMedium,
+			s.channels,
+			map[string]interface{}{
+				"appointment_id":  apt.ID,
+				"organization_id": apt.OrganizationID,
+				"doctor_id":       apt.DoctorID,
+				"date":            apt.Date.Format("2006-01-02"),
+				"start_time":      apt.StartTime,
+				"offset_minutes":  int(offset.Minutes()),
+			},
+		)
+		reminder.LinkAppointment(apt.ID)
+		reminder.ScheduleFor(at)
+		reminders = append(reminders, reminder)
+	}
+
+	if err := s.notificationRepo.ReplaceScheduled(ctx, apt.ID, reminders); err != nil {
+		s.logger.Error(ctx, "Failed to schedule appointment reminders", "error", err, "appointmentID", apt.ID)
+		return err
+	}
+
+	return nil
+}
+
+// Cancel withdraws the appointment's reminders that have not been sent yet
+func (s *Service) Cancel(ctx context.Context, appointmentID string) error {
+	if err := s.notificationRepo.ReplaceScheduled(ctx, appointmentID, nil); err != nil {
+		s.logger.Error(ctx, "Failed to cancel appointment reminders", "error", err, "appointmentID", appointmentID)
+		return err
+	}
+
+	return nil
+}
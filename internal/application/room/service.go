@@ -0,0 +1,160 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"medika-backend/internal/domain/room"
+	"medika-backend/pkg/logger"
+)
+
+// occupancyRoomLimit bounds the number of rooms loaded for an occupancy timeline
+const occupancyRoomLimit = 500
+
+type Service struct {
+	roomRepo room.Repository
+	logger   logger.Logger
+}
+
+func NewService(roomRepo room.Repository, logger logger.Logger) *Service {
+	return &Service{
+		roomRepo: roomRepo,
+		logger:   logger,
+	}
+}
+
+func (s *Service) CreateRoom(ctx context.Context, rm *room.Room) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rm.Equipment = normalizeEquipment(rm.Equipment)
+	rm.CreatedAt = now
+	rm.UpdatedAt = now
+
+	return s.roomRepo.Create(ctx, rm)
+}
+
+func (s *Service) GetRoom(ctx context.Context, id string) (*room.Room, error) {
+	return s.roomRepo.GetByID(ctx, id)
+}
+
+func (s *Service) GetRooms(ctx context.Context, organizationID string, filter room.Filter, limit, offset int) ([]*room.Room, error) {
+	return s.roomRepo.GetByOrganization(ctx, organizationID, filter, limit, offset)
+}
+
+func (s *Service) UpdateRoom(ctx context.Context, rm *room.Room) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	rm.Equipment = normalizeEquipment(rm.Equipment)
+	rm.UpdatedAt = time.Now()
+
+	return s.roomRepo.Update(ctx, rm)
+}
+
+// SetAvailability opens or closes a room for new bookings. Existing appointments
+// in the room are kept.
+func (s *Service) SetAvailability(ctx context.Context, id string, available bool) (*room.Room, error) {
+	rm, err := s.roomRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rm.IsAvailable = available
+	if err := s.UpdateRoom(ctx, rm); err != nil {
+		return nil, err
+	}
+
+	return rm, nil
+}
+
+// SetEquipment replaces a room's equipment list
+func (s *Service) SetEquipment(ctx context.Context, id string, equipment []string) (*room.Room, error) {
+	rm, err := s.roomRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rm.Equipment = equipment
+	if err := s.UpdateRoom(ctx, rm); err != nil {
+		return nil, err
+	}
+
+	return rm, nil
+}
+
+// DeleteRoom removes a room that has no upcoming appointments
+func (s *Service) DeleteRoom(ctx context.Context, id string) error {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return s.roomRepo.Delete(ctx, id, today)
+}
+
+// GetOccupancy returns the booking timeline of every room in an organization on a
+// date. Rooms without bookings are included with an empty timeline.
+func (s *Service) GetOccupancy(ctx context.Context, organizationID string, date time.Time) ([]*room.Occupancy, error) {
+	rooms, err := s.roomRepo.GetByOrganization(ctx, organizationID, room.Filter{}, occupancyRoomLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := s.roomRepo.GetBookings(ctx, organizationID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	byRoom := make(map[string][]*room.Booking)
+	for _, booking := range bookings {
+		byRoom[booking.RoomID] = append(byRoom[booking.RoomID], booking)
+	}
+
+	occupancy := make([]*room.Occupancy, len(rooms))
+	for i, rm := range rooms {
+		roomBookings := byRoom[rm.ID]
+		if roomBookings == nil {
+			roomBookings = []*room.Booking{}
+		}
+
+		segments, err := room.Timeline(roomBookings, rm.Capacity)
+		if err != nil {
+			return nil, err
+		}
+		if segments == nil {
+			segments = []room.OccupancySegment{}
+		}
+
+		occupancy[i] = &room.Occupancy{
+			Room:     rm,
+			Bookings: roomBookings,
+			Segments: segments,
+		}
+	}
+
+	return occupancy, nil
+}
+
+// IsValidationError reports whether err is caused by invalid room data
+func IsValidationError(err error) bool {
+	return errors.Is(err, room.ErrInvalidType) || errors.Is(err, room.ErrInvalidCapacity)
+}
+
+// normalizeEquipment trims items and drops blanks and duplicates so equipment
+// filters match reliably
+func normalizeEquipment(equipment []string) []string {
+	seen := make(map[string]bool, len(equipment))
+	normalized := make([]string, 0, len(equipment))
+	for _, item := range equipment {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		normalized = append(normalized, item)
+	}
+	return normalized
+}
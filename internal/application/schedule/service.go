@@ -0,0 +1,303 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/schedule"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/domain/user"
+	"medika-backend/pkg/logger"
+)
+
+// MaxSlotRangeDays caps how many days a single slot query may span
+const MaxSlotRangeDays = 31
+
+var (
+	ErrNotADoctor           = errors.New("user is not a doctor")
+	ErrDoctorNotInOrg       = errors.New("doctor does not belong to an organization")
+	ErrInvalidDateRange     = errors.New("'to' must not be before 'from'")
+	ErrDateRangeTooLong     = fmt.Errorf("date range must not exceed %d days", MaxSlotRangeDays)
+	ErrTemplateOwnerChanged = errors.New("a template cannot be moved to another owner")
+)
+
+// Repository interfaces for dependency injection
+type AppointmentRepository interface {
+	GetByDoctorAndDateRange(ctx context.Context, doctorID string, from, to time.Time) ([]*appointment.Appointment, error)
+}
+
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*organization.Organization, error)
+}
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id shared.UserID) (*user.User, error)
+}
+
+type Service struct {
+	scheduleRepo    schedule.Repository
+	appointmentRepo AppointmentRepository
+	orgRepo         OrganizationRepository
+	userRepo        UserRepository
+	logger          logger.Logger
+}
+
+func NewService(
+	scheduleRepo schedule.Repository,
+	appointmentRepo AppointmentRepository,
+	orgRepo OrganizationRepository,
+	userRepo UserRepository,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		scheduleRepo:    scheduleRepo,
+		appointmentRepo: appointmentRepo,
+		orgRepo:         orgRepo,
+		userRepo:        userRepo,
+		logger:          logger,
+	}
+}
+
+// GetAvailableSlots computes the free slots of a doctor between two dates (inclusive)
+func (s *Service) GetAvailableSlots(ctx context.Context, doctorID string, from, to time.Time) ([]schedule.Slot, error) {
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	organizationID, err := s.doctorOrganization(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	templates, err := s.effectiveTemplates(ctx, doctorID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.scheduleRepo.GetOverrides(ctx, organizationID, &doctorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := s.appointmentRepo.GetByDoctorAndDateRange(ctx, doctorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	calc := newSlotCalculator(templates, overrides, appointments, org.BusinessHours, org.Location(), time.Now())
+	return calc.slotsBetween(from, to), nil
+}
+
+// GetDoctorSchedule returns a doctor's own templates and the overrides affecting them in the given range
+func (s *Service) GetDoctorSchedule(ctx context.Context, doctorID string, from, to time.Time) ([]*schedule.Template, []*schedule.Override, error) {
+	organizationID, err := s.doctorOrganization(ctx, doctorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	templates, err := s.scheduleRepo.GetDoctorTemplates(ctx, doctorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides, err := s.scheduleRepo.GetOverrides(ctx, organizationID, &doctorID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return templates, overrides, nil
+}
+
+// GetOrganizationSchedule returns an organization's default templates and organization-wide overrides
+func (s *Service) GetOrganizationSchedule(ctx context.Context, organizationID string, from, to time.Time) ([]*schedule.Template, []*schedule.Override, error) {
+	templates, err := s.scheduleRepo.GetOrganizationTemplates(ctx, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides, err := s.scheduleRepo.GetOverrides(ctx, organizationID, nil, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return templates, overrides, nil
+}
+
+// CreateDoctorTemplate adds a weekly working window for a doctor
+func (s *Service) CreateDoctorTemplate(ctx context.Context, doctorID string, t *schedule.Template) error {
+	organizationID, err := s.doctorOrganization(ctx, doctorID)
+	if err != nil {
+		return err
+	}
+
+	t.OrganizationID = organizationID
+	t.DoctorID = &doctorID
+	return s.createTemplate(ctx, t)
+}
+
+// CreateOrganizationTemplate adds a default weekly working window for an organization
+func (s *Service) CreateOrganizationTemplate(ctx context.Context, organizationID string, t *schedule.Template) error {
+	if _, err := s.orgRepo.GetByID(ctx, organizationID); err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	t.OrganizationID = organizationID
+	t.DoctorID = nil
+	return s.createTemplate(ctx, t)
+}
+
+// GetTemplate retrieves a schedule template by ID
+func (s *Service) GetTemplate(ctx context.Context, id string) (*schedule.Template, error) {
+	return s.scheduleRepo.GetTemplateByID(ctx, id)
+}
+
+// UpdateTemplate validates and saves changes to an existing template
+func (s *Service) UpdateTemplate(ctx context.Context, t *schedule.Template) error {
+	existing, err := s.scheduleRepo.GetTemplateByID(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if existing.OrganizationID != t.OrganizationID || !sameDoctor(existing.DoctorID, t.DoctorID) {
+		return ErrTemplateOwnerChanged
+	}
+
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	t.CreatedAt = existing.CreatedAt
+	t.UpdatedAt = time.Now()
+	return s.scheduleRepo.UpdateTemplate(ctx, t)
+}
+
+// DeleteTemplate removes a schedule template
+func (s *Service) DeleteTemplate(ctx context.Context, id string) error {
+	return s.scheduleRepo.DeleteTemplate(ctx, id)
+}
+
+// CreateDoctorOverride closes or changes a doctor's hours for a single date
+func (s *Service) CreateDoctorOverride(ctx context.Context, doctorID string, o *schedule.Override) error {
+	organizationID, err := s.doctorOrganization(ctx, doctorID)
+	if err != nil {
+		return err
+	}
+
+	o.OrganizationID = organizationID
+	o.DoctorID = &doctorID
+	return s.createOverride(ctx, o)
+}
+
+// CreateOrganizationOverride closes or changes an organization's hours for a single date
+func (s *Service) CreateOrganizationOverride(ctx context.Context, organizationID string, o *schedule.Override) error {
+	if _, err := s.orgRepo.GetByID(ctx, organizationID); err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	o.OrganizationID = organizationID
+	o.DoctorID = nil
+	return s.createOverride(ctx, o)
+}
+
+// DeleteOverride removes a date-specific override
+func (s *Service) DeleteOverride(ctx context.Context, id string) error {
+	return s.scheduleRepo.DeleteOverride(ctx, id)
+}
+
+func (s *Service) createTemplate(ctx context.Context, t *schedule.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	return s.scheduleRepo.CreateTemplate(ctx, t)
+}
+
+func (s *Service) createOverride(ctx context.Context, o *schedule.Override) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	o.CreatedAt = now
+	o.UpdatedAt = now
+	return s.scheduleRepo.CreateOverride(ctx, o)
+}
+
+// effectiveTemplates returns the doctor's active templates, falling back to the
+// organization defaults when the doctor has none
+func (s *Service) effectiveTemplates(ctx context.Context, doctorID, organizationID string) ([]*schedule.Template, error) {
+	templates, err := s.scheduleRepo.GetDoctorTemplates(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := activeTemplates(templates)
+	if len(active) > 0 {
+		return active, nil
+	}
+
+	templates, err = s.scheduleRepo.GetOrganizationTemplates(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeTemplates(templates), nil
+}
+
+func (s *Service) doctorOrganization(ctx context.Context, doctorID string) (string, error) {
+	id, err := shared.NewUserIDFromString(doctorID)
+	if err != nil {
+		return "", err
+	}
+
+	doctor, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get doctor: %w", err)
+	}
+	if doctor.Role() != user.RoleDoctor {
+		return "", ErrNotADoctor
+	}
+	if doctor.OrganizationID() == nil {
+		return "", ErrDoctorNotInOrg
+	}
+
+	return doctor.OrganizationID().String(), nil
+}
+
+func activeTemplates(templates []*schedule.Template) []*schedule.Template {
+	active := make([]*schedule.Template, 0, len(templates))
+	for _, t := range templates {
+		if t.IsActive {
+			active = append(active, t)
+		}
+	}
+	return active
+}
+
+func sameDoctor(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func validateRange(from, to time.Time) error {
+	if to.Before(from) {
+		return ErrInvalidDateRange
+	}
+	if to.Sub(from) >= MaxSlotRangeDays*24*time.Hour {
+		return ErrDateRangeTooLong
+	}
+	return nil
+}
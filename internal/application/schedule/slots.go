@@ -0,0 +1,288 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/schedule"
+	"medika-backend/internal/domain/shared"
+)
+
+// defaultSlotDuration is used when an override changes the hours of a day
+// that has no template to take the slot length from
+const defaultSlotDuration = 30
+
+const dateLayout = "2006-01-02"
+
+// interval is a half-open [start, end) range expressed as offsets from midnight
+type interval struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func (i interval) overlaps(o interval) bool {
+	return i.start < o.end && o.start < i.end
+}
+
+// window is a working period of a single day that is cut into slots
+type window struct {
+	interval
+	slotLength time.Duration
+	breaks     []interval
+}
+
+// slotCalculator turns templates and overrides into free slots. Precedence for
+// a given date is: doctor override, organization override, weekly template.
+// Every window is clipped to the organization's business hours. Times of day are
+// wall-clock times in the organization's zone, loc.
+type slotCalculator struct {
+	templates       map[int][]*schedule.Template
+	doctorOverrides map[string]*schedule.Override
+	orgOverrides    map[string]*schedule.Override
+	booked          map[string][]interval
+	businessHours   []organization.BusinessHours
+	loc             *time.Location
+	now             time.Time
+}
+
+func newSlotCalculator(
+	templates []*schedule.Template,
+	overrides []*schedule.Override,
+	appointments []*appointment.Appointment,
+	businessHours []organization.BusinessHours,
+	loc *time.Location,
+	now time.Time,
+) *slotCalculator {
+	calc := &slotCalculator{
+		templates:       make(map[int][]*schedule.Template),
+		doctorOverrides: make(map[string]*schedule.Override),
+		orgOverrides:    make(map[string]*schedule.Override),
+		booked:          make(map[string][]interval),
+		businessHours:   businessHours,
+		loc:             loc,
+		now:             now,
+	}
+
+	for _, t := range templates {
+		calc.templates[t.DayOfWeek] = append(calc.templates[t.DayOfWeek], t)
+	}
+
+	for _, o := range overrides {
+		key := o.Date.Format(dateLayout)
+		if o.DoctorID != nil {
+			calc.doctorOverrides[key] = o
+		} else {
+			calc.orgOverrides[key] = o
+		}
+	}
+
+	for _, apt := range appointments {
+		if !apt.Status.OccupiesSlot() {
+			continue
+		}
+		span, ok := parseInterval(apt.StartTime, apt.EndTime)
+		if !ok {
+			continue
+		}
+		key := apt.Date.Format(dateLayout)
+		calc.booked[key] = append(calc.booked[key], span)
+	}
+
+	return calc
+}
+
+func (c *slotCalculator) slotsBetween(from, to time.Time) []schedule.Slot {
+	slots := []schedule.Slot{}
+	for day := startOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		slots = append(slots, c.slotsOn(day)...)
+	}
+	return slots
+}
+
+func (c *slotCalculator) slotsOn(day time.Time) []schedule.Slot {
+	key := day.Format(dateLayout)
+
+	bounds, open := c.openingHours(day)
+	if !open {
+		return nil
+	}
+
+	booked := c.booked[key]
+	seen := make(map[time.Duration]bool)
+	var slots []schedule.Slot
+
+	for _, w := range c.windowsOn(day) {
+		if bounds != nil {
+			w.start = maxDuration(w.start, bounds.start)
+			w.end = minDuration(w.end, bounds.end)
+		}
+
+		for cursor := w.start; cursor+w.slotLength <= w.end; {
+			slot := interval{start: cursor, end: cursor + w.slotLength}
+
+			if b, ok := firstOverlap(slot, w.breaks); ok {
+				cursor = b.end
+				continue
+			}
+			cursor = slot.end
+
+			if seen[slot.start] || wallClock(day, slot.start, c.loc).Before(c.now) {
+				continue
+			}
+			if _, ok := firstOverlap(slot, booked); ok {
+				continue
+			}
+
+			seen[slot.start] = true
+			slots = append(slots, schedule.Slot{
+				Date:      day,
+				StartTime: shared.FormatTimeOfDay(slot.start),
+				EndTime:   shared.FormatTimeOfDay(slot.end),
+			})
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].StartTime < slots[j].StartTime })
+	return slots
+}
+
+// openingHours returns the organization's hours for the day. A nil interval
+// with open=true means the organization has no business hours configured.
+func (c *slotCalculator) openingHours(day time.Time) (*interval, bool) {
+	key := day.Format(dateLayout)
+
+	// A doctor-specific override is the most explicit instruction for the day
+	if o, ok := c.doctorOverrides[key]; ok && o.IsAvailable {
+		return c.businessHoursOn(day)
+	}
+
+	if o, ok := c.orgOverrides[key]; ok {
+		if !o.IsAvailable || o.StartTime == nil || o.EndTime == nil {
+			return nil, false
+		}
+		span, ok := parseInterval(*o.StartTime, *o.EndTime)
+		return &span, ok
+	}
+
+	return c.businessHoursOn(day)
+}
+
+func (c *slotCalculator) businessHoursOn(day time.Time) (*interval, bool) {
+	if len(c.businessHours) == 0 {
+		return nil, true
+	}
+
+	for _, bh := range c.businessHours {
+		if bh.Day != int(day.Weekday()) {
+			continue
+		}
+		if !bh.IsOpen {
+			return nil, false
+		}
+		span, ok := parseInterval(bh.Open, bh.Close)
+		return &span, ok
+	}
+
+	return nil, false
+}
+
+// windowsOn resolves the working windows of a day from overrides or templates
+func (c *slotCalculator) windowsOn(day time.Time) []window {
+	key := day.Format(dateLayout)
+	templates := c.templates[int(day.Weekday())]
+
+	if o, ok := c.doctorOverrides[key]; ok {
+		if !o.IsAvailable || o.StartTime == nil || o.EndTime == nil {
+			return nil
+		}
+		span, ok := parseInterval(*o.StartTime, *o.EndTime)
+		if !ok {
+			return nil
+		}
+
+		slotDuration := defaultSlotDuration
+		if o.SlotDuration != nil {
+			slotDuration = *o.SlotDuration
+		} else if len(templates) > 0 {
+			slotDuration = templates[0].SlotDuration
+		}
+
+		return []window{{
+			interval:   span,
+			slotLength: time.Duration(slotDuration) * time.Minute,
+			breaks:     parseBreaks(o.Breaks),
+		}}
+	}
+
+	windows := make([]window, 0, len(templates))
+	for _, t := range templates {
+		span, ok := parseInterval(t.StartTime, t.EndTime)
+		if !ok || t.SlotDuration <= 0 {
+			continue
+		}
+		windows = append(windows, window{
+			interval:   span,
+			slotLength: time.Duration(t.SlotDuration) * time.Minute,
+			breaks:     parseBreaks(t.Breaks),
+		})
+	}
+
+	return windows
+}
+
+func parseInterval(startTime, endTime string) (interval, bool) {
+	start, err := shared.ParseTimeOfDay(startTime)
+	if err != nil {
+		return interval{}, false
+	}
+	end, err := shared.ParseTimeOfDay(endTime)
+	if err != nil || end <= start {
+		return interval{}, false
+	}
+	return interval{start: start, end: end}, true
+}
+
+func parseBreaks(breaks []schedule.Break) []interval {
+	result := make([]interval, 0, len(breaks))
+	for _, b := range breaks {
+		if span, ok := parseInterval(b.StartTime, b.EndTime); ok {
+			result = append(result, span)
+		}
+	}
+	return result
+}
+
+func firstOverlap(slot interval, others []interval) (interval, bool) {
+	for _, o := range others {
+		if slot.overlaps(o) {
+			return o, true
+		}
+	}
+	return interval{}, false
+}
+
+// wallClock returns the instant a time of day, given as an offset from midnight, falls
+// on at day's date in loc
+func wallClock(day time.Time, offset time.Duration, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, loc)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/organization"
+	"medika-backend/internal/domain/schedule"
+)
+
+func TestSlotCalculatorSlotsOn(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, wib)
+	doctorID := "doctor-1"
+
+	// 09:00-11:00 in 30 minute slots, with a break at 10:00
+	templates := []*schedule.Template{{
+		DayOfWeek:    int(day.Weekday()),
+		StartTime:    "09:00",
+		EndTime:      "11:00",
+		SlotDuration: 30,
+		Breaks:       []schedule.Break{{StartTime: "10:00", EndTime: "10:30"}},
+		IsActive:     true,
+	}}
+	override := func(doctorID *string, available bool, start, end string) *schedule.Override {
+		o := &schedule.Override{DoctorID: doctorID, Date: day, IsAvailable: available}
+		if start != "" {
+			o.StartTime, o.EndTime = &start, &end
+		}
+		return o
+	}
+	booking := func(start, end string, status appointment.AppointmentStatus) *appointment.Appointment {
+		return &appointment.Appointment{Date: day, StartTime: start, EndTime: end, Status: status}
+	}
+	hours := func(open, close string, isOpen bool) []organization.BusinessHours {
+		return []organization.BusinessHours{{Day: int(day.Weekday()), Open: open, Close: close, IsOpen: isOpen}}
+	}
+
+	tests := []struct {
+		name          string
+		overrides     []*schedule.Override
+		appointments  []*appointment.Appointment
+		businessHours []organization.BusinessHours
+		now           time.Time
+		want          []string
+	}{
+		{
+			name: "weekly template without its break",
+			want: []string{"09:00", "09:30", "10:30"},
+		},
+		{
+			name: "booked slots are taken, cancelled ones are free",
+			appointments: []*appointment.Appointment{
+				booking("09:30", "10:00", appointment.StatusConfirmed),
+				booking("10:30", "11:00", appointment.StatusCancelled),
+			},
+			want: []string{"09:00", "10:30"},
+		},
+		{
+			name:      "organization override closes the day",
+			overrides: []*schedule.Override{override(nil, false, "", "")},
+			want:      nil,
+		},
+		{
+			name:      "organization override narrows the template",
+			overrides: []*schedule.Override{override(nil, true, "09:30", "12:00")},
+			want:      []string{"09:30", "10:30"},
+		},
+		{
+			name: "doctor override wins over a closing organization override",
+			overrides: []*schedule.Override{
+				override(nil, false, "", ""),
+				override(&doctorID, true, "13:00", "14:00"),
+			},
+			want: []string{"13:00", "13:30"},
+		},
+		{
+			name:      "doctor override takes the day off",
+			overrides: []*schedule.Override{override(&doctorID, false, "", "")},
+			want:      nil,
+		},
+		{
+			name:          "business hours clip the template",
+			businessHours: hours("09:30", "17:00", true),
+			want:          []string{"09:30", "10:30"},
+		},
+		{
+			name:          "closed on the weekday",
+			businessHours: hours("09:00", "17:00", false),
+			want:          nil,
+		},
+		{
+			name:          "business hours clip a doctor override",
+			overrides:     []*schedule.Override{override(&doctorID, true, "16:00", "18:00")},
+			businessHours: hours("09:00", "17:00", true),
+			want:          []string{"16:00", "16:30"},
+		},
+		{
+			name: "past slots are left out in the organization's zone",
+			now:  time.Date(2026, 3, 2, 2, 45, 0, 0, time.UTC), // 09:45 WIB
+			want: []string{"10:30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := newSlotCalculator(templates, tt.overrides, tt.appointments, tt.businessHours, wib, tt.now)
+
+			var got []string
+			for _, slot := range calc.slotsOn(day) {
+				got = append(got, slot.StartTime)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("slots = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
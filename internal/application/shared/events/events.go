@@ -2,6 +2,9 @@ package events
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 )
 
 // Event represents a domain event
@@ -21,8 +24,18 @@ type Handler interface {
 	Handle(ctx context.Context, event Event) error
 }
 
-// Simple in-memory event bus implementation
+// Transactor runs work in one database transaction. Repositories called with the
+// context passed to fn take part in it, and so do events published with it: they are
+// published if and only if the work commits.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// InMemoryBus delivers events synchronously to the handlers subscribed to their type,
+// in the order they subscribed. It is safe for concurrent use. Every handler runs even
+// if an earlier one fails; Publish returns all their errors joined.
 type InMemoryBus struct {
+	mu       sync.RWMutex
 	handlers map[string][]Handler
 }
 
@@ -33,27 +46,45 @@ func NewInMemoryBus() Bus {
 }
 
 func (b *InMemoryBus) Publish(ctx context.Context, event Event) error {
-	handlers, exists := b.handlers[event.EventType()]
-	if !exists {
-		return nil // No handlers for this event type
-	}
+	b.mu.RLock()
+	handlers := b.handlers[event.EventType()]
+	b.mu.RUnlock()
 
+	var errs []error
 	for _, handler := range handlers {
-		if err := handler.Handle(ctx, event); err != nil {
-			// Log error but don't fail the entire event publishing
-			// In a real implementation, you might want to retry or use a dead letter queue
-			continue
+		if err := Handle(ctx, handler, event); err != nil {
+			errs = append(errs, fmt.Errorf("%T: %w", handler, err))
 		}
 	}
 
+	if len(errs) > 0 {
+		return fmt.Errorf("handling %s: %w", event.EventType(), errors.Join(errs...))
+	}
 	return nil
 }
 
 func (b *InMemoryBus) Subscribe(ctx context.Context, eventType string, handler Handler) error {
-	if _, exists := b.handlers[eventType]; !exists {
-		b.handlers[eventType] = make([]Handler, 0)
+	if handler == nil {
+		return fmt.Errorf("nil handler for %s", eventType)
 	}
 
-	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Publish reads the slice without the lock, so it is replaced rather than appended to
+	handlers := make([]Handler, len(b.handlers[eventType]), len(b.handlers[eventType])+1)
+	copy(handlers, b.handlers[eventType])
+	b.handlers[eventType] = append(handlers, handler)
 	return nil
 }
+
+// Handle runs one handler, turning a panic into an error so the other handlers still run
+func Handle(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler.Handle(ctx, event)
+}
@@ -15,20 +15,23 @@ import (
 
 // Application Service
 type Service struct {
-	userRepo user.Repository
-	eventBus events.Bus
-	logger   logger.Logger
+	userRepo   user.Repository
+	eventBus   events.Bus
+	transactor events.Transactor
+	logger     logger.Logger
 }
 
 func NewService(
 	userRepo user.Repository,
 	eventBus events.Bus,
+	transactor events.Transactor,
 	logger logger.Logger,
 ) *Service {
 	return &Service{
-		userRepo: userRepo,
-		eventBus: eventBus,
-		logger:   logger,
+		userRepo:   userRepo,
+		eventBus:   eventBus,
+		transactor: transactor,
+		logger:     logger,
 	}
 }
 
@@ -138,22 +141,28 @@ func (s *Service) CreateUser(ctx context.Context, cmd CreateUserCommand) (*UserR
 		}
 	}
 
-	// Save to repository
-	if err := s.userRepo.Save(ctx, newUser); err != nil {
-		return nil, fmt.Errorf("failed to save user: %w", err)
-	}
+	// Save to repository and publish domain event
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Save(ctx, newUser); err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
 
-	// Publish domain event
-	event := user.UserCreatedEvent{
-		UserID:         newUser.ID(),
-		Email:          newUser.Email(),
-		Role:           newUser.Role(),
-		OrganizationID: newUser.OrganizationID(),
-		CreatedAt:      newUser.CreatedAt(),
-	}
+		event := user.UserCreatedEvent{
+			UserID:         newUser.ID(),
+			Email:          newUser.Email(),
+			Role:           newUser.Role(),
+			OrganizationID: newUser.OrganizationID(),
+			CreatedAt:      newUser.CreatedAt(),
+		}
 
-	if err := s.eventBus.Publish(ctx, event); err != nil {
-		s.logger.Error(ctx, "Failed to publish user created event", "error", err)
+		if err := s.eventBus.Publish(ctx, event); err != nil {
+			s.logger.Error(ctx, "Failed to publish user created event", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info(ctx, "User created successfully", "user_id", newUser.ID().String())
@@ -183,8 +192,8 @@ func (s *Service) UpdateUserProfile(ctx context.Context, cmd UpdateUserProfileCo
 	}
 
 	// Save changes
-	if err := s.userRepo.Update(ctx, existingUser); err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+	if err := s.update(ctx, existingUser, "profile"); err != nil {
+		return nil, err
 	}
 
 	s.logger.Info(ctx, "User profile updated", "user_id", userID.String())
@@ -214,8 +223,8 @@ func (s *Service) UpdateMedicalInfo(ctx context.Context, cmd UpdateMedicalInfoCo
 	}
 
 	// Save changes
-	if err := s.userRepo.Update(ctx, existingUser); err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+	if err := s.update(ctx, existingUser, "medical_info"); err != nil {
+		return nil, err
 	}
 
 	s.logger.Info(ctx, "User medical info updated", "user_id", userID.String())
@@ -244,8 +253,8 @@ func (s *Service) UpdateAvatar(ctx context.Context, cmd UpdateAvatarCommand) (*U
 	}
 
 	// Save changes
-	if err := s.userRepo.Update(ctx, existingUser); err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+	if err := s.update(ctx, existingUser, "avatar"); err != nil {
+		return nil, err
 	}
 
 	s.logger.Info(ctx, "User avatar updated", "user_id", userID.String())
@@ -253,6 +262,27 @@ func (s *Service) UpdateAvatar(ctx context.Context, cmd UpdateAvatarCommand) (*U
 	return s.toResponse(existingUser), nil
 }
 
+// update stores a changed user and announces which section changed, in one transaction
+func (s *Service) update(ctx context.Context, u *user.User, section string) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, u); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		event := user.UserUpdatedEvent{
+			UserID:    u.ID(),
+			Changes:   map[string]interface{}{"section": section},
+			UpdatedAt: time.Now(),
+		}
+
+		if err := s.eventBus.Publish(ctx, event); err != nil {
+			s.logger.Error(ctx, "Failed to publish user updated event", "error", err, "user_id", u.ID().String())
+			return err
+		}
+		return nil
+	})
+}
+
 func (s *Service) Login(ctx context.Context, cmd LoginCommand) (*LoginResponse, error) {
 	s.logger.Info(ctx, "User login attempt", "email", cmd.Email)
 
@@ -0,0 +1,283 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/notification"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/domain/waitlist"
+	"medika-backend/pkg/logger"
+)
+
+// Repository interfaces for dependency injection
+type AppointmentRepository interface {
+	Create(ctx context.Context, appointment *appointment.Appointment) error
+}
+
+type NotificationService interface {
+	Notify(ctx context.Context, notice notification.Notice) (*notification.Notification, error)
+}
+
+// Service manages waitlist entries and backfills cancelled appointment slots by
+// holding them for the first matching waitlisted patient
+type Service struct {
+	waitlistRepo        waitlist.Repository
+	appointmentRepo     AppointmentRepository
+	notificationService NotificationService
+	eventBus            events.Bus
+	transactor          events.Transactor
+	holdDuration        time.Duration
+	logger              logger.Logger
+}
+
+func NewService(
+	waitlistRepo waitlist.Repository,
+	appointmentRepo AppointmentRepository,
+	notificationService NotificationService,
+	eventBus events.Bus,
+	transactor events.Transactor,
+	holdDuration time.Duration,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		waitlistRepo:        waitlistRepo,
+		appointmentRepo:     appointmentRepo,
+		notificationService: notificationService,
+		eventBus:            eventBus,
+		transactor:          transactor,
+		holdDuration:        holdDuration,
+		logger:              logger,
+	}
+}
+
+// CreateEntry puts a patient on the waitlist
+func (s *Service) CreateEntry(ctx context.Context, entry *waitlist.Entry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry.Status = waitlist.EntryStatusWaiting
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+
+	return s.waitlistRepo.CreateEntry(ctx, entry)
+}
+
+func (s *Service) GetEntry(ctx context.Context, id string) (*waitlist.Entry, error) {
+	return s.waitlistRepo.GetEntryByID(ctx, id)
+}
+
+func (s *Service) GetEntries(ctx context.Context, organizationID string, status waitlist.EntryStatus, limit, offset int) ([]*waitlist.Entry, error) {
+	return s.waitlistRepo.GetEntries(ctx, organizationID, status, limit, offset)
+}
+
+// CancelEntry removes a patient from the waitlist. A slot currently held for them
+// is offered to the next patient in line.
+func (s *Service) CancelEntry(ctx context.Context, id string) error {
+	released, err := s.waitlistRepo.CancelEntry(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, hold := range released {
+		s.offerSlot(ctx, &hold.Slot)
+	}
+
+	return nil
+}
+
+func (s *Service) GetHold(ctx context.Context, id string) (*waitlist.Hold, error) {
+	return s.waitlistRepo.GetHoldByID(ctx, id)
+}
+
+// AcceptHold books the held slot for the waitlisted patient. The booking goes
+// through the regular conflict checks.
+func (s *Service) AcceptHold(ctx context.Context, holdID string) (*appointment.Appointment, error) {
+	hold, err := s.waitlistRepo.GetHoldByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.Status != waitlist.HoldStatusActive {
+		return nil, waitlist.ErrHoldNotActive
+	}
+	if hold.IsExpired(time.Now()) {
+		return nil, waitlist.ErrHoldExpired
+	}
+
+	now := time.Now()
+	apt := &appointment.Appointment{
+		PatientID:      hold.PatientID,
+		DoctorID:       hold.Slot.DoctorID,
+		OrganizationID: hold.Slot.OrganizationID,
+		RoomID:         hold.Slot.RoomID,
+		Date:           hold.Slot.Date,
+		StartTime:      hold.Slot.StartTime,
+		EndTime:        hold.Slot.EndTime,
+		Duration:       hold.Slot.Duration,
+		Status:         appointment.StatusPending,
+		Type:           hold.Slot.Type,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	// If the hold expired or was withdrawn while booking, the booking is rolled back
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.appointmentRepo.Create(ctx, apt); err != nil {
+			return err
+		}
+		if err := s.waitlistRepo.ResolveHold(ctx, hold.ID, waitlist.HoldStatusAccepted, waitlist.EntryStatusBooked, &apt.ID); err != nil {
+			return err
+		}
+
+		event := appointment.AppointmentScheduledEvent{Appointment: *apt, ScheduledAt: now}
+		if err := s.eventBus.Publish(ctx, event); err != nil {
+			s.logger.Error(ctx, "Failed to publish appointment scheduled event", "error", err, "appointmentID", apt.ID)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apt, nil
+}
+
+// DeclineHold gives up a held slot. The patient stays on the waitlist and the slot
+// is offered to the next patient in line.
+func (s *Service) DeclineHold(ctx context.Context, holdID string) error {
+	hold, err := s.waitlistRepo.GetHoldByID(ctx, holdID)
+	if err != nil {
+		return err
+	}
+
+	err = s.waitlistRepo.ResolveHold(ctx, hold.ID, waitlist.HoldStatusDeclined, waitlist.EntryStatusWaiting, nil)
+	if err != nil {
+		return err
+	}
+
+	s.offerSlot(ctx, &hold.Slot)
+	return nil
+}
+
+// ExpireHolds releases holds that were not accepted in time and moves each slot on
+// to the next patient in line. It runs as a background job.
+func (s *Service) ExpireHolds(ctx context.Context) error {
+	expired, err := s.waitlistRepo.ExpireHolds(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, hold := range expired {
+		s.logger.Info(ctx, "Waitlist hold expired", "holdID", hold.ID, "patientID", hold.PatientID)
+		s.offerSlot(ctx, &hold.Slot)
+	}
+
+	return nil
+}
+
+// Handle implements events.Handler for appointment.cancelled events
+func (s *Service) Handle(ctx context.Context, event events.Event) error {
+	cancelled, ok := event.(appointment.AppointmentCancelledEvent)
+	if !ok {
+		return fmt.Errorf("unexpected event type %q", event.EventType())
+	}
+
+	apt := cancelled.Appointment
+	s.offerSlot(ctx, &waitlist.Slot{
+		SourceAppointmentID: apt.ID,
+		OrganizationID:      apt.OrganizationID,
+		DoctorID:            apt.DoctorID,
+		RoomID:              apt.RoomID,
+		Date:                apt.Date,
+		StartTime:           apt.StartTime,
+		EndTime:             apt.EndTime,
+		Duration:            apt.Duration,
+		Type:                apt.Type,
+	})
+
+	return nil
+}
+
+// offerSlot holds a freed slot for the next matching patient and notifies them.
+// Backfilling is best effort, so failures are logged rather than returned.
+func (s *Service) offerSlot(ctx context.Context, slot *waitlist.Slot) {
+	startsAt, err := slot.StartsAt()
+	if err != nil {
+		s.logger.Error(ctx, "Invalid waitlist slot", "error", err, "appointmentID", slot.SourceAppointmentID)
+		return
+	}
+
+	now := time.Now()
+	if !startsAt.After(now) {
+		return
+	}
+
+	// Never hold a slot past the moment it starts
+	expiresAt := now.Add(s.holdDuration)
+	if expiresAt.After(startsAt) {
+		expiresAt = startsAt
+	}
+
+	hold, err := s.waitlistRepo.OfferSlot(ctx, slot, expiresAt)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to offer slot to waitlist", "error", err, "appointmentID", slot.SourceAppointmentID)
+		return
+	}
+	if hold == nil {
+		return
+	}
+
+	s.logger.Info(ctx, "Slot held for waitlisted patient", "holdID", hold.ID, "patientID", hold.PatientID)
+
+	if err := s.notifyHold(ctx, hold); err != nil {
+		s.logger.Error(ctx, "Failed to notify waitlisted patient", "error", err, "holdID", hold.ID)
+	}
+}
+
+func (s *Service) notifyHold(ctx context.Context, hold *waitlist.Hold) error {
+	patientID, err := shared.NewUserIDFromString(hold.PatientID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.notificationService.Notify(ctx, notification.Notice{
+		UserID:         patientID,
+		OrganizationID: hold.Slot.OrganizationID,
+		Template:       notification.TemplateWaitlistSlotOffered,
+		Vars: map[string]string{
+			"date":       hold.Slot.Date.Format("2006-01-02"),
+			"time":       hold.Slot.StartTime,
+			"hold_until": hold.ExpiresAt.Format("2006-01-02 15:04"),
+		},
+		Type:     notification.NotificationTypeAppointment,
+		Priority: notification.PriorityHigh,
+		Channels: []string{"in_app"},
+		Data: map[string]interface{}{
+			"hold_id":    hold.ID,
+			"entry_id":   hold.EntryID,
+			"doctor_id":  hold.Slot.DoctorID,
+			"date":       hold.Slot.Date.Format("2006-01-02"),
+			"start_time": hold.Slot.StartTime,
+			"end_time":   hold.Slot.EndTime,
+			"expires_at": hold.ExpiresAt,
+		},
+	})
+	return err
+}
+
+// IsClientError reports whether err should be reported as a bad request rather
+// than a server failure
+func IsClientError(err error) bool {
+	return waitlist.IsValidationError(err) ||
+		errors.Is(err, waitlist.ErrEntryClosed) ||
+		errors.Is(err, waitlist.ErrHoldNotActive) ||
+		errors.Is(err, waitlist.ErrHoldExpired)
+}
@@ -0,0 +1,75 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"medika-backend/internal/domain/shared"
+)
+
+// ErrInvalidTimeRange is returned when an appointment does not end after it starts
+var ErrInvalidTimeRange = errors.New("appointment end time must be after start time")
+
+// ConflictResource identifies which booked resource two appointments collide on
+type ConflictResource string
+
+const (
+	ConflictDoctor  ConflictResource = "doctor"
+	ConflictPatient ConflictResource = "patient"
+	ConflictRoom    ConflictResource = "room"
+)
+
+// Conflict describes an existing appointment that overlaps a requested slot
+type Conflict struct {
+	AppointmentID string            `json:"appointmentId"`
+	Resource      ConflictResource  `json:"resource"`
+	Date          time.Time         `json:"date"`
+	StartTime     string            `json:"startTime"`
+	EndTime       string            `json:"endTime"`
+	Status        AppointmentStatus `json:"status"`
+}
+
+// ConflictError is returned when an appointment would double-book a doctor, patient or room
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Conflicts) == 0 {
+		return "appointment overlaps an existing booking"
+	}
+	return fmt.Sprintf("appointment overlaps %d existing booking(s)", len(e.Conflicts))
+}
+
+// OccupiesSlot reports whether an appointment in this status blocks its time slot
+func (s AppointmentStatus) OccupiesSlot() bool {
+	return s != StatusCancelled && s != StatusNoShow
+}
+
+// ValidateTimeRange checks that the appointment has a well-formed, non-empty time window
+func (a *Appointment) ValidateTimeRange() error {
+	start, err := shared.ParseTimeOfDay(a.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %w", err)
+	}
+	end, err := shared.ParseTimeOfDay(a.EndTime)
+	if err != nil {
+		return fmt.Errorf("invalid end time: %w", err)
+	}
+	if end <= start {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
+// StartsAt returns the moment the appointment begins, reading its date and start time
+// in loc, the time zone of its organization
+func (a *Appointment) StartsAt(loc *time.Location) (time.Time, error) {
+	offset, err := shared.ParseTimeOfDay(a.StartTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	year, month, day := a.Date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc).Add(offset), nil
+}
@@ -6,20 +6,29 @@ import (
 )
 
 type Appointment struct {
-	ID             string            `json:"id"`
-	PatientID      string            `json:"patientId"`
-	DoctorID       string            `json:"doctorId"`
-	OrganizationID string            `json:"organizationId"`
-	RoomID         *string           `json:"roomId,omitempty"`
-	Date           time.Time         `json:"date"`
-	StartTime      string            `json:"startTime"`
-	EndTime        string            `json:"endTime"`
-	Duration       int               `json:"duration"` // in minutes
-	Status         AppointmentStatus `json:"status"`
-	Type           string            `json:"type"`
-	Notes          *string           `json:"notes,omitempty"`
-	CreatedAt      time.Time         `json:"createdAt"`
-	UpdatedAt      time.Time         `json:"updatedAt"`
+	ID                string            `json:"id"`
+	PatientID         string            `json:"patientId"`
+	DoctorID          string            `json:"doctorId"`
+	OrganizationID    string            `json:"organizationId"`
+	RoomID            *string           `json:"roomId,omitempty"`
+	SeriesID          *string           `json:"seriesId,omitempty"`
+	RescheduledFromID *string           `json:"rescheduledFromId,omitempty"`
+	Date              time.Time         `json:"date"`
+	StartTime         string            `json:"startTime"`
+	EndTime           string            `json:"endTime"`
+	Duration          int               `json:"duration"` // in minutes
+	Status            AppointmentStatus `json:"status"`
+	Type              string            `json:"type"`
+	Notes             *string           `json:"notes,omitempty"`
+	// Set from the organization's no-show policy when the appointment is booked
+	ConfirmationRequired bool       `json:"confirmationRequired"`
+	DepositRequired      bool       `json:"depositRequired"`
+	DepositPaidAt        *time.Time `json:"depositPaidAt,omitempty"`
+	CheckInCode          string     `json:"checkInCode,omitempty"` // assigned when stored; the patient checks in with it, e.g. from a QR code
+	WalkIn               bool       `json:"walkIn"`                // booked when the patient arrived without an appointment
+	Revision             int        `json:"revision"`              // counts the changes made since it was booked
+	CreatedAt            time.Time  `json:"createdAt"`
+	UpdatedAt            time.Time  `json:"updatedAt"`
 }
 
 type AppointmentStatus string
@@ -27,6 +36,7 @@ type AppointmentStatus string
 const (
 	StatusPending   AppointmentStatus = "pending"
 	StatusConfirmed AppointmentStatus = "confirmed"
+	StatusCheckedIn AppointmentStatus = "checked_in"
 	StatusInProgress AppointmentStatus = "in_progress"
 	StatusCompleted AppointmentStatus = "completed"
 	StatusCancelled AppointmentStatus = "cancelled"
@@ -40,10 +50,29 @@ type Repository interface {
 	GetByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*Appointment, error)
 	GetByPatient(ctx context.Context, patientID string, limit, offset int) ([]*Appointment, error)
 	GetByDoctor(ctx context.Context, doctorID string, limit, offset int) ([]*Appointment, error)
+	GetByDoctorAndDateRange(ctx context.Context, doctorID string, from, to time.Time) ([]*Appointment, error)
 	Update(ctx context.Context, appointment *Appointment) error
-	UpdateStatus(ctx context.Context, id string, status AppointmentStatus) error
+	Reschedule(ctx context.Context, cancellation *StatusChange, replacement *Appointment) error
+	FindConflicts(ctx context.Context, appointment *Appointment) ([]Conflict, error)
+	ChangeStatus(ctx context.Context, changes ...*StatusChange) error
+	GetStatusHistory(ctx context.Context, appointmentID string) ([]*StatusChange, error)
 	Delete(ctx context.Context, id string) error
 	CountByOrganization(ctx context.Context, organizationID string) (int, error)
 	GetAppointmentsByDate(ctx context.Context, organizationID, date string, limit int) ([]*Appointment, error)
 	CountAppointmentsByDate(ctx context.Context, organizationID, date string) (int, error)
+
+	// Recurring series
+	CreateSeries(ctx context.Context, series *Series, occurrences []*Appointment, skipConflicts bool) ([]OccurrenceConflict, error)
+	GetSeriesByID(ctx context.Context, id string) (*Series, error)
+	GetSeriesAppointments(ctx context.Context, seriesID string) ([]*Appointment, error)
+	UpdateOccurrences(ctx context.Context, occurrences []*Appointment, split *SeriesSplit) error
+
+	// No-show policy
+	// FindOverdue returns confirmed appointments whose end time plus the organization's
+	// grace period (defaultGrace without a policy) has passed
+	FindOverdue(ctx context.Context, now time.Time, defaultGrace time.Duration, limit int) ([]*Appointment, error)
+	// FindUnconfirmed returns pending appointments that required confirmation and
+	// were not confirmed before the organization's deadline
+	FindUnconfirmed(ctx context.Context, now time.Time, limit int) ([]*Appointment, error)
+	MarkDepositPaid(ctx context.Context, id string, paidAt time.Time) error
 }
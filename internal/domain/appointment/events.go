@@ -0,0 +1,94 @@
+package appointment
+
+import "time"
+
+// AppointmentCancelledEvent is published whenever an appointment moves to
+// StatusCancelled, including the original of a rescheduled appointment
+type AppointmentCancelledEvent struct {
+	Appointment Appointment
+	ActorID     *string
+	Reason      *string
+	Rescheduled bool // cancelled because it moved to another slot
+	CancelledAt time.Time
+}
+
+func (e AppointmentCancelledEvent) EventType() string {
+	return "appointment.cancelled"
+}
+
+func (e AppointmentCancelledEvent) EventData() map[string]interface{} {
+	data := map[string]interface{}{
+		"appointment_id":  e.Appointment.ID,
+		"organization_id": e.Appointment.OrganizationID,
+		"patient_id":      e.Appointment.PatientID,
+		"doctor_id":       e.Appointment.DoctorID,
+		"date":            e.Appointment.Date.Format("2006-01-02"),
+		"start_time":      e.Appointment.StartTime,
+		"end_time":        e.Appointment.EndTime,
+		"rescheduled":     e.Rescheduled,
+		"cancelled_at":    e.CancelledAt,
+	}
+	if e.ActorID != nil {
+		data["actor_id"] = *e.ActorID
+	}
+	if e.Reason != nil {
+		data["reason"] = *e.Reason
+	}
+	return data
+}
+
+// AppointmentScheduledEvent is published when an appointment is booked and whenever
+// its date or time changes
+type AppointmentScheduledEvent struct {
+	Appointment Appointment
+	Rescheduled bool // false for a new booking
+	ScheduledAt time.Time
+}
+
+func (e AppointmentScheduledEvent) EventType() string {
+	return "appointment.scheduled"
+}
+
+func (e AppointmentScheduledEvent) EventData() map[string]interface{} {
+	return map[string]interface{}{
+		"appointment_id":  e.Appointment.ID,
+		"organization_id": e.Appointment.OrganizationID,
+		"patient_id":      e.Appointment.PatientID,
+		"doctor_id":       e.Appointment.DoctorID,
+		"date":            e.Appointment.Date.Format("2006-01-02"),
+		"start_time":      e.Appointment.StartTime,
+		"end_time":        e.Appointment.EndTime,
+		"rescheduled":     e.Rescheduled,
+		"scheduled_at":    e.ScheduledAt,
+	}
+}
+
+// AppointmentStatusChangedEvent is published whenever an appointment moves through its
+// lifecycle, e.g. when it is confirmed or completed
+type AppointmentStatusChangedEvent struct {
+	Appointment Appointment
+	Change      StatusChange
+}
+
+func (e AppointmentStatusChangedEvent) EventType() string {
+	return "appointment.status_changed"
+}
+
+func (e AppointmentStatusChangedEvent) EventData() map[string]interface{} {
+	data := map[string]interface{}{
+		"appointment_id":  e.Appointment.ID,
+		"organization_id": e.Appointment.OrganizationID,
+		"patient_id":      e.Appointment.PatientID,
+		"doctor_id":       e.Appointment.DoctorID,
+		"from_status":     string(e.Change.FromStatus),
+		"to_status":       string(e.Change.ToStatus),
+		"changed_at":      e.Change.ChangedAt,
+	}
+	if e.Change.ActorID != nil {
+		data["actor_id"] = *e.Change.ActorID
+	}
+	if e.Change.Reason != nil {
+		data["reason"] = *e.Change.Reason
+	}
+	return data
+}
@@ -0,0 +1,186 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxSeriesOccurrences caps how many appointments a single recurrence rule may generate
+const MaxSeriesOccurrences = 104
+
+var (
+	ErrInvalidFrequency     = errors.New("recurrence frequency must be daily, weekly or monthly")
+	ErrInvalidInterval      = errors.New("recurrence interval must be at least 1")
+	ErrMissingRecurrenceEnd = errors.New("recurrence rule requires count or until")
+	ErrUntilBeforeStart     = errors.New("recurrence 'until' must not be before the first occurrence")
+	ErrTooManyOccurrences   = fmt.Errorf("recurrence rule must not generate more than %d occurrences", MaxSeriesOccurrences)
+	ErrNotInSeries          = errors.New("appointment is not part of a series")
+	ErrSeriesDateChange     = errors.New("the date can only be changed for a single occurrence")
+	ErrInvalidSeriesScope   = errors.New("scope must be this, following or all")
+)
+
+// RecurrenceFrequency is the subset of RFC 5545 FREQ values we support
+type RecurrenceFrequency string
+
+const (
+	FrequencyDaily   RecurrenceFrequency = "daily"
+	FrequencyWeekly  RecurrenceFrequency = "weekly"
+	FrequencyMonthly RecurrenceFrequency = "monthly"
+)
+
+// RecurrenceRule describes how a series repeats (RRULE subset: FREQ, INTERVAL, COUNT, UNTIL)
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval"`
+	Count     *int                `json:"count,omitempty"`
+	Until     *time.Time          `json:"until,omitempty"`
+}
+
+// Series links the appointments generated from one recurrence rule
+type Series struct {
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organizationId"`
+	PatientID      string         `json:"patientId"`
+	DoctorID       string         `json:"doctorId"`
+	Rule           RecurrenceRule `json:"rule"`
+	StartDate      time.Time      `json:"startDate"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+// SeriesSplit ends Original early and, when Successor is set, moves the edited
+// occurrences into the successor series ("this and following" edits)
+type SeriesSplit struct {
+	Original  *Series
+	Successor *Series
+}
+
+// SeriesScope selects which occurrences of a series an edit or cancellation applies to
+type SeriesScope string
+
+const (
+	ScopeThis      SeriesScope = "this"
+	ScopeFollowing SeriesScope = "following"
+	ScopeAll       SeriesScope = "all"
+)
+
+// ParseSeriesScope validates a scope coming from the API, defaulting to ScopeThis
+func ParseSeriesScope(value string) (SeriesScope, error) {
+	switch SeriesScope(value) {
+	case "", ScopeThis:
+		return ScopeThis, nil
+	case ScopeFollowing, ScopeAll:
+		return SeriesScope(value), nil
+	default:
+		return "", ErrInvalidSeriesScope
+	}
+}
+
+// OccurrenceConflict lists the existing bookings that collide with one occurrence of a series
+type OccurrenceConflict struct {
+	Date      time.Time  `json:"date"`
+	StartTime string     `json:"startTime"`
+	EndTime   string     `json:"endTime"`
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// SeriesConflictError is returned when one or more occurrences of a series would double-book
+type SeriesConflictError struct {
+	Occurrences []OccurrenceConflict
+}
+
+func (e *SeriesConflictError) Error() string {
+	return fmt.Sprintf("%d occurrence(s) of the series overlap existing bookings", len(e.Occurrences))
+}
+
+// Validate checks the rule against the date of the first occurrence
+func (r RecurrenceRule) Validate(start time.Time) error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	default:
+		return ErrInvalidFrequency
+	}
+	if r.Interval < 1 {
+		return ErrInvalidInterval
+	}
+	if r.Count == nil && r.Until == nil {
+		return ErrMissingRecurrenceEnd
+	}
+	if r.Count != nil && (*r.Count < 1 || *r.Count > MaxSeriesOccurrences) {
+		return ErrTooManyOccurrences
+	}
+	if r.Until != nil {
+		if dateOnly(*r.Until).Before(dateOnly(start)) {
+			return ErrUntilBeforeStart
+		}
+		if r.Count == nil && !r.nth(dateOnly(start), MaxSeriesOccurrences).After(dateOnly(*r.Until)) {
+			return ErrTooManyOccurrences
+		}
+	}
+	return nil
+}
+
+// Occurrences expands the rule into the dates of every occurrence, starting with start.
+// As in RFC 5545, monthly occurrences on a day the month does not have are skipped.
+func (r RecurrenceRule) Occurrences(start time.Time) []time.Time {
+	start = dateOnly(start)
+	dates := []time.Time{}
+
+	for i := 0; len(dates) < MaxSeriesOccurrences; i++ {
+		date := r.nth(start, i)
+		if r.Until != nil && date.After(dateOnly(*r.Until)) {
+			break
+		}
+		if r.Count != nil && len(dates) >= *r.Count {
+			break
+		}
+		if r.Frequency == FrequencyMonthly && date.Day() != start.Day() {
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	return dates
+}
+
+// String renders the rule as an iCalendar RRULE value
+func (r RecurrenceRule) String() string {
+	parts := []string{
+		"FREQ=" + strings.ToUpper(string(r.Frequency)),
+		fmt.Sprintf("INTERVAL=%d", r.Interval),
+	}
+	if r.Count != nil {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", *r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// nth returns the i-th candidate date of the rule without skipping invalid monthly dates
+func (r RecurrenceRule) nth(start time.Time, i int) time.Time {
+	switch r.Frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, i*r.Interval)
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*i*r.Interval)
+	default:
+		return start.AddDate(0, i*r.Interval, 0)
+	}
+}
+
+// EndBefore returns a copy of the rule that stops before the given date, used when
+// a series is split by a "this and following" edit
+func (r RecurrenceRule) EndBefore(date time.Time) RecurrenceRule {
+	until := dateOnly(date).AddDate(0, 0, -1)
+	r.Until = &until
+	r.Count = nil
+	return r
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
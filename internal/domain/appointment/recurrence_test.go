@@ -0,0 +1,129 @@
+package appointment
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	count := func(n int) *int { return &n }
+	until := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily by count",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Count: count(3)},
+			start: date(2026, 3, 2),
+			want:  []time.Time{date(2026, 3, 2), date(2026, 3, 3), date(2026, 3, 4)},
+		},
+		{
+			name:  "every other week until a date",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 2, Until: until(date(2026, 3, 31))},
+			start: date(2026, 3, 2),
+			want:  []time.Time{date(2026, 3, 2), date(2026, 3, 16), date(2026, 3, 30)},
+		},
+		{
+			name:  "until on an occurrence includes it",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 2, Until: until(date(2026, 3, 6))},
+			start: date(2026, 3, 2),
+			want:  []time.Time{date(2026, 3, 2), date(2026, 3, 4), date(2026, 3, 6)},
+		},
+		{
+			name:  "count stops before until",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Count: count(2), Until: until(date(2026, 3, 31))},
+			start: date(2026, 3, 2),
+			want:  []time.Time{date(2026, 3, 2), date(2026, 3, 3)},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, Count: count(3)},
+			start: date(2026, 1, 31),
+			want:  []time.Time{date(2026, 1, 31), date(2026, 3, 31), date(2026, 5, 31)},
+		},
+		{
+			name:  "time of day is dropped",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 1, Count: count(2)},
+			start: time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC),
+			want:  []time.Time{date(2026, 3, 2), date(2026, 3, 9)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Occurrences(tt.start)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleValidate(t *testing.T) {
+	count := func(n int) *int { return &n }
+	until := func(t time.Time) *time.Time { return &t }
+	start := date(2026, 3, 2)
+
+	tests := []struct {
+		name string
+		rule RecurrenceRule
+		want error
+	}{
+		{
+			name: "valid",
+			rule: RecurrenceRule{Frequency: FrequencyWeekly, Interval: 1, Count: count(10)},
+		},
+		{
+			name: "unknown frequency",
+			rule: RecurrenceRule{Frequency: "yearly", Interval: 1, Count: count(1)},
+			want: ErrInvalidFrequency,
+		},
+		{
+			name: "zero interval",
+			rule: RecurrenceRule{Frequency: FrequencyDaily, Count: count(1)},
+			want: ErrInvalidInterval,
+		},
+		{
+			name: "no end",
+			rule: RecurrenceRule{Frequency: FrequencyDaily, Interval: 1},
+			want: ErrMissingRecurrenceEnd,
+		},
+		{
+			name: "count above the cap",
+			rule: RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Count: count(MaxSeriesOccurrences + 1)},
+			want: ErrTooManyOccurrences,
+		},
+		{
+			name: "until before start",
+			rule: RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Until: until(date(2026, 3, 1))},
+			want: ErrUntilBeforeStart,
+		},
+		{
+			name: "until too far away",
+			rule: RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, Until: until(date(2027, 3, 2))},
+			want: ErrTooManyOccurrences,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(start); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
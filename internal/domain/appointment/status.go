@@ -0,0 +1,107 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStatusChanged is returned when the appointment's status changed between reading
+// it and applying a transition
+var ErrStatusChanged = errors.New("appointment status was changed by another request")
+
+// ErrAppointmentClosed is returned when editing or rescheduling an appointment that
+// has already started or been closed
+var ErrAppointmentClosed = errors.New("appointment can no longer be changed")
+
+// ErrDepositRequired is returned when confirming an appointment whose deposit has
+// not been paid
+var ErrDepositRequired = errors.New("a deposit must be paid before the appointment can be confirmed")
+
+// ErrDepositNotRequired is returned when recording a deposit for an appointment
+// that does not need one
+var ErrDepositNotRequired = errors.New("appointment does not require a deposit")
+
+// allowedTransitions is the appointment lifecycle. Completed, cancelled and no-show
+// are terminal; a mistaken cancellation is corrected by booking a new appointment.
+// A patient who has checked in has arrived, so can no longer be a no-show.
+var allowedTransitions = map[AppointmentStatus][]AppointmentStatus{
+	StatusPending:    {StatusConfirmed, StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusConfirmed:  {StatusCheckedIn, StatusInProgress, StatusCancelled, StatusNoShow},
+	StatusCheckedIn:  {StatusInProgress, StatusCancelled},
+	StatusInProgress: {StatusCompleted},
+	StatusCompleted:  {},
+	StatusCancelled:  {},
+	StatusNoShow:     {},
+}
+
+// InvalidTransitionError is returned when a status change is not allowed by the lifecycle
+type InvalidTransitionError struct {
+	From AppointmentStatus
+	To   AppointmentStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	if _, known := allowedTransitions[e.To]; !known {
+		return fmt.Sprintf("unknown appointment status %q", e.To)
+	}
+	return fmt.Sprintf("appointment cannot move from %s to %s", e.From, e.To)
+}
+
+// StatusChange is one entry of an appointment's status history
+type StatusChange struct {
+	ID            string            `json:"id"`
+	AppointmentID string            `json:"appointmentId"`
+	FromStatus    AppointmentStatus `json:"fromStatus"`
+	ToStatus      AppointmentStatus `json:"toStatus"`
+	ActorID       *string           `json:"actorId,omitempty"`
+	Reason        *string           `json:"reason,omitempty"`
+	ChangedAt     time.Time         `json:"changedAt"`
+}
+
+// IsValid reports whether the status is part of the lifecycle
+func (s AppointmentStatus) IsValid() bool {
+	_, ok := allowedTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether no further transitions are possible
+func (s AppointmentStatus) IsTerminal() bool {
+	return s.IsValid() && len(allowedTransitions[s]) == 0
+}
+
+// IsEditable reports whether an appointment in this status may still be edited or
+// rescheduled, i.e. it has not started and has not been closed
+func (s AppointmentStatus) IsEditable() bool {
+	return s == StatusPending || s == StatusConfirmed
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to next
+func (s AppointmentStatus) CanTransitionTo(next AppointmentStatus) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition validates a status change and returns the history entry describing it.
+// The appointment itself is only updated once the change has been persisted.
+func (a *Appointment) Transition(to AppointmentStatus, actorID, reason *string) (*StatusChange, error) {
+	if !a.Status.CanTransitionTo(to) {
+		return nil, &InvalidTransitionError{From: a.Status, To: to}
+	}
+	if (to == StatusConfirmed || to == StatusCheckedIn) && a.DepositRequired && a.DepositPaidAt == nil {
+		return nil, ErrDepositRequired
+	}
+
+	return &StatusChange{
+		AppointmentID: a.ID,
+		FromStatus:    a.Status,
+		ToStatus:      to,
+		ActorID:       actorID,
+		Reason:        reason,
+		ChangedAt:     time.Now(),
+	}, nil
+}
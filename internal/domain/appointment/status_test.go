@@ -0,0 +1,81 @@
+package appointment
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAppointmentTransition(t *testing.T) {
+	paidAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	actor := "staff-1"
+
+	tests := []struct {
+		name            string
+		from            AppointmentStatus
+		to              AppointmentStatus
+		depositRequired bool
+		depositPaidAt   *time.Time
+		wantInvalid     bool
+		wantErr         error
+	}{
+		{name: "pending to confirmed", from: StatusPending, to: StatusConfirmed},
+		{name: "pending to checked in", from: StatusPending, to: StatusCheckedIn},
+		{name: "pending to cancelled", from: StatusPending, to: StatusCancelled},
+		{name: "pending to no-show", from: StatusPending, to: StatusNoShow},
+		{name: "confirmed to in progress", from: StatusConfirmed, to: StatusInProgress},
+		{name: "confirmed to no-show", from: StatusConfirmed, to: StatusNoShow},
+		{name: "checked in to in progress", from: StatusCheckedIn, to: StatusInProgress},
+		{name: "checked in to cancelled", from: StatusCheckedIn, to: StatusCancelled},
+		{name: "in progress to completed", from: StatusInProgress, to: StatusCompleted},
+
+		{name: "pending straight to completed", from: StatusPending, to: StatusCompleted, wantInvalid: true},
+		{name: "checked in cannot be a no-show", from: StatusCheckedIn, to: StatusNoShow, wantInvalid: true},
+		{name: "in progress cannot be cancelled", from: StatusInProgress, to: StatusCancelled, wantInvalid: true},
+		{name: "back from confirmed to pending", from: StatusConfirmed, to: StatusPending, wantInvalid: true},
+		{name: "to the same status", from: StatusConfirmed, to: StatusConfirmed, wantInvalid: true},
+		{name: "completed is terminal", from: StatusCompleted, to: StatusInProgress, wantInvalid: true},
+		{name: "cancelled is terminal", from: StatusCancelled, to: StatusPending, wantInvalid: true},
+		{name: "no-show is terminal", from: StatusNoShow, to: StatusCheckedIn, wantInvalid: true},
+		{name: "unknown status", from: StatusPending, to: "rescheduled", wantInvalid: true},
+
+		{name: "confirming an unpaid deposit", from: StatusPending, to: StatusConfirmed, depositRequired: true, wantErr: ErrDepositRequired},
+		{name: "checking in with an unpaid deposit", from: StatusConfirmed, to: StatusCheckedIn, depositRequired: true, wantErr: ErrDepositRequired},
+		{name: "confirming a paid deposit", from: StatusPending, to: StatusConfirmed, depositRequired: true, depositPaidAt: &paidAt},
+		{name: "cancelling an unpaid deposit", from: StatusPending, to: StatusCancelled, depositRequired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apt := &Appointment{
+				ID:              "apt-1",
+				Status:          tt.from,
+				DepositRequired: tt.depositRequired,
+				DepositPaidAt:   tt.depositPaidAt,
+			}
+
+			change, err := apt.Transition(tt.to, &actor, nil)
+
+			var invalid *InvalidTransitionError
+			switch {
+			case tt.wantInvalid:
+				if !errors.As(err, &invalid) || invalid.From != tt.from || invalid.To != tt.to {
+					t.Fatalf("Transition() error = %v, want an invalid transition from %s to %s", err, tt.from, tt.to)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Transition() error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("Transition() error = %v, want none", err)
+			default:
+				if change.AppointmentID != apt.ID || change.FromStatus != tt.from || change.ToStatus != tt.to || change.ActorID != &actor {
+					t.Errorf("Transition() = %+v, want %s from %s to %s by %s", change, apt.ID, tt.from, tt.to, actor)
+				}
+				if apt.Status != tt.from {
+					t.Errorf("Status = %s, want %s until the change is persisted", apt.Status, tt.from)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// OwnerType is the kind of user whose appointments a feed publishes
+type OwnerType string
+
+const (
+	OwnerTypeDoctor  OwnerType = "doctor"
+	OwnerTypePatient OwnerType = "patient"
+)
+
+// FeedToken grants read access to a subscribable calendar feed. Only a hash of
+// the secret is stored; the secret itself is shown once, when the token is created.
+type FeedToken struct {
+	ID         string     `json:"id"`
+	OwnerType  OwnerType  `json:"ownerType"`
+	OwnerID    string     `json:"ownerId"`
+	TokenHash  string     `json:"-"`
+	Label      *string    `json:"label,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+var (
+	ErrFeedNotFound   = errors.New("calendar feed not found")
+	ErrInvalidOwner   = errors.New("invalid calendar feed owner")
+	ErrAlreadyRevoked = errors.New("calendar feed token is already revoked")
+	ErrForbidden      = errors.New("calendar feeds can only be managed by their owner or an admin of the owner's organization")
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, token *FeedToken) error
+	GetByID(ctx context.Context, id string) (*FeedToken, error)
+	// GetByHash returns the token with the given hash, or ErrFeedNotFound
+	GetByHash(ctx context.Context, tokenHash string) (*FeedToken, error)
+	GetByOwner(ctx context.Context, ownerType OwnerType, ownerID string) ([]*FeedToken, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// IsValid reports whether the owner type is known
+func (t OwnerType) IsValid() bool {
+	return t == OwnerTypeDoctor || t == OwnerTypePatient
+}
+
+// IsRevoked reports whether the token can no longer be used
+func (t *FeedToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,117 @@
+package checkin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/queue"
+)
+
+// ArrivalWindow is how long before and after its start time an appointment can be
+// checked in for
+type ArrivalWindow struct {
+	OpensBefore time.Duration
+	ClosesAfter time.Duration
+}
+
+// Interval is a booked part of a day, as offsets from midnight
+type Interval struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Result is a checked-in appointment and the queue entry the patient now waits in
+type Result struct {
+	Appointment *appointment.Appointment `json:"appointment"`
+	Entry       *queue.PatientQueue      `json:"entry"`
+}
+
+var (
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrCodeNotFound        = errors.New("check-in code not found")
+	ErrAlreadyCheckedIn    = errors.New("appointment is already checked in")
+	ErrNotToday            = errors.New("appointment is not today")
+	ErrTooEarly            = errors.New("check-in has not opened yet")
+	ErrTooLate             = errors.New("check-in has closed")
+	ErrNoWalkInSlot        = errors.New("no free time left today for a walk-in")
+)
+
+// Repository interface
+type Repository interface {
+	// GetAppointmentByCode returns the appointment with the given check-in code, or
+	// ErrCodeNotFound
+	GetAppointmentByCode(ctx context.Context, code string) (*appointment.Appointment, error)
+	// CheckIn applies change, which moves the appointment to checked in, and adds entry
+	// to its queue in one transaction. If the appointment is already queued, that entry
+	// is kept, its ID set on entry, and false returned.
+	CheckIn(ctx context.Context, change *appointment.StatusChange, entry *queue.PatientQueue, actorID *string) (bool, error)
+	// CreateWalkIn books apt, already checked in, at the first time from its start time
+	// that is free for its doctor and patient, and adds entry to its queue in one
+	// transaction
+	CreateWalkIn(ctx context.Context, apt *appointment.Appointment, entry *queue.PatientQueue, actorID *string) error
+}
+
+// Check reports whether apt can be checked in for at now. loc is the time zone of the
+// appointment's organization, which decides what today is.
+func (w ArrivalWindow) Check(apt *appointment.Appointment, loc *time.Location, now time.Time) error {
+	startsAt, err := apt.StartsAt(loc)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %w", err)
+	}
+
+	if startsAt.Format("2006-01-02") != now.In(loc).Format("2006-01-02") {
+		return ErrNotToday
+	}
+	if opens := startsAt.Add(-w.OpensBefore); now.Before(opens) {
+		return fmt.Errorf("%w: check-in opens at %s", ErrTooEarly, opens.Format("15:04"))
+	}
+	if closes := startsAt.Add(w.ClosesAfter); now.After(closes) {
+		return fmt.Errorf("%w: check-in closed at %s", ErrTooLate, closes.Format("15:04"))
+	}
+
+	return nil
+}
+
+// NormalizeCode turns a code as typed or scanned into its stored form: upper case,
+// without spaces or dashes, and with O, I and L read as the digits they resemble
+func NormalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r = unicode.ToUpper(r); r {
+		case ' ', '-':
+			return -1
+		case 'O':
+			return '0'
+		case 'I', 'L':
+			return '1'
+		}
+		return r
+	}, code)
+}
+
+// WalkInStart returns the earliest start from from at which a walk-in of the given
+// length overlaps none of busy and ends the same day. busy is sorted in place.
+func WalkInStart(from, length time.Duration, busy []Interval) (time.Duration, error) {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start < busy[j].Start })
+
+	start := from
+	for _, b := range busy {
+		if b.End <= start {
+			continue
+		}
+		if b.Start >= start+length {
+			break
+		}
+		start = b.End
+	}
+
+	if start+length >= 24*time.Hour {
+		return 0, ErrNoWalkInSlot
+	}
+	return start, nil
+}
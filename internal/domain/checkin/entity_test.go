@@ -0,0 +1,64 @@
+package checkin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"medika-backend/internal/domain/appointment"
+)
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"stored form is unchanged", "7K3M9QXZ", "7K3M9QXZ"},
+		{"lower case", "7k3m9qxz", "7K3M9QXZ"},
+		{"dashes and spaces from a printed code", "7K3M-9QXZ", "7K3M9QXZ"},
+		{"spaces around", " 7K3M 9QXZ ", "7K3M9QXZ"},
+		{"O read as zero", "O7K3M9QX", "07K3M9QX"},
+		{"I and L read as one", "I7K3M9QL", "17K3M9Q1"},
+		{"lower case look-alikes", "o7k3m9il", "07K3M911"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeCode(tt.code); got != tt.want {
+				t.Errorf("NormalizeCode(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrivalWindowCheck(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	window := ArrivalWindow{OpensBefore: 30 * time.Minute, ClosesAfter: 15 * time.Minute}
+	// 07:00 in Jakarta is midnight UTC, so the day differs between the two zones
+	apt := &appointment.Appointment{Date: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), StartTime: "07:00"}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		{name: "at the start", now: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "early, on the previous day in UTC", now: time.Date(2026, 3, 1, 23, 40, 0, 0, time.UTC)},
+		{name: "before the window opens", now: time.Date(2026, 3, 1, 23, 20, 0, 0, time.UTC), want: ErrTooEarly},
+		{name: "after the window closes", now: time.Date(2026, 3, 2, 0, 20, 0, 0, time.UTC), want: ErrTooLate},
+		{name: "another day", now: time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC), want: ErrNotToday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := window.Check(apt, jakarta, tt.now); !errors.Is(err, tt.want) {
+				t.Errorf("Check(%v) = %v, want %v", tt.now, err, tt.want)
+			}
+		})
+	}
+}
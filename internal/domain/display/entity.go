@@ -0,0 +1,68 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"medika-backend/internal/domain/queue"
+)
+
+// DefaultNextUpPerQueue is how many waiting tickets of each queue a board lists
+const DefaultNextUpPerQueue = 3
+
+// Token grants a waiting-room screen read access to an organization's display
+// board, or to the board of a single queue. Only a hash of the secret is stored;
+// the secret itself is shown once, when the token is created.
+type Token struct {
+	ID             string       `json:"id"`
+	OrganizationID string       `json:"organizationId"`
+	Scope          *queue.Scope `json:"scope,omitempty"`
+	TokenHash      string       `json:"-"`
+	Label          *string      `json:"label,omitempty"`
+	LastUsedAt     *time.Time   `json:"lastUsedAt,omitempty"`
+	RevokedAt      *time.Time   `json:"revokedAt,omitempty"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+// BoardEntry is one ticket on a display board. It deliberately carries nothing that
+// identifies the patient.
+type BoardEntry struct {
+	TicketNumber string  `json:"ticketNumber"`
+	RoomName     *string `json:"roomName,omitempty"`
+	Status       string  `json:"status"`
+}
+
+// Board is what a waiting-room screen shows: the tickets being served and the ones
+// due next
+type Board struct {
+	NowServing  []BoardEntry `json:"nowServing"`
+	NextUp      []BoardEntry `json:"nextUp"`
+	GeneratedAt time.Time    `json:"generatedAt"`
+}
+
+var (
+	ErrTokenNotFound        = errors.New("display token not found")
+	ErrAlreadyRevoked       = errors.New("display token is already revoked")
+	ErrOrganizationNotFound = errors.New("organization not found")
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, token *Token) error
+	GetByID(ctx context.Context, id string) (*Token, error)
+	// GetByHash returns the token with the given hash, or ErrTokenNotFound
+	GetByHash(ctx context.Context, tokenHash string) (*Token, error)
+	GetByOrganization(ctx context.Context, organizationID string) ([]*Token, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) error
+	// GetBoard lists today's ticketed entries being served, and the first
+	// nextUpPerQueue waiting entries of each queue
+	GetBoard(ctx context.Context, organizationID string, scope *queue.Scope, nextUpPerQueue int) (*Board, error)
+}
+
+// IsRevoked reports whether the token can no longer be used
+func (t *Token) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
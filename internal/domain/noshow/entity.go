@@ -0,0 +1,105 @@
+package noshow
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultConfirmationLeadHours is how long before the start an appointment has to be
+// confirmed when the organization did not choose otherwise
+const DefaultConfirmationLeadHours = 24
+
+// Requirement is what a patient has to do before a new appointment is secured
+type Requirement string
+
+const (
+	RequirementNone         Requirement = "none"
+	RequirementConfirmation Requirement = "confirmation"
+	RequirementDeposit      Requirement = "deposit"
+)
+
+// Policy is an organization's handling of missed appointments. A threshold of 0
+// turns the matching requirement off.
+type Policy struct {
+	OrganizationID string `json:"organizationId"`
+	// AutoMark enables marking confirmed appointments as no-show once the grace period has passed
+	AutoMark bool `json:"autoMark"`
+	// GracePeriodMinutes overrides the system-wide grace period when set
+	GracePeriodMinutes *int `json:"gracePeriodMinutes,omitempty"`
+	// ConfirmationThreshold is the no-show count from which new appointments must be
+	// confirmed ConfirmationLeadHours before they start, or are cancelled
+	ConfirmationThreshold int `json:"confirmationThreshold"`
+	ConfirmationLeadHours int `json:"confirmationLeadHours"`
+	// DepositThreshold is the no-show count from which new appointments can only be
+	// confirmed once a deposit has been paid
+	DepositThreshold int       `json:"depositThreshold"`
+	DepositAmount    float64   `json:"depositAmount"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// PatientRecord is a patient's no-show history at an organization
+type PatientRecord struct {
+	OrganizationID string     `json:"organizationId"`
+	PatientID      string     `json:"patientId"`
+	NoShowCount    int        `json:"noShowCount"`
+	LastNoShowAt   *time.Time `json:"lastNoShowAt,omitempty"`
+}
+
+var (
+	ErrPolicyNotFound     = errors.New("no-show policy not found")
+	ErrInvalidGracePeriod = errors.New("grace period must not be negative")
+	ErrInvalidThreshold   = errors.New("no-show thresholds must not be negative")
+	ErrInvalidLeadTime    = errors.New("confirmation lead time must be at least one hour")
+	ErrInvalidDeposit     = errors.New("deposit amount must be positive when a deposit threshold is set")
+)
+
+// Repository interface
+type Repository interface {
+	GetPolicy(ctx context.Context, organizationID string) (*Policy, error)
+	// SavePolicy creates or replaces an organization's policy
+	SavePolicy(ctx context.Context, policy *Policy) error
+	// GetPatientRecord returns the patient's record, with a zero count if the patient
+	// never missed an appointment
+	GetPatientRecord(ctx context.Context, organizationID, patientID string) (*PatientRecord, error)
+}
+
+// DefaultPolicy is used for organizations that have not configured a policy: no-shows
+// are marked automatically and nothing is required from patients
+func DefaultPolicy(organizationID string) *Policy {
+	return &Policy{
+		OrganizationID:        organizationID,
+		AutoMark:              true,
+		ConfirmationLeadHours: DefaultConfirmationLeadHours,
+	}
+}
+
+// Validate checks the policy's limits
+func (p *Policy) Validate() error {
+	if p.GracePeriodMinutes != nil && *p.GracePeriodMinutes < 0 {
+		return ErrInvalidGracePeriod
+	}
+	if p.ConfirmationThreshold < 0 || p.DepositThreshold < 0 {
+		return ErrInvalidThreshold
+	}
+	if p.ConfirmationLeadHours < 1 {
+		return ErrInvalidLeadTime
+	}
+	if p.DepositThreshold > 0 && p.DepositAmount <= 0 {
+		return ErrInvalidDeposit
+	}
+	return nil
+}
+
+// RequirementFor returns what a patient with the given number of no-shows has to do
+// for a new appointment. A deposit takes precedence over a confirmation.
+func (p *Policy) RequirementFor(noShowCount int) Requirement {
+	if p.DepositThreshold > 0 && noShowCount >= p.DepositThreshold {
+		return RequirementDeposit
+	}
+	if p.ConfirmationThreshold > 0 && noShowCount >= p.ConfirmationThreshold {
+		return RequirementConfirmation
+	}
+	return RequirementNone
+}
@@ -0,0 +1,115 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+// Channel names, as listed in a notification's channels. In-app notifications are
+// delivered by being stored; every other channel gets a Delivery.
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+// DeliveryLease is how long a claimed delivery is reserved for the dispatcher that
+// claimed it. A delivery whose dispatcher died is attempted again after it.
+const DeliveryLease = 5 * time.Minute
+
+// DeliveryStatus is where a notification stands on one channel
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"  // gave up after the last attempt
+	DeliverySkipped DeliveryStatus = "skipped" // the recipient switched the channel off
+)
+
+var (
+	ErrDeliveryNotFound = errors.New("notification delivery not found")
+	// ErrUndeliverable marks a failure that retrying cannot fix, e.g. a recipient
+	// without a phone number for an SMS
+	ErrUndeliverable = errors.New("notification cannot be delivered on this channel")
+)
+
+// Delivery is a notification's delivery on one external channel, with its attempts
+type Delivery struct {
+	ID             string         `json:"id"`
+	NotificationID NotificationID `json:"notification_id"`
+	Channel        string         `json:"channel"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastError      *string        `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	SentAt         *time.Time     `json:"sent_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// RetryPolicy is how often and how far apart failed deliveries are attempted again
+type RetryPolicy struct {
+	MaxAttempts int
+	// Backoff is the wait after the first failed attempt; it doubles with every
+	// further one
+	Backoff time.Duration
+}
+
+// ExternalChannels returns the channels of channels that need a Delivery, without
+// duplicates
+func ExternalChannels(channels []string) []string {
+	external := make([]string, 0, len(channels))
+	seen := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		if channel == ChannelInApp || channel == "" || seen[channel] {
+			continue
+		}
+		seen[channel] = true
+		external = append(external, channel)
+	}
+	return external
+}
+
+// Succeed records a successful attempt
+func (d *Delivery) Succeed(at time.Time) {
+	d.Status = DeliverySent
+	d.LastError = nil
+	d.SentAt = &at
+	d.UpdatedAt = at
+}
+
+// Skip records that the delivery was not attempted because the recipient does not
+// want it. The claim did not make an attempt, so it is not counted.
+func (d *Delivery) Skip(reason string, at time.Time) {
+	d.Status = DeliverySkipped
+	d.Attempts = max(d.Attempts-1, 0)
+	d.LastError = &reason
+	d.UpdatedAt = at
+}
+
+// Defer puts the delivery off until the given time without counting an attempt, e.g.
+// until the recipient's quiet hours end
+func (d *Delivery) Defer(until, at time.Time) {
+	d.Status = DeliveryPending
+	d.Attempts = max(d.Attempts-1, 0)
+	d.NextAttemptAt = until
+	d.UpdatedAt = at
+}
+
+// Fail records a failed attempt. The delivery is attempted again after the policy's
+// backoff unless its attempts are used up or err is ErrUndeliverable.
+func (d *Delivery) Fail(err error, at time.Time, policy RetryPolicy) {
+	message := err.Error()
+	d.LastError = &message
+	d.UpdatedAt = at
+
+	if errors.Is(err, ErrUndeliverable) || d.Attempts >= policy.MaxAttempts {
+		d.Status = DeliveryFailed
+		return
+	}
+
+	d.Status = DeliveryPending
+	d.NextAttemptAt = at.Add(policy.Backoff << max(d.Attempts-1, 0))
+}
@@ -19,6 +19,10 @@ type Notification struct {
 	isRead      bool
 	channels    []string
 	data        map[string]interface{}
+	appointmentID *string
+	template    TemplateKey
+	smsBody     string
+	emailHTML   string
 	scheduledFor *time.Time
 	sentAt      *time.Time
 	createdAt   time.Time
@@ -85,6 +89,24 @@ func NewNotification(
 	}
 }
 
+// NewTemplatedNotification creates a notification from a rendered template. Its title
+// and body are what the user sees in app; the template's SMS and email bodies are kept
+// for delivery on those channels.
+func NewTemplatedNotification(
+	userID shared.UserID,
+	rendered *Rendered,
+	notificationType NotificationType,
+	priority Priority,
+	channels []string,
+	data map[string]interface{},
+) *Notification {
+	n := NewNotification(userID, rendered.Title, rendered.Body, notificationType, priority, channels, data)
+	n.template = rendered.Key
+	n.smsBody = rendered.SMSBody
+	n.emailHTML = rendered.EmailHTML
+	return n
+}
+
 // Getters
 func (n *Notification) ID() NotificationID {
 	return n.id
@@ -122,6 +144,28 @@ func (n *Notification) Data() map[string]interface{} {
 	return n.data
 }
 
+// AppointmentID returns the appointment a reminder belongs to, if any
+func (n *Notification) AppointmentID() *string {
+	return n.appointmentID
+}
+
+// Template returns the template the notification was rendered from, empty for a
+// free-form notification
+func (n *Notification) Template() TemplateKey {
+	return n.template
+}
+
+// SMSBody returns the text sent by SMS, empty for a free-form notification, which is
+// sent as its message
+func (n *Notification) SMSBody() string {
+	return n.smsBody
+}
+
+// EmailHTML returns the HTML body of the email, empty for a plain-text email
+func (n *Notification) EmailHTML() string {
+	return n.emailHTML
+}
+
 func (n *Notification) ScheduledFor() *time.Time {
 	return n.scheduledFor
 }
@@ -148,3 +192,51 @@ func (n *Notification) MarkAsUnread() {
 func (n *Notification) UpdateData(data map[string]interface{}) {
 	n.data = data
 }
+
+// ScheduleFor defers delivery of the notification until at. Scheduled notifications
+// stay hidden from the user until the dispatcher has sent them.
+func (n *Notification) ScheduleFor(at time.Time) {
+	n.scheduledFor = &at
+}
+
+// LinkAppointment ties the notification to an appointment, so it can be replaced or
+// withdrawn when the appointment changes
+func (n *Notification) LinkAppointment(appointmentID string) {
+	n.appointmentID = &appointmentID
+}
+
+// Reconstruction for repository
+func ReconstructNotification(
+	id NotificationID,
+	userID shared.UserID,
+	title, message string,
+	notificationType NotificationType,
+	priority Priority,
+	isRead bool,
+	channels []string,
+	data map[string]interface{},
+	appointmentID *string,
+	template TemplateKey,
+	smsBody, emailHTML string,
+	scheduledFor, sentAt *time.Time,
+	createdAt time.Time,
+) *Notification {
+	return &Notification{
+		id:               id,
+		userID:           userID,
+		title:            title,
+		message:          message,
+		notificationType: notificationType,
+		priority:         priority,
+		isRead:           isRead,
+		channels:         channels,
+		data:             data,
+		appointmentID:    appointmentID,
+		template:         template,
+		smsBody:          smsBody,
+		emailHTML:        emailHTML,
+		scheduledFor:     scheduledFor,
+		sentAt:           sentAt,
+		createdAt:        createdAt,
+	}
+}
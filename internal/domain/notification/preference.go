@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrPreferencesNotFound = errors.New("notification preferences not found")
+	ErrInvalidTimezone     = errors.New("timezone must be an IANA time zone, e.g. Asia/Jakarta")
+	ErrInvalidQuietHours   = errors.New("quiet hours must be two different times of day in HH:MM format")
+	ErrInvalidPreference   = errors.New("invalid notification preference")
+)
+
+// Preferences are what a user wants to receive on which channel, and when. Users
+// without saved preferences use DefaultPreferences, which allow everything at any time.
+type Preferences struct {
+	UserID   string `json:"user_id"`
+	Timezone string `json:"timezone"` // IANA time zone the quiet hours are in
+	// QuietHours, if set, hold back external deliveries that are not urgent until they end
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+	// Channels switches external channels off per notification type. Types and channels
+	// that are not listed are on.
+	Channels  map[NotificationType]map[string]bool `json:"channels,omitempty"`
+	CreatedAt time.Time                            `json:"created_at"`
+	UpdatedAt time.Time                            `json:"updated_at"`
+}
+
+// QuietHours is a daily period, in the user's timezone, that may span midnight
+type QuietHours struct {
+	Start string `json:"start"` // HH:MM
+	End   string `json:"end"`   // HH:MM
+}
+
+// DefaultPreferences returns the preferences of a user who has not saved any
+func DefaultPreferences(userID, timezone string) *Preferences {
+	return &Preferences{
+		UserID:   userID,
+		Timezone: timezone,
+	}
+}
+
+// IsValid reports whether the notification type is known
+func (t NotificationType) IsValid() bool {
+	switch t {
+	case NotificationTypeAppointment, NotificationTypePatient, NotificationTypeAlert, NotificationTypeMessage,
+		NotificationTypeSystem, NotificationTypeSchedule, NotificationTypeLab, NotificationTypeEmergency:
+		return true
+	}
+	return false
+}
+
+// IsUrgent reports whether a notification of this type and priority is delivered
+// during quiet hours
+func IsUrgent(notificationType NotificationType, priority Priority) bool {
+	return priority == PriorityCritical || notificationType == NotificationTypeEmergency
+}
+
+// Validate checks the timezone, the quiet hours and that only known types and external
+// channels are switched
+func (p *Preferences) Validate() error {
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "" {
+		return ErrInvalidTimezone
+	}
+
+	if p.QuietHours != nil {
+		start, errStart := time.Parse("15:04", p.QuietHours.Start)
+		end, errEnd := time.Parse("15:04", p.QuietHours.End)
+		if errStart != nil || errEnd != nil || start.Equal(end) {
+			return ErrInvalidQuietHours
+		}
+	}
+
+	for notificationType, channels := range p.Channels {
+		if !notificationType.IsValid() {
+			return fmt.Errorf("%w: unknown notification type %q", ErrInvalidPreference, notificationType)
+		}
+		for channel := range channels {
+			switch channel {
+			case ChannelEmail, ChannelSMS, ChannelWebhook:
+			default:
+				return fmt.Errorf("%w: %q is not an external channel", ErrInvalidPreference, channel)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether the user receives notifications of a type on a channel
+func (p *Preferences) Allows(notificationType NotificationType, channel string) bool {
+	enabled, ok := p.Channels[notificationType][channel]
+	return !ok || enabled
+}
+
+// QuietUntil returns when the quiet hours around at end, and false if at is outside them
+func (p *Preferences) QuietUntil(at time.Time) (time.Time, bool) {
+	if p.QuietHours == nil {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, errStart := time.Parse("15:04", p.QuietHours.Start)
+	end, errEnd := time.Parse("15:04", p.QuietHours.End)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, false
+	}
+
+	local := at.In(loc)
+	year, month, day := local.Date()
+	startsAt := time.Date(year, month, day, start.Hour(), start.Minute(), 0, 0, loc)
+	endsAt := time.Date(year, month, day, end.Hour(), end.Minute(), 0, 0, loc)
+
+	if startsAt.Before(endsAt) {
+		// Within one day, e.g. 13:00-15:00
+		if !local.Before(startsAt) && local.Before(endsAt) {
+			return endsAt, true
+		}
+		return time.Time{}, false
+	}
+
+	// Across midnight, e.g. 22:00-07:00
+	if local.Before(endsAt) {
+		return endsAt, true
+	}
+	if !local.Before(startsAt) {
+		return endsAt.AddDate(0, 0, 1), true
+	}
+	return time.Time{}, false
+}
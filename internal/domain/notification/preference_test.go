@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreferencesQuietUntil(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, jakarta)
+	}
+
+	tests := []struct {
+		name      string
+		quiet     *QuietHours
+		at        time.Time
+		wantUntil time.Time
+		wantQuiet bool
+	}{
+		{name: "no quiet hours", quiet: nil, at: at(2, 23, 0)},
+		{name: "before a daytime period", quiet: &QuietHours{Start: "13:00", End: "15:00"}, at: at(2, 12, 59)},
+		{name: "at the start of a daytime period", quiet: &QuietHours{Start: "13:00", End: "15:00"}, at: at(2, 13, 0), wantUntil: at(2, 15, 0), wantQuiet: true},
+		{name: "at the end of a daytime period", quiet: &QuietHours{Start: "13:00", End: "15:00"}, at: at(2, 15, 0)},
+		{name: "evening before midnight", quiet: &QuietHours{Start: "22:00", End: "07:00"}, at: at(2, 23, 30), wantUntil: at(3, 7, 0), wantQuiet: true},
+		{name: "right at the start across midnight", quiet: &QuietHours{Start: "22:00", End: "07:00"}, at: at(2, 22, 0), wantUntil: at(3, 7, 0), wantQuiet: true},
+		{name: "early morning after midnight", quiet: &QuietHours{Start: "22:00", End: "07:00"}, at: at(3, 2, 15), wantUntil: at(3, 7, 0), wantQuiet: true},
+		{name: "at the end across midnight", quiet: &QuietHours{Start: "22:00", End: "07:00"}, at: at(3, 7, 0)},
+		{name: "daytime outside a period across midnight", quiet: &QuietHours{Start: "22:00", End: "07:00"}, at: at(3, 12, 0)},
+		{name: "last day of the month rolls over", quiet: &QuietHours{Start: "22:00", End: "07:00"}, at: at(31, 23, 0), wantUntil: time.Date(2026, 4, 1, 7, 0, 0, 0, jakarta), wantQuiet: true},
+		{
+			name:      "instants are read in the user's zone",
+			quiet:     &QuietHours{Start: "22:00", End: "07:00"},
+			at:        time.Date(2026, 3, 2, 16, 30, 0, 0, time.UTC), // 23:30 in Jakarta
+			wantUntil: at(3, 7, 0),
+			wantQuiet: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Preferences{Timezone: "Asia/Jakarta", QuietHours: tt.quiet}
+
+			until, quiet := p.QuietUntil(tt.at)
+			if quiet != tt.wantQuiet || !until.Equal(tt.wantUntil) {
+				t.Errorf("QuietUntil(%v) = %v, %v, want %v, %v", tt.at, until, quiet, tt.wantUntil, tt.wantQuiet)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ package notification
 
 import (
 	"context"
+	"time"
+
 	"medika-backend/internal/domain/shared"
 )
 
 // Repository defines the interface for notification data operations
 type Repository interface {
-	// Create creates a new notification
+	// Create creates a new notification and, unless it is scheduled, a pending Delivery
+	// for each of its external channels
 	Create(ctx context.Context, notification *Notification) error
 
 	// FindByID finds a notification by ID
@@ -33,6 +36,46 @@ type Repository interface {
 
 	// DeleteByUserID deletes all notifications for a user
 	DeleteByUserID(ctx context.Context, userID shared.UserID) error
+
+	// ReplaceScheduled withdraws the unsent scheduled notifications of an appointment
+	// and stores the given ones in their place
+	ReplaceScheduled(ctx context.Context, appointmentID string, scheduled []*Notification) error
+
+	// ClaimDue stamps sent_at on up to limit scheduled notifications that are due at
+	// now, creates their pending deliveries and returns them. Concurrent dispatchers
+	// never claim the same notification.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Notification, error)
+
+	// ClaimDeliveries counts an attempt on up to limit pending deliveries due at now and
+	// reserves them for DeliveryLease. Concurrent dispatchers never claim the same delivery.
+	ClaimDeliveries(ctx context.Context, now time.Time, limit int) ([]*Delivery, error)
+
+	// RecordDelivery stores the outcome of a delivery attempt, or of skipping or
+	// deferring it
+	RecordDelivery(ctx context.Context, delivery *Delivery) error
+
+	// FindDeliveries returns a notification's deliveries
+	FindDeliveries(ctx context.Context, id NotificationID) ([]*Delivery, error)
+
+	// GetTemplate returns an organization's override of a template, or
+	// ErrTemplateNotFound when it uses the built-in one
+	GetTemplate(ctx context.Context, organizationID string, key TemplateKey, locale Locale) (*Template, error)
+
+	// FindTemplates returns all of an organization's template overrides
+	FindTemplates(ctx context.Context, organizationID string) ([]*Template, error)
+
+	// SaveTemplate creates or replaces an organization's override of a template
+	SaveTemplate(ctx context.Context, template *Template) error
+
+	// DeleteTemplate removes an organization's override of a template, returning
+	// ErrTemplateNotFound when there is none
+	DeleteTemplate(ctx context.Context, organizationID string, key TemplateKey, locale Locale) error
+
+	// GetPreferences returns a user's saved preferences, or ErrPreferencesNotFound
+	GetPreferences(ctx context.Context, userID string) (*Preferences, error)
+
+	// SavePreferences creates or replaces a user's preferences
+	SavePreferences(ctx context.Context, preferences *Preferences) error
 }
 
 // NotificationFilters represents filters for notification queries
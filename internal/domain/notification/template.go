@@ -0,0 +1,315 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"text/template"
+	"time"
+
+	"medika-backend/internal/domain/shared"
+)
+
+// TemplateKey names the event a notification template is written for
+type TemplateKey string
+
+const (
+	TemplateAppointmentBooked    TemplateKey = "appointment_booked" // to the doctor
+	TemplateAppointmentConfirmed TemplateKey = "appointment_confirmed"
+	TemplateAppointmentReminder  TemplateKey = "appointment_reminder"
+	TemplateAppointmentCancelled TemplateKey = "appointment_cancelled"
+	TemplateQueueUpNext          TemplateKey = "queue_up_next"
+	TemplateQueueCalled          TemplateKey = "queue_called"
+	TemplateLabReady             TemplateKey = "lab_ready"
+	TemplateWaitlistSlotOffered  TemplateKey = "waitlist_slot_offered"
+	TemplateWelcome              TemplateKey = "welcome"
+)
+
+// Locale is the language a template is written in
+type Locale string
+
+const (
+	LocaleIndonesian Locale = "id"
+	LocaleEnglish    Locale = "en"
+)
+
+var (
+	ErrTemplateNotFound   = errors.New("notification template not found")
+	ErrUnknownTemplate    = errors.New("unknown notification template")
+	ErrUnsupportedLocale  = errors.New("locale must be id or en")
+	ErrInvalidTemplate    = errors.New("invalid notification template")
+	ErrTemplateIncomplete = errors.New("notification template needs a title and a body")
+)
+
+// Template is the text of the notifications for one event in one locale. Its parts
+// are Go templates over the event's variables, e.g. {{.doctor_name}}. Built-in
+// templates have no organization; an organization can override them with its own.
+type Template struct {
+	Key            TemplateKey `json:"key"`
+	Locale         Locale      `json:"locale"`
+	OrganizationID *string     `json:"organization_id,omitempty"`
+	Title          string      `json:"title"`
+	Body           string      `json:"body"`                 // in app, and on any channel without its own body
+	SMSBody        string      `json:"sms_body,omitempty"`   // short text for SMS
+	EmailHTML      string      `json:"email_html,omitempty"` // HTML email body; variables are escaped
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// Rendered is a template filled in with an event's variables
+type Rendered struct {
+	Key       TemplateKey
+	Title     string
+	Body      string
+	SMSBody   string // Body when the template has no SMS body
+	EmailHTML string // empty when the template has no HTML body
+}
+
+// Notice is a notification for an event, written by the event's template
+type Notice struct {
+	UserID         shared.UserID
+	OrganizationID string // whose template overrides apply; empty for the built-in templates
+	Template       TemplateKey
+	Locale         Locale // empty for the default locale
+	Vars           map[string]string
+	Type           NotificationType
+	Priority       Priority
+	Channels       []string
+	Data           map[string]interface{}
+}
+
+// TemplateVariables lists, per event, the variables its templates can use
+var TemplateVariables = map[TemplateKey][]string{
+	TemplateAppointmentBooked:    {"patient_name", "date", "time"},
+	TemplateAppointmentConfirmed: {"doctor_name", "date", "time"},
+	TemplateAppointmentReminder:  {"date", "time"},
+	TemplateAppointmentCancelled: {"date", "time"},
+	TemplateQueueUpNext:          {"ticket_number"},
+	TemplateQueueCalled:          {"ticket_number"},
+	TemplateLabReady:             {"test_name"},
+	TemplateWaitlistSlotOffered:  {"date", "time", "hold_until"},
+	TemplateWelcome:              {"name"},
+}
+
+// TemplateKeys returns the known events, sorted
+func TemplateKeys() []TemplateKey {
+	keys := make([]TemplateKey, 0, len(TemplateVariables))
+	for key := range TemplateVariables {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Locales returns the supported locales
+func Locales() []Locale {
+	return []Locale{LocaleIndonesian, LocaleEnglish}
+}
+
+// IsValid reports whether the key names a known event
+func (k TemplateKey) IsValid() bool {
+	_, ok := TemplateVariables[k]
+	return ok
+}
+
+// IsValid reports whether the locale is supported
+func (l Locale) IsValid() bool {
+	return l == LocaleIndonesian || l == LocaleEnglish
+}
+
+// Validate checks that the template is for a known event and locale, has a title and a
+// body, and that every part parses
+func (t *Template) Validate() error {
+	if !t.Key.IsValid() {
+		return fmt.Errorf("%w: %q", ErrUnknownTemplate, t.Key)
+	}
+	if !t.Locale.IsValid() {
+		return ErrUnsupportedLocale
+	}
+	if t.Title == "" || t.Body == "" {
+		return ErrTemplateIncomplete
+	}
+
+	// Rendering with every variable set catches syntax errors and unknown functions
+	vars := make(map[string]string)
+	for _, name := range TemplateVariables[t.Key] {
+		vars[name] = name
+	}
+	_, err := t.Render(vars)
+	return err
+}
+
+// Render fills in the template with vars. Variables missing from vars render empty.
+func (t *Template) Render(vars map[string]string) (*Rendered, error) {
+	rendered := &Rendered{Key: t.Key}
+
+	var err error
+	if rendered.Title, err = renderText("title", t.Title, vars); err != nil {
+		return nil, err
+	}
+	if rendered.Body, err = renderText("body", t.Body, vars); err != nil {
+		return nil, err
+	}
+
+	rendered.SMSBody = rendered.Body
+	if t.SMSBody != "" {
+		if rendered.SMSBody, err = renderText("sms_body", t.SMSBody, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.EmailHTML != "" {
+		tmpl, err := htmltemplate.New("email_html").Option("missingkey=zero").Parse(t.EmailHTML)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		rendered.EmailHTML = b.String()
+	}
+
+	return rendered, nil
+}
+
+func renderText(name, text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, name, err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, name, err)
+	}
+	return b.String(), nil
+}
+
+// BuiltinTemplate returns the template shipped for an event in a locale
+func BuiltinTemplate(key TemplateKey, locale Locale) (*Template, bool) {
+	tmpl, ok := builtinTemplates[key][locale]
+	if !ok {
+		return nil, false
+	}
+	tmpl.Key = key
+	tmpl.Locale = locale
+	return &tmpl, true
+}
+
+var builtinTemplates = map[TemplateKey]map[Locale]Template{
+	TemplateAppointmentBooked: {
+		LocaleIndonesian: {
+			Title:   "Janji temu baru",
+			Body:    "{{.patient_name}} membuat janji temu pada {{.date}} pukul {{.time}}.",
+			SMSBody: "Janji temu baru: {{.patient_name}}, {{.date}} {{.time}}.",
+		},
+		LocaleEnglish: {
+			Title:   "New booking",
+			Body:    "{{.patient_name}} booked an appointment on {{.date}} at {{.time}}.",
+			SMSBody: "New booking: {{.patient_name}}, {{.date}} {{.time}}.",
+		},
+	},
+	TemplateAppointmentConfirmed: {
+		LocaleIndonesian: {
+			Title:     "Janji temu dikonfirmasi",
+			Body:      "Janji temu Anda dengan {{.doctor_name}} pada {{.date}} pukul {{.time}} telah dikonfirmasi.",
+			SMSBody:   "Janji temu {{.date}} {{.time}} dengan {{.doctor_name}} dikonfirmasi.",
+			EmailHTML: "<p>Janji temu Anda dengan <strong>{{.doctor_name}}</strong> pada <strong>{{.date}}</strong> pukul <strong>{{.time}}</strong> telah dikonfirmasi.</p>",
+		},
+		LocaleEnglish: {
+			Title:     "Appointment confirmed",
+			Body:      "Your appointment with {{.doctor_name}} on {{.date}} at {{.time}} is confirmed.",
+			SMSBody:   "Appointment {{.date}} {{.time}} with {{.doctor_name}} confirmed.",
+			EmailHTML: "<p>Your appointment with <strong>{{.doctor_name}}</strong> on <strong>{{.date}}</strong> at <strong>{{.time}}</strong> is confirmed.</p>",
+		},
+	},
+	TemplateAppointmentReminder: {
+		LocaleIndonesian: {
+			Title:     "Pengingat janji temu",
+			Body:      "Anda memiliki janji temu pada {{.date}} pukul {{.time}}.",
+			SMSBody:   "Pengingat: janji temu {{.date}} {{.time}}.",
+			EmailHTML: "<p>Anda memiliki janji temu pada <strong>{{.date}}</strong> pukul <strong>{{.time}}</strong>.</p>",
+		},
+		LocaleEnglish: {
+			Title:     "Appointment reminder",
+			Body:      "You have an appointment on {{.date}} at {{.time}}.",
+			SMSBody:   "Reminder: appointment {{.date}} {{.time}}.",
+			EmailHTML: "<p>You have an appointment on <strong>{{.date}}</strong> at <strong>{{.time}}</strong>.</p>",
+		},
+	},
+	TemplateAppointmentCancelled: {
+		LocaleIndonesian: {
+			Title:   "Janji temu dibatalkan",
+			Body:    "Janji temu Anda pada {{.date}} pukul {{.time}} telah dibatalkan.",
+			SMSBody: "Janji temu {{.date}} {{.time}} dibatalkan.",
+		},
+		LocaleEnglish: {
+			Title:   "Appointment cancelled",
+			Body:    "Your appointment on {{.date}} at {{.time}} has been cancelled.",
+			SMSBody: "Appointment {{.date}} {{.time}} cancelled.",
+		},
+	},
+	TemplateQueueUpNext: {
+		LocaleIndonesian: {
+			Title:   "Anda berikutnya",
+			Body:    "Nomor antrean {{.ticket_number}} akan segera dipanggil. Mohon bersiap.",
+			SMSBody: "Antrean {{.ticket_number}}: Anda berikutnya.",
+		},
+		LocaleEnglish: {
+			Title:   "You're next",
+			Body:    "Ticket {{.ticket_number}} will be called shortly. Please get ready.",
+			SMSBody: "Ticket {{.ticket_number}}: you're next.",
+		},
+	},
+	TemplateQueueCalled: {
+		LocaleIndonesian: {
+			Title:   "Giliran Anda",
+			Body:    "Nomor antrean {{.ticket_number}} dipanggil sekarang.",
+			SMSBody: "Antrean {{.ticket_number}} dipanggil.",
+		},
+		LocaleEnglish: {
+			Title:   "It's your turn",
+			Body:    "Ticket {{.ticket_number}} is being called now.",
+			SMSBody: "Ticket {{.ticket_number}} called.",
+		},
+	},
+	TemplateLabReady: {
+		LocaleIndonesian: {
+			Title:     "Hasil lab tersedia",
+			Body:      "Hasil {{.test_name}} Anda sudah tersedia.",
+			EmailHTML: "<p>Hasil <strong>{{.test_name}}</strong> Anda sudah tersedia.</p>",
+		},
+		LocaleEnglish: {
+			Title:     "Lab results ready",
+			Body:      "Your {{.test_name}} results are ready.",
+			EmailHTML: "<p>Your <strong>{{.test_name}}</strong> results are ready.</p>",
+		},
+	},
+	TemplateWaitlistSlotOffered: {
+		LocaleIndonesian: {
+			Title:   "Slot janji temu tersedia",
+			Body:    "Janji temu lebih awal pada {{.date}} pukul {{.time}} tersedia. Slot ini ditahan untuk Anda hingga {{.hold_until}}.",
+			SMSBody: "Slot {{.date}} {{.time}} ditahan untuk Anda hingga {{.hold_until}}.",
+		},
+		LocaleEnglish: {
+			Title:   "Appointment slot available",
+			Body:    "An earlier appointment on {{.date}} at {{.time}} is available. It is held for you until {{.hold_until}}.",
+			SMSBody: "Slot {{.date}} {{.time}} held for you until {{.hold_until}}.",
+		},
+	},
+	TemplateWelcome: {
+		LocaleIndonesian: {
+			Title:     "Selamat datang di Medika",
+			Body:      "Halo {{.name}}, akun Anda sudah siap.",
+			EmailHTML: "<p>Halo {{.name}},</p><p>Akun Medika Anda sudah siap.</p>",
+		},
+		LocaleEnglish: {
+			Title:     "Welcome to Medika",
+			Body:      "Hi {{.name}}, your account is ready.",
+			EmailHTML: "<p>Hi {{.name}},</p><p>Your Medika account is ready.</p>",
+		},
+	},
+}
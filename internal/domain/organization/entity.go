@@ -14,6 +14,7 @@ type Organization struct {
 	Email         string          `json:"email"`
 	Website       *string         `json:"website,omitempty"`
 	BusinessHours []BusinessHours `json:"businessHours"`
+	Timezone      string          `json:"timezone,omitempty"` // IANA time zone of the business hours and schedules
 	IsActive      bool            `json:"isActive"`
 	CreatedAt     time.Time       `json:"createdAt"`
 	UpdatedAt     time.Time       `json:"updatedAt"`
@@ -26,6 +27,19 @@ type BusinessHours struct {
 	IsOpen bool   `json:"isOpen"`
 }
 
+// Location returns the time zone the organization's hours are in, the server's own
+// if it has none or an unknown one
+func (o *Organization) Location() *time.Location {
+	if o.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(o.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
 // Repository interface
 type Repository interface {
 	Create(ctx context.Context, organization *Organization) error
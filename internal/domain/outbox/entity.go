@@ -0,0 +1,121 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Lease is how long a claimed message is reserved for the relay that claimed it. A
+// message whose relay died is published again after it.
+const Lease = 5 * time.Minute
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrUndecodable marks a message that no retry can publish, e.g. one of an event
+	// type nobody knows how to decode
+	ErrUndecodable = errors.New("event cannot be decoded")
+)
+
+// Message is a domain event waiting to be published. It is stored in the transaction
+// that made the change the event is about, and deleted once its handlers succeeded.
+type Message struct {
+	ID             string          `json:"id"`
+	EventType      string          `json:"event_type"`
+	OrganizationID string          `json:"organization_id,omitempty"` // the organization the event is about, if any
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	Delivered      []string        `json:"delivered,omitempty"` // the subscribers that handled it already
+	LastError      *string         `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// DeadLetter is a message the relay gave up on. It stays until it is replayed.
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	EventType      string          `json:"event_type"`
+	OrganizationID string          `json:"organization_id,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	Delivered      []string        `json:"delivered,omitempty"` // the subscribers that handled it before it failed
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"` // when the event was published
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+// RetryPolicy is how often and how far apart failed messages are published again
+type RetryPolicy struct {
+	MaxAttempts int
+	// Backoff is the wait after the first failed attempt; it doubles with every
+	// further one
+	Backoff time.Duration
+}
+
+// Repository interface
+type Repository interface {
+	// Append stores a message, in the transaction ctx carries if any
+	Append(ctx context.Context, msg *Message) error
+	// Claim reserves up to limit due messages, oldest first, for Lease and counts an
+	// attempt for each
+	Claim(ctx context.Context, now time.Time, limit int) ([]*Message, error)
+	// Delete removes a published message
+	Delete(ctx context.Context, id string) error
+	// Retry stores a failed attempt and who the message was delivered to; it is claimed
+	// again at its NextAttemptAt
+	Retry(ctx context.Context, msg *Message) error
+	// Bury moves a message to the dead letters
+	Bury(ctx context.Context, msg *Message, at time.Time) error
+
+	// FindDeadLetters and CountDeadLetters see only the dead letters about the given
+	// organization
+	FindDeadLetters(ctx context.Context, organizationID, eventType string, limit, offset int) ([]*DeadLetter, error)
+	CountDeadLetters(ctx context.Context, organizationID, eventType string) (int, error)
+	// Replay moves a dead letter of the given organization back to the outbox, due at,
+	// with its attempts reset. It is not delivered again to the subscribers that
+	// handled it.
+	Replay(ctx context.Context, organizationID, id string, at time.Time) (*Message, error)
+}
+
+// NewMessage returns a message, due now, for an encoded event of the given type
+func NewMessage(eventType string, payload json.RawMessage, now time.Time) *Message {
+	return &Message{
+		EventType:     eventType,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Fail records a failed attempt and reports whether the message should be given up on:
+// its attempts are used up or err is ErrUndecodable. Otherwise it is due again after
+// the policy's backoff.
+func (m *Message) Fail(err error, at time.Time, policy RetryPolicy) bool {
+	message := err.Error()
+	m.LastError = &message
+	m.UpdatedAt = at
+
+	if errors.Is(err, ErrUndecodable) || m.Attempts >= policy.MaxAttempts {
+		return true
+	}
+
+	m.NextAttemptAt = at.Add(policy.Backoff << max(m.Attempts-1, 0))
+	return false
+}
+
+// IsDeliveredTo reports whether subscriber handled the message already
+func (m *Message) IsDeliveredTo(subscriber string) bool {
+	return slices.Contains(m.Delivered, subscriber)
+}
+
+// MarkDelivered records that subscriber handled the message, so that it is skipped
+// when the message is published again
+func (m *Message) MarkDelivered(subscriber string) {
+	if !m.IsDeliveredTo(subscriber) {
+		m.Delivered = append(m.Delivered, subscriber)
+	}
+}
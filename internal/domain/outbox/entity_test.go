@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMessageFail(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxAttempts: 4, Backoff: time.Minute}
+	failure := errors.New("smtp unavailable")
+
+	tests := []struct {
+		name     string
+		attempts int
+		err      error
+		wantBury bool
+		wantNext time.Time
+	}{
+		{name: "first attempt waits one backoff", attempts: 1, err: failure, wantNext: now.Add(time.Minute)},
+		{name: "backoff doubles", attempts: 2, err: failure, wantNext: now.Add(2 * time.Minute)},
+		{name: "and doubles again", attempts: 3, err: failure, wantNext: now.Add(4 * time.Minute)},
+		{name: "last attempt buries", attempts: 4, err: failure, wantBury: true},
+		{name: "undecodable buries at once", attempts: 1, err: fmt.Errorf("%w: unknown event type", ErrUndecodable), wantBury: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := NewMessage("appointment.scheduled", []byte(`{}`), now.Add(-time.Hour))
+			msg.Attempts = tt.attempts
+
+			if bury := msg.Fail(tt.err, now, policy); bury != tt.wantBury {
+				t.Fatalf("Fail() = %v, want %v", bury, tt.wantBury)
+			}
+			if msg.LastError == nil || *msg.LastError != tt.err.Error() {
+				t.Errorf("LastError = %v, want %q", msg.LastError, tt.err.Error())
+			}
+			if !msg.UpdatedAt.Equal(now) {
+				t.Errorf("UpdatedAt = %v, want %v", msg.UpdatedAt, now)
+			}
+			if !tt.wantBury && !msg.NextAttemptAt.Equal(tt.wantNext) {
+				t.Errorf("NextAttemptAt = %v, want %v", msg.NextAttemptAt, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestMessageMarkDelivered(t *testing.T) {
+	msg := NewMessage("appointment.scheduled", []byte(`{}`), time.Now())
+
+	msg.MarkDelivered("*notifier.Service")
+	msg.MarkDelivered("*notifier.Service")
+	msg.MarkDelivered("*waitlist.Service")
+
+	if len(msg.Delivered) != 2 {
+		t.Errorf("Delivered = %v, want each subscriber once", msg.Delivered)
+	}
+	if !msg.IsDeliveredTo("*waitlist.Service") || msg.IsDeliveredTo("*realtime.QueueHub") {
+		t.Errorf("Delivered = %v", msg.Delivered)
+	}
+}
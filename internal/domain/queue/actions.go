@@ -0,0 +1,96 @@
+package queue
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidTransition = errors.New("queue entry cannot make this change in its current status")
+	ErrSameScope         = errors.New("queue entry is already in this queue")
+	ErrQueuePaused       = errors.New("queue is paused")
+)
+
+// State is the state of a queue as a whole. A paused queue keeps its patients but
+// none are called, e.g. during a doctor's break.
+type State struct {
+	OrganizationID string     `json:"organization_id"`
+	Scope          Scope      `json:"scope"`
+	Paused         bool       `json:"paused"`
+	Reason         *string    `json:"reason,omitempty"`
+	ChangedBy      *string    `json:"changed_by,omitempty"`
+	ChangedAt      *time.Time `json:"changed_at,omitempty"`
+}
+
+// Start begins the consultation of a called patient
+func (q *PatientQueue) Start(at time.Time) error {
+	return q.transition(QueueStatusInProgress, at, QueueStatusCalled)
+}
+
+// Complete ends a consultation in progress
+func (q *PatientQueue) Complete(at time.Time) error {
+	return q.transition(QueueStatusCompleted, at, QueueStatusInProgress)
+}
+
+// Skip marks a called patient who did not answer, so the next one can be called
+func (q *PatientQueue) Skip(at time.Time) error {
+	return q.transition(QueueStatusSkipped, at, QueueStatusCalled)
+}
+
+// Recall calls a skipped patient again. The call time restarts so their consultation
+// is not counted from the call they missed.
+func (q *PatientQueue) Recall(at time.Time) error {
+	if err := q.transition(QueueStatusCalled, at, QueueStatusSkipped); err != nil {
+		return err
+	}
+	q.CalledAt = &at
+	return nil
+}
+
+// Hold takes a waiting or called patient out of the line for a while, e.g. for a lab
+// draw
+func (q *PatientQueue) Hold(at time.Time) error {
+	return q.transition(QueueStatusOnHold, at, QueueStatusWaiting, QueueStatusCalled)
+}
+
+// Release returns a patient on hold to the line. Their triage level and arrival time
+// are unchanged, so they rank where they would have without the hold.
+func (q *PatientQueue) Release(at time.Time) error {
+	return q.transition(QueueStatusWaiting, at, QueueStatusOnHold)
+}
+
+// Transfer moves a patient who has not been seen yet to another queue, where they wait
+// with the triage level and arrival time that rank them in their current one
+func (q *PatientQueue) Transfer(to Scope, at time.Time) error {
+	if !to.IsValid() {
+		return ErrInvalidScope
+	}
+	if to == q.Scope() {
+		return ErrSameScope
+	}
+	if err := q.transition(QueueStatusWaiting, at, PendingStatuses...); err != nil {
+		return err
+	}
+
+	q.ScopeType = to.Type
+	q.ScopeID = to.ID
+	// A call in the old queue does not carry over
+	q.CalledAt = nil
+	return nil
+}
+
+// Cancel takes a patient who has not been seen yet out of the queue for good
+func (q *PatientQueue) Cancel(at time.Time) error {
+	return q.transition(QueueStatusCancelled, at, PendingStatuses...)
+}
+
+func (q *PatientQueue) transition(to string, at time.Time, from ...string) error {
+	for _, status := range from {
+		if q.Status == status {
+			q.SetStatus(to, at)
+			q.UpdatedAt = at
+			return nil
+		}
+	}
+	return ErrInvalidTransition
+}
@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LeftoverAction is what closing a queue day does with patients still waiting
+type LeftoverAction string
+
+const (
+	// LeftoverCancel cancels the leftover entries and their appointments
+	LeftoverCancel LeftoverAction = "cancel"
+	// LeftoverCarryOver keeps the leftover entries waiting for the next day, with a
+	// ticket of that day and their place in line
+	LeftoverCarryOver LeftoverAction = "carry_over"
+)
+
+var (
+	ErrClosingPolicyNotFound = errors.New("queue closing policy not found")
+	ErrInvalidCloseTime      = errors.New("close time must be a time of day in HH:MM format")
+	ErrInvalidLeftoverAction = errors.New("leftover action must be cancel or carry_over")
+	ErrAlreadyClosed         = errors.New("queue day is already closed")
+)
+
+// ClosingPolicy is when an organization's queues close for the day and what happens
+// to the patients left in them. Organizations without a saved policy use
+// DefaultClosingPolicy.
+type ClosingPolicy struct {
+	OrganizationID string         `json:"organization_id"`
+	Enabled        bool           `json:"enabled"`
+	CloseTime      string         `json:"close_time"` // HH:MM, in the organization's time zone
+	LeftoverAction LeftoverAction `json:"leftover_action"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Closure is the summary of one organization's closed queue day. The counts of the
+// day come from the queue event log; the leftover counts are what the close did.
+type Closure struct {
+	ID                 string         `json:"id"`
+	OrganizationID     string         `json:"organization_id"`
+	Day                time.Time      `json:"day"`
+	LeftoverAction     LeftoverAction `json:"leftover_action"`
+	Joined             int            `json:"joined"`
+	Completed          int            `json:"completed"`
+	Cancelled          int            `json:"cancelled"` // during the day, before the close
+	LeftoverCancelled  int            `json:"leftover_cancelled"`
+	CarriedOver        int            `json:"carried_over"`
+	InProgress         int            `json:"in_progress"` // still being seen at the close, left as they are
+	AverageWaitMinutes int            `json:"average_wait_minutes"`
+	ClosedAt           time.Time      `json:"closed_at"`
+}
+
+// DefaultClosingPolicy returns the policy of an organization that has not saved one
+func DefaultClosingPolicy(organizationID, closeTime string, action LeftoverAction) *ClosingPolicy {
+	return &ClosingPolicy{
+		OrganizationID: organizationID,
+		Enabled:        true,
+		CloseTime:      closeTime,
+		LeftoverAction: action,
+	}
+}
+
+// Validate checks the close time and leftover action
+func (p *ClosingPolicy) Validate() error {
+	if _, err := time.Parse("15:04", p.CloseTime); err != nil {
+		return ErrInvalidCloseTime
+	}
+	switch p.LeftoverAction {
+	case LeftoverCancel, LeftoverCarryOver:
+		return nil
+	}
+	return ErrInvalidLeftoverAction
+}
+
+// ClosesAt returns when the queues close on the given day
+func (p *ClosingPolicy) ClosesAt(day time.Time) (time.Time, error) {
+	closeTime, err := time.Parse("15:04", p.CloseTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidCloseTime, p.CloseTime)
+	}
+
+	year, month, date := day.Date()
+	return time.Date(year, month, date, closeTime.Hour(), closeTime.Minute(), 0, 0, day.Location()), nil
+}
@@ -2,19 +2,68 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// DefaultConsultationMinutes is how long a consultation is assumed to take until there
+// is enough history to estimate it
+const DefaultConsultationMinutes = 15
+
+// ScopeType is what a queue is kept for
+type ScopeType string
+
+const (
+	ScopeDoctor       ScopeType = "doctor"
+	ScopeRoom         ScopeType = "room"
+	ScopeServicePoint ScopeType = "service_point"
+)
+
+// Scope identifies one queue within an organization, e.g. a doctor's queue
+type Scope struct {
+	Type ScopeType `json:"type"`
+	ID   string    `json:"id"`
+}
+
+var (
+	ErrInvalidScope = errors.New("invalid queue scope")
+	ErrUnknownScope = errors.New("queue does not exist in this organization")
+	ErrQueueEmpty   = errors.New("no patients waiting in queue")
+	ErrNotFound     = errors.New("queue entry not found")
+)
+
 // PatientQueue represents a patient in the queue
 type PatientQueue struct {
-	ID                string    `json:"id"`
-	AppointmentID     string    `json:"appointment_id"`
-	OrganizationID    string    `json:"organization_id"`
-	Position          int       `json:"position"`
-	EstimatedWaitTime int       `json:"estimated_wait_time"` // minutes
-	Status            string    `json:"status"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                string      `json:"id"`
+	AppointmentID     string      `json:"appointment_id"`
+	OrganizationID    string      `json:"organization_id"`
+	ScopeType         ScopeType   `json:"scope_type"`
+	ScopeID           string      `json:"scope_id"`
+	TriageLevel       TriageLevel `json:"triage_level"`
+	TicketNumber      string      `json:"ticket_number,omitempty"` // e.g. A-014, issued on Create
+	Position          int         `json:"position"`
+	EstimatedWaitTime int         `json:"estimated_wait_time"` // minutes
+	EstimatedWaitLow  int         `json:"estimated_wait_low"`  // minutes, lower end of an 80% range
+	EstimatedWaitHigh int         `json:"estimated_wait_high"` // minutes, upper end of an 80% range
+	Status            string      `json:"status"`
+	CalledAt          *time.Time  `json:"called_at,omitempty"`
+	StartedAt         *time.Time  `json:"started_at,omitempty"`
+	CompletedAt       *time.Time  `json:"completed_at,omitempty"`
+	CarriedOverTo     *time.Time  `json:"carried_over_to,omitempty"` // queue day the entry was last carried over to
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// StatusChange is one status transition of a queue entry, as kept in the queue event
+// log for wait-time and throughput reporting
+type StatusChange struct {
+	ID             string    `json:"id"`
+	QueueID        string    `json:"queue_id"`
+	OrganizationID string    `json:"organization_id"`
+	FromStatus     string    `json:"from_status,omitempty"` // empty when the entry joined the queue
+	ToStatus       string    `json:"to_status"`
+	ActorID        *string   `json:"actor_id,omitempty"`
+	ChangedAt      time.Time `json:"changed_at"`
 }
 
 // QueueStatus constants
@@ -24,22 +73,103 @@ const (
 	QueueStatusInProgress = "in_progress"
 	QueueStatusCompleted  = "completed"
 	QueueStatusCancelled  = "cancelled"
+	QueueStatusSkipped    = "skipped" // called but did not answer; can be recalled
+	QueueStatusOnHold     = "on_hold" // stepped out, e.g. for a lab draw; keeps their priority
 )
 
-// Repository defines the interface for queue data operations
+// PendingStatuses are the statuses of entries still due to be seen
+var PendingStatuses = []string{QueueStatusWaiting, QueueStatusCalled, QueueStatusSkipped, QueueStatusOnHold}
+
+// Repository defines the interface for queue data operations. Changes to a queue are
+// serialized, and each one leaves the queues it touched renumbered.
 type Repository interface {
-	Create(ctx context.Context, queue *PatientQueue) error
+	// Create stores a new entry at the end of its queue, issues it the next ticket number
+	// of the queue and ranks it by triage level. Create, Update and CallNext log status
+	// changes, made by actorID if known, in the queue event log.
+	Create(ctx context.Context, queue *PatientQueue, actorID *string) error
 	GetByID(ctx context.Context, id string) (*PatientQueue, error)
 	GetByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*PatientQueue, error)
 	GetByAppointment(ctx context.Context, appointmentID string) (*PatientQueue, error)
 	GetByPatient(ctx context.Context, patientID string) (*PatientQueue, error)
 	GetPatientQueueWithDetails(ctx context.Context, patientID string) (*PatientQueueWithDetails, error)
-	Update(ctx context.Context, queue *PatientQueue) error
+	// Update stores an entry, or returns ErrNotFound
+	Update(ctx context.Context, queue *PatientQueue, actorID *string) error
+	// Delete removes an entry, or returns ErrNotFound
 	Delete(ctx context.Context, id string) error
 	CountByOrganization(ctx context.Context, organizationID string) (int, error)
-	GetNextInQueue(ctx context.Context, organizationID string) (*PatientQueue, error)
+	GetByScope(ctx context.Context, organizationID string, scope Scope, limit, offset int) ([]*PatientQueue, error)
+	CountByScope(ctx context.Context, organizationID string, scope Scope) (int, error)
+	// GetNextInQueue returns the waiting patient to call next, ranked by aged triage level
+	// and then arrival, or ErrQueueEmpty
+	GetNextInQueue(ctx context.Context, organizationID string, scope Scope) (*PatientQueue, error)
+	// CallNext marks the patient GetNextInQueue would return as called, so that two
+	// concurrent calls never call the same patient, or returns ErrQueueEmpty
+	CallNext(ctx context.Context, organizationID string, scope Scope, calledAt time.Time, actorID *string) (*PatientQueue, error)
+	// GetLastPosition returns the position of the last waiting patient, or 0 for an empty queue
+	GetLastPosition(ctx context.Context, organizationID string, scope Scope) (int, error)
+	// UpdatePosition renumbers the waiting patients of every queue in an organization and
+	// re-estimates their waits from the organization's consultation history
 	UpdatePosition(ctx context.Context, organizationID string) error
 	GetQueueStats(ctx context.Context, organizationID string) (*QueueStats, error)
+	// Retriage stores an entry's new triage level with its history and re-ranks its queue.
+	// It returns ErrNotWaiting if the entry was called or re-triaged in the meantime.
+	Retriage(ctx context.Context, change *TriageChange) error
+	GetTriageHistory(ctx context.Context, queueID string) ([]*TriageChange, error)
+	// GetStatusHistory returns an entry's status changes from the event log, oldest first
+	GetStatusHistory(ctx context.Context, queueID string) ([]*StatusChange, error)
+	// GetState returns whether a queue is paused; queues that were never paused are not
+	GetState(ctx context.Context, organizationID string, scope Scope) (*State, error)
+	// SetState pauses or resumes a queue. CallNext returns ErrQueuePaused while it is paused.
+	SetState(ctx context.Context, state *State) error
+	// GetClosingPolicy returns the organization's saved closing policy, or
+	// ErrClosingPolicyNotFound
+	GetClosingPolicy(ctx context.Context, organizationID string) (*ClosingPolicy, error)
+	// SaveClosingPolicy creates or replaces an organization's closing policy
+	SaveClosingPolicy(ctx context.Context, policy *ClosingPolicy) error
+	// FindUnclosed returns the active organizations whose queues were not closed for day
+	FindUnclosed(ctx context.Context, day time.Time) ([]string, error)
+	// CloseDay closes closure.Day for closure.OrganizationID: entries still pending from
+	// that day or before are cancelled or carried over to the next day as
+	// closure.LeftoverAction says, and closure is stored with the day's counts. It returns
+	// the queues whose entries changed, or ErrAlreadyClosed.
+	CloseDay(ctx context.Context, closure *Closure) ([]Scope, error)
+	// GetClosures returns an organization's closed days, latest first
+	GetClosures(ctx context.Context, organizationID string, limit, offset int) ([]*Closure, error)
+}
+
+// IsValid reports whether the scope names a known kind of queue and an owner
+func (s Scope) IsValid() bool {
+	switch s.Type {
+	case ScopeDoctor, ScopeRoom, ScopeServicePoint:
+		return s.ID != ""
+	}
+	return false
+}
+
+// Scope returns the queue the entry belongs to
+func (q *PatientQueue) Scope() Scope {
+	return Scope{Type: q.ScopeType, ID: q.ScopeID}
+}
+
+// SetStatus moves the entry to status, stamping when it was first called, started or
+// completed
+func (q *PatientQueue) SetStatus(status string, at time.Time) {
+	q.Status = status
+
+	var stamp **time.Time
+	switch status {
+	case QueueStatusCalled:
+		stamp = &q.CalledAt
+	case QueueStatusInProgress:
+		stamp = &q.StartedAt
+	case QueueStatusCompleted:
+		stamp = &q.CompletedAt
+	default:
+		return
+	}
+	if *stamp == nil {
+		*stamp = &at
+	}
 }
 
 // QueueStats represents aggregated queue statistics
@@ -0,0 +1,122 @@
+package queue
+
+import (
+	"math"
+	"time"
+)
+
+// EstimationWindow is how far back completed consultations are used to estimate waits
+const EstimationWindow = 30 * 24 * time.Hour
+
+// MinEstimateSamples is how many consultations a doctor, or a doctor and appointment
+// type, needs before their own durations are trusted over broader ones
+const MinEstimateSamples = 5
+
+// estimateConfidenceZ gives an 80% range around an estimate, assuming consultation
+// times are roughly normal
+const estimateConfidenceZ = 1.2816
+
+// DurationStats summarises how long consultations took, from the patient being called
+// to the consultation being completed, in minutes
+type DurationStats struct {
+	Samples int
+	Mean    float64
+	StdDev  float64
+}
+
+// defaultDurationStats is used when an organization has no usable history yet
+var defaultDurationStats = DurationStats{
+	Mean:   DefaultConsultationMinutes,
+	StdDev: DefaultConsultationMinutes / 3,
+}
+
+// DurationModel holds consultation statistics per doctor and appointment type, per
+// doctor, and for a whole organization
+type DurationModel struct {
+	byDoctorAndType map[[2]string]DurationStats
+	byDoctor        map[string]DurationStats
+	overall         *DurationStats
+}
+
+func NewDurationModel() *DurationModel {
+	return &DurationModel{
+		byDoctorAndType: make(map[[2]string]DurationStats),
+		byDoctor:        make(map[string]DurationStats),
+	}
+}
+
+// Add records statistics. An empty appointment type means the doctor's overall
+// statistics, and an empty doctor the organization's.
+func (m *DurationModel) Add(doctorID, appointmentType string, stats DurationStats) {
+	switch {
+	case doctorID == "":
+		m.overall = &stats
+	case appointmentType == "":
+		m.byDoctor[doctorID] = stats
+	default:
+		m.byDoctorAndType[[2]string{doctorID, appointmentType}] = stats
+	}
+}
+
+// For returns the statistics to expect for a consultation, falling back from the
+// doctor and appointment type to the doctor, the organization and finally
+// DefaultConsultationMinutes while there are too few samples
+func (m *DurationModel) For(doctorID, appointmentType string) DurationStats {
+	if stats, ok := m.byDoctorAndType[[2]string{doctorID, appointmentType}]; ok && stats.Samples >= MinEstimateSamples {
+		return stats
+	}
+	if stats, ok := m.byDoctor[doctorID]; ok && stats.Samples >= MinEstimateSamples {
+		return stats
+	}
+	if m.overall != nil && m.overall.Samples >= MinEstimateSamples {
+		return *m.overall
+	}
+	return defaultDurationStats
+}
+
+// Consultation is a queue entry as seen by the estimator
+type Consultation struct {
+	DoctorID        string
+	AppointmentType string
+	// CalledAt is when a patient being seen was called; nil for waiting patients
+	CalledAt *time.Time
+}
+
+// Estimate is the expected wait of a queue entry with an 80% range, in minutes
+type Estimate struct {
+	Minutes int
+	Low     int
+	High    int
+}
+
+// EstimateWaits estimates the wait of each waiting patient of one queue, given in
+// the order they will be called, behind the patients currently being seen
+func EstimateWaits(inProgress, waiting []Consultation, model *DurationModel, now time.Time) []Estimate {
+	var mean, variance float64
+
+	for _, c := range inProgress {
+		stats := model.For(c.DoctorID, c.AppointmentType)
+		remaining := stats.Mean
+		if c.CalledAt != nil {
+			remaining -= now.Sub(*c.CalledAt).Minutes()
+		}
+		mean += math.Max(remaining, 0)
+		variance += stats.StdDev * stats.StdDev
+	}
+
+	estimates := make([]Estimate, len(waiting))
+	for i, c := range waiting {
+		spread := estimateConfidenceZ * math.Sqrt(variance)
+		estimates[i] = Estimate{
+			Minutes: int(math.Round(mean)),
+			Low:     int(math.Round(math.Max(mean-spread, 0))),
+			High:    int(math.Round(mean + spread)),
+		}
+
+		stats := model.For(c.DoctorID, c.AppointmentType)
+		mean += stats.Mean
+		variance += stats.StdDev * stats.StdDev
+	}
+
+	return estimates
+}
@@ -0,0 +1,58 @@
+package queue
+
+import "time"
+
+// Queue event types, as broadcast to real-time subscribers
+const (
+	EventCreated          = "queue.created"
+	EventCalled           = "queue.called"
+	EventStarted          = "queue.started"
+	EventCompleted        = "queue.completed"
+	EventSkipped          = "queue.skipped"
+	EventHeld             = "queue.held"
+	EventTransferred      = "queue.transferred"
+	EventPositionsChanged = "queue.positions_changed"
+	EventPaused           = "queue.paused"
+	EventResumed          = "queue.resumed"
+)
+
+// QueueEvent is published whenever a queue changes. Entry is the entry the event is
+// about and is nil for EventPositionsChanged, EventPaused and EventResumed, which
+// cover the whole queue. EventTransferred is published for both the queue the entry
+// left and the one it joined.
+type QueueEvent struct {
+	Type           string        `json:"type"`
+	OrganizationID string        `json:"organization_id"`
+	Scope          Scope         `json:"scope"`
+	Entry          *PatientQueue `json:"entry,omitempty"`
+	OccurredAt     time.Time     `json:"occurred_at"`
+}
+
+func (e QueueEvent) EventType() string {
+	return e.Type
+}
+
+func (e QueueEvent) EventData() map[string]interface{} {
+	data := map[string]interface{}{
+		"organization_id": e.OrganizationID,
+		"scope_type":      string(e.Scope.Type),
+		"scope_id":        e.Scope.ID,
+		"occurred_at":     e.OccurredAt,
+	}
+	if e.Entry != nil {
+		data["queue_id"] = e.Entry.ID
+		data["appointment_id"] = e.Entry.AppointmentID
+		data["position"] = e.Entry.Position
+		data["status"] = e.Entry.Status
+	}
+	return data
+}
+
+// Matches reports whether a subscriber to an organization's queues, optionally
+// narrowed to one queue, should receive the event
+func (e QueueEvent) Matches(organizationID string, scope *Scope) bool {
+	if e.OrganizationID != organizationID {
+		return false
+	}
+	return scope == nil || *scope == e.Scope
+}
@@ -0,0 +1,19 @@
+package queue
+
+import "fmt"
+
+// TicketPrefix returns the letter prefix of the index-th queue to issue a ticket on a
+// given day: A to Z, then AA, AB and so on
+func TicketPrefix(index int) string {
+	prefix := ""
+	for index >= 0 {
+		prefix = string(rune('A'+index%26)) + prefix
+		index = index/26 - 1
+	}
+	return prefix
+}
+
+// FormatTicket renders a ticket number as shown on display boards, e.g. A-014
+func FormatTicket(prefix string, number int) string {
+	return fmt.Sprintf("%s-%03d", prefix, number)
+}
@@ -0,0 +1,93 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// TriageLevel is an ESI-style acuity level; 1 is the most urgent
+type TriageLevel int
+
+const (
+	TriageResuscitation TriageLevel = 1
+	TriageEmergent      TriageLevel = 2
+	TriageUrgent        TriageLevel = 3
+	TriageLessUrgent    TriageLevel = 4
+	TriageNonUrgent     TriageLevel = 5
+)
+
+// DefaultTriageLevel is given to patients who have not been triaged, such as routine
+// check-ups
+const DefaultTriageLevel = TriageNonUrgent
+
+// TriageAgingInterval is how long a patient waits before they are ranked one level
+// more urgent, so low-acuity patients are not starved by a stream of urgent ones
+const TriageAgingInterval = 30 * time.Minute
+
+// MaxAgedTriageLevel is the most urgent level aging alone can reach; only a nurse can
+// put a patient on par with resuscitation cases
+const MaxAgedTriageLevel = TriageEmergent
+
+var (
+	ErrInvalidTriageLevel   = errors.New("triage level must be between 1 and 5")
+	ErrTriageReasonRequired = errors.New("a reason is required to re-triage a patient")
+	ErrNotWaiting           = errors.New("queue entry is no longer waiting")
+)
+
+// TriageChange records a queue entry being re-triaged
+type TriageChange struct {
+	ID        string      `json:"id"`
+	QueueID   string      `json:"queue_id"`
+	FromLevel TriageLevel `json:"from_level"`
+	ToLevel   TriageLevel `json:"to_level"`
+	Reason    string      `json:"reason"`
+	ActorID   *string     `json:"actor_id,omitempty"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
+
+// IsValid reports whether the level is on the 1-5 scale
+func (l TriageLevel) IsValid() bool {
+	return l >= TriageResuscitation && l <= TriageNonUrgent
+}
+
+// Effective returns the level a patient is ranked at after waiting for waited. The
+// repository orders queues by the same rule.
+func (l TriageLevel) Effective(waited time.Duration) TriageLevel {
+	if l <= MaxAgedTriageLevel {
+		return l
+	}
+	aged := l - TriageLevel(waited/TriageAgingInterval)
+	if aged < MaxAgedTriageLevel {
+		return MaxAgedTriageLevel
+	}
+	return aged
+}
+
+// Retriage moves a waiting patient to another triage level and returns the change to
+// record in the triage history
+func (q *PatientQueue) Retriage(level TriageLevel, reason string, actorID *string, at time.Time) (*TriageChange, error) {
+	if !level.IsValid() {
+		return nil, ErrInvalidTriageLevel
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrTriageReasonRequired
+	}
+	if q.Status != QueueStatusWaiting {
+		return nil, ErrNotWaiting
+	}
+
+	change := &TriageChange{
+		QueueID:   q.ID,
+		FromLevel: q.TriageLevel,
+		ToLevel:   level,
+		Reason:    reason,
+		ActorID:   actorID,
+		ChangedAt: at,
+	}
+	q.TriageLevel = level
+	q.UpdatedAt = at
+
+	return change, nil
+}
@@ -0,0 +1,32 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTriageLevelEffective(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  TriageLevel
+		waited time.Duration
+		want   TriageLevel
+	}{
+		{"not waited", TriageNonUrgent, 0, TriageNonUrgent},
+		{"just under one interval", TriageNonUrgent, TriageAgingInterval - time.Second, TriageNonUrgent},
+		{"one interval", TriageNonUrgent, TriageAgingInterval, TriageLessUrgent},
+		{"two intervals", TriageNonUrgent, 2 * TriageAgingInterval, TriageUrgent},
+		{"aging stops at the cap", TriageNonUrgent, 10 * TriageAgingInterval, MaxAgedTriageLevel},
+		{"urgent ages to the cap", TriageUrgent, 5 * TriageAgingInterval, MaxAgedTriageLevel},
+		{"emergent does not age", TriageEmergent, 10 * TriageAgingInterval, TriageEmergent},
+		{"resuscitation does not age", TriageResuscitation, 10 * TriageAgingInterval, TriageResuscitation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.level.Effective(tt.waited); got != tt.want {
+				t.Errorf("Effective(%v) = %d, want %d", tt.waited, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"medika-backend/internal/domain/shared"
+)
+
+// Type is the purpose a room is set up for
+type Type string
+
+const (
+	TypeConsultation Type = "consultation"
+	TypeExamination  Type = "examination"
+	TypeProcedure    Type = "procedure"
+	TypeWaiting      Type = "waiting"
+	TypeOffice       Type = "office"
+)
+
+type Room struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	Type           Type      `json:"type"`
+	Capacity       int       `json:"capacity"` // appointments the room can host at the same time
+	IsAvailable    bool      `json:"isAvailable"`
+	Equipment      []string  `json:"equipment"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// Filter narrows down a room listing. Zero values match every room.
+type Filter struct {
+	Type          Type
+	AvailableOnly bool
+	// Equipment lists items a room must have all of
+	Equipment []string
+}
+
+// Booking is an appointment that uses a room
+type Booking struct {
+	AppointmentID string `json:"appointmentId"`
+	RoomID        string `json:"roomId"`
+	DoctorID      string `json:"doctorId"`
+	PatientID     string `json:"patientId"`
+	StartTime     string `json:"startTime"`
+	EndTime       string `json:"endTime"`
+	Status        string `json:"status"`
+}
+
+// OccupancySegment is a stretch of the day during which the number of
+// appointments in a room does not change
+type OccupancySegment struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	Occupied  int    `json:"occupied"`
+	Capacity  int    `json:"capacity"`
+}
+
+// Occupancy is a room's bookings on one day and the resulting timeline
+type Occupancy struct {
+	Room     *Room              `json:"room"`
+	Bookings []*Booking         `json:"bookings"`
+	Segments []OccupancySegment `json:"segments"`
+}
+
+var (
+	ErrRoomNotFound        = errors.New("room not found")
+	ErrInvalidType         = errors.New("invalid room type")
+	ErrInvalidCapacity     = errors.New("room capacity must be at least 1")
+	ErrRoomUnavailable     = errors.New("room is not available for booking")
+	ErrRoomNotBookable     = errors.New("appointments cannot be booked in this type of room")
+	ErrRoomOtherOrg        = errors.New("room belongs to another organization")
+	ErrRoomHasAppointments = errors.New("room has upcoming appointments")
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, room *Room) error
+	GetByID(ctx context.Context, id string) (*Room, error)
+	GetByOrganization(ctx context.Context, organizationID string, filter Filter, limit, offset int) ([]*Room, error)
+	Update(ctx context.Context, room *Room) error
+	// Delete removes a room unless active appointments from the given date on use it
+	Delete(ctx context.Context, id string, from time.Time) error
+	// GetBookings returns the active appointments in an organization's rooms on a date,
+	// ordered by start time
+	GetBookings(ctx context.Context, organizationID string, date time.Time) ([]*Booking, error)
+}
+
+// IsValid reports whether the room type is known
+func (t Type) IsValid() bool {
+	switch t {
+	case TypeConsultation, TypeExamination, TypeProcedure, TypeWaiting, TypeOffice:
+		return true
+	}
+	return false
+}
+
+// IsBookable reports whether appointments can take place in a room of this type
+func (t Type) IsBookable() bool {
+	return t == TypeConsultation || t == TypeExamination || t == TypeProcedure
+}
+
+// Validate checks the room's type and capacity
+func (r *Room) Validate() error {
+	if !r.Type.IsValid() {
+		return ErrInvalidType
+	}
+	if r.Capacity < 1 {
+		return ErrInvalidCapacity
+	}
+	return nil
+}
+
+// CheckBookable verifies that an appointment of the given organization can be
+// booked in the room. Capacity is checked against existing bookings when the
+// appointment is saved.
+func (r *Room) CheckBookable(organizationID string) error {
+	if r.OrganizationID != organizationID {
+		return ErrRoomOtherOrg
+	}
+	if !r.Type.IsBookable() {
+		return ErrRoomNotBookable
+	}
+	if !r.IsAvailable {
+		return ErrRoomUnavailable
+	}
+	return nil
+}
+
+// Timeline splits the day into segments with a constant number of bookings.
+// Gaps without bookings are left out.
+func Timeline(bookings []*Booking, capacity int) ([]OccupancySegment, error) {
+	type boundary struct {
+		at    time.Duration
+		delta int
+	}
+
+	boundaries := make([]boundary, 0, len(bookings)*2)
+	for _, b := range bookings {
+		start, err := shared.ParseTimeOfDay(b.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := shared.ParseTimeOfDay(b.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		boundaries = append(boundaries, boundary{start, 1}, boundary{end, -1})
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].at < boundaries[j].at
+	})
+
+	var segments []OccupancySegment
+	occupied := 0
+	for i := 0; i < len(boundaries); {
+		at := boundaries[i].at
+		for ; i < len(boundaries) && boundaries[i].at == at; i++ {
+			occupied += boundaries[i].delta
+		}
+		if occupied == 0 || i == len(boundaries) {
+			continue
+		}
+
+		next := boundaries[i].at
+		if n := len(segments); n > 0 && segments[n-1].Occupied == occupied && segments[n-1].EndTime == shared.FormatTimeOfDay(at) {
+			segments[n-1].EndTime = shared.FormatTimeOfDay(next)
+			continue
+		}
+		segments = append(segments, OccupancySegment{
+			StartTime: shared.FormatTimeOfDay(at),
+			EndTime:   shared.FormatTimeOfDay(next),
+			Occupied:  occupied,
+			Capacity:  capacity,
+		})
+	}
+
+	return segments, nil
+}
+
+// PeakOccupancy returns the largest number of bookings that overlap at any moment
+// between from and to (offsets from midnight)
+func PeakOccupancy(bookings []*Booking, from, to time.Duration) (int, error) {
+	clipped := make([]*Booking, 0, len(bookings))
+	for _, b := range bookings {
+		start, err := shared.ParseTimeOfDay(b.StartTime)
+		if err != nil {
+			return 0, err
+		}
+		end, err := shared.ParseTimeOfDay(b.EndTime)
+		if err != nil {
+			return 0, err
+		}
+		if start < from {
+			start = from
+		}
+		if end > to {
+			end = to
+		}
+		if end <= start {
+			continue
+		}
+		clipped = append(clipped, &Booking{
+			StartTime: shared.FormatTimeOfDay(start),
+			EndTime:   shared.FormatTimeOfDay(end),
+		})
+	}
+
+	segments, err := Timeline(clipped, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	peak := 0
+	for _, segment := range segments {
+		if segment.Occupied > peak {
+			peak = segment.Occupied
+		}
+	}
+	return peak, nil
+}
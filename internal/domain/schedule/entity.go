@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"medika-backend/internal/domain/shared"
+)
+
+// Template describes the weekly working hours of a doctor, or of a whole
+// organization when DoctorID is nil. Organization templates act as the default
+// for doctors that have no template of their own.
+type Template struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	DoctorID       *string   `json:"doctorId,omitempty"`
+	DayOfWeek      int       `json:"dayOfWeek"`    // 0-6 (Sunday-Saturday)
+	StartTime      string    `json:"startTime"`    // "09:00"
+	EndTime        string    `json:"endTime"`      // "17:00"
+	SlotDuration   int       `json:"slotDuration"` // minutes
+	Breaks         []Break   `json:"breaks"`
+	IsActive       bool      `json:"isActive"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// Break is a period inside a working window where no slots are offered
+type Break struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+// Override replaces the weekly template for a single date, either closing the
+// day entirely or providing different working hours
+type Override struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	DoctorID       *string   `json:"doctorId,omitempty"`
+	Date           time.Time `json:"date"`
+	IsAvailable    bool      `json:"isAvailable"`
+	StartTime      *string   `json:"startTime,omitempty"`
+	EndTime        *string   `json:"endTime,omitempty"`
+	SlotDuration   *int      `json:"slotDuration,omitempty"`
+	Breaks         []Break   `json:"breaks"`
+	Reason         *string   `json:"reason,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// Slot is a bookable time window computed from templates, overrides and existing appointments
+type Slot struct {
+	Date      time.Time `json:"date"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+}
+
+var (
+	ErrInvalidDayOfWeek    = errors.New("day of week must be between 0 (Sunday) and 6 (Saturday)")
+	ErrInvalidSlotDuration = errors.New("slot duration must be between 5 and 480 minutes")
+	ErrInvalidWorkingHours = errors.New("working hours must end after they start")
+	ErrBreakOutsideHours   = errors.New("breaks must fall within working hours")
+	ErrMissingOverrideTime = errors.New("available overrides require both start and end time")
+	ErrInvalidTimeFormat   = errors.New("invalid time of day")
+)
+
+// Repository interface
+type Repository interface {
+	CreateTemplate(ctx context.Context, template *Template) error
+	GetTemplateByID(ctx context.Context, id string) (*Template, error)
+	UpdateTemplate(ctx context.Context, template *Template) error
+	DeleteTemplate(ctx context.Context, id string) error
+	GetDoctorTemplates(ctx context.Context, doctorID string) ([]*Template, error)
+	GetOrganizationTemplates(ctx context.Context, organizationID string) ([]*Template, error)
+
+	CreateOverride(ctx context.Context, override *Override) error
+	DeleteOverride(ctx context.Context, id string) error
+	GetOverrides(ctx context.Context, organizationID string, doctorID *string, from, to time.Time) ([]*Override, error)
+}
+
+// Validate checks the template's day, hours, slot length and breaks
+func (t *Template) Validate() error {
+	if t.DayOfWeek < 0 || t.DayOfWeek > 6 {
+		return ErrInvalidDayOfWeek
+	}
+	if t.SlotDuration < 5 || t.SlotDuration > 480 {
+		return ErrInvalidSlotDuration
+	}
+	return validateWindow(t.StartTime, t.EndTime, t.Breaks)
+}
+
+// IsValidationError reports whether err was caused by invalid schedule input
+func IsValidationError(err error) bool {
+	for _, target := range []error{
+		ErrInvalidDayOfWeek,
+		ErrInvalidSlotDuration,
+		ErrInvalidWorkingHours,
+		ErrBreakOutsideHours,
+		ErrMissingOverrideTime,
+		ErrInvalidTimeFormat,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that an available override carries usable working hours
+func (o *Override) Validate() error {
+	if !o.IsAvailable {
+		return nil
+	}
+	if o.StartTime == nil || o.EndTime == nil {
+		return ErrMissingOverrideTime
+	}
+	if o.SlotDuration != nil && (*o.SlotDuration < 5 || *o.SlotDuration > 480) {
+		return ErrInvalidSlotDuration
+	}
+	return validateWindow(*o.StartTime, *o.EndTime, o.Breaks)
+}
+
+func validateWindow(startTime, endTime string, breaks []Break) error {
+	start, err := shared.ParseTimeOfDay(startTime)
+	if err != nil {
+		return fmt.Errorf("%w: start time: %v", ErrInvalidTimeFormat, err)
+	}
+	end, err := shared.ParseTimeOfDay(endTime)
+	if err != nil {
+		return fmt.Errorf("%w: end time: %v", ErrInvalidTimeFormat, err)
+	}
+	if end <= start {
+		return ErrInvalidWorkingHours
+	}
+
+	for _, b := range breaks {
+		breakStart, err := shared.ParseTimeOfDay(b.StartTime)
+		if err != nil {
+			return fmt.Errorf("%w: break start time: %v", ErrInvalidTimeFormat, err)
+		}
+		breakEnd, err := shared.ParseTimeOfDay(b.EndTime)
+		if err != nil {
+			return fmt.Errorf("%w: break end time: %v", ErrInvalidTimeFormat, err)
+		}
+		if breakEnd <= breakStart || breakStart < start || breakEnd > end {
+			return ErrBreakOutsideHours
+		}
+	}
+
+	return nil
+}
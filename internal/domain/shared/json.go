@@ -0,0 +1,49 @@
+package shared
+
+import "encoding/json"
+
+// The value objects carried by domain events encode as their string value, so the
+// events can be stored in the outbox and read back. Decoding validates the value the
+// way the constructors do; an empty string decodes to the zero value.
+
+func (id UserID) MarshalJSON() ([]byte, error) { return json.Marshal(id.value) }
+
+func (id *UserID) UnmarshalJSON(data []byte) error {
+	return unmarshalValue(data, func(s string) error {
+		parsed, err := NewUserIDFromString(s)
+		*id = parsed
+		return err
+	})
+}
+
+func (id OrganizationID) MarshalJSON() ([]byte, error) { return json.Marshal(id.value) }
+
+func (id *OrganizationID) UnmarshalJSON(data []byte) error {
+	return unmarshalValue(data, func(s string) error {
+		parsed, err := NewOrganizationID(s)
+		*id = parsed
+		return err
+	})
+}
+
+func (e Email) MarshalJSON() ([]byte, error) { return json.Marshal(e.value) }
+
+func (e *Email) UnmarshalJSON(data []byte) error {
+	return unmarshalValue(data, func(s string) error {
+		parsed, err := NewEmail(s)
+		*e = parsed
+		return err
+	})
+}
+
+// unmarshalValue decodes a JSON string and, unless it is empty, hands it to set
+func unmarshalValue(data []byte, set func(s string) error) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+	return set(s)
+}
@@ -234,6 +234,25 @@ func (at AppointmentTime) IsToday() bool {
 	return at.value.Format("2006-01-02") == time.Now().Format("2006-01-02")
 }
 
+// Time of day helpers for TIME columns stored as "HH:MM" or "HH:MM:SS"
+
+// ParseTimeOfDay parses "HH:MM" or "HH:MM:SS" into an offset from midnight
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("expected HH:MM or HH:MM:SS, got %q", value)
+}
+
+// FormatTimeOfDay formats an offset from midnight as "HH:MM"
+func FormatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
 // Password handling utilities
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
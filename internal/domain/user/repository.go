@@ -81,14 +81,40 @@ func (e UserCreatedEvent) EventData() map[string]interface{} {
 	return data
 }
 
+// UserUpdatedEvent is published when a user's profile, medical info or avatar changes.
+// Changes names what changed, without the values.
 type UserUpdatedEvent struct {
 	UserID    shared.UserID
 	Changes   map[string]interface{}
 	UpdatedAt time.Time
 }
 
+func (e UserUpdatedEvent) EventType() string {
+	return "user.updated"
+}
+
+func (e UserUpdatedEvent) EventData() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    e.UserID.String(),
+		"changes":    e.Changes,
+		"updated_at": e.UpdatedAt,
+	}
+}
+
 type UserDeactivatedEvent struct {
 	UserID         shared.UserID
 	DeactivatedAt  time.Time
 	DeactivatedBy  shared.UserID
 }
+
+func (e UserDeactivatedEvent) EventType() string {
+	return "user.deactivated"
+}
+
+func (e UserDeactivatedEvent) EventData() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":        e.UserID.String(),
+		"deactivated_at": e.DeactivatedAt,
+		"deactivated_by": e.DeactivatedBy.String(),
+	}
+}
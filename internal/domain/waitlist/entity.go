@@ -0,0 +1,130 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"medika-backend/internal/domain/shared"
+)
+
+// EntryStatus is the state of a waitlist registration
+type EntryStatus string
+
+const (
+	EntryStatusWaiting   EntryStatus = "waiting"
+	EntryStatusOffered   EntryStatus = "offered"
+	EntryStatusBooked    EntryStatus = "booked"
+	EntryStatusCancelled EntryStatus = "cancelled"
+)
+
+// HoldStatus is the state of a slot offered to a waitlisted patient
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusAccepted HoldStatus = "accepted"
+	HoldStatusDeclined HoldStatus = "declined"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// Entry registers a patient's wish for an earlier appointment with a specific
+// doctor, or with any doctor of a specialty, between two dates (inclusive)
+type Entry struct {
+	ID             string      `json:"id"`
+	OrganizationID string      `json:"organizationId"`
+	PatientID      string      `json:"patientId"`
+	DoctorID       *string     `json:"doctorId,omitempty"`
+	Specialty      *string     `json:"specialty,omitempty"`
+	DateFrom       time.Time   `json:"dateFrom"`
+	DateTo         time.Time   `json:"dateTo"`
+	Status         EntryStatus `json:"status"`
+	Notes          *string     `json:"notes,omitempty"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+}
+
+// Slot is a time window freed by a cancelled appointment
+type Slot struct {
+	SourceAppointmentID string    `json:"sourceAppointmentId"`
+	OrganizationID      string    `json:"organizationId"`
+	DoctorID            string    `json:"doctorId"`
+	RoomID              *string   `json:"roomId,omitempty"`
+	Date                time.Time `json:"date"`
+	StartTime           string    `json:"startTime"`
+	EndTime             string    `json:"endTime"`
+	Duration            int       `json:"duration"` // minutes
+	Type                string    `json:"type"`
+}
+
+// Hold reserves a freed slot for the first matching waitlisted patient until
+// ExpiresAt. Accepting it books the appointment; otherwise the slot moves on to
+// the next patient in line.
+type Hold struct {
+	ID            string     `json:"id"`
+	EntryID       string     `json:"entryId"`
+	PatientID     string     `json:"patientId"`
+	Slot          Slot       `json:"slot"`
+	Status        HoldStatus `json:"status"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	AppointmentID *string    `json:"appointmentId,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+var (
+	ErrMissingTarget    = errors.New("a waitlist entry needs a doctor or a specialty")
+	ErrInvalidDateRange = errors.New("'dateTo' must not be before 'dateFrom'")
+	ErrEntryClosed      = errors.New("waitlist entry is no longer waiting")
+	ErrHoldNotActive    = errors.New("slot hold is no longer active")
+	ErrHoldExpired      = errors.New("slot hold has expired")
+)
+
+// Repository interface
+type Repository interface {
+	CreateEntry(ctx context.Context, entry *Entry) error
+	GetEntryByID(ctx context.Context, id string) (*Entry, error)
+	GetEntries(ctx context.Context, organizationID string, status EntryStatus, limit, offset int) ([]*Entry, error)
+	// CancelEntry withdraws an entry and returns the holds it released
+	CancelEntry(ctx context.Context, id string) ([]*Hold, error)
+
+	// OfferSlot places a hold on the slot for the oldest waiting entry that matches
+	// it and has not been offered this slot before. It returns nil when nobody matches.
+	OfferSlot(ctx context.Context, slot *Slot, expiresAt time.Time) (*Hold, error)
+	GetHoldByID(ctx context.Context, id string) (*Hold, error)
+	// ResolveHold closes an active hold and moves its entry to entryStatus
+	ResolveHold(ctx context.Context, holdID string, status HoldStatus, entryStatus EntryStatus, appointmentID *string) error
+	// ExpireHolds expires every active hold whose deadline has passed and returns them
+	ExpireHolds(ctx context.Context, now time.Time) ([]*Hold, error)
+}
+
+// Validate checks that the entry has a target and a well-formed date range
+func (e *Entry) Validate() error {
+	if (e.DoctorID == nil || *e.DoctorID == "") && (e.Specialty == nil || *e.Specialty == "") {
+		return ErrMissingTarget
+	}
+	if e.DateTo.Before(e.DateFrom) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// StartsAt returns the moment the slot begins in the server's time zone
+func (s *Slot) StartsAt() (time.Time, error) {
+	offset, err := shared.ParseTimeOfDay(s.StartTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	year, month, day := s.Date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local).Add(offset), nil
+}
+
+// IsExpired reports whether the hold can no longer be accepted at now
+func (h *Hold) IsExpired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// IsValidationError reports whether err is caused by invalid input
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrMissingTarget) || errors.Is(err, ErrInvalidDateRange)
+}
@@ -14,6 +14,14 @@ type Config struct {
 	Auth          AuthConfig          `mapstructure:"auth"`
 	Observability ObservabilityConfig `mapstructure:"observability"`
 	Log           LogConfig           `mapstructure:"log"`
+	Waitlist      WaitlistConfig      `mapstructure:"waitlist"`
+	NoShow        NoShowConfig        `mapstructure:"no_show"`
+	Reminders     ReminderConfig      `mapstructure:"reminders"`
+	CheckIn       CheckInConfig       `mapstructure:"check_in"`
+	QueueClosing  QueueClosingConfig  `mapstructure:"queue_closing"`
+	Delivery      DeliveryConfig      `mapstructure:"delivery"`
+	Notifications NotificationConfig  `mapstructure:"notifications"`
+	Outbox        OutboxConfig        `mapstructure:"outbox"`
 }
 
 type ServerConfig struct {
@@ -64,6 +72,94 @@ type MetricsConfig struct {
 	Path    string `mapstructure:"path"`
 }
 
+type WaitlistConfig struct {
+	HoldDuration   time.Duration `mapstructure:"hold_duration"`
+	ExpiryInterval time.Duration `mapstructure:"expiry_interval"`
+}
+
+// NoShowConfig holds defaults for organizations without their own no-show policy
+type NoShowConfig struct {
+	GracePeriod   time.Duration `mapstructure:"grace_period"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+}
+
+// ReminderConfig controls appointment reminders and the scheduled notification dispatcher
+type ReminderConfig struct {
+	// Offsets before the appointment start at which a reminder is sent
+	Offsets          []time.Duration `mapstructure:"offsets"`
+	DispatchInterval time.Duration   `mapstructure:"dispatch_interval"`
+	BatchSize        int             `mapstructure:"batch_size"`
+	Channels         []string        `mapstructure:"channels"` // channels reminders are sent on
+}
+
+// CheckInConfig is how long before and after its start time an appointment can be
+// checked in for
+type CheckInConfig struct {
+	OpensBefore time.Duration `mapstructure:"opens_before"`
+	ClosesAfter time.Duration `mapstructure:"closes_after"`
+}
+
+// QueueClosingConfig holds defaults for organizations without their own queue closing
+// policy, and how often due queue days are looked for
+type QueueClosingConfig struct {
+	CloseTime      string        `mapstructure:"close_time"`
+	LeftoverAction string        `mapstructure:"leftover_action"`
+	CheckInterval  time.Duration `mapstructure:"check_interval"`
+}
+
+// DeliveryConfig controls delivery of notifications on external channels. A channel
+// that is not configured is replaced by a fake that writes to FakeOutbox, or to the
+// log when that is empty.
+type DeliveryConfig struct {
+	FakeOutbox       string        `mapstructure:"fake_outbox"`
+	MaxAttempts      int           `mapstructure:"max_attempts"`
+	RetryBackoff     time.Duration `mapstructure:"retry_backoff"`
+	DispatchInterval time.Duration `mapstructure:"dispatch_interval"`
+	BatchSize        int           `mapstructure:"batch_size"`
+	SMTP             SMTPConfig    `mapstructure:"smtp"`
+	SMS              SMSConfig     `mapstructure:"sms"`
+	Webhook          WebhookConfig `mapstructure:"webhook"`
+}
+
+// NotificationConfig holds defaults for rendering notifications and for users without
+// notification preferences, and the channels of notifications sent on domain events
+type NotificationConfig struct {
+	DefaultLocale   string   `mapstructure:"default_locale"`
+	DefaultTimezone string   `mapstructure:"default_timezone"`
+	Channels        []string `mapstructure:"channels"` // channels event notifications are sent on
+}
+
+// OutboxConfig controls the relay that publishes stored domain events. The relay also
+// runs right after every transaction that stored events.
+type OutboxConfig struct {
+	RelayInterval time.Duration `mapstructure:"relay_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	RetryBackoff  time.Duration `mapstructure:"retry_backoff"`
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+type SMSConfig struct {
+	GatewayURL string        `mapstructure:"gateway_url"`
+	APIKey     string        `mapstructure:"api_key"`
+	Sender     string        `mapstructure:"sender"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+}
+
+type WebhookConfig struct {
+	URL     string        `mapstructure:"url"`
+	Secret  string        `mapstructure:"secret"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
 type LogConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
@@ -105,6 +201,9 @@ func bindEnvVars() {
 	viper.BindEnv("auth.jwt_secret", "JWT_SECRET")
 	viper.BindEnv("log.level", "LOG_LEVEL")
 	viper.BindEnv("observability.tracing.jaeger_endpoint", "OTEL_EXPORTER_JAEGER_ENDPOINT")
+	viper.BindEnv("delivery.smtp.password", "SMTP_PASSWORD")
+	viper.BindEnv("delivery.sms.api_key", "SMS_API_KEY")
+	viper.BindEnv("delivery.webhook.secret", "WEBHOOK_SECRET")
 }
 
 func setDefaults() {
@@ -146,4 +245,49 @@ func setDefaults() {
 	// Log defaults
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
+
+	// Waitlist defaults
+	viper.SetDefault("waitlist.hold_duration", "30m")
+	viper.SetDefault("waitlist.expiry_interval", "1m")
+
+	// No-show defaults
+	viper.SetDefault("no_show.grace_period", "15m")
+	viper.SetDefault("no_show.check_interval", "5m")
+	viper.SetDefault("no_show.batch_size", 200)
+
+	// Reminder defaults
+	viper.SetDefault("reminders.offsets", []string{"24h", "2h"})
+	viper.SetDefault("reminders.dispatch_interval", "1m")
+	viper.SetDefault("reminders.batch_size", 100)
+	viper.SetDefault("reminders.channels", []string{"in_app", "email"})
+
+	// Check-in defaults
+	viper.SetDefault("check_in.opens_before", "1h")
+	viper.SetDefault("check_in.closes_after", "30m")
+
+	// Queue closing defaults
+	viper.SetDefault("queue_closing.close_time", "21:00")
+	viper.SetDefault("queue_closing.leftover_action", "cancel")
+	viper.SetDefault("queue_closing.check_interval", "5m")
+
+	// Notification delivery defaults
+	viper.SetDefault("delivery.fake_outbox", "")
+	viper.SetDefault("delivery.max_attempts", 5)
+	viper.SetDefault("delivery.retry_backoff", "1m")
+	viper.SetDefault("delivery.dispatch_interval", "30s")
+	viper.SetDefault("delivery.batch_size", 100)
+	viper.SetDefault("delivery.smtp.port", 587)
+	viper.SetDefault("delivery.sms.timeout", "10s")
+	viper.SetDefault("delivery.webhook.timeout", "10s")
+
+	// Notification defaults
+	viper.SetDefault("notifications.default_locale", "id")
+	viper.SetDefault("notifications.default_timezone", "Asia/Jakarta")
+	viper.SetDefault("notifications.channels", []string{"in_app", "email", "sms"})
+
+	// Outbox relay defaults
+	viper.SetDefault("outbox.relay_interval", "5s")
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.max_attempts", 8)
+	viper.SetDefault("outbox.retry_backoff", "10s")
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -50,8 +51,29 @@ func registerModels(db *bun.DB) {
 		(*models.Appointment)(nil),
 		(*models.Room)(nil),
 		(*models.PatientQueue)(nil),
+		(*models.QueueTriageHistory)(nil),
+		(*models.QueueEvent)(nil),
+		(*models.QueueState)(nil),
+		(*models.QueueClosingPolicy)(nil),
+		(*models.QueueClosure)(nil),
+		(*models.QueueTicketCounter)(nil),
 		(*models.Notification)(nil),
+		(*models.NotificationDelivery)(nil),
+		(*models.NotificationTemplate)(nil),
+		(*models.NotificationPreference)(nil),
 		(*models.Media)(nil),
+		(*models.ScheduleTemplate)(nil),
+		(*models.ScheduleOverride)(nil),
+		(*models.AppointmentSeries)(nil),
+		(*models.AppointmentStatusHistory)(nil),
+		(*models.WaitlistEntry)(nil),
+		(*models.WaitlistHold)(nil),
+		(*models.CalendarFeedToken)(nil),
+		(*models.DisplayToken)(nil),
+		(*models.NoShowPolicy)(nil),
+		(*models.PatientNoShowStats)(nil),
+		(*models.OutboxMessage)(nil),
+		(*models.OutboxDeadLetter)(nil),
 	)
 }
 
@@ -66,6 +88,11 @@ func Migrate(db *bun.DB) error {
 	}
 
 	for _, file := range files {
+		// Down migrations are for manual rollbacks only
+		if strings.HasSuffix(file, ".down.sql") {
+			continue
+		}
+
 		content, err := ioutil.ReadFile(file)
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", file, err)
@@ -0,0 +1,90 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"medika-backend/internal/application/notification"
+	notificationDomain "medika-backend/internal/domain/notification"
+)
+
+// EmailChannel sends notifications as email over SMTP
+type EmailChannel struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewEmailChannel creates an email channel for the given SMTP server. Without a
+// username the server is used without authentication.
+func NewEmailChannel(host string, port int, username, password, from string) *EmailChannel {
+	channel := &EmailChannel{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+	if username != "" {
+		channel.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return channel
+}
+
+func (c *EmailChannel) Name() string {
+	return notificationDomain.ChannelEmail
+}
+
+func (c *EmailChannel) Send(ctx context.Context, msg notification.Message) error {
+	if msg.Recipient.Email == "" {
+		return fmt.Errorf("%w: recipient has no email address", notificationDomain.ErrUndeliverable)
+	}
+
+	if err := smtp.SendMail(c.addr, c.auth, c.from, []string{msg.Recipient.Email}, c.compose(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// compose builds the email for msg, with its title as the subject. A message with HTML
+// is sent as multipart/alternative with the plain body as the fallback.
+func (c *EmailChannel) compose(msg notification.Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.Recipient.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(msg.Title)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" {
+		writePart(&b, "text/plain", msg.Body)
+		return b.Bytes()
+	}
+
+	boundary := "medika-" + msg.DeliveryID
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	writePart(&b, "text/plain", msg.Body)
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	writePart(&b, "text/html", msg.HTML)
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes()
+}
+
+// writePart writes the content headers and body of one part of an email
+func writePart(b *bytes.Buffer, contentType, body string) {
+	fmt.Fprintf(b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+}
+
+// headerValue keeps line breaks in a value from starting new headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,66 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"medika-backend/internal/application/notification"
+	"medika-backend/pkg/logger"
+)
+
+// FakeChannel stands in for a real channel during development and testing. It never
+// contacts anyone: each message is appended as a JSON line to a file, or logged when
+// no file is set.
+type FakeChannel struct {
+	name   string
+	path   string
+	logger logger.Logger
+	mu     sync.Mutex
+}
+
+// NewFakeChannel creates a fake for the channel with the given name, writing to path
+// if it is not empty
+func NewFakeChannel(name, path string, logger logger.Logger) *FakeChannel {
+	return &FakeChannel{
+		name:   name,
+		path:   path,
+		logger: logger,
+	}
+}
+
+func (c *FakeChannel) Name() string {
+	return c.name
+}
+
+func (c *FakeChannel) Send(ctx context.Context, msg notification.Message) error {
+	if c.path == "" {
+		c.logger.Info(ctx, "Fake notification delivery", "channel", c.name, "delivery_id", msg.DeliveryID, "user_id", msg.Recipient.UserID, "title", msg.Title)
+		return nil
+	}
+
+	line, err := json.Marshal(struct {
+		SentAt time.Time `json:"sentAt"`
+		notification.Message
+	}{time.Now(), msg})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open fake outbox: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write fake outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"medika-backend/internal/application/notification"
+	notificationDomain "medika-backend/internal/domain/notification"
+)
+
+// SMSProvider sends text messages through an SMS gateway
+type SMSProvider interface {
+	SendSMS(ctx context.Context, to, text string) error
+}
+
+// SMSChannel sends notifications as text messages through an SMSProvider
+type SMSChannel struct {
+	provider SMSProvider
+}
+
+func NewSMSChannel(provider SMSProvider) *SMSChannel {
+	return &SMSChannel{provider: provider}
+}
+
+func (c *SMSChannel) Name() string {
+	return notificationDomain.ChannelSMS
+}
+
+func (c *SMSChannel) Send(ctx context.Context, msg notification.Message) error {
+	if msg.Recipient.Phone == "" {
+		return fmt.Errorf("%w: recipient has no phone number", notificationDomain.ErrUndeliverable)
+	}
+
+	if err := c.provider.SendSMS(ctx, msg.Recipient.Phone, msg.Body); err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+	return nil
+}
+
+// HTTPSMSProvider sends text messages by posting them as JSON to a gateway URL,
+// authenticated with a bearer API key
+type HTTPSMSProvider struct {
+	url    string
+	apiKey string
+	sender string
+	client *http.Client
+}
+
+func NewHTTPSMSProvider(url, apiKey, sender string, timeout time.Duration) *HTTPSMSProvider {
+	return &HTTPSMSProvider{
+		url:    url,
+		apiKey: apiKey,
+		sender: sender,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *HTTPSMSProvider) SendSMS(ctx context.Context, to, text string) error {
+	body, err := json.Marshal(map[string]string{
+		"from": p.sender,
+		"to":   to,
+		"text": text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + p.apiKey,
+	}
+	return postJSON(ctx, p.client, p.url, headers, body)
+}
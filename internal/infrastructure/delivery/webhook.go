@@ -0,0 +1,85 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"medika-backend/internal/application/notification"
+	notificationDomain "medika-backend/internal/domain/notification"
+)
+
+// WebhookChannel posts notifications as JSON to an outbound webhook. With a secret,
+// each request carries an HMAC-SHA256 of its body in the X-Medika-Signature header so
+// the receiver can verify it.
+type WebhookChannel struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookChannel(url, secret string, timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *WebhookChannel) Name() string {
+	return notificationDomain.ChannelWebhook
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, msg notification.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	headers := map[string]string{
+		"X-Medika-Delivery": msg.DeliveryID,
+	}
+	if c.secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.secret))
+		mac.Write(body)
+		headers["X-Medika-Signature"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return postJSON(ctx, c.client, c.url, headers, body)
+}
+
+// postJSON posts a JSON body to url and fails on any status other than 2xx. Client
+// errors other than 429 are not worth retrying and are reported as undeliverable.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", notificationDomain.ErrUndeliverable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s responded %s", notificationDomain.ErrUndeliverable, url, resp.Status)
+	default:
+		return fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"medika-backend/pkg/logger"
+)
+
+// Job is a piece of background work that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Trigger, if set, runs the job early whenever it receives
+	Trigger <-chan struct{}
+	Run     func(ctx context.Context) error
+}
+
+// Runner runs periodic jobs alongside the HTTP server until it is stopped
+type Runner struct {
+	jobs   []Job
+	logger logger.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRunner(logger logger.Logger) *Runner {
+	return &Runner{logger: logger}
+}
+
+// Add registers a job; jobs must be added before Start
+func (r *Runner) Add(job Job) {
+	r.jobs = append(r.jobs, job)
+}
+
+// Start launches every registered job in its own goroutine
+func (r *Runner) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go r.loop(ctx, job)
+	}
+}
+
+// Stop cancels all jobs and waits for running iterations to finish
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	r.logger.Info(ctx, "Background job started", "job", job.Name, "interval", job.Interval.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.run(ctx, job)
+		case <-job.Trigger:
+			r.run(ctx, job)
+		}
+	}
+}
+
+// run executes one iteration, keeping a panicking job from taking the server down
+func (r *Runner) run(ctx context.Context, job Job) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.logger.Error(ctx, "Background job panicked", "job", job.Name, "panic", recovered)
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		r.logger.Error(ctx, "Background job failed", "job", job.Name, "error", err)
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AppointmentSeries model
+type AppointmentSeries struct {
+	bun.BaseModel `bun:"table:appointment_series"`
+
+	ID             string     `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OrganizationID string     `bun:"organization_id,type:uuid,notnull"`
+	PatientID      string     `bun:"patient_id,type:uuid,notnull"`
+	DoctorID       string     `bun:"doctor_id,type:uuid,notnull"`
+	Frequency      string     `bun:"frequency,notnull"`
+	Interval       int        `bun:"repeat_interval,notnull"`
+	Count          *int       `bun:"occurrence_count"`
+	Until          *time.Time `bun:"until_date"`
+	RRule          string     `bun:"rrule,notnull"`
+	StartDate      time.Time  `bun:"start_date,notnull"`
+	CreatedAt      time.Time  `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time  `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Appointments []Appointment `bun:"rel:has-many,join:id=series_id"`
+}
+
+// AppointmentStatusHistory model
+type AppointmentStatusHistory struct {
+	bun.BaseModel `bun:"table:appointment_status_history"`
+
+	ID            string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	AppointmentID string    `bun:"appointment_id,type:uuid,notnull"`
+	FromStatus    string    `bun:"from_status,notnull"`
+	ToStatus      string    `bun:"to_status,notnull"`
+	ActorID       *string   `bun:"actor_id,type:uuid"`
+	Reason        *string   `bun:"reason"`
+	ChangedAt     time.Time `bun:"changed_at,default:current_timestamp"`
+}
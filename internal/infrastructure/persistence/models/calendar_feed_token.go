@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// CalendarFeedToken model
+type CalendarFeedToken struct {
+	bun.BaseModel `bun:"table:calendar_feed_tokens"`
+
+	ID         string     `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OwnerType  string     `bun:"owner_type,notnull"`
+	OwnerID    string     `bun:"owner_id,type:uuid,notnull"`
+	TokenHash  string     `bun:"token_hash,notnull,unique"`
+	Label      *string    `bun:"label"`
+	LastUsedAt *time.Time `bun:"last_used_at"`
+	RevokedAt  *time.Time `bun:"revoked_at"`
+	CreatedAt  time.Time  `bun:"created_at,default:current_timestamp"`
+	UpdatedAt  time.Time  `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Owner *User `bun:"rel:belongs-to,join:owner_id=id"`
+}
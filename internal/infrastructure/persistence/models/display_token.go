@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DisplayToken model
+type DisplayToken struct {
+	bun.BaseModel `bun:"table:display_tokens"`
+
+	ID             string     `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OrganizationID string     `bun:"organization_id,type:uuid,notnull"`
+	ScopeType      *string    `bun:"scope_type"`
+	ScopeID        *string    `bun:"scope_id"`
+	TokenHash      string     `bun:"token_hash,notnull,unique"`
+	Label          *string    `bun:"label"`
+	LastUsedAt     *time.Time `bun:"last_used_at"`
+	RevokedAt      *time.Time `bun:"revoked_at"`
+	CreatedAt      time.Time  `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time  `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
+}
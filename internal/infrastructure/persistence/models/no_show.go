@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// NoShowPolicy model
+type NoShowPolicy struct {
+	bun.BaseModel `bun:"table:no_show_policies"`
+
+	OrganizationID        string    `bun:"organization_id,pk,type:uuid"`
+	AutoMark              bool      `bun:"auto_mark,notnull"`
+	GracePeriodMinutes    *int      `bun:"grace_period_minutes"`
+	ConfirmationThreshold int       `bun:"confirmation_threshold,notnull"`
+	ConfirmationLeadHours int       `bun:"confirmation_lead_hours,notnull"`
+	DepositThreshold      int       `bun:"deposit_threshold,notnull"`
+	DepositAmount         float64   `bun:"deposit_amount,type:decimal(10,2),notnull"`
+	CreatedAt             time.Time `bun:"created_at,default:current_timestamp"`
+	UpdatedAt             time.Time `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
+}
+
+// PatientNoShowStats model
+type PatientNoShowStats struct {
+	bun.BaseModel `bun:"table:patient_no_show_stats"`
+
+	OrganizationID string     `bun:"organization_id,pk,type:uuid"`
+	PatientID      string     `bun:"patient_id,pk,type:uuid"`
+	NoShowCount    int        `bun:"no_show_count,notnull"`
+	LastNoShowAt   *time.Time `bun:"last_no_show_at"`
+	CreatedAt      time.Time  `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time  `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
+	Patient      *User         `bun:"rel:belongs-to,join:patient_id=id"`
+}
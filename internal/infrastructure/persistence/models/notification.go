@@ -12,23 +12,73 @@ import (
 type Notification struct {
 	bun.BaseModel `bun:"table:notifications"`
 
-	ID           string    `bun:"id,pk" json:"id"`
-	UserID       string    `bun:"user_id,notnull" json:"user_id"`
-	Type         string    `bun:"type,notnull" json:"type"`
-	Title        string    `bun:"title,notnull" json:"title"`
-	Message      string    `bun:"message,notnull" json:"message"`
-	Data         JSONB     `bun:"data,type:jsonb" json:"data"`
-	IsRead       bool      `bun:"is_read,default:false" json:"is_read"`
-	Channels     []string  `bun:"channels,array" json:"channels"`
-	Priority     string    `bun:"priority,notnull" json:"priority"`
-	ScheduledFor *time.Time `bun:"scheduled_for" json:"scheduled_for"`
-	SentAt       *time.Time `bun:"sent_at" json:"sent_at"`
-	CreatedAt    time.Time `bun:"created_at,notnull,default:now()" json:"created_at"`
+	ID            string     `bun:"id,pk" json:"id"`
+	UserID        string     `bun:"user_id,notnull" json:"user_id"`
+	Type          string     `bun:"type,notnull" json:"type"`
+	Title         string     `bun:"title,notnull" json:"title"`
+	Message       string     `bun:"message,notnull" json:"message"`
+	Data          JSONB      `bun:"data,type:jsonb" json:"data"`
+	IsRead        bool       `bun:"is_read,default:false" json:"is_read"`
+	Channels      []string   `bun:"channels,array" json:"channels"`
+	Priority      string     `bun:"priority,notnull" json:"priority"`
+	AppointmentID *string    `bun:"appointment_id,type:uuid" json:"appointment_id"`
+	Template      string     `bun:"template,nullzero" json:"template"`
+	SMSBody       string     `bun:"sms_body,nullzero" json:"sms_body"`
+	EmailHTML     string     `bun:"email_html,nullzero" json:"email_html"`
+	ScheduledFor  *time.Time `bun:"scheduled_for" json:"scheduled_for"`
+	SentAt        *time.Time `bun:"sent_at" json:"sent_at"`
+	CreatedAt     time.Time  `bun:"created_at,notnull,default:now()" json:"created_at"`
 
 	// Relations
 	User *User `bun:"rel:belongs-to,join:user_id=id" json:"user,omitempty"`
 }
 
+// NotificationDelivery model, a notification's delivery on one external channel
+type NotificationDelivery struct {
+	bun.BaseModel `bun:"table:notification_deliveries"`
+
+	ID             string     `bun:"id,pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	NotificationID string     `bun:"notification_id,type:uuid,notnull" json:"notification_id"`
+	Channel        string     `bun:"channel,notnull" json:"channel"`
+	Status         string     `bun:"status,notnull" json:"status"`
+	Attempts       int        `bun:"attempts,notnull" json:"attempts"`
+	LastError      *string    `bun:"last_error" json:"last_error"`
+	NextAttemptAt  time.Time  `bun:"next_attempt_at,notnull" json:"next_attempt_at"`
+	SentAt         *time.Time `bun:"sent_at" json:"sent_at"`
+	CreatedAt      time.Time  `bun:"created_at,default:current_timestamp" json:"created_at"`
+	UpdatedAt      time.Time  `bun:"updated_at,default:current_timestamp" json:"updated_at"`
+}
+
+// NotificationTemplate model, an organization's override of a built-in notification
+// template
+type NotificationTemplate struct {
+	bun.BaseModel `bun:"table:notification_templates"`
+
+	ID             string    `bun:"id,pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	OrganizationID string    `bun:"organization_id,type:uuid,notnull" json:"organization_id"`
+	Key            string    `bun:"key,notnull" json:"key"`
+	Locale         string    `bun:"locale,notnull" json:"locale"`
+	Title          string    `bun:"title,notnull" json:"title"`
+	Body           string    `bun:"body,notnull" json:"body"`
+	SMSBody        string    `bun:"sms_body,nullzero" json:"sms_body"`
+	EmailHTML      string    `bun:"email_html,nullzero" json:"email_html"`
+	CreatedAt      time.Time `bun:"created_at,default:current_timestamp" json:"created_at"`
+	UpdatedAt      time.Time `bun:"updated_at,default:current_timestamp" json:"updated_at"`
+}
+
+// NotificationPreference model, a user's notification preferences
+type NotificationPreference struct {
+	bun.BaseModel `bun:"table:notification_preferences"`
+
+	UserID     string                     `bun:"user_id,pk,type:uuid" json:"user_id"`
+	Timezone   string                     `bun:"timezone,notnull" json:"timezone"`
+	QuietStart *string                    `bun:"quiet_start" json:"quiet_start"`
+	QuietEnd   *string                    `bun:"quiet_end" json:"quiet_end"`
+	Channels   map[string]map[string]bool `bun:"channels,type:jsonb,notnull" json:"channels"`
+	CreatedAt  time.Time                  `bun:"created_at,default:current_timestamp" json:"created_at"`
+	UpdatedAt  time.Time                  `bun:"updated_at,default:current_timestamp" json:"updated_at"`
+}
+
 // JSONB is a custom type for handling JSONB columns
 type JSONB map[string]interface{}
 
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// OutboxMessage model, a domain event waiting to be published
+type OutboxMessage struct {
+	bun.BaseModel `bun:"table:outbox"`
+
+	ID             string          `bun:"id,pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	EventType      string          `bun:"event_type,notnull" json:"event_type"`
+	OrganizationID string          `bun:"organization_id,type:uuid,nullzero" json:"organization_id"`
+	Payload        json.RawMessage `bun:"payload,type:jsonb,notnull" json:"payload"`
+	Attempts       int             `bun:"attempts,notnull" json:"attempts"`
+	Delivered      []string        `bun:"delivered,array" json:"delivered"`
+	LastError      *string         `bun:"last_error" json:"last_error"`
+	NextAttemptAt  time.Time       `bun:"next_attempt_at,notnull" json:"next_attempt_at"`
+	CreatedAt      time.Time       `bun:"created_at,default:current_timestamp" json:"created_at"`
+	UpdatedAt      time.Time       `bun:"updated_at,default:current_timestamp" json:"updated_at"`
+}
+
+// OutboxDeadLetter model, a domain event the relay gave up on
+type OutboxDeadLetter struct {
+	bun.BaseModel `bun:"table:outbox_dead_letters"`
+
+	ID             string          `bun:"id,pk,type:uuid" json:"id"`
+	EventType      string          `bun:"event_type,notnull" json:"event_type"`
+	OrganizationID string          `bun:"organization_id,type:uuid,nullzero" json:"organization_id"`
+	Payload        json.RawMessage `bun:"payload,type:jsonb,notnull" json:"payload"`
+	Attempts       int             `bun:"attempts,notnull" json:"attempts"`
+	Delivered      []string        `bun:"delivered,array" json:"delivered"`
+	LastError      string          `bun:"last_error,notnull" json:"last_error"`
+	CreatedAt      time.Time       `bun:"created_at,notnull" json:"created_at"`
+	FailedAt       time.Time       `bun:"failed_at,notnull" json:"failed_at"`
+}
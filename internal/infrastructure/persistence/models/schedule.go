@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ScheduleTemplate model
+type ScheduleTemplate struct {
+	bun.BaseModel `bun:"table:schedule_templates"`
+
+	ID             string          `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OrganizationID string          `bun:"organization_id,type:uuid,notnull"`
+	DoctorID       *string         `bun:"doctor_id,type:uuid"`
+	DayOfWeek      int             `bun:"day_of_week,notnull"`
+	StartTime      string          `bun:"start_time,notnull"`
+	EndTime        string          `bun:"end_time,notnull"`
+	SlotDuration   int             `bun:"slot_duration,notnull"` // minutes
+	Breaks         []ScheduleBreak `bun:"breaks,type:jsonb"`
+	IsActive       bool            `bun:"is_active,notnull"`
+	CreatedAt      time.Time       `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time       `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
+	Doctor       *User         `bun:"rel:belongs-to,join:doctor_id=id"`
+}
+
+// ScheduleOverride model
+type ScheduleOverride struct {
+	bun.BaseModel `bun:"table:schedule_overrides"`
+
+	ID             string          `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OrganizationID string          `bun:"organization_id,type:uuid,notnull"`
+	DoctorID       *string         `bun:"doctor_id,type:uuid"`
+	Date           time.Time       `bun:"date,notnull"`
+	IsAvailable    bool            `bun:"is_available,notnull"`
+	StartTime      *string         `bun:"start_time"`
+	EndTime        *string         `bun:"end_time"`
+	SlotDuration   *int            `bun:"slot_duration"`
+	Breaks         []ScheduleBreak `bun:"breaks,type:jsonb"`
+	Reason         *string         `bun:"reason"`
+	CreatedAt      time.Time       `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time       `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
+	Doctor       *User         `bun:"rel:belongs-to,join:doctor_id=id"`
+}
+
+// ScheduleBreak embedded struct
+type ScheduleBreak struct {
+	StartTime string `json:"start_time"` // "12:00"
+	EndTime   string `json:"end_time"`   // "13:00"
+}
@@ -42,6 +42,7 @@ type UserProfile struct {
 	MedicalHistory   *string    `bun:"medical_history"`
 	Allergies        []string   `bun:"allergies,type:text[]"`
 	BloodType        *string    `bun:"blood_type"`
+	Specialization   *string    `bun:"specialization"` // doctors only
 	CreatedAt        time.Time  `bun:"created_at,default:current_timestamp"`
 	UpdatedAt        time.Time  `bun:"updated_at,default:current_timestamp"`
 
@@ -61,6 +62,7 @@ type Organization struct {
 	Email         string          `bun:"email,notnull"`
 	Website       *string         `bun:"website"`
 	BusinessHours []BusinessHours `bun:"business_hours,type:jsonb"`
+	Timezone      string          `bun:"timezone,nullzero"`
 	IsActive      bool            `bun:"is_active,default:true"`
 	CreatedAt     time.Time       `bun:"created_at,default:current_timestamp"`
 	UpdatedAt     time.Time       `bun:"updated_at,default:current_timestamp"`
@@ -81,20 +83,28 @@ type BusinessHours struct {
 type Appointment struct {
 	bun.BaseModel `bun:"table:appointments"`
 
-	ID             string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
-	PatientID      string    `bun:"patient_id,type:uuid,notnull"`
-	DoctorID       string    `bun:"doctor_id,type:uuid,notnull"`
-	OrganizationID string    `bun:"organization_id,type:uuid,notnull"`
-	RoomID         *string   `bun:"room_id,type:uuid"`
-	Date           time.Time `bun:"date,notnull"`
-	StartTime      string    `bun:"start_time,notnull"`
-	EndTime        string    `bun:"end_time,notnull"`
-	Duration       int       `bun:"duration,notnull"` // minutes
-	Status         string    `bun:"status,notnull"`
-	Type           string    `bun:"type,notnull"`
-	Notes          *string   `bun:"notes"`
-	CreatedAt      time.Time `bun:"created_at,default:current_timestamp"`
-	UpdatedAt      time.Time `bun:"updated_at,default:current_timestamp"`
+	ID                   string     `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	PatientID            string     `bun:"patient_id,type:uuid,notnull"`
+	DoctorID             string     `bun:"doctor_id,type:uuid,notnull"`
+	OrganizationID       string     `bun:"organization_id,type:uuid,notnull"`
+	RoomID               *string    `bun:"room_id,type:uuid"`
+	SeriesID             *string    `bun:"series_id,type:uuid"`
+	RescheduledFromID    *string    `bun:"rescheduled_from_id,type:uuid"`
+	Date                 time.Time  `bun:"date,notnull"`
+	StartTime            string     `bun:"start_time,notnull"`
+	EndTime              string     `bun:"end_time,notnull"`
+	Duration             int        `bun:"duration,notnull"` // minutes
+	Status               string     `bun:"status,notnull"`
+	Type                 string     `bun:"type,notnull"`
+	Notes                *string    `bun:"notes"`
+	ConfirmationRequired bool       `bun:"confirmation_required,notnull"`
+	DepositRequired      bool       `bun:"deposit_required,notnull"`
+	DepositPaidAt        *time.Time `bun:"deposit_paid_at"`
+	CheckInCode          string     `bun:"check_in_code,nullzero"` // generated by the database on insert
+	WalkIn               bool       `bun:"walk_in,notnull"`
+	Revision             int        `bun:"revision,notnull,default:0"` // incremented by the database on every update
+	CreatedAt            time.Time  `bun:"created_at,default:current_timestamp"`
+	UpdatedAt            time.Time  `bun:"updated_at,default:current_timestamp"`
 
 	// Relations
 	Patient      *User         `bun:"rel:belongs-to,join:patient_id=id"`
@@ -112,8 +122,8 @@ type Room struct {
 	Name           string    `bun:"name,notnull"`
 	Type           string    `bun:"type,notnull"`
 	Capacity       int       `bun:"capacity,notnull"`
-	IsAvailable    bool      `bun:"is_available,default:true"`
-	Equipment      []string  `bun:"equipment,type:text[]"`
+	IsAvailable    bool      `bun:"is_available,notnull"`
+	Equipment      []string  `bun:"equipment,type:text[],array"`
 	CreatedAt      time.Time `bun:"created_at,default:current_timestamp"`
 	UpdatedAt      time.Time `bun:"updated_at,default:current_timestamp"`
 
@@ -126,20 +136,111 @@ type Room struct {
 type PatientQueue struct {
 	bun.BaseModel `bun:"table:patient_queues"`
 
-	ID                string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
-	AppointmentID     string    `bun:"appointment_id,type:uuid,notnull"`
-	OrganizationID    string    `bun:"organization_id,type:uuid,notnull"`
-	Position          int       `bun:"position,notnull"`
-	EstimatedWaitTime int       `bun:"estimated_wait_time,notnull"` // minutes
-	Status            string    `bun:"status,notnull"`
-	CreatedAt         time.Time `bun:"created_at,default:current_timestamp"`
-	UpdatedAt         time.Time `bun:"updated_at,default:current_timestamp"`
+	ID                string     `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	AppointmentID     string     `bun:"appointment_id,type:uuid,notnull"`
+	OrganizationID    string     `bun:"organization_id,type:uuid,notnull"`
+	ScopeType         string     `bun:"scope_type,notnull"`
+	ScopeID           string     `bun:"scope_id,notnull"`
+	TriageLevel       int        `bun:"triage_level,notnull,default:5"`
+	TicketNumber      string     `bun:"ticket_number,nullzero"`
+	Position          int        `bun:"position,notnull"`
+	EstimatedWaitTime int        `bun:"estimated_wait_time,notnull"` // minutes
+	EstimatedWaitLow  int        `bun:"estimated_wait_low,notnull"`
+	EstimatedWaitHigh int        `bun:"estimated_wait_high,notnull"`
+	Status            string     `bun:"status,notnull"`
+	CalledAt          *time.Time `bun:"called_at"`
+	StartedAt         *time.Time `bun:"started_at"`
+	CompletedAt       *time.Time `bun:"completed_at"`
+	CarriedOverTo     *time.Time `bun:"carried_over_to,type:date"`
+	CreatedAt         time.Time  `bun:"created_at,default:current_timestamp"`
+	UpdatedAt         time.Time  `bun:"updated_at,default:current_timestamp"`
 
 	// Relations
 	Appointment  *Appointment  `bun:"rel:belongs-to,join:appointment_id=id"`
 	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
 }
 
+// QueueTicketCounter model
+type QueueTicketCounter struct {
+	bun.BaseModel `bun:"table:queue_ticket_counters"`
+
+	OrganizationID string    `bun:"organization_id,pk,type:uuid"`
+	ScopeType      string    `bun:"scope_type,pk"`
+	ScopeID        string    `bun:"scope_id,pk"`
+	Day            time.Time `bun:"day,pk,type:date,default:current_date"`
+	Prefix         string    `bun:"prefix,notnull"`
+	LastNumber     int       `bun:"last_number,notnull"`
+}
+
+// QueueTriageHistory model
+type QueueTriageHistory struct {
+	bun.BaseModel `bun:"table:queue_triage_history"`
+
+	ID        string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	QueueID   string    `bun:"queue_id,type:uuid,notnull"`
+	FromLevel int       `bun:"from_level,notnull"`
+	ToLevel   int       `bun:"to_level,notnull"`
+	Reason    string    `bun:"reason,notnull"`
+	ActorID   *string   `bun:"actor_id,type:uuid"`
+	ChangedAt time.Time `bun:"changed_at,default:current_timestamp"`
+}
+
+// QueueState model, kept for queues that have been paused
+type QueueState struct {
+	bun.BaseModel `bun:"table:queue_states"`
+
+	OrganizationID string    `bun:"organization_id,pk,type:uuid"`
+	ScopeType      string    `bun:"scope_type,pk"`
+	ScopeID        string    `bun:"scope_id,pk"`
+	Paused         bool      `bun:"paused,notnull"`
+	Reason         *string   `bun:"reason"`
+	ChangedBy      *string   `bun:"changed_by,type:uuid"`
+	ChangedAt      time.Time `bun:"changed_at,notnull"`
+}
+
+// QueueClosingPolicy model
+type QueueClosingPolicy struct {
+	bun.BaseModel `bun:"table:queue_closing_policies"`
+
+	OrganizationID string    `bun:"organization_id,pk,type:uuid"`
+	Enabled        bool      `bun:"enabled,notnull"`
+	CloseTime      string    `bun:"close_time,notnull"`
+	LeftoverAction string    `bun:"leftover_action,notnull"`
+	CreatedAt      time.Time `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time `bun:"updated_at,default:current_timestamp"`
+}
+
+// QueueClosure model, the summary of a closed queue day
+type QueueClosure struct {
+	bun.BaseModel `bun:"table:queue_closures"`
+
+	ID                 string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OrganizationID     string    `bun:"organization_id,type:uuid,notnull"`
+	Day                time.Time `bun:"day,type:date,notnull"`
+	LeftoverAction     string    `bun:"leftover_action,notnull"`
+	Joined             int       `bun:"joined,notnull"`
+	Completed          int       `bun:"completed,notnull"`
+	Cancelled          int       `bun:"cancelled,notnull"`
+	LeftoverCancelled  int       `bun:"leftover_cancelled,notnull"`
+	CarriedOver        int       `bun:"carried_over,notnull"`
+	InProgress         int       `bun:"in_progress,notnull"`
+	AverageWaitMinutes int       `bun:"average_wait_minutes,notnull"`
+	ClosedAt           time.Time `bun:"closed_at,notnull"`
+}
+
+// QueueEvent model, the queue event log of status changes
+type QueueEvent struct {
+	bun.BaseModel `bun:"table:queue_events"`
+
+	ID             string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	QueueID        string    `bun:"queue_id,type:uuid,notnull"`
+	OrganizationID string    `bun:"organization_id,type:uuid,notnull"`
+	FromStatus     string    `bun:"from_status,nullzero"`
+	ToStatus       string    `bun:"to_status,notnull"`
+	ActorID        *string   `bun:"actor_id,type:uuid"`
+	ChangedAt      time.Time `bun:"changed_at,default:current_timestamp"`
+}
+
 
 // Media model
 type Media struct {
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// WaitlistEntry model
+type WaitlistEntry struct {
+	bun.BaseModel `bun:"table:waitlist_entries"`
+
+	ID             string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	OrganizationID string    `bun:"organization_id,type:uuid,notnull"`
+	PatientID      string    `bun:"patient_id,type:uuid,notnull"`
+	DoctorID       *string   `bun:"doctor_id,type:uuid"`
+	Specialty      *string   `bun:"specialty"`
+	DateFrom       time.Time `bun:"date_from,notnull"`
+	DateTo         time.Time `bun:"date_to,notnull"`
+	Status         string    `bun:"status,notnull"`
+	Notes          *string   `bun:"notes"`
+	CreatedAt      time.Time `bun:"created_at,default:current_timestamp"`
+	UpdatedAt      time.Time `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Organization *Organization `bun:"rel:belongs-to,join:organization_id=id"`
+	Patient      *User         `bun:"rel:belongs-to,join:patient_id=id"`
+	Doctor       *User         `bun:"rel:belongs-to,join:doctor_id=id"`
+}
+
+// WaitlistHold model
+type WaitlistHold struct {
+	bun.BaseModel `bun:"table:waitlist_holds"`
+
+	ID                  string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	EntryID             string    `bun:"entry_id,type:uuid,notnull"`
+	PatientID           string    `bun:"patient_id,type:uuid,notnull"`
+	SourceAppointmentID string    `bun:"source_appointment_id,type:uuid,notnull"`
+	OrganizationID      string    `bun:"organization_id,type:uuid,notnull"`
+	DoctorID            string    `bun:"doctor_id,type:uuid,notnull"`
+	RoomID              *string   `bun:"room_id,type:uuid"`
+	Date                time.Time `bun:"date,notnull"`
+	StartTime           string    `bun:"start_time,notnull"`
+	EndTime             string    `bun:"end_time,notnull"`
+	Duration            int       `bun:"duration,notnull"` // minutes
+	Type                string    `bun:"type,notnull"`
+	Status              string    `bun:"status,notnull"`
+	ExpiresAt           time.Time `bun:"expires_at,notnull"`
+	AppointmentID       *string   `bun:"appointment_id,type:uuid"`
+	CreatedAt           time.Time `bun:"created_at,default:current_timestamp"`
+	UpdatedAt           time.Time `bun:"updated_at,default:current_timestamp"`
+
+	// Relations
+	Entry *WaitlistEntry `bun:"rel:belongs-to,join:entry_id=id"`
+}
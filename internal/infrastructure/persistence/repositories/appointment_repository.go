@@ -2,11 +2,21 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
 
 	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/noshow"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/domain/room"
+	"medika-backend/internal/domain/shared"
 	"medika-backend/internal/infrastructure/persistence/models"
 	"medika-backend/pkg/logger"
 )
@@ -27,21 +37,38 @@ func NewAppointmentRepository(db *bun.DB) appointment.Repository {
 func (r *AppointmentRepository) Create(ctx context.Context, apt *appointment.Appointment) error {
 	model := r.toModel(apt)
 
-	_, err := r.db.NewInsert().
-		Model(model).
-		Exec(ctx)
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.lockSlot(ctx, tx, apt); err != nil {
+			return err
+		}
+
+		conflicts, err := r.findConflicts(ctx, tx, apt)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return &appointment.ConflictError{Conflicts: conflicts}
+		}
+
+		return insertAppointment(ctx, tx, model)
+	})
 
 	if err != nil {
+		if conflictErr := r.asConflictError(ctx, err, apt); conflictErr != nil {
+			return conflictErr
+		}
 		return fmt.Errorf("failed to create appointment: %w", err)
 	}
 
+	apt.ID = model.ID
+	apt.CheckInCode = model.CheckInCode
 	return nil
 }
 
 func (r *AppointmentRepository) GetByID(ctx context.Context, id string) (*appointment.Appointment, error) {
 	model := &models.Appointment{}
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(model).
 		Where("id = ?", id).
 		Scan(ctx)
@@ -56,7 +83,7 @@ func (r *AppointmentRepository) GetByID(ctx context.Context, id string) (*appoin
 func (r *AppointmentRepository) GetByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*appointment.Appointment, error) {
 	var models []models.Appointment
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(&models).
 		Where("organization_id = ?", organizationID).
 		Limit(limit).
@@ -78,9 +105,10 @@ func (r *AppointmentRepository) GetByOrganization(ctx context.Context, organizat
 func (r *AppointmentRepository) GetByPatient(ctx context.Context, patientID string, limit, offset int) ([]*appointment.Appointment, error) {
 	var models []models.Appointment
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(&models).
 		Where("patient_id = ?", patientID).
+		Order("date DESC", "start_time DESC").
 		Limit(limit).
 		Offset(offset).
 		Scan(ctx)
@@ -100,9 +128,10 @@ func (r *AppointmentRepository) GetByPatient(ctx context.Context, patientID stri
 func (r *AppointmentRepository) GetByDoctor(ctx context.Context, doctorID string, limit, offset int) ([]*appointment.Appointment, error) {
 	var models []models.Appointment
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(&models).
 		Where("doctor_id = ?", doctorID).
+		Order("date DESC", "start_time DESC").
 		Limit(limit).
 		Offset(offset).
 		Scan(ctx)
@@ -119,37 +148,445 @@ func (r *AppointmentRepository) GetByDoctor(ctx context.Context, doctorID string
 	return appointments, nil
 }
 
+// GetByDoctorAndDateRange returns a doctor's appointments between two dates (inclusive)
+func (r *AppointmentRepository) GetByDoctorAndDateRange(ctx context.Context, doctorID string, from, to time.Time) ([]*appointment.Appointment, error) {
+	var models []models.Appointment
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&models).
+		Where("doctor_id = ?", doctorID).
+		Where("date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC", "start_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointments by doctor and date range: %w", err)
+	}
+
+	appointments := make([]*appointment.Appointment, len(models))
+	for i, model := range models {
+		appointments[i] = r.toDomain(&model)
+	}
+
+	return appointments, nil
+}
+
 func (r *AppointmentRepository) Update(ctx context.Context, apt *appointment.Appointment) error {
 	model := r.toModel(apt)
 
-	_, err := r.db.NewUpdate().
-		Model(model).
-		Where("id = ?", apt.ID).
-		Exec(ctx)
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.lockSlot(ctx, tx, apt); err != nil {
+			return err
+		}
+
+		conflicts, err := r.findConflicts(ctx, tx, apt)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return &appointment.ConflictError{Conflicts: conflicts}
+		}
+
+		// Status only changes through ChangeStatus so every transition is recorded, and
+		// the check-in code stays the one the patient was given
+		_, err = tx.NewUpdate().
+			Model(model).
+			ExcludeColumn("status", "check_in_code").
+			Where("id = ?", apt.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return r.syncQueueEntry(ctx, tx, apt.ID, apt)
+	})
 
 	if err != nil {
+		if conflictErr := r.asConflictError(ctx, err, apt); conflictErr != nil {
+			return conflictErr
+		}
 		return fmt.Errorf("failed to update appointment: %w", err)
 	}
 
 	return nil
 }
 
-func (r *AppointmentRepository) UpdateStatus(ctx context.Context, id string, status appointment.AppointmentStatus) error {
-	_, err := r.db.NewUpdate().
+// FindConflicts returns active appointments that overlap the given appointment's
+// doctor, patient or room within the same time window
+func (r *AppointmentRepository) FindConflicts(ctx context.Context, apt *appointment.Appointment) ([]appointment.Conflict, error) {
+	conflicts, err := r.findConflicts(ctx, idb(ctx, r.db), apt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointment conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// ChangeStatus applies status transitions and records them in the history in one
+// transaction. Each change only applies if the appointment is still in FromStatus.
+func (r *AppointmentRepository) ChangeStatus(ctx context.Context, changes ...*appointment.StatusChange) error {
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, change := range changes {
+			if err := r.applyStatusChange(ctx, tx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, appointment.ErrStatusChanged) {
+			return err
+		}
+		return fmt.Errorf("failed to change appointment status: %w", err)
+	}
+
+	return nil
+}
+
+// Reschedule cancels the original appointment and books its replacement in one
+// transaction. The original no longer occupies its slot while the replacement is
+// checked for conflicts, and an active queue entry follows the replacement.
+func (r *AppointmentRepository) Reschedule(ctx context.Context, cancellation *appointment.StatusChange, replacement *appointment.Appointment) error {
+	model := r.toModel(replacement)
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.lockSlot(ctx, tx, replacement); err != nil {
+			return err
+		}
+
+		if err := r.applyStatusChange(ctx, tx, cancellation); err != nil {
+			return err
+		}
+
+		conflicts, err := r.findConflicts(ctx, tx, replacement)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return &appointment.ConflictError{Conflicts: conflicts}
+		}
+
+		if err := insertAppointment(ctx, tx, model); err != nil {
+			return err
+		}
+		replacement.ID = model.ID
+		replacement.CheckInCode = model.CheckInCode
+
+		return r.syncQueueEntry(ctx, tx, cancellation.AppointmentID, replacement)
+	})
+
+	if err != nil {
+		replacement.ID = ""
+		replacement.CheckInCode = ""
+		if errors.Is(err, appointment.ErrStatusChanged) {
+			return err
+		}
+		// The original was rolled back to active; it does not conflict with its replacement
+		if conflictErr := r.asConflictError(ctx, err, replacement, cancellation.AppointmentID); conflictErr != nil {
+			return conflictErr
+		}
+		return fmt.Errorf("failed to reschedule appointment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AppointmentRepository) applyStatusChange(ctx context.Context, tx bun.Tx, change *appointment.StatusChange) error {
+	var updated models.Appointment
+	err := tx.NewUpdate().
+		Model(&updated).
+		Set("status = ?", string(change.ToStatus)).
+		Set("updated_at = ?", change.ChangedAt).
+		Where("id = ?", change.AppointmentID).
+		Where("status = ?", string(change.FromStatus)).
+		Returning("organization_id, patient_id").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return appointment.ErrStatusChanged
+	}
+	if err != nil {
+		return err
+	}
+
+	model := &models.AppointmentStatusHistory{
+		AppointmentID: change.AppointmentID,
+		FromStatus:    string(change.FromStatus),
+		ToStatus:      string(change.ToStatus),
+		ActorID:       change.ActorID,
+		Reason:        change.Reason,
+		ChangedAt:     change.ChangedAt,
+	}
+	if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+		return err
+	}
+	change.ID = model.ID
+
+	if change.ToStatus == appointment.StatusNoShow {
+		return r.recordNoShow(ctx, tx, change.AppointmentID, updated.OrganizationID, updated.PatientID, change.ActorID, change.ChangedAt)
+	}
+
+	return nil
+}
+
+// recordNoShow counts a no-show against the patient and cancels the appointment's
+// queue entry
+func (r *AppointmentRepository) recordNoShow(ctx context.Context, tx bun.Tx, appointmentID, organizationID, patientID string, actorID *string, at time.Time) error {
+	_, err := tx.NewInsert().
+		Model(&models.PatientNoShowStats{
+			OrganizationID: organizationID,
+			PatientID:      patientID,
+			NoShowCount:    1,
+			LastNoShowAt:   &at,
+		}).
+		On("CONFLICT (organization_id, patient_id) DO UPDATE").
+		Set("no_show_count = patient_no_show_stats.no_show_count + 1").
+		Set("last_no_show_at = EXCLUDED.last_no_show_at").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	var entryID string
+	err = tx.NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		Column("id").
+		Where("appointment_id = ?", appointmentID).
+		Where("status IN (?)", bun.In(queue.PendingStatuses)).
+		Limit(1).
+		Scan(ctx, &entryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, scope, err := lockQueuesOf(ctx, tx, entryID, nil)
+	if err != nil {
+		return err
+	}
+
+	var entry models.PatientQueue
+	err = tx.NewSelect().
+		Model(&entry).
+		Where("id = ?", entryID).
+		Where("status IN (?)", bun.In(queue.PendingStatuses)).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return cancelQueueEntry(ctx, tx, &entry, scope, actorID, at)
+}
+
+// cancelQueueEntry cancels a locked pending entry, logs the change and renumbers the
+// queue it left
+func cancelQueueEntry(ctx context.Context, tx bun.Tx, entry *models.PatientQueue, scope queue.Scope, actorID *string, at time.Time) error {
+	_, err := tx.NewUpdate().
+		Model((*models.PatientQueue)(nil)).
+		Set("status = ?", queue.QueueStatusCancelled).
+		Set("updated_at = ?", at).
+		Where("id = ?", entry.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := logStatusChange(ctx, tx, entry.ID, entry.OrganizationID, entry.Status, queue.QueueStatusCancelled, actorID, at); err != nil {
+		return err
+	}
+
+	return renumberWaitingQueue(ctx, tx, entry.OrganizationID, scope)
+}
+
+// syncQueueEntry moves the active queue entry of appointmentID to apt. Queues only
+// hold today's patients, so an entry whose appointment moved to another day is
+// cancelled and the remaining waiting patients are renumbered.
+func (r *AppointmentRepository) syncQueueEntry(ctx context.Context, tx bun.Tx, appointmentID string, apt *appointment.Appointment) error {
+	var entryID string
+	err := tx.NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		Column("id").
+		Where("appointment_id = ?", appointmentID).
+		Where("status IN (?)", bun.In(queue.PendingStatuses)).
+		Limit(1).
+		Scan(ctx, &entryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Doctor and room queues follow the appointment to its new doctor or room
+	follow := func(from queue.Scope) queue.Scope {
+		switch from.Type {
+		case queue.ScopeDoctor:
+			from.ID = apt.DoctorID
+		case queue.ScopeRoom:
+			if apt.RoomID != nil && *apt.RoomID != "" {
+				from.ID = *apt.RoomID
+			}
+		}
+		return from
+	}
+	_, from, err := lockQueuesOf(ctx, tx, entryID, follow)
+	if err != nil {
+		return err
+	}
+
+	var entry models.PatientQueue
+	err = tx.NewSelect().
+		Model(&entry).
+		Where("id = ?", entryID).
+		Where("status IN (?)", bun.In(queue.PendingStatuses)).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if apt.Date.Format("2006-01-02") == time.Now().Format("2006-01-02") {
+		to := follow(from)
+		_, err = tx.NewUpdate().
+			Model((*models.PatientQueue)(nil)).
+			Set("appointment_id = ?", apt.ID).
+			Set("scope_id = ?", to.ID).
+			Set("updated_at = CURRENT_TIMESTAMP").
+			Where("id = ?", entry.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		// A new doctor or appointment type changes the wait estimates too
+		return renumberWaitingQueue(ctx, tx, entry.OrganizationID, from, to)
+	}
+
+	return cancelQueueEntry(ctx, tx, &entry, from, nil, time.Now())
+}
+
+// GetStatusHistory returns an appointment's status transitions, oldest first
+func (r *AppointmentRepository) GetStatusHistory(ctx context.Context, appointmentID string) ([]*appointment.StatusChange, error) {
+	var rows []models.AppointmentStatusHistory
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Where("appointment_id = ?", appointmentID).
+		Order("changed_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment status history: %w", err)
+	}
+
+	history := make([]*appointment.StatusChange, len(rows))
+	for i, row := range rows {
+		history[i] = &appointment.StatusChange{
+			ID:            row.ID,
+			AppointmentID: row.AppointmentID,
+			FromStatus:    appointment.AppointmentStatus(row.FromStatus),
+			ToStatus:      appointment.AppointmentStatus(row.ToStatus),
+			ActorID:       row.ActorID,
+			Reason:        row.Reason,
+			ChangedAt:     row.ChangedAt,
+		}
+	}
+
+	return history, nil
+}
+
+// organizationNow is the SQL for the wall-clock time of the now argument in the time
+// zone of the appointment's organization, joined as o. Like organization.Location, it
+// falls back on the server's zone, given as the second argument, when the
+// organization has no zone or one the database does not know.
+const organizationNow = "(CASE WHEN o.timezone IN (SELECT name FROM pg_timezone_names) THEN ?::timestamptz AT TIME ZONE o.timezone ELSE ?::timestamp END)"
+
+// FindOverdue returns confirmed appointments that ended more than the organization's
+// grace period ago, in its own time zone. Organizations that turned automatic marking
+// off are skipped.
+func (r *AppointmentRepository) FindOverdue(ctx context.Context, now time.Time, defaultGrace time.Duration, limit int) ([]*appointment.Appointment, error) {
+	var rows []models.Appointment
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Join("LEFT JOIN no_show_policies AS p ON p.organization_id = ?TableAlias.organization_id").
+		Join("LEFT JOIN organizations AS o ON o.id = ?TableAlias.organization_id").
+		Where("?TableAlias.status = ?", string(appointment.StatusConfirmed)).
+		Where("COALESCE(p.auto_mark, TRUE)").
+		Where("?TableAlias.date + ?TableAlias.end_time + make_interval(mins => COALESCE(p.grace_period_minutes, ?)) < "+organizationNow,
+			int(defaultGrace.Minutes()), now, now.In(time.Local).Format("2006-01-02 15:04:05")).
+		Order("date ASC", "end_time ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overdue appointments: %w", err)
+	}
+
+	appointments := make([]*appointment.Appointment, len(rows))
+	for i, row := range rows {
+		appointments[i] = r.toDomain(&row)
+	}
+
+	return appointments, nil
+}
+
+// FindUnconfirmed returns pending appointments that had to be confirmed a number of
+// hours before they start in the organization's time zone, as set by its policy, and
+// were not
+func (r *AppointmentRepository) FindUnconfirmed(ctx context.Context, now time.Time, limit int) ([]*appointment.Appointment, error) {
+	var rows []models.Appointment
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Join("LEFT JOIN no_show_policies AS p ON p.organization_id = ?TableAlias.organization_id").
+		Join("LEFT JOIN organizations AS o ON o.id = ?TableAlias.organization_id").
+		Where("?TableAlias.status = ?", string(appointment.StatusPending)).
+		Where("?TableAlias.confirmation_required = TRUE").
+		Where("?TableAlias.date + ?TableAlias.start_time - make_interval(hours => COALESCE(p.confirmation_lead_hours, ?)) < "+organizationNow,
+			noshow.DefaultConfirmationLeadHours, now, now.In(time.Local).Format("2006-01-02 15:04:05")).
+		Order("date ASC", "start_time ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unconfirmed appointments: %w", err)
+	}
+
+	appointments := make([]*appointment.Appointment, len(rows))
+	for i, row := range rows {
+		appointments[i] = r.toDomain(&row)
+	}
+
+	return appointments, nil
+}
+
+func (r *AppointmentRepository) MarkDepositPaid(ctx context.Context, id string, paidAt time.Time) error {
+	_, err := idb(ctx, r.db).NewUpdate().
 		Model((*models.Appointment)(nil)).
-		Set("status = ?", string(status)).
+		Set("deposit_paid_at = ?", paidAt).
+		Set("updated_at = ?", paidAt).
 		Where("id = ?", id).
+		Where("deposit_paid_at IS NULL").
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to update appointment status: %w", err)
+		return fmt.Errorf("failed to record appointment deposit: %w", err)
 	}
 
 	return nil
 }
 
 func (r *AppointmentRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.NewDelete().
+	_, err := idb(ctx, r.db).NewDelete().
 		Model((*models.Appointment)(nil)).
 		Where("id = ?", id).
 		Exec(ctx)
@@ -162,7 +599,7 @@ func (r *AppointmentRepository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *AppointmentRepository) CountByOrganization(ctx context.Context, organizationID string) (int, error) {
-	count, err := r.db.NewSelect().
+	count, err := idb(ctx, r.db).NewSelect().
 		Model((*models.Appointment)(nil)).
 		Where("organization_id = ?", organizationID).
 		Count(ctx)
@@ -178,7 +615,7 @@ func (r *AppointmentRepository) CountByOrganization(ctx context.Context, organiz
 func (r *AppointmentRepository) GetAppointmentsByDate(ctx context.Context, organizationID, date string, limit int) ([]*appointment.Appointment, error) {
 	var models []models.Appointment
 	
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model(&models).
 		Where("date = ?", date)
 	
@@ -205,7 +642,7 @@ func (r *AppointmentRepository) GetAppointmentsByDate(ctx context.Context, organ
 
 // CountAppointmentsByDate returns count of appointments for a specific date
 func (r *AppointmentRepository) CountAppointmentsByDate(ctx context.Context, organizationID, date string) (int, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.Appointment)(nil)).
 		Where("date = ?", date)
 	
@@ -222,40 +659,256 @@ func (r *AppointmentRepository) CountAppointmentsByDate(ctx context.Context, org
 	return count, nil
 }
 
+// lockSlot serializes bookings that touch the same doctor, patient or room so the
+// conflict check and the write happen atomically with respect to each other
+func (r *AppointmentRepository) lockSlot(ctx context.Context, tx bun.Tx, apt *appointment.Appointment) error {
+	keys := []string{
+		"appointment:doctor:" + apt.DoctorID,
+		"appointment:patient:" + apt.PatientID,
+	}
+	if apt.RoomID != nil && *apt.RoomID != "" {
+		keys = append(keys, "appointment:room:"+*apt.RoomID)
+	}
+	// Always acquire in the same order to avoid deadlocks between concurrent bookings
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", key); err != nil {
+			return fmt.Errorf("failed to lock appointment slot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *AppointmentRepository) findConflicts(ctx context.Context, db bun.IDB, apt *appointment.Appointment) ([]appointment.Conflict, error) {
+	if !apt.Status.OccupiesSlot() {
+		return nil, nil
+	}
+
+	var rows []models.Appointment
+
+	query := db.NewSelect().
+		Model(&rows).
+		Where("date = ?", apt.Date.Format("2006-01-02")).
+		Where("status NOT IN (?)", bun.In([]string{
+			string(appointment.StatusCancelled),
+			string(appointment.StatusNoShow),
+		})).
+		Where("start_time < ?", apt.EndTime).
+		Where("end_time > ?", apt.StartTime).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.Where("doctor_id = ?", apt.DoctorID).
+				WhereOr("patient_id = ?", apt.PatientID)
+			if apt.RoomID != nil && *apt.RoomID != "" {
+				q = q.WhereOr("room_id = ?", *apt.RoomID)
+			}
+			return q
+		})
+
+	if apt.ID != "" {
+		query = query.Where("id != ?", apt.ID)
+	}
+
+	if err := query.Order("start_time ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	roomFull, err := r.roomFull(ctx, db, apt, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]appointment.Conflict, 0, len(rows))
+	for _, row := range rows {
+		for _, resource := range conflictResources(apt, &row) {
+			if resource == appointment.ConflictRoom && !roomFull {
+				continue
+			}
+			conflicts = append(conflicts, appointment.Conflict{
+				AppointmentID: row.ID,
+				Resource:      resource,
+				Date:          row.Date,
+				StartTime:     row.StartTime,
+				EndTime:       row.EndTime,
+				Status:        appointment.AppointmentStatus(row.Status),
+			})
+		}
+	}
+
+	return conflicts, nil
+}
+
+// roomFull reports whether the appointment's room has no capacity left for it.
+// Rooms can host as many overlapping appointments as their capacity allows.
+func (r *AppointmentRepository) roomFull(ctx context.Context, db bun.IDB, apt *appointment.Appointment, overlapping []models.Appointment) (bool, error) {
+	if apt.RoomID == nil || *apt.RoomID == "" {
+		return false, nil
+	}
+
+	var bookings []*room.Booking
+	for _, row := range overlapping {
+		if row.RoomID != nil && *row.RoomID == *apt.RoomID {
+			bookings = append(bookings, &room.Booking{StartTime: row.StartTime, EndTime: row.EndTime})
+		}
+	}
+	if len(bookings) == 0 {
+		return false, nil
+	}
+
+	var capacity int
+	err := db.NewSelect().
+		Model((*models.Room)(nil)).
+		Column("capacity").
+		Where("id = ?", *apt.RoomID).
+		Scan(ctx, &capacity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, room.ErrRoomNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+
+	start, err := shared.ParseTimeOfDay(apt.StartTime)
+	if err != nil {
+		return false, err
+	}
+	end, err := shared.ParseTimeOfDay(apt.EndTime)
+	if err != nil {
+		return false, err
+	}
+
+	peak, err := room.PeakOccupancy(bookings, start, end)
+	if err != nil {
+		return false, err
+	}
+
+	return peak >= capacity, nil
+}
+
+// conflictResources lists every resource the existing row shares with the requested appointment
+func conflictResources(apt *appointment.Appointment, existing *models.Appointment) []appointment.ConflictResource {
+	var resources []appointment.ConflictResource
+	if existing.DoctorID == apt.DoctorID {
+		resources = append(resources, appointment.ConflictDoctor)
+	}
+	if existing.PatientID == apt.PatientID {
+		resources = append(resources, appointment.ConflictPatient)
+	}
+	if apt.RoomID != nil && existing.RoomID != nil && *existing.RoomID == *apt.RoomID {
+		resources = append(resources, appointment.ConflictRoom)
+	}
+	return resources
+}
+
+// checkInCodeAttempts is how often an appointment is inserted before giving up on
+// check-in codes that are already taken
+const checkInCodeAttempts = 5
+
+// insertAppointment stores model with the check-in code the database generates for
+// it, inserting it again should that code be taken by another appointment. Each
+// attempt is a savepoint, so a taken code does not abort tx.
+func insertAppointment(ctx context.Context, tx bun.Tx, model *models.Appointment) error {
+	for attempt := 1; ; attempt++ {
+		err := tx.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			_, err := sp.NewInsert().Model(model).Exec(ctx)
+			return err
+		})
+		if attempt < checkInCodeAttempts && isCheckInCodeTaken(err) {
+			model.CheckInCode = ""
+			continue
+		}
+		return err
+	}
+}
+
+// isCheckInCodeTaken reports whether err violates the uniqueness of check-in codes
+func isCheckInCodeTaken(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == "23505" && pgErr.Field('n') == "idx_appointments_check_in_code"
+}
+
+// asConflictError unwraps conflict errors raised inside a transaction. A violation of
+// the appointments overlap exclusion constraints, raised when an overlapping booking
+// committed after the conflict check, is reported with the bookings apt overlaps now,
+// leaving out the appointments in exclude.
+func (r *AppointmentRepository) asConflictError(ctx context.Context, err error, apt *appointment.Appointment, exclude ...string) *appointment.ConflictError {
+	var conflictErr *appointment.ConflictError
+	if errors.As(err, &conflictErr) {
+		return conflictErr
+	}
+
+	if !isOverlapViolation(err) {
+		return nil
+	}
+	conflicts, findErr := r.findConflicts(ctx, idb(ctx, r.db), apt)
+	if findErr != nil {
+		return &appointment.ConflictError{}
+	}
+	conflicts = slices.DeleteFunc(conflicts, func(conflict appointment.Conflict) bool {
+		return slices.Contains(exclude, conflict.AppointmentID)
+	})
+	return &appointment.ConflictError{Conflicts: conflicts}
+}
+
+// isOverlapViolation reports whether err violates the appointments overlap exclusion
+// constraints
+func isOverlapViolation(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == "23P01"
+}
+
+// toModel leaves out the check-in code, so that every stored appointment, including
+// the replacement of a rescheduled one, gets a code of its own, and the revision, which
+// the database keeps
 func (r *AppointmentRepository) toModel(apt *appointment.Appointment) *models.Appointment {
 	return &models.Appointment{
-		ID:             apt.ID,
-		PatientID:      apt.PatientID,
-		DoctorID:       apt.DoctorID,
-		OrganizationID: apt.OrganizationID,
-		RoomID:         apt.RoomID,
-		Date:           apt.Date,
-		StartTime:      apt.StartTime,
-		EndTime:        apt.EndTime,
-		Duration:       apt.Duration,
-		Status:         string(apt.Status),
-		Type:           apt.Type,
-		Notes:          apt.Notes,
-		CreatedAt:      apt.CreatedAt,
-		UpdatedAt:      apt.UpdatedAt,
+		ID:                   apt.ID,
+		PatientID:            apt.PatientID,
+		DoctorID:             apt.DoctorID,
+		OrganizationID:       apt.OrganizationID,
+		RoomID:               apt.RoomID,
+		SeriesID:             apt.SeriesID,
+		RescheduledFromID:    apt.RescheduledFromID,
+		Date:                 apt.Date,
+		StartTime:            apt.StartTime,
+		EndTime:              apt.EndTime,
+		Duration:             apt.Duration,
+		Status:               string(apt.Status),
+		Type:                 apt.Type,
+		Notes:                apt.Notes,
+		ConfirmationRequired: apt.ConfirmationRequired,
+		DepositRequired:      apt.DepositRequired,
+		DepositPaidAt:        apt.DepositPaidAt,
+		WalkIn:               apt.WalkIn,
+		CreatedAt:            apt.CreatedAt,
+		UpdatedAt:            apt.UpdatedAt,
 	}
 }
 
 func (r *AppointmentRepository) toDomain(model *models.Appointment) *appointment.Appointment {
 	return &appointment.Appointment{
-		ID:             model.ID,
-		PatientID:      model.PatientID,
-		DoctorID:       model.DoctorID,
-		OrganizationID: model.OrganizationID,
-		RoomID:         model.RoomID,
-		Date:           model.Date,
-		StartTime:      model.StartTime,
-		EndTime:        model.EndTime,
-		Duration:       model.Duration,
-		Status:         appointment.AppointmentStatus(model.Status),
-		Type:           model.Type,
-		Notes:          model.Notes,
-		CreatedAt:      model.CreatedAt,
-		UpdatedAt:      model.UpdatedAt,
+		ID:                   model.ID,
+		PatientID:            model.PatientID,
+		DoctorID:             model.DoctorID,
+		OrganizationID:       model.OrganizationID,
+		RoomID:               model.RoomID,
+		SeriesID:             model.SeriesID,
+		RescheduledFromID:    model.RescheduledFromID,
+		Date:                 model.Date,
+		StartTime:            model.StartTime,
+		EndTime:              model.EndTime,
+		Duration:             model.Duration,
+		Status:               appointment.AppointmentStatus(model.Status),
+		Type:                 model.Type,
+		Notes:                model.Notes,
+		ConfirmationRequired: model.ConfirmationRequired,
+		DepositRequired:      model.DepositRequired,
+		DepositPaidAt:        model.DepositPaidAt,
+		CheckInCode:          model.CheckInCode,
+		WalkIn:               model.WalkIn,
+		Revision:             model.Revision,
+		CreatedAt:            model.CreatedAt,
+		UpdatedAt:            model.UpdatedAt,
 	}
 }
@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/infrastructure/persistence/models"
+)
+
+// CreateSeries stores a series and its occurrences in one transaction. Occurrences
+// that overlap existing bookings are returned; unless skipConflicts is set they abort
+// the whole series. Created occurrences get their ID and SeriesID populated.
+func (r *AppointmentRepository) CreateSeries(ctx context.Context, series *appointment.Series, occurrences []*appointment.Appointment, skipConflicts bool) ([]appointment.OccurrenceConflict, error) {
+	seriesModel := r.seriesToModel(series)
+	var skipped []appointment.OccurrenceConflict
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		skipped = nil
+
+		if _, err := tx.NewInsert().Model(seriesModel).Exec(ctx); err != nil {
+			return err
+		}
+
+		created := 0
+		for _, apt := range occurrences {
+			apt.SeriesID = &seriesModel.ID
+
+			if err := r.lockSlot(ctx, tx, apt); err != nil {
+				return err
+			}
+
+			conflicts, err := r.findConflicts(ctx, tx, apt)
+			if err != nil {
+				return err
+			}
+			if len(conflicts) > 0 {
+				skipped = append(skipped, occurrenceConflict(apt, conflicts))
+				continue
+			}
+
+			model := r.toModel(apt)
+			if err := insertAppointment(ctx, tx, model); err != nil {
+				return err
+			}
+			apt.ID = model.ID
+			apt.CheckInCode = model.CheckInCode
+			created++
+		}
+
+		if len(skipped) > 0 && (!skipConflicts || created == 0) {
+			return &appointment.SeriesConflictError{Occurrences: skipped}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		for _, apt := range occurrences {
+			apt.ID = ""
+			apt.SeriesID = nil
+			apt.CheckInCode = ""
+		}
+		return nil, r.seriesError(ctx, "create appointment series", err, occurrences)
+	}
+
+	series.ID = seriesModel.ID
+	for _, apt := range occurrences {
+		if apt.ID == "" {
+			apt.SeriesID = nil
+		}
+	}
+
+	return skipped, nil
+}
+
+func (r *AppointmentRepository) GetSeriesByID(ctx context.Context, id string) (*appointment.Series, error) {
+	model := &models.AppointmentSeries{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment series: %w", err)
+	}
+
+	return r.seriesToDomain(model), nil
+}
+
+// GetSeriesAppointments returns every occurrence of a series in chronological order
+func (r *AppointmentRepository) GetSeriesAppointments(ctx context.Context, seriesID string) ([]*appointment.Appointment, error) {
+	var models []models.Appointment
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&models).
+		Where("series_id = ?", seriesID).
+		Order("date ASC", "start_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series appointments: %w", err)
+	}
+
+	appointments := make([]*appointment.Appointment, len(models))
+	for i, model := range models {
+		appointments[i] = r.toDomain(&model)
+	}
+
+	return appointments, nil
+}
+
+// UpdateOccurrences saves several occurrences of a series atomically, optionally
+// splitting the series first. Any overlapping occurrence rolls back the whole change.
+func (r *AppointmentRepository) UpdateOccurrences(ctx context.Context, occurrences []*appointment.Appointment, split *appointment.SeriesSplit) error {
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if split != nil {
+			if err := r.applySplit(ctx, tx, split, occurrences); err != nil {
+				return err
+			}
+		}
+
+		var conflicted []appointment.OccurrenceConflict
+		for _, apt := range occurrences {
+			if err := r.lockSlot(ctx, tx, apt); err != nil {
+				return err
+			}
+
+			conflicts, err := r.findConflicts(ctx, tx, apt)
+			if err != nil {
+				return err
+			}
+			if len(conflicts) > 0 {
+				conflicted = append(conflicted, occurrenceConflict(apt, conflicts))
+				continue
+			}
+
+			_, err = tx.NewUpdate().
+				Model(r.toModel(apt)).
+				ExcludeColumn("status", "check_in_code").
+				Where("id = ?", apt.ID).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(conflicted) > 0 {
+			return &appointment.SeriesConflictError{Occurrences: conflicted}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return r.seriesError(ctx, "update series occurrences", err, occurrences)
+	}
+
+	return nil
+}
+
+func (r *AppointmentRepository) applySplit(ctx context.Context, tx bun.Tx, split *appointment.SeriesSplit, occurrences []*appointment.Appointment) error {
+	if split.Original != nil {
+		_, err := tx.NewUpdate().
+			Model(r.seriesToModel(split.Original)).
+			ExcludeColumn("created_at").
+			Where("id = ?", split.Original.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if split.Successor != nil {
+		model := r.seriesToModel(split.Successor)
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return err
+		}
+		split.Successor.ID = model.ID
+
+		for _, apt := range occurrences {
+			apt.SeriesID = &model.ID
+		}
+	}
+
+	return nil
+}
+
+// seriesError keeps conflict errors intact so the handler can report each occurrence.
+// A violation of the overlap constraints is reported with the bookings the occurrences
+// overlap now.
+func (r *AppointmentRepository) seriesError(ctx context.Context, action string, err error, occurrences []*appointment.Appointment) error {
+	var seriesErr *appointment.SeriesConflictError
+	if errors.As(err, &seriesErr) {
+		return seriesErr
+	}
+	if isOverlapViolation(err) {
+		var conflicted []appointment.OccurrenceConflict
+		for _, apt := range occurrences {
+			if conflicts, err := r.findConflicts(ctx, idb(ctx, r.db), apt); err == nil && len(conflicts) > 0 {
+				conflicted = append(conflicted, occurrenceConflict(apt, conflicts))
+			}
+		}
+		if len(conflicted) > 0 {
+			return &appointment.SeriesConflictError{Occurrences: conflicted}
+		}
+		return &appointment.ConflictError{}
+	}
+	var conflictErr *appointment.ConflictError
+	if errors.As(err, &conflictErr) {
+		return conflictErr
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+func occurrenceConflict(apt *appointment.Appointment, conflicts []appointment.Conflict) appointment.OccurrenceConflict {
+	return appointment.OccurrenceConflict{
+		Date:      apt.Date,
+		StartTime: apt.StartTime,
+		EndTime:   apt.EndTime,
+		Conflicts: conflicts,
+	}
+}
+
+func (r *AppointmentRepository) seriesToModel(series *appointment.Series) *models.AppointmentSeries {
+	return &models.AppointmentSeries{
+		ID:             series.ID,
+		OrganizationID: series.OrganizationID,
+		PatientID:      series.PatientID,
+		DoctorID:       series.DoctorID,
+		Frequency:      string(series.Rule.Frequency),
+		Interval:       series.Rule.Interval,
+		Count:          series.Rule.Count,
+		Until:          series.Rule.Until,
+		RRule:          series.Rule.String(),
+		StartDate:      series.StartDate,
+		CreatedAt:      series.CreatedAt,
+		UpdatedAt:      series.UpdatedAt,
+	}
+}
+
+func (r *AppointmentRepository) seriesToDomain(model *models.AppointmentSeries) *appointment.Series {
+	return &appointment.Series{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		PatientID:      model.PatientID,
+		DoctorID:       model.DoctorID,
+		Rule: appointment.RecurrenceRule{
+			Frequency: appointment.RecurrenceFrequency(model.Frequency),
+			Interval:  model.Interval,
+			Count:     model.Count,
+			Until:     model.Until,
+		},
+		StartDate: model.StartDate,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
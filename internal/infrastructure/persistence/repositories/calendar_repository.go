@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/calendar"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// CalendarRepository implements calendar.Repository
+type CalendarRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewCalendarRepository(db *bun.DB) calendar.Repository {
+	return &CalendarRepository{
+		db:     db,
+		logger: logger.New(),
+	}
+}
+
+func (r *CalendarRepository) Create(ctx context.Context, token *calendar.FeedToken) error {
+	model := r.toModel(token)
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create calendar feed token: %w", err)
+	}
+
+	token.ID = model.ID
+	return nil
+}
+
+func (r *CalendarRepository) GetByID(ctx context.Context, id string) (*calendar.FeedToken, error) {
+	return r.getOne(ctx, "id = ?", id)
+}
+
+func (r *CalendarRepository) GetByHash(ctx context.Context, tokenHash string) (*calendar.FeedToken, error) {
+	return r.getOne(ctx, "token_hash = ?", tokenHash)
+}
+
+func (r *CalendarRepository) getOne(ctx context.Context, query string, arg interface{}) (*calendar.FeedToken, error) {
+	model := &models.CalendarFeedToken{}
+
+	err := r.db.NewSelect().
+		Model(model).
+		Where(query, arg).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, calendar.ErrFeedNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed token: %w", err)
+	}
+
+	return r.toDomain(model), nil
+}
+
+func (r *CalendarRepository) GetByOwner(ctx context.Context, ownerType calendar.OwnerType, ownerID string) ([]*calendar.FeedToken, error) {
+	var rows []models.CalendarFeedToken
+
+	err := r.db.NewSelect().
+		Model(&rows).
+		Where("owner_type = ?", string(ownerType)).
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed tokens: %w", err)
+	}
+
+	tokens := make([]*calendar.FeedToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = r.toDomain(&row)
+	}
+
+	return tokens, nil
+}
+
+func (r *CalendarRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.CalendarFeedToken)(nil)).
+		Set("revoked_at = ?", revokedAt).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke calendar feed token: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return calendar.ErrAlreadyRevoked
+	}
+
+	return nil
+}
+
+func (r *CalendarRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.CalendarFeedToken)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark calendar feed token as used: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CalendarRepository) toModel(token *calendar.FeedToken) *models.CalendarFeedToken {
+	return &models.CalendarFeedToken{
+		ID:         token.ID,
+		OwnerType:  string(token.OwnerType),
+		OwnerID:    token.OwnerID,
+		TokenHash:  token.TokenHash,
+		Label:      token.Label,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+		UpdatedAt:  token.UpdatedAt,
+	}
+}
+
+func (r *CalendarRepository) toDomain(model *models.CalendarFeedToken) *calendar.FeedToken {
+	return &calendar.FeedToken{
+		ID:         model.ID,
+		OwnerType:  calendar.OwnerType(model.OwnerType),
+		OwnerID:    model.OwnerID,
+		TokenHash:  model.TokenHash,
+		Label:      model.Label,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+	}
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/checkin"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/domain/shared"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// CheckInRepository implements checkin.Repository. Appointments and queue entries are
+// written through the same helpers as AppointmentRepository and QueueRepository, so
+// a check-in takes the same locks as any other booking or queue change.
+type CheckInRepository struct {
+	db           bun.IDB
+	appointments *AppointmentRepository
+	logger       logger.Logger
+}
+
+func NewCheckInRepository(db *bun.DB) checkin.Repository {
+	return &CheckInRepository{
+		db:           db,
+		appointments: &AppointmentRepository{db: db, logger: logger.New()},
+		logger:       logger.New(),
+	}
+}
+
+func (r *CheckInRepository) GetAppointmentByCode(ctx context.Context, code string) (*appointment.Appointment, error) {
+	model := &models.Appointment{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("check_in_code = ?", code).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, checkin.ErrCodeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment by check-in code: %w", err)
+	}
+
+	return r.appointments.toDomain(model), nil
+}
+
+func (r *CheckInRepository) CheckIn(ctx context.Context, change *appointment.StatusChange, entry *queue.PatientQueue, actorID *string) (bool, error) {
+	created := false
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.appointments.applyStatusChange(ctx, tx, change); err != nil {
+			return err
+		}
+
+		// Taken before reading the entries, as insertQueueEntry would take it anyway
+		if err := lockQueue(ctx, tx, entry.OrganizationID, entry.Scope()); err != nil {
+			return err
+		}
+
+		var existingID string
+		err := tx.NewSelect().
+			Model((*models.PatientQueue)(nil)).
+			Column("id").
+			Where("appointment_id = ?", change.AppointmentID).
+			Where("status IN (?)", bun.In(queue.PendingStatuses)).
+			Limit(1).
+			Scan(ctx, &existingID)
+		if err == nil {
+			entry.ID = existingID
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		entry.AppointmentID = change.AppointmentID
+		created = true
+		return insertQueueEntry(ctx, tx, entry, actorID)
+	})
+
+	if err != nil {
+		entry.ID = ""
+		if errors.Is(err, appointment.ErrStatusChanged) {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to check in appointment: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *CheckInRepository) CreateWalkIn(ctx context.Context, apt *appointment.Appointment, entry *queue.PatientQueue, actorID *string) error {
+	from, err := shared.ParseTimeOfDay(apt.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %w", err)
+	}
+	length := time.Duration(apt.Duration) * time.Minute
+
+	var model *models.Appointment
+	err = idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.appointments.lockSlot(ctx, tx, apt); err != nil {
+			return err
+		}
+
+		busy, err := r.busyIntervals(ctx, tx, apt)
+		if err != nil {
+			return err
+		}
+		start, err := checkin.WalkInStart(from, length, busy)
+		if err != nil {
+			return err
+		}
+		apt.StartTime = shared.FormatTimeOfDay(start)
+		apt.EndTime = shared.FormatTimeOfDay(start + length)
+
+		model = r.appointments.toModel(apt)
+		if err := insertAppointment(ctx, tx, model); err != nil {
+			return err
+		}
+
+		entry.AppointmentID = model.ID
+		return insertQueueEntry(ctx, tx, entry, actorID)
+	})
+
+	if err != nil {
+		entry.ID = ""
+		if errors.Is(err, checkin.ErrNoWalkInSlot) {
+			return err
+		}
+		if conflictErr := r.appointments.asConflictError(ctx, err, apt); conflictErr != nil {
+			return conflictErr
+		}
+		return fmt.Errorf("failed to create walk-in appointment: %w", err)
+	}
+
+	apt.ID = model.ID
+	apt.CheckInCode = model.CheckInCode
+	apt.CreatedAt = model.CreatedAt
+	apt.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// busyIntervals returns the times of apt's day already booked for its doctor or patient
+func (r *CheckInRepository) busyIntervals(ctx context.Context, tx bun.Tx, apt *appointment.Appointment) ([]checkin.Interval, error) {
+	var rows []models.Appointment
+	err := tx.NewSelect().
+		Model(&rows).
+		Column("start_time", "end_time").
+		Where("date = ?", apt.Date.Format("2006-01-02")).
+		Where("doctor_id = ? OR patient_id = ?", apt.DoctorID, apt.PatientID).
+		Where("status NOT IN (?)", bun.In([]string{
+			string(appointment.StatusCancelled),
+			string(appointment.StatusNoShow),
+		})).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]checkin.Interval, 0, len(rows))
+	for _, row := range rows {
+		start, err := shared.ParseTimeOfDay(row.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := shared.ParseTimeOfDay(row.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		busy = append(busy, checkin.Interval{Start: start, End: end})
+	}
+
+	return busy, nil
+}
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/display"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// DisplayRepository implements display.Repository
+type DisplayRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewDisplayRepository(db *bun.DB) display.Repository {
+	return &DisplayRepository{
+		db:     db,
+		logger: logger.New(),
+	}
+}
+
+func (r *DisplayRepository) Create(ctx context.Context, token *display.Token) error {
+	model := r.toModel(token)
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create display token: %w", err)
+	}
+
+	token.ID = model.ID
+	return nil
+}
+
+func (r *DisplayRepository) GetByID(ctx context.Context, id string) (*display.Token, error) {
+	return r.getOne(ctx, "id = ?", id)
+}
+
+func (r *DisplayRepository) GetByHash(ctx context.Context, tokenHash string) (*display.Token, error) {
+	return r.getOne(ctx, "token_hash = ?", tokenHash)
+}
+
+func (r *DisplayRepository) getOne(ctx context.Context, query string, arg interface{}) (*display.Token, error) {
+	model := &models.DisplayToken{}
+
+	err := r.db.NewSelect().
+		Model(model).
+		Where(query, arg).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, display.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get display token: %w", err)
+	}
+
+	return r.toDomain(model), nil
+}
+
+func (r *DisplayRepository) GetByOrganization(ctx context.Context, organizationID string) ([]*display.Token, error) {
+	var rows []models.DisplayToken
+
+	err := r.db.NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get display tokens: %w", err)
+	}
+
+	tokens := make([]*display.Token, len(rows))
+	for i, row := range rows {
+		tokens[i] = r.toDomain(&row)
+	}
+
+	return tokens, nil
+}
+
+func (r *DisplayRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.DisplayToken)(nil)).
+		Set("revoked_at = ?", revokedAt).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke display token: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return display.ErrAlreadyRevoked
+	}
+
+	return nil
+}
+
+func (r *DisplayRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.DisplayToken)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark display token as used: %w", err)
+	}
+
+	return nil
+}
+
+// boardRow is a ticket joined with the room the patient is seen in: the queue's own
+// room for room queues, otherwise the appointment's room
+type boardRow struct {
+	TicketNumber string  `bun:"ticket_number"`
+	Status       string  `bun:"status"`
+	RoomName     *string `bun:"room_name"`
+}
+
+func (r *DisplayRepository) GetBoard(ctx context.Context, organizationID string, scope *queue.Scope, nextUpPerQueue int) (*display.Board, error) {
+	var serving []boardRow
+	err := r.boardQuery(organizationID, scope).
+		Where("pq.status IN (?)", bun.In([]string{queue.QueueStatusCalled, queue.QueueStatusInProgress})).
+		OrderExpr("pq.updated_at DESC").
+		Scan(ctx, &serving)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets being served: %w", err)
+	}
+
+	var waiting []boardRow
+	err = r.boardQuery(organizationID, scope).
+		Where("pq.status = ? AND pq.position <= ?", queue.QueueStatusWaiting, nextUpPerQueue).
+		OrderExpr("pq.position ASC, pq.ticket_number ASC").
+		Scan(ctx, &waiting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waiting tickets: %w", err)
+	}
+
+	return &display.Board{
+		NowServing:  toBoardEntries(serving),
+		NextUp:      toBoardEntries(waiting),
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+func (r *DisplayRepository) boardQuery(organizationID string, scope *queue.Scope) *bun.SelectQuery {
+	query := r.db.NewSelect().
+		TableExpr("patient_queues AS pq").
+		ColumnExpr("pq.ticket_number, pq.status").
+		ColumnExpr("COALESCE(sr.name, ar.name) AS room_name").
+		Join("JOIN appointments AS a ON a.id = pq.appointment_id").
+		Join("LEFT JOIN rooms AS sr ON pq.scope_type = ? AND sr.id::text = pq.scope_id", string(queue.ScopeRoom)).
+		Join("LEFT JOIN rooms AS ar ON ar.id = a.room_id").
+		Where("pq.organization_id = ?", organizationID).
+		Where("pq.ticket_number IS NOT NULL").
+		Where("(pq.created_at >= CURRENT_DATE OR pq.carried_over_to = CURRENT_DATE)")
+
+	if scope != nil {
+		query = query.Where("pq.scope_type = ? AND pq.scope_id = ?", string(scope.Type), scope.ID)
+	}
+
+	return query
+}
+
+func toBoardEntries(rows []boardRow) []display.BoardEntry {
+	entries := make([]display.BoardEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = display.BoardEntry{
+			TicketNumber: row.TicketNumber,
+			RoomName:     row.RoomName,
+			Status:       row.Status,
+		}
+	}
+	return entries
+}
+
+func (r *DisplayRepository) toModel(token *display.Token) *models.DisplayToken {
+	model := &models.DisplayToken{
+		ID:             token.ID,
+		OrganizationID: token.OrganizationID,
+		TokenHash:      token.TokenHash,
+		Label:          token.Label,
+		LastUsedAt:     token.LastUsedAt,
+		RevokedAt:      token.RevokedAt,
+		CreatedAt:      token.CreatedAt,
+		UpdatedAt:      token.UpdatedAt,
+	}
+	if token.Scope != nil {
+		scopeType := string(token.Scope.Type)
+		model.ScopeType = &scopeType
+		model.ScopeID = &token.Scope.ID
+	}
+	return model
+}
+
+func (r *DisplayRepository) toDomain(model *models.DisplayToken) *display.Token {
+	token := &display.Token{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		TokenHash:      model.TokenHash,
+		Label:          model.Label,
+		LastUsedAt:     model.LastUsedAt,
+		RevokedAt:      model.RevokedAt,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+	if model.ScopeType != nil && model.ScopeID != nil {
+		token.Scope = &queue.Scope{Type: queue.ScopeType(*model.ScopeType), ID: *model.ScopeID}
+	}
+	return token
+}
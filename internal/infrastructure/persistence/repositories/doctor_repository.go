@@ -53,7 +53,8 @@ func (r *DoctorRepository) GetByID(ctx context.Context, id string) (*doctor.Doct
 	
 	err := r.db.NewSelect().
 		Model(userModel).
-		Where("id = ? AND role = ?", id, "doctor").
+		Relation("Profile").
+		Where("?TableAlias.id = ? AND role = ?", id, "doctor").
 		Scan(ctx)
 
 	if err != nil {
@@ -68,11 +69,12 @@ func (r *DoctorRepository) GetByOrganization(ctx context.Context, organizationID
 	
 	query := r.db.NewSelect().
 		Model(&userModels).
+		Relation("Profile").
 		Where("role = ?", "doctor")
 	
 	// Only filter by organization if organizationID is provided
 	if organizationID != "" {
-		query = query.Where("organization_id = ?", organizationID)
+		query = query.Where("?TableAlias.organization_id = ?", organizationID)
 	}
 	
 	err := query.
@@ -147,12 +149,18 @@ func (r *DoctorRepository) CountByOrganization(ctx context.Context, organization
 
 func (r *DoctorRepository) toDomain(userModel *models.User) *doctor.Doctor {
 	// This is a simplified conversion - in a real app you'd have more complex logic
+	var specialization string
+	if userModel.Profile != nil && userModel.Profile.Specialization != nil {
+		specialization = *userModel.Profile.Specialization
+	}
+
 	return &doctor.Doctor{
 		ID:             userModel.ID,
 		UserID:         userModel.ID,
 		Name:           userModel.Name,
 		Email:          userModel.Email,
 		Phone:          *userModel.Phone,
+		Specialization: specialization,
 		OrganizationID: *userModel.OrganizationID,
 		Status:         "active",
 		CreatedAt:      userModel.CreatedAt,
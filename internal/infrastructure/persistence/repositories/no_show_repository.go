@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/noshow"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// NoShowRepository implements noshow.Repository
+type NoShowRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewNoShowRepository(db *bun.DB) noshow.Repository {
+	return &NoShowRepository{
+		db:     db,
+		logger: logger.New(),
+	}
+}
+
+func (r *NoShowRepository) GetPolicy(ctx context.Context, organizationID string) (*noshow.Policy, error) {
+	model := &models.NoShowPolicy{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("organization_id = ?", organizationID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, noshow.ErrPolicyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get no-show policy: %w", err)
+	}
+
+	return r.toDomain(model), nil
+}
+
+func (r *NoShowRepository) SavePolicy(ctx context.Context, policy *noshow.Policy) error {
+	model := r.toModel(policy)
+
+	err := idb(ctx, r.db).NewInsert().
+		Model(model).
+		On("CONFLICT (organization_id) DO UPDATE").
+		Set("auto_mark = EXCLUDED.auto_mark").
+		Set("grace_period_minutes = EXCLUDED.grace_period_minutes").
+		Set("confirmation_threshold = EXCLUDED.confirmation_threshold").
+		Set("confirmation_lead_hours = EXCLUDED.confirmation_lead_hours").
+		Set("deposit_threshold = EXCLUDED.deposit_threshold").
+		Set("deposit_amount = EXCLUDED.deposit_amount").
+		Returning("created_at, updated_at").
+		Scan(ctx, &policy.CreatedAt, &policy.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save no-show policy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NoShowRepository) GetPatientRecord(ctx context.Context, organizationID, patientID string) (*noshow.PatientRecord, error) {
+	model := &models.PatientNoShowStats{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("organization_id = ?", organizationID).
+		Where("patient_id = ?", patientID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &noshow.PatientRecord{
+			OrganizationID: organizationID,
+			PatientID:      patientID,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get patient no-show record: %w", err)
+	}
+
+	return &noshow.PatientRecord{
+		OrganizationID: model.OrganizationID,
+		PatientID:      model.PatientID,
+		NoShowCount:    model.NoShowCount,
+		LastNoShowAt:   model.LastNoShowAt,
+	}, nil
+}
+
+func (r *NoShowRepository) toModel(policy *noshow.Policy) *models.NoShowPolicy {
+	return &models.NoShowPolicy{
+		OrganizationID:        policy.OrganizationID,
+		AutoMark:              policy.AutoMark,
+		GracePeriodMinutes:    policy.GracePeriodMinutes,
+		ConfirmationThreshold: policy.ConfirmationThreshold,
+		ConfirmationLeadHours: policy.ConfirmationLeadHours,
+		DepositThreshold:      policy.DepositThreshold,
+		DepositAmount:         policy.DepositAmount,
+		CreatedAt:             policy.CreatedAt,
+		UpdatedAt:             policy.UpdatedAt,
+	}
+}
+
+func (r *NoShowRepository) toDomain(model *models.NoShowPolicy) *noshow.Policy {
+	return &noshow.Policy{
+		OrganizationID:        model.OrganizationID,
+		AutoMark:              model.AutoMark,
+		GracePeriodMinutes:    model.GracePeriodMinutes,
+		ConfirmationThreshold: model.ConfirmationThreshold,
+		ConfirmationLeadHours: model.ConfirmationLeadHours,
+		DepositThreshold:      model.DepositThreshold,
+		DepositAmount:         model.DepositAmount,
+		CreatedAt:             model.CreatedAt,
+		UpdatedAt:             model.UpdatedAt,
+	}
+}
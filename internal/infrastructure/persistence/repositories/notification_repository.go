@@ -2,7 +2,11 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
+
 	"medika-backend/internal/domain/notification"
 	"medika-backend/internal/domain/shared"
 	"medika-backend/internal/infrastructure/persistence/models"
@@ -22,8 +26,17 @@ func NewNotificationRepository(db *bun.DB) *NotificationRepository {
 
 func (r *NotificationRepository) Create(ctx context.Context, notif *notification.Notification) error {
 	model := r.toModel(notif)
-	_, err := r.db.NewInsert().Model(model).Exec(ctx)
-	return err
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return err
+		}
+
+		if notif.ScheduledFor() != nil {
+			// Delivered once ClaimDue finds it due
+			return nil
+		}
+		return createDeliveries(ctx, tx, []models.Notification{*model}, notif.CreatedAt())
+	})
 }
 
 func (r *NotificationRepository) FindByID(ctx context.Context, id notification.NotificationID) (*notification.Notification, error) {
@@ -45,7 +58,8 @@ func (r *NotificationRepository) FindByUserID(ctx context.Context, userID shared
 	
 	query := r.db.NewSelect().
 		Model(&models).
-		Where("user_id = ?", userID.String())
+		Where("user_id = ?", userID.String()).
+		Where("scheduled_for IS NULL OR sent_at IS NOT NULL") // Scheduled notifications show up once sent
 	
 	// Apply filters
 	if filters.Type != nil {
@@ -96,7 +110,8 @@ func (r *NotificationRepository) FindByUserID(ctx context.Context, userID shared
 func (r *NotificationRepository) CountByUserID(ctx context.Context, userID shared.UserID, filters notification.NotificationFilters) (int, error) {
 	query := r.db.NewSelect().
 		Model((*models.Notification)(nil)).
-		Where("user_id = ?", userID.String())
+		Where("user_id = ?", userID.String()).
+		Where("scheduled_for IS NULL OR sent_at IS NOT NULL") // Scheduled notifications show up once sent
 	
 	// Apply filters
 	if filters.Type != nil {
@@ -159,6 +174,363 @@ func (r *NotificationRepository) DeleteByUserID(ctx context.Context, userID shar
 	return err
 }
 
+func (r *NotificationRepository) ReplaceScheduled(ctx context.Context, appointmentID string, scheduled []*notification.Notification) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Serialize replacements for the same appointment so reminders are never doubled
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "notification:appointment:"+appointmentID); err != nil {
+			return err
+		}
+
+		_, err := tx.NewDelete().
+			Model((*models.Notification)(nil)).
+			Where("appointment_id = ?", appointmentID).
+			Where("scheduled_for IS NOT NULL").
+			Where("sent_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(scheduled) == 0 {
+			return nil
+		}
+
+		rows := make([]*models.Notification, len(scheduled))
+		for i, notif := range scheduled {
+			rows[i] = r.toModel(notif)
+		}
+
+		_, err = tx.NewInsert().
+			Model(&rows).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to replace scheduled notifications: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*notification.Notification, error) {
+	var rows []models.Notification
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		due := tx.NewSelect().
+			Model((*models.Notification)(nil)).
+			Column("id").
+			Where("sent_at IS NULL").
+			Where("scheduled_for <= ?", now).
+			Order("scheduled_for ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED")
+
+		err := tx.NewUpdate().
+			Model((*models.Notification)(nil)).
+			Set("sent_at = ?", now).
+			Where("id IN (?)", due).
+			Returning("*").
+			Scan(ctx, &rows)
+		if err != nil {
+			return err
+		}
+
+		return createDeliveries(ctx, tx, rows, now)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due notifications: %w", err)
+	}
+
+	notifications := make([]*notification.Notification, len(rows))
+	for i, row := range rows {
+		notifications[i] = r.toDomain(&row)
+	}
+
+	return notifications, nil
+}
+
+// createDeliveries adds a pending delivery, due at, for each external channel of the
+// given notifications
+func createDeliveries(ctx context.Context, tx bun.Tx, notifications []models.Notification, at time.Time) error {
+	var deliveries []models.NotificationDelivery
+	for _, notif := range notifications {
+		for _, channel := range notification.ExternalChannels(notif.Channels) {
+			deliveries = append(deliveries, models.NotificationDelivery{
+				NotificationID: notif.ID,
+				Channel:        channel,
+				Status:         string(notification.DeliveryPending),
+				NextAttemptAt:  at,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	_, err := tx.NewInsert().
+		Model(&deliveries).
+		On("CONFLICT (notification_id, channel) DO NOTHING").
+		Exec(ctx)
+	return err
+}
+
+func (r *NotificationRepository) ClaimDeliveries(ctx context.Context, now time.Time, limit int) ([]*notification.Delivery, error) {
+	due := r.db.NewSelect().
+		Model((*models.NotificationDelivery)(nil)).
+		Column("id").
+		Where("status = ?", string(notification.DeliveryPending)).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var rows []models.NotificationDelivery
+	err := r.db.NewUpdate().
+		Model((*models.NotificationDelivery)(nil)).
+		Set("attempts = attempts + 1").
+		Set("next_attempt_at = ?", now.Add(notification.DeliveryLease)).
+		Set("updated_at = ?", now).
+		Where("id IN (?)", due).
+		Returning("*").
+		Scan(ctx, &rows)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notification deliveries: %w", err)
+	}
+
+	deliveries := make([]*notification.Delivery, len(rows))
+	for i := range rows {
+		deliveries[i] = r.toDeliveryDomain(&rows[i])
+	}
+
+	return deliveries, nil
+}
+
+func (r *NotificationRepository) RecordDelivery(ctx context.Context, delivery *notification.Delivery) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.NotificationDelivery)(nil)).
+		Set("status = ?", string(delivery.Status)).
+		Set("attempts = ?", delivery.Attempts).
+		Set("last_error = ?", delivery.LastError).
+		Set("next_attempt_at = ?", delivery.NextAttemptAt).
+		Set("sent_at = ?", delivery.SentAt).
+		Set("updated_at = ?", delivery.UpdatedAt).
+		Where("id = ?", delivery.ID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record notification delivery: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return notification.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) FindDeliveries(ctx context.Context, id notification.NotificationID) ([]*notification.Delivery, error) {
+	var rows []models.NotificationDelivery
+	err := r.db.NewSelect().
+		Model(&rows).
+		Where("notification_id = ?", id.String()).
+		Order("channel ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification deliveries: %w", err)
+	}
+
+	deliveries := make([]*notification.Delivery, len(rows))
+	for i := range rows {
+		deliveries[i] = r.toDeliveryDomain(&rows[i])
+	}
+
+	return deliveries, nil
+}
+
+func (r *NotificationRepository) GetTemplate(ctx context.Context, organizationID string, key notification.TemplateKey, locale notification.Locale) (*notification.Template, error) {
+	model := &models.NotificationTemplate{}
+
+	err := r.db.NewSelect().
+		Model(model).
+		Where("organization_id = ?", organizationID).
+		Where("key = ?", string(key)).
+		Where("locale = ?", string(locale)).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notification.ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification template: %w", err)
+	}
+
+	return r.toTemplateDomain(model), nil
+}
+
+func (r *NotificationRepository) FindTemplates(ctx context.Context, organizationID string) ([]*notification.Template, error) {
+	var rows []models.NotificationTemplate
+
+	err := r.db.NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID).
+		Order("key ASC", "locale ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification templates: %w", err)
+	}
+
+	templates := make([]*notification.Template, len(rows))
+	for i := range rows {
+		templates[i] = r.toTemplateDomain(&rows[i])
+	}
+
+	return templates, nil
+}
+
+func (r *NotificationRepository) SaveTemplate(ctx context.Context, template *notification.Template) error {
+	model := &models.NotificationTemplate{
+		OrganizationID: *template.OrganizationID,
+		Key:            string(template.Key),
+		Locale:         string(template.Locale),
+		Title:          template.Title,
+		Body:           template.Body,
+		SMSBody:        template.SMSBody,
+		EmailHTML:      template.EmailHTML,
+	}
+
+	err := r.db.NewInsert().
+		Model(model).
+		On("CONFLICT (organization_id, key, locale) DO UPDATE").
+		Set("title = EXCLUDED.title").
+		Set("body = EXCLUDED.body").
+		Set("sms_body = EXCLUDED.sms_body").
+		Set("email_html = EXCLUDED.email_html").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Returning("created_at, updated_at").
+		Scan(ctx, &template.CreatedAt, &template.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save notification template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) DeleteTemplate(ctx context.Context, organizationID string, key notification.TemplateKey, locale notification.Locale) error {
+	result, err := r.db.NewDelete().
+		Model((*models.NotificationTemplate)(nil)).
+		Where("organization_id = ?", organizationID).
+		Where("key = ?", string(key)).
+		Where("locale = ?", string(locale)).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification template: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return notification.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID string) (*notification.Preferences, error) {
+	model := &models.NotificationPreference{}
+
+	err := r.db.NewSelect().
+		Model(model).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notification.ErrPreferencesNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	preferences := &notification.Preferences{
+		UserID:    model.UserID,
+		Timezone:  model.Timezone,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+	if model.QuietStart != nil && model.QuietEnd != nil {
+		preferences.QuietHours = &notification.QuietHours{Start: *model.QuietStart, End: *model.QuietEnd}
+	}
+	if len(model.Channels) > 0 {
+		preferences.Channels = make(map[notification.NotificationType]map[string]bool, len(model.Channels))
+		for notificationType, channels := range model.Channels {
+			preferences.Channels[notification.NotificationType(notificationType)] = channels
+		}
+	}
+
+	return preferences, nil
+}
+
+func (r *NotificationRepository) SavePreferences(ctx context.Context, preferences *notification.Preferences) error {
+	model := &models.NotificationPreference{
+		UserID:   preferences.UserID,
+		Timezone: preferences.Timezone,
+		Channels: make(map[string]map[string]bool, len(preferences.Channels)),
+	}
+	if preferences.QuietHours != nil {
+		model.QuietStart = &preferences.QuietHours.Start
+		model.QuietEnd = &preferences.QuietHours.End
+	}
+	for notificationType, channels := range preferences.Channels {
+		model.Channels[string(notificationType)] = channels
+	}
+
+	err := r.db.NewInsert().
+		Model(model).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("timezone = EXCLUDED.timezone").
+		Set("quiet_start = EXCLUDED.quiet_start").
+		Set("quiet_end = EXCLUDED.quiet_end").
+		Set("channels = EXCLUDED.channels").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Returning("created_at, updated_at").
+		Scan(ctx, &preferences.CreatedAt, &preferences.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) toTemplateDomain(model *models.NotificationTemplate) *notification.Template {
+	organizationID := model.OrganizationID
+	return &notification.Template{
+		Key:            notification.TemplateKey(model.Key),
+		Locale:         notification.Locale(model.Locale),
+		OrganizationID: &organizationID,
+		Title:          model.Title,
+		Body:           model.Body,
+		SMSBody:        model.SMSBody,
+		EmailHTML:      model.EmailHTML,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
+func (r *NotificationRepository) toDeliveryDomain(model *models.NotificationDelivery) *notification.Delivery {
+	return &notification.Delivery{
+		ID:             model.ID,
+		NotificationID: notification.NotificationID(model.NotificationID),
+		Channel:        model.Channel,
+		Status:         notification.DeliveryStatus(model.Status),
+		Attempts:       model.Attempts,
+		LastError:      model.LastError,
+		NextAttemptAt:  model.NextAttemptAt,
+		SentAt:         model.SentAt,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
 func (r *NotificationRepository) toModel(notif *notification.Notification) *models.Notification {
 	data := make(models.JSONB)
 	if notif.Data() != nil {
@@ -168,18 +540,22 @@ func (r *NotificationRepository) toModel(notif *notification.Notification) *mode
 	}
 	
 	return &models.Notification{
-		ID:           notif.ID().String(),
-		UserID:       notif.UserID().String(),
-		Type:         string(notif.Type()),
-		Title:        notif.Title(),
-		Message:      notif.Message(),
-		Data:         data,
-		IsRead:       notif.IsRead(),
-		Channels:     notif.Channels(),
-		Priority:     string(notif.Priority()),
-		ScheduledFor: notif.ScheduledFor(),
-		SentAt:       notif.SentAt(),
-		CreatedAt:    notif.CreatedAt(),
+		ID:            notif.ID().String(),
+		UserID:        notif.UserID().String(),
+		Type:          string(notif.Type()),
+		Title:         notif.Title(),
+		Message:       notif.Message(),
+		Data:          data,
+		IsRead:        notif.IsRead(),
+		Channels:      notif.Channels(),
+		Priority:      string(notif.Priority()),
+		AppointmentID: notif.AppointmentID(),
+		Template:      string(notif.Template()),
+		SMSBody:       notif.SMSBody(),
+		EmailHTML:     notif.EmailHTML(),
+		ScheduledFor:  notif.ScheduledFor(),
+		SentAt:        notif.SentAt(),
+		CreatedAt:     notif.CreatedAt(),
 	}
 }
 
@@ -192,18 +568,22 @@ func (r *NotificationRepository) toDomain(model *models.Notification) *notificat
 	}
 	
 	userID, _ := shared.NewUserIDFromString(model.UserID)
-	notif := notification.NewNotification(
+	return notification.ReconstructNotification(
+		notification.NotificationID(model.ID),
 		userID,
 		model.Title,
 		model.Message,
 		notification.NotificationType(model.Type),
 		notification.Priority(model.Priority),
+		model.IsRead,
 		model.Channels,
 		data,
+		model.AppointmentID,
+		notification.TemplateKey(model.Template),
+		model.SMSBody,
+		model.EmailHTML,
+		model.ScheduledFor,
+		model.SentAt,
+		model.CreatedAt,
 	)
-	
-	// Set the ID and timestamps from the model
-	// Note: This is a bit of a hack since the domain entity doesn't expose setters
-	// In a real implementation, you might want to add a constructor that accepts these values
-	return notif
 }
@@ -123,6 +123,8 @@ func (r *OrganizationRepository) toModel(org *organization.Organization) *models
 		Phone:       org.Phone,
 		Email:       org.Email,
 		Website:     org.Website,
+		BusinessHours: businessHoursToModel(org.BusinessHours),
+		Timezone:    org.Timezone,
 		IsActive:    org.IsActive,
 		CreatedAt:   org.CreatedAt,
 		UpdatedAt:   org.UpdatedAt,
@@ -138,8 +140,26 @@ func (r *OrganizationRepository) toDomain(model *models.Organization) *organizat
 		Phone:       model.Phone,
 		Email:       model.Email,
 		Website:     model.Website,
+		BusinessHours: businessHoursToDomain(model.BusinessHours),
+		Timezone:    model.Timezone,
 		IsActive:    model.IsActive,
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
 	}
 }
+
+func businessHoursToModel(hours []organization.BusinessHours) []models.BusinessHours {
+	result := make([]models.BusinessHours, len(hours))
+	for i, h := range hours {
+		result[i] = models.BusinessHours{Day: h.Day, Open: h.Open, Close: h.Close, IsOpen: h.IsOpen}
+	}
+	return result
+}
+
+func businessHoursToDomain(hours []models.BusinessHours) []organization.BusinessHours {
+	result := make([]organization.BusinessHours, len(hours))
+	for i, h := range hours {
+		result[i] = organization.BusinessHours{Day: h.Day, Open: h.Open, Close: h.Close, IsOpen: h.IsOpen}
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+
+	"medika-backend/internal/domain/outbox"
+	"medika-backend/internal/infrastructure/persistence/models"
+)
+
+// OutboxRepository implements outbox.Repository
+type OutboxRepository struct {
+	db bun.IDB
+}
+
+func NewOutboxRepository(db *bun.DB) outbox.Repository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Append(ctx context.Context, msg *outbox.Message) error {
+	model := &models.OutboxMessage{
+		EventType:      msg.EventType,
+		OrganizationID: msg.OrganizationID,
+		Payload:        msg.Payload,
+		Attempts:       msg.Attempts,
+		NextAttemptAt:  msg.NextAttemptAt,
+		CreatedAt:      msg.CreatedAt,
+		UpdatedAt:      msg.UpdatedAt,
+	}
+
+	_, err := idb(ctx, r.db).NewInsert().
+		Model(model).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to append %s event to outbox: %w", msg.EventType, err)
+	}
+
+	msg.ID = model.ID
+	return nil
+}
+
+func (r *OutboxRepository) Claim(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error) {
+	due := idb(ctx, r.db).NewSelect().
+		Model((*models.OutboxMessage)(nil)).
+		Column("id").
+		Where("next_attempt_at <= ?", now).
+		Order("created_at ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var rows []models.OutboxMessage
+	err := idb(ctx, r.db).NewUpdate().
+		Model((*models.OutboxMessage)(nil)).
+		Set("attempts = attempts + 1").
+		Set("next_attempt_at = ?", now.Add(outbox.Lease)).
+		Set("updated_at = ?", now).
+		Where("id IN (?)", due).
+		Returning("*").
+		Scan(ctx, &rows)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	// RETURNING does not keep the subquery's order
+	messages := make([]*outbox.Message, len(rows))
+	for i := range rows {
+		messages[i] = toOutboxMessage(&rows[i])
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) Delete(ctx context.Context, id string) error {
+	_, err := idb(ctx, r.db).NewDelete().
+		Model((*models.OutboxMessage)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox message: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) Retry(ctx context.Context, msg *outbox.Message) error {
+	_, err := idb(ctx, r.db).NewUpdate().
+		Model((*models.OutboxMessage)(nil)).
+		Set("delivered = ?", pgdialect.Array(msg.Delivered)).
+		Set("last_error = ?", msg.LastError).
+		Set("next_attempt_at = ?", msg.NextAttemptAt).
+		Set("updated_at = ?", msg.UpdatedAt).
+		Where("id = ?", msg.ID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record outbox attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) Bury(ctx context.Context, msg *outbox.Message, at time.Time) error {
+	lastError := ""
+	if msg.LastError != nil {
+		lastError = *msg.LastError
+	}
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		deadLetter := &models.OutboxDeadLetter{
+			ID:             msg.ID,
+			EventType:      msg.EventType,
+			OrganizationID: msg.OrganizationID,
+			Payload:        msg.Payload,
+			Attempts:       msg.Attempts,
+			Delivered:      msg.Delivered,
+			LastError:      lastError,
+			CreatedAt:      msg.CreatedAt,
+			FailedAt:       at,
+		}
+		if _, err := tx.NewInsert().Model(deadLetter).Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.NewDelete().
+			Model((*models.OutboxMessage)(nil)).
+			Where("id = ?", msg.ID).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to move outbox message to dead letters: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) FindDeadLetters(ctx context.Context, organizationID, eventType string, limit, offset int) ([]*outbox.DeadLetter, error) {
+	var rows []models.OutboxDeadLetter
+	query := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID).
+		Order("failed_at DESC").
+		Limit(limit).
+		Offset(offset)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	deadLetters := make([]*outbox.DeadLetter, len(rows))
+	for i, row := range rows {
+		deadLetters[i] = &outbox.DeadLetter{
+			ID:             row.ID,
+			EventType:      row.EventType,
+			OrganizationID: row.OrganizationID,
+			Payload:        row.Payload,
+			Attempts:       row.Attempts,
+			Delivered:      row.Delivered,
+			LastError:      row.LastError,
+			CreatedAt:      row.CreatedAt,
+			FailedAt:       row.FailedAt,
+		}
+	}
+
+	return deadLetters, nil
+}
+
+func (r *OutboxRepository) CountDeadLetters(ctx context.Context, organizationID, eventType string) (int, error) {
+	query := idb(ctx, r.db).NewSelect().
+		Model((*models.OutboxDeadLetter)(nil)).
+		Where("organization_id = ?", organizationID)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	count, err := query.Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+	return count, nil
+}
+
+func (r *OutboxRepository) Replay(ctx context.Context, organizationID, id string, at time.Time) (*outbox.Message, error) {
+	model := &models.OutboxMessage{}
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var deadLetter models.OutboxDeadLetter
+		err := tx.NewDelete().
+			Model(&deadLetter).
+			Where("id = ?", id).
+			Where("organization_id = ?", organizationID).
+			Returning("*").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		model = &models.OutboxMessage{
+			ID:             deadLetter.ID,
+			EventType:      deadLetter.EventType,
+			OrganizationID: deadLetter.OrganizationID,
+			Payload:        deadLetter.Payload,
+			Delivered:      deadLetter.Delivered,
+			NextAttemptAt:  at,
+			CreatedAt:      deadLetter.CreatedAt,
+			UpdatedAt:      at,
+		}
+		_, err = tx.NewInsert().Model(model).Exec(ctx)
+		return err
+	})
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, outbox.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay dead letter: %w", err)
+	}
+
+	return toOutboxMessage(model), nil
+}
+
+func toOutboxMessage(model *models.OutboxMessage) *outbox.Message {
+	return &outbox.Message{
+		ID:             model.ID,
+		EventType:      model.EventType,
+		OrganizationID: model.OrganizationID,
+		Payload:        model.Payload,
+		Attempts:       model.Attempts,
+		Delivered:      model.Delivered,
+		LastError:      model.LastError,
+		NextAttemptAt:  model.NextAttemptAt,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
@@ -2,11 +2,17 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/uptrace/bun"
 
+	"medika-backend/internal/domain/appointment"
 	"medika-backend/internal/domain/queue"
 	"medika-backend/internal/infrastructure/persistence/models"
 	"medika-backend/pkg/logger"
@@ -25,35 +31,130 @@ func NewQueueRepository(db *bun.DB) queue.Repository {
 	}
 }
 
-func (r *QueueRepository) Create(ctx context.Context, q *queue.PatientQueue) error {
+func (r *QueueRepository) Create(ctx context.Context, q *queue.PatientQueue, actorID *string) error {
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return insertQueueEntry(ctx, tx, q, actorID)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create queue: %w", err)
+	}
+
+	return nil
+}
+
+// insertQueueEntry adds q to the end of its queue with a new ticket, records it in the
+// queue event log and ranks it among those already waiting
+func insertQueueEntry(ctx context.Context, tx bun.Tx, q *queue.PatientQueue, actorID *string) error {
 	queueModel := &models.PatientQueue{
 		ID:                q.ID,
 		AppointmentID:     q.AppointmentID,
 		OrganizationID:    q.OrganizationID,
+		ScopeType:         string(q.ScopeType),
+		ScopeID:           q.ScopeID,
+		TriageLevel:       int(q.TriageLevel),
 		Position:          q.Position,
 		EstimatedWaitTime: q.EstimatedWaitTime,
+		EstimatedWaitLow:  q.EstimatedWaitLow,
+		EstimatedWaitHigh: q.EstimatedWaitHigh,
 		Status:            q.Status,
 	}
 
-	_, err := r.db.NewInsert().
+	if err := lockQueue(ctx, tx, q.OrganizationID, q.Scope()); err != nil {
+		return err
+	}
+
+	last, err := lastWaitingPosition(ctx, tx, q.OrganizationID, q.Scope())
+	if err != nil {
+		return err
+	}
+	queueModel.Position = last + 1
+
+	ticket, err := issueTicket(ctx, tx, q.OrganizationID, q.Scope(), time.Now())
+	if err != nil {
+		return err
+	}
+	queueModel.TicketNumber = ticket
+
+	_, err = tx.NewInsert().
 		Model(queueModel).
 		Exec(ctx)
-
 	if err != nil {
-		return fmt.Errorf("failed to create queue: %w", err)
+		return err
+	}
+
+	if err := logStatusChange(ctx, tx, queueModel.ID, q.OrganizationID, "", q.Status, actorID, queueModel.CreatedAt); err != nil {
+		return err
 	}
 
+	if err := renumberWaitingQueue(ctx, tx, q.OrganizationID, q.Scope()); err != nil {
+		return err
+	}
+
+	q.ID = queueModel.ID
+	q.Position = queueModel.Position
+	q.TicketNumber = queueModel.TicketNumber
+	q.CreatedAt = queueModel.CreatedAt
+	q.UpdatedAt = queueModel.UpdatedAt
 	return nil
 }
 
+// issueTicket hands out the next of day's ticket numbers for a queue. A queue gets its
+// prefix letter when it issues its first ticket of the day.
+func issueTicket(ctx context.Context, tx bun.Tx, organizationID string, scope queue.Scope, day time.Time) (string, error) {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "queue:tickets:"+organizationID); err != nil {
+		return "", fmt.Errorf("failed to lock ticket counters: %w", err)
+	}
+
+	counter := &models.QueueTicketCounter{}
+	err := tx.NewUpdate().
+		Model(counter).
+		Set("last_number = last_number + 1").
+		Where("organization_id = ? AND day = ?", organizationID, day.Format("2006-01-02")).
+		Where("scope_type = ? AND scope_id = ?", string(scope.Type), scope.ID).
+		Returning("prefix, last_number").
+		Scan(ctx)
+	if err == nil {
+		return queue.FormatTicket(counter.Prefix, counter.LastNumber), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to advance ticket counter: %w", err)
+	}
+
+	queuesToday, err := tx.NewSelect().
+		Model((*models.QueueTicketCounter)(nil)).
+		Where("organization_id = ? AND day = ?", organizationID, day.Format("2006-01-02")).
+		Count(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to count ticket counters: %w", err)
+	}
+
+	counter = &models.QueueTicketCounter{
+		OrganizationID: organizationID,
+		ScopeType:      string(scope.Type),
+		ScopeID:        scope.ID,
+		Day:            sqlDate(day),
+		Prefix:         queue.TicketPrefix(queuesToday),
+		LastNumber:     1,
+	}
+	if _, err := tx.NewInsert().Model(counter).Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to create ticket counter: %w", err)
+	}
+
+	return queue.FormatTicket(counter.Prefix, counter.LastNumber), nil
+}
+
 func (r *QueueRepository) GetByID(ctx context.Context, id string) (*queue.PatientQueue, error) {
 	queueModel := &models.PatientQueue{}
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(queueModel).
 		Where("id = ?", id).
 		Scan(ctx)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, queue.ErrNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get queue: %w", err)
 	}
@@ -64,7 +165,7 @@ func (r *QueueRepository) GetByID(ctx context.Context, id string) (*queue.Patien
 func (r *QueueRepository) GetByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*queue.PatientQueue, error) {
 	var queueModels []models.PatientQueue
 	
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model(&queueModels)
 	
 	// Only filter by organization if organizationID is provided
@@ -93,7 +194,7 @@ func (r *QueueRepository) GetByOrganization(ctx context.Context, organizationID
 func (r *QueueRepository) GetByAppointment(ctx context.Context, appointmentID string) (*queue.PatientQueue, error) {
 	queueModel := &models.PatientQueue{}
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(queueModel).
 		Where("appointment_id = ?", appointmentID).
 		Scan(ctx)
@@ -109,11 +210,13 @@ func (r *QueueRepository) GetByPatient(ctx context.Context, patientID string) (*
 	queueModel := &models.PatientQueue{}
 	
 	// Join with appointments to get the patient's queue entry
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(queueModel).
-		Join("JOIN appointments ON patient_queues.appointment_id = appointments.id").
+		Join("JOIN appointments ON patient_queue.appointment_id = appointments.id").
 		Where("appointments.patient_id = ?", patientID).
-		Where("appointments.date = CURRENT_DATE"). // Only today's appointments
+		Where("appointments.date = CURRENT_DATE OR patient_queue.carried_over_to = CURRENT_DATE"). // Only today's queue
+		OrderExpr("patient_queue.created_at DESC").
+		Limit(1).
 		Scan(ctx)
 
 	if err != nil {
@@ -127,38 +230,56 @@ func (r *QueueRepository) GetPatientQueueWithDetails(ctx context.Context, patien
 	// Use a raw query to get all the data we need in one go
 	var result struct {
 		// Queue fields
-		QueueID                string    `bun:"queue_id"`
-		AppointmentID          string    `bun:"appointment_id"`
-		OrganizationID         string    `bun:"organization_id"`
-		Position               int       `bun:"position"`
-		EstimatedWaitTime      int       `bun:"estimated_wait_time"`
-		Status                 string    `bun:"status"`
-		QueueCreatedAt         time.Time `bun:"queue_created_at"`
-		QueueUpdatedAt         time.Time `bun:"queue_updated_at"`
+		QueueID                string     `bun:"queue_id"`
+		AppointmentID          string     `bun:"appointment_id"`
+		OrganizationID         string     `bun:"organization_id"`
+		ScopeType              string     `bun:"scope_type"`
+		ScopeID                string     `bun:"scope_id"`
+		TriageLevel            int        `bun:"triage_level"`
+		TicketNumber           string     `bun:"ticket_number"`
+		Position               int        `bun:"position"`
+		EstimatedWaitTime      int        `bun:"estimated_wait_time"`
+		EstimatedWaitLow       int        `bun:"estimated_wait_low"`
+		EstimatedWaitHigh      int        `bun:"estimated_wait_high"`
+		Status                 string     `bun:"status"`
+		CalledAt               *time.Time `bun:"called_at"`
+		StartedAt              *time.Time `bun:"started_at"`
+		CompletedAt            *time.Time `bun:"completed_at"`
+		QueueCreatedAt         time.Time  `bun:"queue_created_at"`
+		QueueUpdatedAt         time.Time  `bun:"queue_updated_at"`
 		
 		// Patient fields
-		PatientName            string    `bun:"patient_name"`
-		PatientID              string    `bun:"patient_id"`
+		PatientName            string     `bun:"patient_name"`
+		PatientID              string     `bun:"patient_id"`
 		
 		// Doctor fields
-		DoctorName             string    `bun:"doctor_name"`
-		DoctorID               string    `bun:"doctor_id"`
+		DoctorName             string     `bun:"doctor_name"`
+		DoctorID               string     `bun:"doctor_id"`
 		
 		// Appointment fields
-		AppointmentDate        string    `bun:"appointment_date"`
-		AppointmentTime        string    `bun:"appointment_time"`
-		AppointmentType        string    `bun:"appointment_type"`
-		AppointmentStatus      string    `bun:"appointment_status"`
+		AppointmentDate        string     `bun:"appointment_date"`
+		AppointmentTime        string     `bun:"appointment_time"`
+		AppointmentType        string     `bun:"appointment_type"`
+		AppointmentStatus      string     `bun:"appointment_status"`
 	}
 	
-	err := r.db.NewRaw(`
+	err := idb(ctx, r.db).NewRaw(`
 		SELECT 
 			pq.id as queue_id,
 			pq.appointment_id,
 			pq.organization_id,
+			pq.scope_type,
+			pq.scope_id,
+			pq.triage_level,
+			COALESCE(pq.ticket_number, '') as ticket_number,
 			pq.position,
 			pq.estimated_wait_time,
+			pq.estimated_wait_low,
+			pq.estimated_wait_high,
 			pq.status,
+			pq.called_at,
+			pq.started_at,
+			pq.completed_at,
 			pq.created_at as queue_created_at,
 			pq.updated_at as queue_updated_at,
 			u.name as patient_name,
@@ -174,7 +295,8 @@ func (r *QueueRepository) GetPatientQueueWithDetails(ctx context.Context, patien
 		JOIN users u ON a.patient_id = u.id
 		JOIN users d ON a.doctor_id = d.id
 		WHERE a.patient_id = ? 
-		AND a.date = CURRENT_DATE
+		AND (a.date = CURRENT_DATE OR pq.carried_over_to = CURRENT_DATE)
+		ORDER BY pq.created_at DESC
 		LIMIT 1
 	`, patientID).Scan(ctx, &result)
 
@@ -193,9 +315,18 @@ func (r *QueueRepository) GetPatientQueueWithDetails(ctx context.Context, patien
 			ID:                result.QueueID,
 			AppointmentID:     result.AppointmentID,
 			OrganizationID:    result.OrganizationID,
+			ScopeType:         queue.ScopeType(result.ScopeType),
+			ScopeID:           result.ScopeID,
+			TriageLevel:       queue.TriageLevel(result.TriageLevel),
+			TicketNumber:      result.TicketNumber,
 			Position:          result.Position,
 			EstimatedWaitTime: result.EstimatedWaitTime,
+			EstimatedWaitLow:  result.EstimatedWaitLow,
+			EstimatedWaitHigh: result.EstimatedWaitHigh,
 			Status:            result.Status,
+			CalledAt:          result.CalledAt,
+			StartedAt:         result.StartedAt,
+			CompletedAt:       result.CompletedAt,
 			CreatedAt:         result.QueueCreatedAt,
 			UpdatedAt:         result.QueueUpdatedAt,
 		},
@@ -212,34 +343,93 @@ func (r *QueueRepository) GetPatientQueueWithDetails(ctx context.Context, patien
 	return queueWithDetails, nil
 }
 
-func (r *QueueRepository) Update(ctx context.Context, q *queue.PatientQueue) error {
+func (r *QueueRepository) Update(ctx context.Context, q *queue.PatientQueue, actorID *string) error {
 	queueModel := &models.PatientQueue{
 		ID:                q.ID,
 		AppointmentID:     q.AppointmentID,
 		OrganizationID:    q.OrganizationID,
+		ScopeType:         string(q.ScopeType),
+		ScopeID:           q.ScopeID,
+		TriageLevel:       int(q.TriageLevel),
 		Position:          q.Position,
-		EstimatedWaitTime: q.EstimatedWaitTime,
 		Status:            q.Status,
+		CalledAt:          q.CalledAt,
+		StartedAt:         q.StartedAt,
+		CompletedAt:       q.CompletedAt,
+		CreatedAt:         q.CreatedAt,
+		UpdatedAt:         time.Now(),
 	}
 
-	_, err := r.db.NewUpdate().
-		Model(queueModel).
-		Where("id = ?", q.ID).
-		Exec(ctx)
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// A transferred entry leaves one queue for another; both are renumbered
+		_, from, err := lockQueuesOf(ctx, tx, q.ID, func(queue.Scope) queue.Scope { return q.Scope() })
+		if err != nil {
+			return err
+		}
+
+		var previousStatus string
+		err = tx.NewSelect().
+			Model((*models.PatientQueue)(nil)).
+			Column("status").
+			Where("id = ?", q.ID).
+			Scan(ctx, &previousStatus)
+		if err != nil {
+			return err
+		}
+
+		// Arrival time drives triage aging, triage levels only change through Retriage,
+		// tickets are issued once, wait estimates are derived when renumbering and
+		// entries are only carried over by CloseDay
+		_, err = tx.NewUpdate().
+			Model(queueModel).
+			ExcludeColumn("created_at", "triage_level", "ticket_number", "estimated_wait_time", "estimated_wait_low", "estimated_wait_high", "carried_over_to").
+			Where("id = ?", q.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if previousStatus != q.Status {
+			if err := logStatusChange(ctx, tx, q.ID, q.OrganizationID, previousStatus, q.Status, actorID, queueModel.UpdatedAt); err != nil {
+				return err
+			}
+		}
 
+		return renumberWaitingQueue(ctx, tx, q.OrganizationID, from, q.Scope())
+	})
+
+	if errors.Is(err, queue.ErrNotFound) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update queue: %w", err)
 	}
 
+	q.UpdatedAt = queueModel.UpdatedAt
 	return nil
 }
 
 func (r *QueueRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.NewDelete().
-		Model((*models.PatientQueue)(nil)).
-		Where("id = ?", id).
-		Exec(ctx)
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		organizationID, scope, err := lockQueuesOf(ctx, tx, id, nil)
+		if err != nil {
+			return err
+		}
 
+		_, err = tx.NewDelete().
+			Model((*models.PatientQueue)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return renumberWaitingQueue(ctx, tx, organizationID, scope)
+	})
+
+	if errors.Is(err, queue.ErrNotFound) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete queue: %w", err)
 	}
@@ -248,7 +438,7 @@ func (r *QueueRepository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *QueueRepository) CountByOrganization(ctx context.Context, organizationID string) (int, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.PatientQueue)(nil))
 	
 	// Only filter by organization if organizationID is provided
@@ -265,39 +455,177 @@ func (r *QueueRepository) CountByOrganization(ctx context.Context, organizationI
 	return count, nil
 }
 
-func (r *QueueRepository) GetNextInQueue(ctx context.Context, organizationID string) (*queue.PatientQueue, error) {
+func (r *QueueRepository) GetByScope(ctx context.Context, organizationID string, scope queue.Scope, limit, offset int) ([]*queue.PatientQueue, error) {
+	var queueModels []models.PatientQueue
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&queueModels).
+		Where("organization_id = ?", organizationID).
+		Where("scope_type = ? AND scope_id = ?", string(scope.Type), scope.ID).
+		Order("position ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queues by scope: %w", err)
+	}
+
+	queues := make([]*queue.PatientQueue, len(queueModels))
+	for i, queueModel := range queueModels {
+		queues[i] = r.toDomain(&queueModel)
+	}
+
+	return queues, nil
+}
+
+func (r *QueueRepository) CountByScope(ctx context.Context, organizationID string, scope queue.Scope) (int, error) {
+	count, err := idb(ctx, r.db).NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		Where("organization_id = ?", organizationID).
+		Where("scope_type = ? AND scope_id = ?", string(scope.Type), scope.ID).
+		Count(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count queues by scope: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *QueueRepository) GetNextInQueue(ctx context.Context, organizationID string, scope queue.Scope) (*queue.PatientQueue, error) {
+	queueModel, err := nextWaiting(ctx, idb(ctx, r.db), organizationID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(queueModel), nil
+}
+
+func (r *QueueRepository) CallNext(ctx context.Context, organizationID string, scope queue.Scope, calledAt time.Time, actorID *string) (*queue.PatientQueue, error) {
+	var called *queue.PatientQueue
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockQueue(ctx, tx, organizationID, scope); err != nil {
+			return err
+		}
+
+		state, err := getState(ctx, tx, organizationID, scope)
+		if err != nil {
+			return err
+		}
+		if state.Paused {
+			return queue.ErrQueuePaused
+		}
+
+		queueModel, err := nextWaiting(ctx, tx, organizationID, scope)
+		if err != nil {
+			return err
+		}
+
+		called = r.toDomain(queueModel)
+		called.SetStatus(queue.QueueStatusCalled, calledAt)
+		_, err = tx.NewUpdate().
+			Model((*models.PatientQueue)(nil)).
+			Set("status = ?", called.Status).
+			Set("called_at = ?", called.CalledAt).
+			Set("updated_at = ?", calledAt).
+			Where("id = ?", called.ID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update queue status: %w", err)
+		}
+		called.UpdatedAt = calledAt
+
+		if err := logStatusChange(ctx, tx, called.ID, organizationID, queue.QueueStatusWaiting, called.Status, actorID, calledAt); err != nil {
+			return err
+		}
+
+		// The called patient leaves the waiting line; move everyone behind them up
+		if err := renumberWaitingQueue(ctx, tx, organizationID, scope); err != nil {
+			return fmt.Errorf("failed to update queue positions: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return called, nil
+}
+
+// nextWaiting returns the waiting patient to call next, or queue.ErrQueueEmpty
+func nextWaiting(ctx context.Context, db bun.IDB, organizationID string, scope queue.Scope) (*models.PatientQueue, error) {
 	queueModel := &models.PatientQueue{}
-	
-	err := r.db.NewSelect().
+
+	err := db.NewSelect().
 		Model(queueModel).
 		Where("organization_id = ? AND status = ?", organizationID, queue.QueueStatusWaiting).
-		Order("position DESC").
+		Where("scope_type = ? AND scope_id = ?", string(scope.Type), scope.ID).
+		OrderExpr(triageRankOrder).
 		Limit(1).
 		Scan(ctx)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, queue.ErrQueueEmpty
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get next in queue: %w", err)
 	}
 
-	return r.toDomain(queueModel), nil
+	return queueModel, nil
+}
+
+func (r *QueueRepository) GetLastPosition(ctx context.Context, organizationID string, scope queue.Scope) (int, error) {
+	return lastWaitingPosition(ctx, idb(ctx, r.db), organizationID, scope)
+}
+
+// lastWaitingPosition returns the position of the last waiting patient of a queue, or
+// 0 for an empty queue
+func lastWaitingPosition(ctx context.Context, db bun.IDB, organizationID string, scope queue.Scope) (int, error) {
+	var position int
+
+	err := db.NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		ColumnExpr("COALESCE(MAX(position), 0)").
+		Where("organization_id = ? AND status = ?", organizationID, queue.QueueStatusWaiting).
+		Where("scope_type = ? AND scope_id = ?", string(scope.Type), scope.ID).
+		Scan(ctx, &position)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last queue position: %w", err)
+	}
+
+	return position, nil
 }
 
 func (r *QueueRepository) UpdatePosition(ctx context.Context, organizationID string) error {
-	// This is a simplified implementation
-	// In a real application, you might want to use a more sophisticated approach
-	// like using database transactions and proper position recalculation
-	
-	// For now, we'll just update the positions of waiting patients
-	_, err := r.db.NewRaw(`
-		UPDATE patient_queues 
-		SET position = subquery.new_position, updated_at = CURRENT_TIMESTAMP
-		FROM (
-			SELECT id, ROW_NUMBER() OVER (ORDER BY created_at ASC) as new_position
-			FROM patient_queues 
-			WHERE organization_id = ? AND status = ?
-		) as subquery
-		WHERE patient_queues.id = subquery.id
-	`, organizationID, queue.QueueStatusWaiting).Exec(ctx)
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var rows []struct {
+			ScopeType string `bun:"scope_type"`
+			ScopeID   string `bun:"scope_id"`
+		}
+		err := tx.NewSelect().
+			Model((*models.PatientQueue)(nil)).
+			Distinct().
+			Column("scope_type", "scope_id").
+			Where("organization_id = ? AND status = ?", organizationID, queue.QueueStatusWaiting).
+			Scan(ctx, &rows)
+		if err != nil {
+			return err
+		}
+
+		scopes := make([]queue.Scope, len(rows))
+		for i, row := range rows {
+			scopes[i] = queue.Scope{Type: queue.ScopeType(row.ScopeType), ID: row.ScopeID}
+		}
+		if err := lockQueues(ctx, tx, organizationID, scopes...); err != nil {
+			return err
+		}
+		return renumberWaitingQueue(ctx, tx, organizationID, scopes...)
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to update queue positions: %w", err)
@@ -306,15 +634,329 @@ func (r *QueueRepository) UpdatePosition(ctx context.Context, organizationID str
 	return nil
 }
 
+func (r *QueueRepository) GetState(ctx context.Context, organizationID string, scope queue.Scope) (*queue.State, error) {
+	return getState(ctx, idb(ctx, r.db), organizationID, scope)
+}
+
+func getState(ctx context.Context, db bun.IDB, organizationID string, scope queue.Scope) (*queue.State, error) {
+	model := &models.QueueState{}
+	err := db.NewSelect().
+		Model(model).
+		Where("organization_id = ?", organizationID).
+		Where("scope_type = ? AND scope_id = ?", string(scope.Type), scope.ID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &queue.State{OrganizationID: organizationID, Scope: scope}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue state: %w", err)
+	}
+
+	return &queue.State{
+		OrganizationID: model.OrganizationID,
+		Scope:          queue.Scope{Type: queue.ScopeType(model.ScopeType), ID: model.ScopeID},
+		Paused:         model.Paused,
+		Reason:         model.Reason,
+		ChangedBy:      model.ChangedBy,
+		ChangedAt:      &model.ChangedAt,
+	}, nil
+}
+
+func (r *QueueRepository) SetState(ctx context.Context, state *queue.State) error {
+	model := &models.QueueState{
+		OrganizationID: state.OrganizationID,
+		ScopeType:      string(state.Scope.Type),
+		ScopeID:        state.Scope.ID,
+		Paused:         state.Paused,
+		Reason:         state.Reason,
+		ChangedBy:      state.ChangedBy,
+		ChangedAt:      time.Now(),
+	}
+
+	// Serialized with CallNext, so no patient is called once a pause is stored
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockQueue(ctx, tx, state.OrganizationID, state.Scope); err != nil {
+			return err
+		}
+
+		_, err := tx.NewInsert().
+			Model(model).
+			On("CONFLICT (organization_id, scope_type, scope_id) DO UPDATE").
+			Set("paused = EXCLUDED.paused").
+			Set("reason = EXCLUDED.reason").
+			Set("changed_by = EXCLUDED.changed_by").
+			Set("changed_at = EXCLUDED.changed_at").
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set queue state: %w", err)
+	}
+
+	state.ChangedAt = &model.ChangedAt
+	return nil
+}
+
+// lockQueue serializes changes to one queue until the transaction ends, so that its
+// positions are read and renumbered by one transaction at a time. It must be taken
+// before any of the queue's rows is written, and before renumberWaitingQueue. Changes
+// to other queues, even of the same organization, are not held up.
+func lockQueue(ctx context.Context, tx bun.Tx, organizationID string, scope queue.Scope) error {
+	return lockQueues(ctx, tx, organizationID, scope)
+}
+
+// lockQueues takes lockQueue for several queues at once. The locks are taken in a
+// fixed order, so that transactions locking overlapping queues cannot deadlock.
+func lockQueues(ctx context.Context, tx bun.Tx, organizationID string, scopes ...queue.Scope) error {
+	keys := make([]string, len(scopes))
+	for i, scope := range scopes {
+		keys[i] = "queue:positions:" + organizationID + ":" + string(scope.Type) + ":" + scope.ID
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if i > 0 && key == keys[i-1] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", key); err != nil {
+			return fmt.Errorf("failed to lock queues: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockQueuesOf takes lockQueue for the queue a queue entry is in and, if moveTo is
+// given, for the queue moveTo picks to move it to. It returns the entry's organization
+// and queue, or queue.ErrNotFound. Entries only move between queues under the lock of
+// the queue they leave, so the queue returned is the one locked.
+func lockQueuesOf(ctx context.Context, tx bun.Tx, queueID string, moveTo func(from queue.Scope) queue.Scope) (string, queue.Scope, error) {
+	for {
+		organizationID, from, err := entryQueue(ctx, tx, queueID)
+		if err != nil {
+			return "", queue.Scope{}, err
+		}
+
+		scopes := []queue.Scope{from}
+		if moveTo != nil {
+			scopes = append(scopes, moveTo(from))
+		}
+		if err := lockQueues(ctx, tx, organizationID, scopes...); err != nil {
+			return "", queue.Scope{}, err
+		}
+
+		// Moved before the lock was granted; lock the queue it is in now
+		_, current, err := entryQueue(ctx, tx, queueID)
+		if err != nil {
+			return "", queue.Scope{}, err
+		}
+		if current == from {
+			return organizationID, from, nil
+		}
+	}
+}
+
+// entryQueue returns the organization and queue of a queue entry, or queue.ErrNotFound
+func entryQueue(ctx context.Context, tx bun.Tx, queueID string) (string, queue.Scope, error) {
+	var row struct {
+		OrganizationID string `bun:"organization_id"`
+		ScopeType      string `bun:"scope_type"`
+		ScopeID        string `bun:"scope_id"`
+	}
+	err := tx.NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		Column("organization_id", "scope_type", "scope_id").
+		Where("id = ?", queueID).
+		Scan(ctx, &row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", queue.Scope{}, queue.ErrNotFound
+	}
+	if err != nil {
+		return "", queue.Scope{}, fmt.Errorf("failed to get queue of entry: %w", err)
+	}
+
+	return row.OrganizationID, queue.Scope{Type: queue.ScopeType(row.ScopeType), ID: row.ScopeID}, nil
+}
+
+// triageRankOrder ranks waiting patients by triage level, made one level more urgent
+// for every queue.TriageAgingInterval waited (see queue.TriageLevel.Effective), then by
+// arrival
+var triageRankOrder = fmt.Sprintf(
+	"CASE WHEN triage_level <= %[1]d THEN triage_level "+
+		"ELSE GREATEST(%[1]d, triage_level - FLOOR(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - created_at)) / %[2]d)::int) END ASC, created_at ASC",
+	queue.MaxAgedTriageLevel, int(queue.TriageAgingInterval/time.Second),
+)
+
+// renumberWaitingQueue recalculates the positions and wait estimates of the waiting
+// patients in the given queues of an organization, in the order they will be called.
+// Callers hold lockQueue for each of them.
+func renumberWaitingQueue(ctx context.Context, db bun.IDB, organizationID string, scopes ...queue.Scope) error {
+	var model *queue.DurationModel
+	for i, scope := range scopes {
+		if slices.Contains(scopes[:i], scope) {
+			continue
+		}
+
+		_, err := db.NewRaw(`
+			UPDATE patient_queues 
+			SET position = subquery.new_position,
+				updated_at = CURRENT_TIMESTAMP
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY `+triageRankOrder+`) as new_position
+				FROM patient_queues 
+				WHERE organization_id = ? AND scope_type = ? AND scope_id = ? AND status = ?
+			) as subquery
+			WHERE patient_queues.id = subquery.id
+		`, organizationID, string(scope.Type), scope.ID, queue.QueueStatusWaiting).Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if model == nil {
+			if model, err = loadDurationModel(ctx, db, organizationID); err != nil {
+				return err
+			}
+		}
+		if err := refreshWaitEstimates(ctx, db, organizationID, scope, model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// durationStatsRow is one grouping of consultation durations: per doctor and
+// appointment type, per doctor (null type) or for the organization (null doctor)
+type durationStatsRow struct {
+	DoctorID        *string `bun:"doctor_id"`
+	AppointmentType *string `bun:"appointment_type"`
+	Samples         int     `bun:"samples"`
+	Mean            float64 `bun:"mean"`
+	StdDev          float64 `bun:"std_dev"`
+}
+
+// activeEntryRow is a queue entry that is waiting or being seen, with what the
+// estimator needs to know about its appointment
+type activeEntryRow struct {
+	ID              string     `bun:"id"`
+	Status          string     `bun:"status"`
+	CalledAt        *time.Time `bun:"called_at"`
+	DoctorID        string     `bun:"doctor_id"`
+	AppointmentType string     `bun:"appointment_type"`
+}
+
+// loadDurationModel builds the organization's consultation durations, from call to
+// completion, over the last queue.EstimationWindow
+func loadDurationModel(ctx context.Context, db bun.IDB, organizationID string) (*queue.DurationModel, error) {
+	var rows []durationStatsRow
+	err := db.NewRaw(`
+		SELECT
+			a.doctor_id::text AS doctor_id,
+			a.type AS appointment_type,
+			COUNT(*) AS samples,
+			AVG(d.minutes) AS mean,
+			COALESCE(STDDEV_SAMP(d.minutes), 0) AS std_dev
+		FROM patient_queues pq
+		JOIN appointments a ON a.id = pq.appointment_id
+		CROSS JOIN LATERAL (
+			SELECT EXTRACT(EPOCH FROM pq.completed_at - COALESCE(pq.called_at, pq.started_at)) / 60 AS minutes
+		) d
+		WHERE pq.organization_id = ?
+		AND pq.completed_at >= ?
+		AND COALESCE(pq.called_at, pq.started_at) IS NOT NULL
+		GROUP BY GROUPING SETS ((a.doctor_id, a.type), (a.doctor_id), ())
+	`, organizationID, time.Now().Add(-queue.EstimationWindow)).Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consultation durations: %w", err)
+	}
+
+	model := queue.NewDurationModel()
+	for _, row := range rows {
+		var doctorID, appointmentType string
+		if row.DoctorID != nil {
+			doctorID = *row.DoctorID
+		}
+		if row.AppointmentType != nil {
+			appointmentType = *row.AppointmentType
+		}
+		model.Add(doctorID, appointmentType, queue.DurationStats{
+			Samples: row.Samples,
+			Mean:    row.Mean,
+			StdDev:  row.StdDev,
+		})
+	}
+
+	return model, nil
+}
+
+// refreshWaitEstimates re-estimates the wait of every waiting patient in a queue,
+// behind the patients being seen in it
+func refreshWaitEstimates(ctx context.Context, db bun.IDB, organizationID string, scope queue.Scope, model *queue.DurationModel) error {
+	var rows []activeEntryRow
+	err := db.NewRaw(`
+		SELECT pq.id, pq.status, pq.called_at, a.doctor_id::text AS doctor_id, a.type AS appointment_type
+		FROM patient_queues pq
+		JOIN appointments a ON a.id = pq.appointment_id
+		WHERE pq.organization_id = ? AND pq.scope_type = ? AND pq.scope_id = ? AND pq.status IN (?)
+		ORDER BY pq.position ASC
+	`, organizationID, string(scope.Type), scope.ID, bun.In([]string{queue.QueueStatusWaiting, queue.QueueStatusCalled, queue.QueueStatusInProgress})).Scan(ctx, &rows)
+	if err != nil {
+		return fmt.Errorf("failed to get active queue entries: %w", err)
+	}
+
+	var inProgress, waiting []queue.Consultation
+	var waitingIDs []string
+	for _, row := range rows {
+		consultation := queue.Consultation{DoctorID: row.DoctorID, AppointmentType: row.AppointmentType}
+		if row.Status == queue.QueueStatusWaiting {
+			waiting = append(waiting, consultation)
+			waitingIDs = append(waitingIDs, row.ID)
+		} else {
+			consultation.CalledAt = row.CalledAt
+			inProgress = append(inProgress, consultation)
+		}
+	}
+
+	var updates []models.PatientQueue
+	for i, estimate := range queue.EstimateWaits(inProgress, waiting, model, time.Now()) {
+		updates = append(updates, models.PatientQueue{
+			ID:                waitingIDs[i],
+			EstimatedWaitTime: estimate.Minutes,
+			EstimatedWaitLow:  estimate.Low,
+			EstimatedWaitHigh: estimate.High,
+		})
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	_, err = db.NewUpdate().
+		Model(&updates).
+		Column("estimated_wait_time", "estimated_wait_low", "estimated_wait_high").
+		Bulk().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update wait estimates: %w", err)
+	}
+
+	return nil
+}
+
+// currentQueueDay selects the entries of today's queues: those that joined today and
+// those carried over to today
+const currentQueueDay = "(created_at >= CURRENT_DATE OR carried_over_to = CURRENT_DATE)"
+
 // GetQueueStats returns aggregated queue statistics for dashboard
 func (r *QueueRepository) GetQueueStats(ctx context.Context, organizationID string) (*queue.QueueStats, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.PatientQueue)(nil))
 	
 	// Only filter by organization if organizationID is provided
 	if organizationID != "" {
 		query = query.Where("organization_id = ?", organizationID)
 	}
+	query = query.Where(currentQueueDay)
 	
 	// Get total count
 	total, err := query.Count(ctx)
@@ -323,46 +965,610 @@ func (r *QueueRepository) GetQueueStats(ctx context.Context, organizationID stri
 	}
 	
 	// Get waiting count
-	waitingQuery := r.db.NewSelect().
+	waitingQuery := idb(ctx, r.db).NewSelect().
 		Model((*models.PatientQueue)(nil))
 	if organizationID != "" {
 		waitingQuery = waitingQuery.Where("organization_id = ?", organizationID)
 	}
-	waiting, err := waitingQuery.Where("status = ?", queue.QueueStatusWaiting).Count(ctx)
+	waiting, err := waitingQuery.Where(currentQueueDay).Where("status = ?", queue.QueueStatusWaiting).Count(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count waiting queues: %w", err)
 	}
 	
 	// Get in progress count
-	inProgressQuery := r.db.NewSelect().
+	inProgressQuery := idb(ctx, r.db).NewSelect().
 		Model((*models.PatientQueue)(nil))
 	if organizationID != "" {
 		inProgressQuery = inProgressQuery.Where("organization_id = ?", organizationID)
 	}
-	inProgress, err := inProgressQuery.Where("status = ?", queue.QueueStatusInProgress).Count(ctx)
+	inProgress, err := inProgressQuery.Where(currentQueueDay).Where("status = ?", queue.QueueStatusInProgress).Count(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count in progress queues: %w", err)
 	}
 	
-	// Calculate average wait time (simplified - in real app, this would be more sophisticated)
-	avgWaitTime := "15 min" // TODO: Calculate from actual data
+	// Average time from arrival to being called, over today's called patients
+	var avgWaitMinutes float64
+	avgWaitQuery := idb(ctx, r.db).NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		ColumnExpr("COALESCE(AVG(EXTRACT(EPOCH FROM called_at - created_at)) / 60, 0)")
+	if organizationID != "" {
+		avgWaitQuery = avgWaitQuery.Where("organization_id = ?", organizationID)
+	}
+	err = avgWaitQuery.Where("called_at >= CURRENT_DATE").Scan(ctx, &avgWaitMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate average wait time: %w", err)
+	}
 	
 	return &queue.QueueStats{
 		Total:           total,
 		Waiting:         waiting,
 		InProgress:      inProgress,
-		AverageWaitTime: avgWaitTime,
+		AverageWaitTime: fmt.Sprintf("%d min", int(math.Round(avgWaitMinutes))),
 	}, nil
 }
 
+func (r *QueueRepository) Retriage(ctx context.Context, change *queue.TriageChange) error {
+	return idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		organizationID, scope, err := lockQueuesOf(ctx, tx, change.QueueID, nil)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.NewUpdate().
+			Model((*models.PatientQueue)(nil)).
+			Set("triage_level = ?", int(change.ToLevel)).
+			Set("updated_at = ?", change.ChangedAt).
+			Where("id = ?", change.QueueID).
+			Where("status = ? AND triage_level = ?", queue.QueueStatusWaiting, int(change.FromLevel)).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update triage level: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return queue.ErrNotWaiting
+		}
+
+		model := &models.QueueTriageHistory{
+			QueueID:   change.QueueID,
+			FromLevel: int(change.FromLevel),
+			ToLevel:   int(change.ToLevel),
+			Reason:    change.Reason,
+			ActorID:   change.ActorID,
+			ChangedAt: change.ChangedAt,
+		}
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to record triage change: %w", err)
+		}
+		change.ID = model.ID
+
+		if err := renumberWaitingQueue(ctx, tx, organizationID, scope); err != nil {
+			return fmt.Errorf("failed to update queue positions: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// logStatusChange records a status change of a queue entry in the queue event log
+func logStatusChange(ctx context.Context, db bun.IDB, queueID, organizationID, fromStatus, toStatus string, actorID *string, at time.Time) error {
+	_, err := db.NewInsert().
+		Model(&models.QueueEvent{
+			QueueID:        queueID,
+			OrganizationID: organizationID,
+			FromStatus:     fromStatus,
+			ToStatus:       toStatus,
+			ActorID:        actorID,
+			ChangedAt:      at,
+		}).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to log queue status change: %w", err)
+	}
+
+	return nil
+}
+
+func (r *QueueRepository) GetStatusHistory(ctx context.Context, queueID string) ([]*queue.StatusChange, error) {
+	var rows []models.QueueEvent
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Where("queue_id = ?", queueID).
+		Order("changed_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue status history: %w", err)
+	}
+
+	history := make([]*queue.StatusChange, len(rows))
+	for i, row := range rows {
+		history[i] = &queue.StatusChange{
+			ID:             row.ID,
+			QueueID:        row.QueueID,
+			OrganizationID: row.OrganizationID,
+			FromStatus:     row.FromStatus,
+			ToStatus:       row.ToStatus,
+			ActorID:        row.ActorID,
+			ChangedAt:      row.ChangedAt,
+		}
+	}
+
+	return history, nil
+}
+
+// GetTriageHistory returns an entry's triage changes, oldest first
+func (r *QueueRepository) GetTriageHistory(ctx context.Context, queueID string) ([]*queue.TriageChange, error) {
+	var rows []models.QueueTriageHistory
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Where("queue_id = ?", queueID).
+		Order("changed_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue triage history: %w", err)
+	}
+
+	history := make([]*queue.TriageChange, len(rows))
+	for i, row := range rows {
+		history[i] = &queue.TriageChange{
+			ID:        row.ID,
+			QueueID:   row.QueueID,
+			FromLevel: queue.TriageLevel(row.FromLevel),
+			ToLevel:   queue.TriageLevel(row.ToLevel),
+			Reason:    row.Reason,
+			ActorID:   row.ActorID,
+			ChangedAt: row.ChangedAt,
+		}
+	}
+
+	return history, nil
+}
+
+func (r *QueueRepository) GetClosingPolicy(ctx context.Context, organizationID string) (*queue.ClosingPolicy, error) {
+	model := &models.QueueClosingPolicy{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("organization_id = ?", organizationID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, queue.ErrClosingPolicyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue closing policy: %w", err)
+	}
+
+	return &queue.ClosingPolicy{
+		OrganizationID: model.OrganizationID,
+		Enabled:        model.Enabled,
+		CloseTime:      model.CloseTime,
+		LeftoverAction: queue.LeftoverAction(model.LeftoverAction),
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}, nil
+}
+
+func (r *QueueRepository) SaveClosingPolicy(ctx context.Context, policy *queue.ClosingPolicy) error {
+	model := &models.QueueClosingPolicy{
+		OrganizationID: policy.OrganizationID,
+		Enabled:        policy.Enabled,
+		CloseTime:      policy.CloseTime,
+		LeftoverAction: string(policy.LeftoverAction),
+	}
+
+	err := idb(ctx, r.db).NewInsert().
+		Model(model).
+		On("CONFLICT (organization_id) DO UPDATE").
+		Set("enabled = EXCLUDED.enabled").
+		Set("close_time = EXCLUDED.close_time").
+		Set("leftover_action = EXCLUDED.leftover_action").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Returning("created_at, updated_at").
+		Scan(ctx, &policy.CreatedAt, &policy.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save queue closing policy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *QueueRepository) FindUnclosed(ctx context.Context, day time.Time) ([]string, error) {
+	var organizationIDs []string
+
+	err := idb(ctx, r.db).NewSelect().
+		Model((*models.Organization)(nil)).
+		Column("id").
+		Where("is_active = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM queue_closures AS qc WHERE qc.organization_id = organization.id AND qc.day = ?)", day.Format("2006-01-02")).
+		Scan(ctx, &organizationIDs)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organizations with unclosed queues: %w", err)
+	}
+
+	return organizationIDs, nil
+}
+
+const (
+	closedCancelReason    = "Not seen before the queue closed"
+	closedCarryOverReason = "Carried over to the next queue day"
+)
+
+func (r *QueueRepository) CloseDay(ctx context.Context, closure *queue.Closure) ([]queue.Scope, error) {
+	start := closure.Day
+	end := start.AddDate(0, 0, 1)
+	now := time.Now()
+
+	model := &models.QueueClosure{
+		OrganizationID: closure.OrganizationID,
+		Day:            sqlDate(start),
+		LeftoverAction: string(closure.LeftoverAction),
+		ClosedAt:       now,
+	}
+	var scopes []queue.Scope
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Appointments are locked before the queues, in the order a check-in takes them
+		var appointmentIDs []string
+		err := tx.NewSelect().
+			Model((*models.PatientQueue)(nil)).
+			Column("appointment_id").
+			Where("organization_id = ?", closure.OrganizationID).
+			Where("status IN (?)", bun.In(queue.PendingStatuses)).
+			Where("created_at < ?", end).
+			Scan(ctx, &appointmentIDs)
+		if err != nil {
+			return fmt.Errorf("failed to find leftover entries: %w", err)
+		}
+		if len(appointmentIDs) > 0 {
+			_, err := tx.NewSelect().
+				Model((*models.Appointment)(nil)).
+				Column("id").
+				Where("id IN (?)", bun.In(appointmentIDs)).
+				Order("id").
+				For("UPDATE").
+				Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to lock leftover appointments: %w", err)
+			}
+		}
+
+		// Closures of an organization are serialized with each other, and the queues
+		// with leftovers with the changes to them
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "queue:closing:"+closure.OrganizationID); err != nil {
+			return fmt.Errorf("failed to lock queue closure: %w", err)
+		}
+
+		var leftoverQueues []struct {
+			ScopeType string `bun:"scope_type"`
+			ScopeID   string `bun:"scope_id"`
+		}
+		err = tx.NewSelect().
+			Model((*models.PatientQueue)(nil)).
+			Distinct().
+			Column("scope_type", "scope_id").
+			Where("organization_id = ?", closure.OrganizationID).
+			Where("status IN (?)", bun.In(queue.PendingStatuses)).
+			Where("created_at < ?", end).
+			Scan(ctx, &leftoverQueues)
+		if err != nil {
+			return fmt.Errorf("failed to find queues with leftover entries: %w", err)
+		}
+		lockScopes := make([]queue.Scope, len(leftoverQueues))
+		for i, row := range leftoverQueues {
+			lockScopes[i] = queue.Scope{Type: queue.ScopeType(row.ScopeType), ID: row.ScopeID}
+		}
+		if err := lockQueues(ctx, tx, closure.OrganizationID, lockScopes...); err != nil {
+			return err
+		}
+
+		closed, err := tx.NewSelect().
+			Model((*models.QueueClosure)(nil)).
+			Where("organization_id = ? AND day = ?", closure.OrganizationID, start.Format("2006-01-02")).
+			Exists(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check queue closure: %w", err)
+		}
+		if closed {
+			return queue.ErrAlreadyClosed
+		}
+
+		if err := countQueueDay(ctx, tx, model, start, end); err != nil {
+			return err
+		}
+
+		// In the order they would have been called, so carried over patients get the
+		// next day's tickets in that order
+		var leftovers []models.PatientQueue
+		for {
+			err = tx.NewSelect().
+				Model(&leftovers).
+				Where("organization_id = ?", closure.OrganizationID).
+				Where("status IN (?)", bun.In(queue.PendingStatuses)).
+				Where("created_at < ?", end).
+				OrderExpr("scope_type, scope_id, " + triageRankOrder).
+				Scan(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get leftover entries: %w", err)
+			}
+
+			// Patients who joined a queue after the queues were locked
+			var unlocked []queue.Scope
+			for _, entry := range leftovers {
+				scope := queue.Scope{Type: queue.ScopeType(entry.ScopeType), ID: entry.ScopeID}
+				if !slices.Contains(lockScopes, scope) && !slices.Contains(unlocked, scope) {
+					unlocked = append(unlocked, scope)
+				}
+			}
+			if len(unlocked) == 0 {
+				break
+			}
+			if err := lockQueues(ctx, tx, closure.OrganizationID, unlocked...); err != nil {
+				return err
+			}
+			lockScopes = append(lockScopes, unlocked...)
+		}
+
+		leftoverAppointments := make([]string, 0, len(leftovers))
+		for _, entry := range leftovers {
+			if closure.LeftoverAction == queue.LeftoverCarryOver {
+				err = carryOverEntry(ctx, tx, &entry, end, now)
+				model.CarriedOver++
+			} else {
+				err = cancelLeftoverEntry(ctx, tx, &entry, now)
+				model.LeftoverCancelled++
+			}
+			if err != nil {
+				return err
+			}
+
+			leftoverAppointments = append(leftoverAppointments, entry.AppointmentID)
+			scope := queue.Scope{Type: queue.ScopeType(entry.ScopeType), ID: entry.ScopeID}
+			if len(scopes) == 0 || scopes[len(scopes)-1] != scope {
+				scopes = append(scopes, scope)
+			}
+		}
+
+		// Carried over patients are treated as arrived, so they are not marked as
+		// no-show while they wait for the next day
+		if closure.LeftoverAction == queue.LeftoverCarryOver {
+			err = closeAppointments(ctx, tx, leftoverAppointments, []appointment.AppointmentStatus{
+				appointment.StatusConfirmed,
+			}, appointment.StatusCheckedIn, closedCarryOverReason, now)
+		} else {
+			err = closeAppointments(ctx, tx, leftoverAppointments, []appointment.AppointmentStatus{
+				appointment.StatusPending,
+				appointment.StatusConfirmed,
+				appointment.StatusCheckedIn,
+			}, appointment.StatusCancelled, closedCancelReason, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := renumberWaitingQueue(ctx, tx, closure.OrganizationID, scopes...); err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(model).Exec(ctx)
+		return err
+	})
+
+	if errors.Is(err, queue.ErrAlreadyClosed) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to close queue day: %w", err)
+	}
+
+	*closure = *toClosure(model)
+	closure.Day = start
+	return scopes, nil
+}
+
+// countQueueDay fills in the counts of a closure from the queue event log and the
+// entries called between start and end
+func countQueueDay(ctx context.Context, tx bun.Tx, model *models.QueueClosure, start, end time.Time) error {
+	err := tx.NewSelect().
+		Model((*models.QueueEvent)(nil)).
+		ColumnExpr("COUNT(*) FILTER (WHERE from_status IS NULL)").
+		ColumnExpr("COUNT(*) FILTER (WHERE to_status = ?)", queue.QueueStatusCompleted).
+		ColumnExpr("COUNT(*) FILTER (WHERE to_status = ?)", queue.QueueStatusCancelled).
+		Where("organization_id = ?", model.OrganizationID).
+		Where("changed_at >= ? AND changed_at < ?", start, end).
+		Scan(ctx, &model.Joined, &model.Completed, &model.Cancelled)
+	if err != nil {
+		return fmt.Errorf("failed to count queue events: %w", err)
+	}
+
+	var avgWaitMinutes float64
+	err = tx.NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		ColumnExpr("COALESCE(AVG(EXTRACT(EPOCH FROM called_at - created_at)) / 60, 0)").
+		Where("organization_id = ?", model.OrganizationID).
+		Where("called_at >= ? AND called_at < ?", start, end).
+		Scan(ctx, &avgWaitMinutes)
+	if err != nil {
+		return fmt.Errorf("failed to calculate average wait time: %w", err)
+	}
+	model.AverageWaitMinutes = int(math.Round(avgWaitMinutes))
+
+	model.InProgress, err = tx.NewSelect().
+		Model((*models.PatientQueue)(nil)).
+		Where("organization_id = ?", model.OrganizationID).
+		Where("status = ?", queue.QueueStatusInProgress).
+		Count(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count entries in progress: %w", err)
+	}
+
+	return nil
+}
+
+// carryOverEntry moves a leftover entry to the queue day starting at nextDay. It waits
+// again with a ticket of that day, its triage level and its arrival time, so it ranks
+// ahead of the patients arriving that day.
+func carryOverEntry(ctx context.Context, tx bun.Tx, entry *models.PatientQueue, nextDay, at time.Time) error {
+	ticket, err := issueTicket(ctx, tx, entry.OrganizationID, queue.Scope{Type: queue.ScopeType(entry.ScopeType), ID: entry.ScopeID}, nextDay)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.PatientQueue)(nil)).
+		Set("status = ?", queue.QueueStatusWaiting).
+		Set("called_at = NULL").
+		Set("ticket_number = ?", ticket).
+		Set("carried_over_to = ?", nextDay.Format("2006-01-02")).
+		Set("updated_at = ?", at).
+		Where("id = ?", entry.ID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to carry over queue entry: %w", err)
+	}
+
+	if entry.Status != queue.QueueStatusWaiting {
+		return logStatusChange(ctx, tx, entry.ID, entry.OrganizationID, entry.Status, queue.QueueStatusWaiting, nil, at)
+	}
+	return nil
+}
+
+// cancelLeftoverEntry cancels an entry that was not seen before its queue closed
+func cancelLeftoverEntry(ctx context.Context, tx bun.Tx, entry *models.PatientQueue, at time.Time) error {
+	_, err := tx.NewUpdate().
+		Model((*models.PatientQueue)(nil)).
+		Set("status = ?", queue.QueueStatusCancelled).
+		Set("updated_at = ?", at).
+		Where("id = ?", entry.ID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to cancel queue entry: %w", err)
+	}
+
+	return logStatusChange(ctx, tx, entry.ID, entry.OrganizationID, entry.Status, queue.QueueStatusCancelled, nil, at)
+}
+
+// closeAppointments moves those of the given appointments that are in one of the from
+// statuses to status to, recording each change in their status history
+func closeAppointments(ctx context.Context, tx bun.Tx, appointmentIDs []string, from []appointment.AppointmentStatus, to appointment.AppointmentStatus, reason string, at time.Time) error {
+	if len(appointmentIDs) == 0 {
+		return nil
+	}
+
+	var rows []models.Appointment
+	err := tx.NewSelect().
+		Model(&rows).
+		Column("id", "status").
+		Where("id IN (?)", bun.In(appointmentIDs)).
+		Where("status IN (?)", bun.In(from)).
+		Scan(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get leftover appointments: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	history := make([]models.AppointmentStatusHistory, len(rows))
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+		history[i] = models.AppointmentStatusHistory{
+			AppointmentID: row.ID,
+			FromStatus:    row.Status,
+			ToStatus:      string(to),
+			Reason:        &reason,
+			ChangedAt:     at,
+		}
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Appointment)(nil)).
+		Set("status = ?", string(to)).
+		Set("updated_at = ?", at).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update leftover appointments: %w", err)
+	}
+
+	if _, err := tx.NewInsert().Model(&history).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record appointment status changes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *QueueRepository) GetClosures(ctx context.Context, organizationID string, limit, offset int) ([]*queue.Closure, error) {
+	var rows []models.QueueClosure
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID).
+		Order("day DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue closures: %w", err)
+	}
+
+	closures := make([]*queue.Closure, len(rows))
+	for i := range rows {
+		closures[i] = toClosure(&rows[i])
+	}
+
+	return closures, nil
+}
+
+func toClosure(model *models.QueueClosure) *queue.Closure {
+	return &queue.Closure{
+		ID:                 model.ID,
+		OrganizationID:     model.OrganizationID,
+		Day:                model.Day,
+		LeftoverAction:     queue.LeftoverAction(model.LeftoverAction),
+		Joined:             model.Joined,
+		Completed:          model.Completed,
+		Cancelled:          model.Cancelled,
+		LeftoverCancelled:  model.LeftoverCancelled,
+		CarriedOver:        model.CarriedOver,
+		InProgress:         model.InProgress,
+		AverageWaitMinutes: model.AverageWaitMinutes,
+		ClosedAt:           model.ClosedAt,
+	}
+}
+
+// sqlDate returns t's calendar day at midnight UTC, as bun writes times in UTC and a
+// DATE column keeps only the date of what it is given
+func sqlDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 func (r *QueueRepository) toDomain(queueModel *models.PatientQueue) *queue.PatientQueue {
 	return &queue.PatientQueue{
 		ID:                queueModel.ID,
 		AppointmentID:     queueModel.AppointmentID,
 		OrganizationID:    queueModel.OrganizationID,
+		ScopeType:         queue.ScopeType(queueModel.ScopeType),
+		ScopeID:           queueModel.ScopeID,
+		TriageLevel:       queue.TriageLevel(queueModel.TriageLevel),
+		TicketNumber:      queueModel.TicketNumber,
 		Position:          queueModel.Position,
 		EstimatedWaitTime: queueModel.EstimatedWaitTime,
+		EstimatedWaitLow:  queueModel.EstimatedWaitLow,
+		EstimatedWaitHigh: queueModel.EstimatedWaitHigh,
 		Status:            queueModel.Status,
+		CalledAt:          queueModel.CalledAt,
+		StartedAt:         queueModel.StartedAt,
+		CompletedAt:       queueModel.CompletedAt,
+		CarriedOverTo:     queueModel.CarriedOverTo,
 		CreatedAt:         queueModel.CreatedAt,
 		UpdatedAt:         queueModel.UpdatedAt,
 	}
@@ -0,0 +1,317 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/infrastructure/persistence/models"
+)
+
+// testDB connects to the migrated database in TEST_DATABASE_URL, skipping the test
+// when there is none
+func testDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New())
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	return db
+}
+
+// createTestAppointment stores an organization with a doctor, a patient and an
+// appointment between them, all removed when the test ends
+func createTestAppointment(t *testing.T, db *bun.DB) *models.Appointment {
+	t.Helper()
+	ctx := context.Background()
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	org := &models.Organization{
+		Name:    "Queue Test " + suffix,
+		Type:    "clinic",
+		Address: "Test Street 1",
+		Phone:   "+620000000000",
+		Email:   "queue-test-" + suffix + "@example.com",
+	}
+	if _, err := db.NewInsert().Model(org).Exec(ctx); err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+
+	users := make([]*models.User, 2)
+	for i, role := range []string{"doctor", "patient"} {
+		users[i] = &models.User{
+			Email:          fmt.Sprintf("queue-test-%s-%s@example.com", role, suffix),
+			Name:           "Queue Test " + role,
+			PasswordHash:   "-",
+			Role:           role,
+			OrganizationID: &org.ID,
+		}
+		if _, err := db.NewInsert().Model(users[i]).Exec(ctx); err != nil {
+			t.Fatalf("failed to create %s: %v", role, err)
+		}
+	}
+
+	t.Cleanup(func() {
+		// Appointments and queue entries go with their users and organization
+		for _, u := range users {
+			db.NewDelete().Model(u).WherePK().Exec(ctx)
+		}
+		db.NewDelete().Model(org).WherePK().Exec(ctx)
+	})
+
+	apt := &models.Appointment{
+		PatientID:      users[1].ID,
+		DoctorID:       users[0].ID,
+		OrganizationID: org.ID,
+		Date:           time.Now(),
+		StartTime:      "09:00",
+		EndTime:        "09:30",
+		Duration:       30,
+		Status:         "checked_in",
+		Type:           "consultation",
+	}
+	if _, err := db.NewInsert().Model(apt).Exec(ctx); err != nil {
+		t.Fatalf("failed to create appointment: %v", err)
+	}
+
+	return apt
+}
+
+// TestQueuePositionsUnderConcurrentChanges has many clients check patients in, call,
+// cancel and re-triage them on two queues of one organization at once. Afterwards the
+// waiting patients of each queue must hold positions 1..n, with no duplicates or gaps.
+func TestQueuePositionsUnderConcurrentChanges(t *testing.T) {
+	db := testDB(t)
+	apt := createTestAppointment(t, db)
+	repo := NewQueueRepository(db)
+	ctx := context.Background()
+
+	scopes := []queue.Scope{
+		{Type: queue.ScopeServicePoint, ID: "queue-test-a"},
+		{Type: queue.ScopeServicePoint, ID: "queue-test-b"},
+	}
+
+	const clients, rounds = 16, 20
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []error
+	)
+	for c := 0; c < clients; c++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+
+			var created []string
+			for i := 0; i < rounds; i++ {
+				scope := scopes[rng.Intn(len(scopes))]
+				if err := changeQueue(ctx, repo, rng, apt, scope, &created); err != nil {
+					mu.Lock()
+					failures = append(failures, err)
+					mu.Unlock()
+				}
+			}
+		}(int64(c))
+	}
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("queue change failed: %v", err)
+	}
+
+	for _, scope := range scopes {
+		var positions []int
+		err := db.NewSelect().
+			Model((*models.PatientQueue)(nil)).
+			Column("position").
+			Where("organization_id = ? AND scope_type = ? AND scope_id = ?", apt.OrganizationID, string(scope.Type), scope.ID).
+			Where("status = ?", queue.QueueStatusWaiting).
+			Order("position ASC").
+			Scan(ctx, &positions)
+		if err != nil {
+			t.Fatalf("failed to read positions: %v", err)
+		}
+
+		for i, position := range positions {
+			if position != i+1 {
+				t.Errorf("waiting positions of %s are not 1..%d: %v", scope.ID, len(positions), positions)
+				break
+			}
+		}
+	}
+}
+
+// TestQueueTriageOrdering checks the ranking the database gives waiting patients: by
+// triage level aged with the time waited, then by arrival
+func TestQueueTriageOrdering(t *testing.T) {
+	db := testDB(t)
+	apt := createTestAppointment(t, db)
+	repo := NewQueueRepository(db)
+	ctx := context.Background()
+
+	type arrival struct {
+		name  string
+		level queue.TriageLevel
+		ago   time.Duration
+	}
+	tests := []struct {
+		name     string
+		arrivals []arrival
+		want     []string
+	}{
+		{
+			name: "more urgent first, then by arrival",
+			arrivals: []arrival{
+				{"routine", queue.TriageNonUrgent, 10 * time.Minute},
+				{"urgent-late", queue.TriageUrgent, time.Minute},
+				{"urgent-early", queue.TriageUrgent, 5 * time.Minute},
+			},
+			want: []string{"urgent-early", "urgent-late", "routine"},
+		},
+		{
+			name: "a long wait lets a routine patient catch up",
+			arrivals: []arrival{
+				{"urgent", queue.TriageUrgent, time.Minute},
+				{"routine", queue.TriageNonUrgent, 2*queue.TriageAgingInterval + time.Minute},
+			},
+			want: []string{"routine", "urgent"},
+		},
+		{
+			name: "aging reaches emergent but never resuscitation",
+			arrivals: []arrival{
+				{"routine", queue.TriageNonUrgent, 8 * queue.TriageAgingInterval},
+				{"emergent", queue.TriageEmergent, time.Minute},
+				{"resuscitation", queue.TriageResuscitation, 0},
+			},
+			want: []string{"resuscitation", "routine", "emergent"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := queue.Scope{Type: queue.ScopeServicePoint, ID: fmt.Sprintf("triage-test-%d", i)}
+
+			names := make(map[string]string, len(tt.arrivals))
+			for _, a := range tt.arrivals {
+				entry := &queue.PatientQueue{
+					AppointmentID:  apt.ID,
+					OrganizationID: apt.OrganizationID,
+					ScopeType:      scope.Type,
+					ScopeID:        scope.ID,
+					TriageLevel:    a.level,
+					Status:         queue.QueueStatusWaiting,
+				}
+				if err := repo.Create(ctx, entry, nil); err != nil {
+					t.Fatalf("failed to check in %s: %v", a.name, err)
+				}
+				names[entry.ID] = a.name
+
+				_, err := db.NewUpdate().
+					Model((*models.PatientQueue)(nil)).
+					Set("created_at = ?", time.Now().Add(-a.ago)).
+					Where("id = ?", entry.ID).
+					Exec(ctx)
+				if err != nil {
+					t.Fatalf("failed to backdate %s: %v", a.name, err)
+				}
+			}
+			if err := renumberWaitingQueue(ctx, db, apt.OrganizationID, scope); err != nil {
+				t.Fatalf("failed to renumber: %v", err)
+			}
+
+			entries, err := repo.GetByScope(ctx, apt.OrganizationID, scope, len(tt.arrivals), 0)
+			if err != nil {
+				t.Fatalf("failed to get queue: %v", err)
+			}
+			got := make([]string, len(entries))
+			for i, entry := range entries {
+				got[i] = names[entry.ID]
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+
+			next, err := repo.GetNextInQueue(ctx, apt.OrganizationID, scope)
+			if err != nil {
+				t.Fatalf("failed to get next: %v", err)
+			}
+			if names[next.ID] != tt.want[0] {
+				t.Errorf("next = %s, want %s", names[next.ID], tt.want[0])
+			}
+		})
+	}
+}
+
+// changeQueue makes one random queue change. Losing a race, e.g. calling from a queue
+// another client just emptied, is expected and not a failure.
+func changeQueue(ctx context.Context, repo queue.Repository, rng *rand.Rand, apt *models.Appointment, scope queue.Scope, created *[]string) error {
+	switch n := rng.Intn(10); {
+	case n < 5 || len(*created) == 0:
+		entry := &queue.PatientQueue{
+			AppointmentID:  apt.ID,
+			OrganizationID: apt.OrganizationID,
+			ScopeType:      scope.Type,
+			ScopeID:        scope.ID,
+			TriageLevel:    queue.TriageLevel(rng.Intn(5) + 1),
+			Status:         queue.QueueStatusWaiting,
+		}
+		if err := repo.Create(ctx, entry, nil); err != nil {
+			return fmt.Errorf("check in: %w", err)
+		}
+		*created = append(*created, entry.ID)
+
+	case n < 7:
+		_, err := repo.CallNext(ctx, apt.OrganizationID, scope, time.Now(), nil)
+		if err != nil && !errors.Is(err, queue.ErrQueueEmpty) {
+			return fmt.Errorf("call next: %w", err)
+		}
+
+	case n < 9:
+		entry, err := repo.GetByID(ctx, (*created)[rng.Intn(len(*created))])
+		if err != nil {
+			return fmt.Errorf("get entry: %w", err)
+		}
+		change, err := entry.Retriage(queue.TriageLevel(rng.Intn(5)+1), "queue test", nil, time.Now())
+		if errors.Is(err, queue.ErrNotWaiting) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("re-triage: %w", err)
+		}
+		if err := repo.Retriage(ctx, change); err != nil && !errors.Is(err, queue.ErrNotWaiting) {
+			return fmt.Errorf("re-triage: %w", err)
+		}
+
+	default:
+		entry, err := repo.GetByID(ctx, (*created)[rng.Intn(len(*created))])
+		if err != nil {
+			return fmt.Errorf("get entry: %w", err)
+		}
+		entry.SetStatus(queue.QueueStatusCancelled, time.Now())
+		if err := repo.Update(ctx, entry, nil); err != nil {
+			return fmt.Errorf("cancel: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/room"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// RoomRepository implements room.Repository
+type RoomRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewRoomRepository(db *bun.DB) room.Repository {
+	return &RoomRepository{
+		db:     db,
+		logger: logger.New(),
+	}
+}
+
+func (r *RoomRepository) Create(ctx context.Context, rm *room.Room) error {
+	model := r.toModel(rm)
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create room: %w", err)
+	}
+
+	rm.ID = model.ID
+	return nil
+}
+
+func (r *RoomRepository) GetByID(ctx context.Context, id string) (*room.Room, error) {
+	model := &models.Room{}
+
+	err := r.db.NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, room.ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	return r.toDomain(model), nil
+}
+
+func (r *RoomRepository) GetByOrganization(ctx context.Context, organizationID string, filter room.Filter, limit, offset int) ([]*room.Room, error) {
+	var rows []models.Room
+
+	query := r.db.NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", string(filter.Type))
+	}
+	if filter.AvailableOnly {
+		query = query.Where("is_available = TRUE")
+	}
+	if len(filter.Equipment) > 0 {
+		query = query.Where("equipment @> ?", pgdialect.Array(filter.Equipment))
+	}
+
+	err := query.
+		Order("name ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms by organization: %w", err)
+	}
+
+	rooms := make([]*room.Room, len(rows))
+	for i, row := range rows {
+		rooms[i] = r.toDomain(&row)
+	}
+
+	return rooms, nil
+}
+
+func (r *RoomRepository) Update(ctx context.Context, rm *room.Room) error {
+	model := r.toModel(rm)
+
+	result, err := r.db.NewUpdate().
+		Model(model).
+		ExcludeColumn("created_at").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update room: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return room.ErrRoomNotFound
+	}
+
+	return nil
+}
+
+func (r *RoomRepository) Delete(ctx context.Context, id string, from time.Time) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Block bookings into the room while checking it is unused
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "appointment:room:"+id); err != nil {
+			return err
+		}
+
+		inUse, err := tx.NewSelect().
+			Model((*models.Appointment)(nil)).
+			Where("room_id = ?", id).
+			Where("date >= ?", from.Format("2006-01-02")).
+			Where("status NOT IN (?)", bun.In([]string{
+				string(appointment.StatusCancelled),
+				string(appointment.StatusNoShow),
+				string(appointment.StatusCompleted),
+			})).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return room.ErrRoomHasAppointments
+		}
+
+		result, err := tx.NewDelete().
+			Model((*models.Room)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return room.ErrRoomNotFound
+		}
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, room.ErrRoomHasAppointments) || errors.Is(err, room.ErrRoomNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RoomRepository) GetBookings(ctx context.Context, organizationID string, date time.Time) ([]*room.Booking, error) {
+	var rows []models.Appointment
+
+	err := r.db.NewSelect().
+		Model(&rows).
+		Join("JOIN rooms AS r ON r.id = ?TableAlias.room_id").
+		Where("r.organization_id = ?", organizationID).
+		Where("?TableAlias.date = ?", date.Format("2006-01-02")).
+		Where("?TableAlias.status NOT IN (?)", bun.In([]string{
+			string(appointment.StatusCancelled),
+			string(appointment.StatusNoShow),
+		})).
+		Order("start_time ASC", "end_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room bookings: %w", err)
+	}
+
+	bookings := make([]*room.Booking, len(rows))
+	for i, row := range rows {
+		bookings[i] = &room.Booking{
+			AppointmentID: row.ID,
+			RoomID:        *row.RoomID,
+			DoctorID:      row.DoctorID,
+			PatientID:     row.PatientID,
+			StartTime:     row.StartTime,
+			EndTime:       row.EndTime,
+			Status:        row.Status,
+		}
+	}
+
+	return bookings, nil
+}
+
+func (r *RoomRepository) toModel(rm *room.Room) *models.Room {
+	return &models.Room{
+		ID:             rm.ID,
+		OrganizationID: rm.OrganizationID,
+		Name:           rm.Name,
+		Type:           string(rm.Type),
+		Capacity:       rm.Capacity,
+		IsAvailable:    rm.IsAvailable,
+		Equipment:      rm.Equipment,
+		CreatedAt:      rm.CreatedAt,
+		UpdatedAt:      rm.UpdatedAt,
+	}
+}
+
+func (r *RoomRepository) toDomain(model *models.Room) *room.Room {
+	equipment := model.Equipment
+	if equipment == nil {
+		equipment = []string{}
+	}
+
+	return &room.Room{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		Name:           model.Name,
+		Type:           room.Type(model.Type),
+		Capacity:       model.Capacity,
+		IsAvailable:    model.IsAvailable,
+		Equipment:      equipment,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
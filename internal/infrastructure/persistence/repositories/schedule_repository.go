@@ -0,0 +1,266 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/schedule"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// ScheduleRepository implements schedule.Repository
+type ScheduleRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewScheduleRepository(db *bun.DB) schedule.Repository {
+	return &ScheduleRepository{
+		db:     db,
+		logger: logger.New(),
+	}
+}
+
+func (r *ScheduleRepository) CreateTemplate(ctx context.Context, t *schedule.Template) error {
+	model := r.templateToModel(t)
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create schedule template: %w", err)
+	}
+
+	t.ID = model.ID
+	return nil
+}
+
+func (r *ScheduleRepository) GetTemplateByID(ctx context.Context, id string) (*schedule.Template, error) {
+	model := &models.ScheduleTemplate{}
+
+	err := r.db.NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule template: %w", err)
+	}
+
+	return r.templateToDomain(model), nil
+}
+
+func (r *ScheduleRepository) UpdateTemplate(ctx context.Context, t *schedule.Template) error {
+	model := r.templateToModel(t)
+
+	_, err := r.db.NewUpdate().
+		Model(model).
+		ExcludeColumn("created_at").
+		Where("id = ?", t.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update schedule template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) DeleteTemplate(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().
+		Model((*models.ScheduleTemplate)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) GetDoctorTemplates(ctx context.Context, doctorID string) ([]*schedule.Template, error) {
+	var rows []models.ScheduleTemplate
+
+	err := r.db.NewSelect().
+		Model(&rows).
+		Where("doctor_id = ?", doctorID).
+		Order("day_of_week ASC", "start_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get doctor schedule templates: %w", err)
+	}
+
+	return r.templatesToDomain(rows), nil
+}
+
+func (r *ScheduleRepository) GetOrganizationTemplates(ctx context.Context, organizationID string) ([]*schedule.Template, error) {
+	var rows []models.ScheduleTemplate
+
+	err := r.db.NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID).
+		Where("doctor_id IS NULL").
+		Order("day_of_week ASC", "start_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization schedule templates: %w", err)
+	}
+
+	return r.templatesToDomain(rows), nil
+}
+
+func (r *ScheduleRepository) CreateOverride(ctx context.Context, o *schedule.Override) error {
+	model := r.overrideToModel(o)
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create schedule override: %w", err)
+	}
+
+	o.ID = model.ID
+	return nil
+}
+
+func (r *ScheduleRepository) DeleteOverride(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().
+		Model((*models.ScheduleOverride)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule override: %w", err)
+	}
+
+	return nil
+}
+
+// GetOverrides returns organization-wide overrides plus, when doctorID is set,
+// the doctor's own overrides for the given date range
+func (r *ScheduleRepository) GetOverrides(ctx context.Context, organizationID string, doctorID *string, from, to time.Time) ([]*schedule.Override, error) {
+	var rows []models.ScheduleOverride
+
+	query := r.db.NewSelect().
+		Model(&rows).
+		Where("date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	if doctorID != nil {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("doctor_id = ?", *doctorID).
+				WhereOr("organization_id = ? AND doctor_id IS NULL", organizationID)
+		})
+	} else {
+		query = query.Where("organization_id = ? AND doctor_id IS NULL", organizationID)
+	}
+
+	if err := query.Order("date ASC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get schedule overrides: %w", err)
+	}
+
+	overrides := make([]*schedule.Override, len(rows))
+	for i, row := range rows {
+		overrides[i] = r.overrideToDomain(&row)
+	}
+
+	return overrides, nil
+}
+
+func (r *ScheduleRepository) templatesToDomain(rows []models.ScheduleTemplate) []*schedule.Template {
+	templates := make([]*schedule.Template, len(rows))
+	for i, row := range rows {
+		templates[i] = r.templateToDomain(&row)
+	}
+	return templates
+}
+
+func (r *ScheduleRepository) templateToModel(t *schedule.Template) *models.ScheduleTemplate {
+	return &models.ScheduleTemplate{
+		ID:             t.ID,
+		OrganizationID: t.OrganizationID,
+		DoctorID:       t.DoctorID,
+		DayOfWeek:      t.DayOfWeek,
+		StartTime:      t.StartTime,
+		EndTime:        t.EndTime,
+		SlotDuration:   t.SlotDuration,
+		Breaks:         breaksToModel(t.Breaks),
+		IsActive:       t.IsActive,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+func (r *ScheduleRepository) templateToDomain(model *models.ScheduleTemplate) *schedule.Template {
+	return &schedule.Template{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		DoctorID:       model.DoctorID,
+		DayOfWeek:      model.DayOfWeek,
+		StartTime:      model.StartTime,
+		EndTime:        model.EndTime,
+		SlotDuration:   model.SlotDuration,
+		Breaks:         breaksToDomain(model.Breaks),
+		IsActive:       model.IsActive,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
+func (r *ScheduleRepository) overrideToModel(o *schedule.Override) *models.ScheduleOverride {
+	return &models.ScheduleOverride{
+		ID:             o.ID,
+		OrganizationID: o.OrganizationID,
+		DoctorID:       o.DoctorID,
+		Date:           o.Date,
+		IsAvailable:    o.IsAvailable,
+		StartTime:      o.StartTime,
+		EndTime:        o.EndTime,
+		SlotDuration:   o.SlotDuration,
+		Breaks:         breaksToModel(o.Breaks),
+		Reason:         o.Reason,
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.UpdatedAt,
+	}
+}
+
+func (r *ScheduleRepository) overrideToDomain(model *models.ScheduleOverride) *schedule.Override {
+	return &schedule.Override{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		DoctorID:       model.DoctorID,
+		Date:           model.Date,
+		IsAvailable:    model.IsAvailable,
+		StartTime:      model.StartTime,
+		EndTime:        model.EndTime,
+		SlotDuration:   model.SlotDuration,
+		Breaks:         breaksToDomain(model.Breaks),
+		Reason:         model.Reason,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
+func breaksToModel(breaks []schedule.Break) []models.ScheduleBreak {
+	result := make([]models.ScheduleBreak, len(breaks))
+	for i, b := range breaks {
+		result[i] = models.ScheduleBreak{StartTime: b.StartTime, EndTime: b.EndTime}
+	}
+	return result
+}
+
+func breaksToDomain(breaks []models.ScheduleBreak) []schedule.Break {
+	result := make([]schedule.Break, len(breaks))
+	for i, b := range breaks {
+		result[i] = schedule.Break{StartTime: b.StartTime, EndTime: b.EndTime}
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+type txKey struct{}
+
+// Transactor runs work in one transaction. The repositories of aggregates that publish
+// events, and the outbox, join the transaction carried by their context; transactions
+// they start themselves become savepoints of it, so their locks are held until it
+// commits.
+type Transactor struct {
+	db *bun.DB
+	// committed is called after each transaction commits, e.g. to wake the outbox relay
+	committed func()
+}
+
+func NewTransactor(db *bun.DB, committed func()) *Transactor {
+	return &Transactor{
+		db:        db,
+		committed: committed,
+	}
+}
+
+// WithinTx runs fn in a transaction, or in the caller's if ctx already carries one
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return fn(ctx)
+	}
+
+	err := t.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err != nil {
+		return err
+	}
+
+	if t.committed != nil {
+		t.committed()
+	}
+	return nil
+}
+
+// idb returns the transaction ctx carries, or db outside of one
+func idb(ctx context.Context, db bun.IDB) bun.IDB {
+	if tx, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -115,7 +115,7 @@ func NewUserRepository(db *bun.DB) user.Repository {
 
 // FindAll implements the unified user query with comprehensive filtering
 func (r *UserRepository) FindAll(ctx context.Context, filters user.UserFilters) ([]*user.User, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil)).
 		Relation("Profile")
 
@@ -181,7 +181,7 @@ func (r *UserRepository) FindAll(ctx context.Context, filters user.UserFilters)
 
 // Count implements the unified count query with comprehensive filtering
 func (r *UserRepository) Count(ctx context.Context, filters user.UserFilters) (int64, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil))
 
 	// Apply filters
@@ -226,7 +226,7 @@ func (r *UserRepository) Count(ctx context.Context, filters user.UserFilters) (i
 func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
 	model := r.toModel(u)
 
-	_, err := r.db.NewInsert().
+	_, err := idb(ctx, r.db).NewInsert().
 		Model(model).
 		Exec(ctx)
 
@@ -237,7 +237,7 @@ func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
 	// Save profile if exists
 	if u.Profile() != nil {
 		profileModel := r.toProfileModel(u.ID(), u.Profile())
-		_, err = r.db.NewInsert().
+		_, err = idb(ctx, r.db).NewInsert().
 			Model(profileModel).
 			Exec(ctx)
 		if err != nil {
@@ -251,7 +251,7 @@ func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	model := r.toModel(u)
 
-	_, err := r.db.NewUpdate().
+	_, err := idb(ctx, r.db).NewUpdate().
 		Model(model).
 		Where("id = ? AND version = ?", u.ID().String(), u.Version()-1).
 		Exec(ctx)
@@ -264,7 +264,7 @@ func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	if u.Profile() != nil {
 		profileModel := r.toProfileModel(u.ID(), u.Profile())
 		
-		_, err = r.db.NewInsert().
+		_, err = idb(ctx, r.db).NewInsert().
 			Model(profileModel).
 			On("CONFLICT (user_id) DO UPDATE").
 			Set("date_of_birth = EXCLUDED.date_of_birth").
@@ -286,7 +286,7 @@ func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id shared.UserID) error {
-	_, err := r.db.NewDelete().
+	_, err := idb(ctx, r.db).NewDelete().
 		Model((*models.User)(nil)).
 		Where("id = ?", id.String()).
 		Exec(ctx)
@@ -301,7 +301,7 @@ func (r *UserRepository) Delete(ctx context.Context, id shared.UserID) error {
 func (r *UserRepository) FindByID(ctx context.Context, id shared.UserID) (*user.User, error) {
 	model := &models.User{}
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(model).
 		Relation("Profile").
 		Where("id = ?", id.String()).
@@ -321,7 +321,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id shared.UserID) (*user.
 func (r *UserRepository) FindByEmail(ctx context.Context, email shared.Email) (*user.User, error) {
 	model := &models.User{}
 	
-	err := r.db.NewSelect().
+	err := idb(ctx, r.db).NewSelect().
 		Model(model).
 		Where("email = ?", email.String()).
 		Scan(ctx)
@@ -337,7 +337,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email shared.Email) (*
 }
 
 func (r *UserRepository) FindByOrganization(ctx context.Context, orgID shared.OrganizationID, filters user.UserFilters) ([]*user.User, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil)).
 		Relation("Profile").
 		Where("user.organization_id = ?", orgID.String())
@@ -388,7 +388,7 @@ func (r *UserRepository) FindByOrganization(ctx context.Context, orgID shared.Or
 }
 
 func (r *UserRepository) FindByRole(ctx context.Context, role user.Role, orgID *shared.OrganizationID) ([]*user.User, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil)).
 		Relation("Profile").
 		Where("user.role = ?", string(role))
@@ -418,7 +418,7 @@ func (r *UserRepository) FindByRole(ctx context.Context, role user.Role, orgID *
 }
 
 func (r *UserRepository) CountByOrganization(ctx context.Context, orgID shared.OrganizationID) (int64, error) {
-	count, err := r.db.NewSelect().
+	count, err := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil)).
 		Where("organization_id = ?", orgID.String()).
 		Count(ctx)
@@ -431,7 +431,7 @@ func (r *UserRepository) CountByOrganization(ctx context.Context, orgID shared.O
 }
 
 func (r *UserRepository) CountByRole(ctx context.Context, role user.Role) (int64, error) {
-	count, err := r.db.NewSelect().
+	count, err := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil)).
 		Where("role = ?", string(role)).
 		Count(ctx)
@@ -444,7 +444,7 @@ func (r *UserRepository) CountByRole(ctx context.Context, role user.Role) (int64
 }
 
 func (r *UserRepository) FindActiveUsers(ctx context.Context, orgID *shared.OrganizationID) ([]*user.User, error) {
-	query := r.db.NewSelect().
+	query := idb(ctx, r.db).NewSelect().
 		Model((*models.User)(nil)).
 		Relation("Profile").
 		Where("user.is_active = ?", true)
@@ -474,7 +474,7 @@ func (r *UserRepository) FindActiveUsers(ctx context.Context, orgID *shared.Orga
 }
 
 func (r *UserRepository) WithTx(ctx context.Context, fn func(user.Repository) error) error {
-	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+	return idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		txRepo := &UserRepository{db: tx}
 		return fn(txRepo)
 	})
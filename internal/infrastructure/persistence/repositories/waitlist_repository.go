@@ -0,0 +1,407 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"medika-backend/internal/domain/waitlist"
+	"medika-backend/internal/infrastructure/persistence/models"
+	"medika-backend/pkg/logger"
+)
+
+// WaitlistRepository implements waitlist.Repository
+type WaitlistRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewWaitlistRepository(db *bun.DB) waitlist.Repository {
+	return &WaitlistRepository{
+		db:     db,
+		logger: logger.New(),
+	}
+}
+
+func (r *WaitlistRepository) CreateEntry(ctx context.Context, entry *waitlist.Entry) error {
+	model := r.entryToModel(entry)
+
+	_, err := idb(ctx, r.db).NewInsert().
+		Model(model).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+
+	entry.ID = model.ID
+	return nil
+}
+
+func (r *WaitlistRepository) GetEntryByID(ctx context.Context, id string) (*waitlist.Entry, error) {
+	model := &models.WaitlistEntry{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+
+	return r.entryToDomain(model), nil
+}
+
+// GetEntries lists an organization's entries in the order they will be served.
+// An empty status returns entries in any state.
+func (r *WaitlistRepository) GetEntries(ctx context.Context, organizationID string, status waitlist.EntryStatus, limit, offset int) ([]*waitlist.Entry, error) {
+	var rows []models.WaitlistEntry
+
+	query := idb(ctx, r.db).NewSelect().
+		Model(&rows).
+		Where("organization_id = ?", organizationID)
+
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+
+	err := query.
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist entries: %w", err)
+	}
+
+	entries := make([]*waitlist.Entry, len(rows))
+	for i := range rows {
+		entries[i] = r.entryToDomain(&rows[i])
+	}
+
+	return entries, nil
+}
+
+// CancelEntry withdraws a waiting or offered entry. Holds the entry still had are
+// declined and returned so their slots can be offered to the next patient.
+func (r *WaitlistRepository) CancelEntry(ctx context.Context, id string) ([]*waitlist.Hold, error) {
+	var released []models.WaitlistHold
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Model((*models.WaitlistEntry)(nil)).
+			Set("status = ?", string(waitlist.EntryStatusCancelled)).
+			Where("id = ?", id).
+			Where("status IN (?)", bun.In([]string{
+				string(waitlist.EntryStatusWaiting),
+				string(waitlist.EntryStatusOffered),
+			})).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return waitlist.ErrEntryClosed
+		}
+
+		err = tx.NewSelect().
+			Model(&released).
+			Where("entry_id = ?", id).
+			Where("status = ?", string(waitlist.HoldStatusActive)).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(released) == 0 {
+			return nil
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.WaitlistHold)(nil)).
+			Set("status = ?", string(waitlist.HoldStatusDeclined)).
+			Where("entry_id = ?", id).
+			Where("status = ?", string(waitlist.HoldStatusActive)).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, waitlist.ErrEntryClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+
+	holds := make([]*waitlist.Hold, len(released))
+	for i := range released {
+		released[i].Status = string(waitlist.HoldStatusDeclined)
+		holds[i] = r.holdToDomain(&released[i])
+	}
+
+	return holds, nil
+}
+
+func (r *WaitlistRepository) OfferSlot(ctx context.Context, slot *waitlist.Slot, expiresAt time.Time) (*waitlist.Hold, error) {
+	var hold *waitlist.Hold
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		hold = nil
+
+		// Serialize offers of the same slot so it is never held twice
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "waitlist:slot:"+slot.SourceAppointmentID); err != nil {
+			return err
+		}
+
+		held, err := tx.NewSelect().
+			Model((*models.WaitlistHold)(nil)).
+			Where("source_appointment_id = ?", slot.SourceAppointmentID).
+			Where("status IN (?)", bun.In([]string{
+				string(waitlist.HoldStatusActive),
+				string(waitlist.HoldStatusAccepted),
+			})).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if held {
+			return nil
+		}
+
+		var entry models.WaitlistEntry
+		err = tx.NewSelect().
+			Model(&entry).
+			Where("organization_id = ?", slot.OrganizationID).
+			Where("status = ?", string(waitlist.EntryStatusWaiting)).
+			Where("? BETWEEN date_from AND date_to", slot.Date.Format("2006-01-02")).
+			WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Where("doctor_id = ?", slot.DoctorID).
+					WhereOr("doctor_id IS NULL AND specialty = (SELECT specialization FROM user_profiles WHERE user_id = ?)", slot.DoctorID)
+			}).
+			Where("NOT EXISTS (SELECT 1 FROM waitlist_holds WHERE waitlist_holds.entry_id = ?TableAlias.id AND waitlist_holds.source_appointment_id = ?)", slot.SourceAppointmentID).
+			Order("created_at ASC").
+			Limit(1).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		model := r.slotToHoldModel(slot)
+		model.EntryID = entry.ID
+		model.PatientID = entry.PatientID
+		model.Status = string(waitlist.HoldStatusActive)
+		model.ExpiresAt = expiresAt
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.WaitlistEntry)(nil)).
+			Set("status = ?", string(waitlist.EntryStatusOffered)).
+			Where("id = ?", entry.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		hold = r.holdToDomain(model)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to offer waitlist slot: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (r *WaitlistRepository) GetHoldByID(ctx context.Context, id string) (*waitlist.Hold, error) {
+	model := &models.WaitlistHold{}
+
+	err := idb(ctx, r.db).NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist hold: %w", err)
+	}
+
+	return r.holdToDomain(model), nil
+}
+
+func (r *WaitlistRepository) ResolveHold(ctx context.Context, holdID string, status waitlist.HoldStatus, entryStatus waitlist.EntryStatus, appointmentID *string) error {
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var hold models.WaitlistHold
+		err := tx.NewUpdate().
+			Model(&hold).
+			Set("status = ?", string(status)).
+			Set("appointment_id = ?", appointmentID).
+			Where("id = ?", holdID).
+			Where("status = ?", string(waitlist.HoldStatusActive)).
+			Returning("entry_id").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return waitlist.ErrHoldNotActive
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.WaitlistEntry)(nil)).
+			Set("status = ?", string(entryStatus)).
+			Where("id = ?", hold.EntryID).
+			Where("status = ?", string(waitlist.EntryStatusOffered)).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, waitlist.ErrHoldNotActive) {
+			return err
+		}
+		return fmt.Errorf("failed to resolve waitlist hold: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WaitlistRepository) ExpireHolds(ctx context.Context, now time.Time) ([]*waitlist.Hold, error) {
+	var expired []models.WaitlistHold
+
+	err := idb(ctx, r.db).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		expired = nil
+
+		err := tx.NewSelect().
+			Model(&expired).
+			Where("status = ?", string(waitlist.HoldStatusActive)).
+			Where("expires_at <= ?", now).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+
+		holdIDs := make([]string, len(expired))
+		entryIDs := make([]string, len(expired))
+		for i, hold := range expired {
+			holdIDs[i] = hold.ID
+			entryIDs[i] = hold.EntryID
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.WaitlistHold)(nil)).
+			Set("status = ?", string(waitlist.HoldStatusExpired)).
+			Where("id IN (?)", bun.In(holdIDs)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// The patient stays on the waitlist for later cancellations
+		_, err = tx.NewUpdate().
+			Model((*models.WaitlistEntry)(nil)).
+			Set("status = ?", string(waitlist.EntryStatusWaiting)).
+			Where("id IN (?)", bun.In(entryIDs)).
+			Where("status = ?", string(waitlist.EntryStatusOffered)).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire waitlist holds: %w", err)
+	}
+
+	holds := make([]*waitlist.Hold, len(expired))
+	for i := range expired {
+		expired[i].Status = string(waitlist.HoldStatusExpired)
+		holds[i] = r.holdToDomain(&expired[i])
+	}
+
+	return holds, nil
+}
+
+func (r *WaitlistRepository) entryToModel(entry *waitlist.Entry) *models.WaitlistEntry {
+	return &models.WaitlistEntry{
+		ID:             entry.ID,
+		OrganizationID: entry.OrganizationID,
+		PatientID:      entry.PatientID,
+		DoctorID:       entry.DoctorID,
+		Specialty:      entry.Specialty,
+		DateFrom:       entry.DateFrom,
+		DateTo:         entry.DateTo,
+		Status:         string(entry.Status),
+		Notes:          entry.Notes,
+		CreatedAt:      entry.CreatedAt,
+		UpdatedAt:      entry.UpdatedAt,
+	}
+}
+
+func (r *WaitlistRepository) entryToDomain(model *models.WaitlistEntry) *waitlist.Entry {
+	return &waitlist.Entry{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		PatientID:      model.PatientID,
+		DoctorID:       model.DoctorID,
+		Specialty:      model.Specialty,
+		DateFrom:       model.DateFrom,
+		DateTo:         model.DateTo,
+		Status:         waitlist.EntryStatus(model.Status),
+		Notes:          model.Notes,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
+func (r *WaitlistRepository) slotToHoldModel(slot *waitlist.Slot) *models.WaitlistHold {
+	return &models.WaitlistHold{
+		SourceAppointmentID: slot.SourceAppointmentID,
+		OrganizationID:      slot.OrganizationID,
+		DoctorID:            slot.DoctorID,
+		RoomID:              slot.RoomID,
+		Date:                slot.Date,
+		StartTime:           slot.StartTime,
+		EndTime:             slot.EndTime,
+		Duration:            slot.Duration,
+		Type:                slot.Type,
+	}
+}
+
+func (r *WaitlistRepository) holdToDomain(model *models.WaitlistHold) *waitlist.Hold {
+	return &waitlist.Hold{
+		ID:        model.ID,
+		EntryID:   model.EntryID,
+		PatientID: model.PatientID,
+		Slot: waitlist.Slot{
+			SourceAppointmentID: model.SourceAppointmentID,
+			OrganizationID:      model.OrganizationID,
+			DoctorID:            model.DoctorID,
+			RoomID:              model.RoomID,
+			Date:                model.Date,
+			StartTime:           model.StartTime,
+			EndTime:             model.EndTime,
+			Duration:            model.Duration,
+			Type:                model.Type,
+		},
+		Status:        waitlist.HoldStatus(model.Status),
+		ExpiresAt:     model.ExpiresAt,
+		AppointmentID: model.AppointmentID,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
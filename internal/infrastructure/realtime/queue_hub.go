@@ -0,0 +1,165 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"medika-backend/internal/application/shared/events"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/pkg/logger"
+)
+
+// queueChannelPrefix prefixes the Redis channel each organization's queue events are
+// published on
+const queueChannelPrefix = "queue-events:"
+
+// subscriberBuffer is how many events a slow subscriber may fall behind before
+// events are dropped for it
+const subscriberBuffer = 32
+
+// QueueHub relays queue events between API instances over Redis pub/sub and fans them
+// out to the SSE and WebSocket clients connected to this instance
+type QueueHub struct {
+	redis  *redis.Client
+	logger logger.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+type subscriber struct {
+	organizationID string
+	scope          *queue.Scope
+	events         chan queue.QueueEvent
+}
+
+func NewQueueHub(redis *redis.Client, logger logger.Logger) *QueueHub {
+	return &QueueHub{
+		redis:       redis,
+		logger:      logger,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Handle publishes a queue event from the event bus to Redis, from where every
+// instance, this one included, delivers it to its subscribers
+func (h *QueueHub) Handle(ctx context.Context, event events.Event) error {
+	queueEvent, ok := event.(queue.QueueEvent)
+	if !ok {
+		return nil
+	}
+
+	payload, err := json.Marshal(queueEvent)
+	if err != nil {
+		return fmt.Errorf("failed to encode queue event: %w", err)
+	}
+
+	if err := h.redis.Publish(ctx, queueChannelPrefix+queueEvent.OrganizationID, payload).Err(); err != nil {
+		h.logger.Error(ctx, "Failed to publish queue event", "error", err, "type", queueEvent.Type)
+		return fmt.Errorf("failed to publish queue event: %w", err)
+	}
+
+	return nil
+}
+
+// Start listens for queue events on Redis until Stop is called
+func (h *QueueHub) Start(ctx context.Context) {
+	ctx, h.cancel = context.WithCancel(ctx)
+
+	pubsub := h.redis.PSubscribe(ctx, queueChannelPrefix+"*")
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event queue.QueueEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					h.logger.Warn(ctx, "Discarding malformed queue event", "error", err, "channel", msg.Channel)
+					continue
+				}
+				h.dispatch(ctx, event)
+			}
+		}
+	}()
+}
+
+// Stop stops listening and closes every subscription, ending open streams
+func (h *QueueHub) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+		h.wg.Wait()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		close(sub.events)
+		delete(h.subscribers, sub)
+	}
+}
+
+// Subscribe returns the events of an organization's queues, or of one of them when
+// scope is set, until the returned function is called
+func (h *QueueHub) Subscribe(organizationID string, scope *queue.Scope) (<-chan queue.QueueEvent, func()) {
+	sub := &subscriber{
+		organizationID: organizationID,
+		scope:          scope,
+		events:         make(chan queue.QueueEvent, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(sub.events)
+		return sub.events, func() {}
+	}
+	h.subscribers[sub] = struct{}{}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subscribers[sub]; ok {
+			close(sub.events)
+			delete(h.subscribers, sub)
+		}
+	}
+
+	return sub.events, unsubscribe
+}
+
+func (h *QueueHub) dispatch(ctx context.Context, event queue.QueueEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if !event.Matches(sub.organizationID, sub.scope) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.logger.Warn(ctx, "Dropping queue event for slow subscriber", "type", event.Type, "organizationID", event.OrganizationID)
+		}
+	}
+}
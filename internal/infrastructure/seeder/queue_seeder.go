@@ -25,45 +25,56 @@ func (s *QueueSeeder) Name() string {
 
 // Seed creates patient queues
 func (s *QueueSeeder) Seed(ctx context.Context, db *bun.DB) error {
+	// Positions are per doctor's queue
 	queues := []*models.PatientQueue{
 		{
 			ID:                "11180001-1111-1111-1111-111111111111",
 			AppointmentID:     "11170001-1111-1111-1111-111111111111", // John Doe's appointment
 			OrganizationID:    "01234567-89ab-cdef-0123-456789abcdef", // Medika General Hospital
+			ScopeType:         string(queue.ScopeDoctor),
+			ScopeID:           "11130001-1111-1111-1111-111111111111", // Dr. Michael Smith
 			Position:          1,
-			EstimatedWaitTime: 15,
+			EstimatedWaitTime: 0,
 			Status:            queue.QueueStatusWaiting,
 		},
 		{
 			ID:                "11180002-1111-1111-1111-111111111111",
 			AppointmentID:     "11170002-1111-1111-1111-111111111111", // Jane Smith's appointment
 			OrganizationID:    "01234567-89ab-cdef-0123-456789abcdef", // Medika General Hospital
-			Position:          2,
-			EstimatedWaitTime: 30,
+			ScopeType:         string(queue.ScopeDoctor),
+			ScopeID:           "11130002-1111-1111-1111-111111111111", // Dr. Jennifer Jones
+			Position:          1,
+			EstimatedWaitTime: 0,
 			Status:            queue.QueueStatusWaiting,
 		},
 		{
 			ID:                "11180003-1111-1111-1111-111111111111",
 			AppointmentID:     "11170003-1111-1111-1111-111111111111", // Bob Johnson's appointment
 			OrganizationID:    "01234567-89ab-cdef-0123-456789abcde0", // Downtown Medical Clinic
+			ScopeType:         string(queue.ScopeDoctor),
+			ScopeID:           "11130003-1111-1111-1111-111111111111", // Dr. Robert Brown
 			Position:          1,
-			EstimatedWaitTime: 15,
+			EstimatedWaitTime: 0,
 			Status:            queue.QueueStatusCalled,
 		},
 		{
 			ID:                "11180004-1111-1111-1111-111111111111",
 			AppointmentID:     "11170004-1111-1111-1111-111111111111", // John Doe's tomorrow appointment
 			OrganizationID:    "01234567-89ab-cdef-0123-456789abcdef", // Medika General Hospital
-			Position:          3,
-			EstimatedWaitTime: 45,
+			ScopeType:         string(queue.ScopeDoctor),
+			ScopeID:           "11130001-1111-1111-1111-111111111111", // Dr. Michael Smith
+			Position:          2,
+			EstimatedWaitTime: 15,
 			Status:            queue.QueueStatusWaiting,
 		},
 		{
 			ID:                "11180005-1111-1111-1111-111111111111",
 			AppointmentID:     "11170005-1111-1111-1111-111111111111", // Jane Smith's future appointment
 			OrganizationID:    "01234567-89ab-cdef-0123-456789abcdef", // Medika General Hospital
-			Position:          4,
-			EstimatedWaitTime: 60,
+			ScopeType:         string(queue.ScopeDoctor),
+			ScopeID:           "11130002-1111-1111-1111-111111111111", // Dr. Jennifer Jones
+			Position:          2,
+			EstimatedWaitTime: 15,
 			Status:            queue.QueueStatusWaiting,
 		},
 	}
@@ -74,6 +85,8 @@ func (s *QueueSeeder) Seed(ctx context.Context, db *bun.DB) error {
 			On("CONFLICT (id) DO UPDATE").
 			Set("appointment_id = EXCLUDED.appointment_id").
 			Set("organization_id = EXCLUDED.organization_id").
+			Set("scope_type = EXCLUDED.scope_type").
+			Set("scope_id = EXCLUDED.scope_id").
 			Set("position = EXCLUDED.position").
 			Set("estimated_wait_time = EXCLUDED.estimated_wait_time").
 			Set("status = EXCLUDED.status").
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
@@ -15,38 +16,60 @@ import (
 	"github.com/uptrace/bun"
 
 	"medika-backend/internal/application/appointment"
+	"medika-backend/internal/application/calendar"
+	"medika-backend/internal/application/checkin"
 	"medika-backend/internal/application/dashboard"
+	"medika-backend/internal/application/display"
 	"medika-backend/internal/application/doctor"
+	"medika-backend/internal/application/noshow"
 	"medika-backend/internal/application/notification"
+	"medika-backend/internal/application/notifier"
 	"medika-backend/internal/application/organization"
+	"medika-backend/internal/application/outbox"
 	"medika-backend/internal/application/patient"
 	"medika-backend/internal/application/queue"
+	"medika-backend/internal/application/reminder"
+	"medika-backend/internal/application/room"
+	"medika-backend/internal/application/schedule"
+	"medika-backend/internal/application/shared/events"
 	"medika-backend/internal/application/user"
+	"medika-backend/internal/application/waitlist"
+	appointmentDomain "medika-backend/internal/domain/appointment"
+	checkinDomain "medika-backend/internal/domain/checkin"
+	notificationDomain "medika-backend/internal/domain/notification"
+	outboxDomain "medika-backend/internal/domain/outbox"
+	queueDomain "medika-backend/internal/domain/queue"
+	userDomain "medika-backend/internal/domain/user"
 	"medika-backend/internal/infrastructure/config"
+	"medika-backend/internal/infrastructure/delivery"
+	"medika-backend/internal/infrastructure/jobs"
 	"medika-backend/internal/infrastructure/persistence/repositories"
+	"medika-backend/internal/infrastructure/realtime"
 	"medika-backend/internal/presentation/http/handlers"
 	"medika-backend/internal/presentation/http/middleware"
 	"medika-backend/pkg/logger"
 )
 
 type Server struct {
-	app    *fiber.App
-	config config.ServerConfig
-	logger logger.Logger
+	app      *fiber.App
+	config   config.ServerConfig
+	jobs     *jobs.Runner
+	queueHub *realtime.QueueHub
+	logger   logger.Logger
 }
 
 func New(
-	cfg config.ServerConfig,
+	cfg *config.Config,
 	db *bun.DB,
 	redis *redis.Client,
 	logger logger.Logger,
 ) *Server {
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
-		IdleTimeout:  cfg.IdleTimeout,
-		Prefork:      cfg.Prefork,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		Prefork:      cfg.Server.Prefork,
 		ErrorHandler: middleware.ErrorHandler,
 	})
 
@@ -61,16 +84,107 @@ func New(
 	appointmentRepo := repositories.NewAppointmentRepository(db)
 	queueRepo := repositories.NewQueueRepository(db)
 	notificationRepo := repositories.NewNotificationRepository(db)
+	scheduleRepo := repositories.NewScheduleRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
+	calendarRepo := repositories.NewCalendarRepository(db)
+	roomRepo := repositories.NewRoomRepository(db)
+	noShowRepo := repositories.NewNoShowRepository(db)
+	displayRepo := repositories.NewDisplayRepository(db)
+	checkinRepo := repositories.NewCheckInRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+
+	// Domain events are stored in the outbox in the transaction of the change they are
+	// about, and relayed to their handlers by the outbox-relay job
+	outboxService := outbox.NewService(outboxRepo, eventDecoders(), outboxDomain.RetryPolicy{
+		MaxAttempts: cfg.Outbox.MaxAttempts,
+		Backoff:     cfg.Outbox.RetryBackoff,
+	}, cfg.Outbox.BatchSize, logger)
+	var eventBus events.Bus = outboxService
+	transactor := repositories.NewTransactor(db, outboxService.Wake)
 	
 	// Application services
-	userService := user.NewService(userRepo, nil, logger) // eventBus would be injected
+	userService := user.NewService(userRepo, eventBus, transactor, logger)
 	patientService := patient.NewService(patientRepo, logger)
 	doctorService := doctor.NewService(doctorRepo, userRepo, logger)
 	organizationService := organization.NewService(organizationRepo, logger)
-	appointmentService := appointment.NewService(appointmentRepo, logger)
-	queueService := queue.NewService(queueRepo, logger)
-	notificationService := notification.NewService(notificationRepo, logger)
+	noShowService := noshow.NewService(noShowRepo, appointmentRepo, eventBus, transactor, cfg.NoShow.GracePeriod, cfg.NoShow.BatchSize, logger)
+	appointmentService := appointment.NewService(appointmentRepo, roomRepo, noShowService, eventBus, transactor, logger)
+	queueService := queue.NewService(queueRepo, appointmentRepo, organizationRepo, roomRepo, userRepo, eventBus, transactor, cfg.QueueClosing.CloseTime, queueDomain.LeftoverAction(cfg.QueueClosing.LeftoverAction), logger)
+	notificationService := notification.NewService(notificationRepo, userRepo, newDeliveryChannels(cfg.Delivery, logger), notificationDomain.RetryPolicy{
+		MaxAttempts: cfg.Delivery.MaxAttempts,
+		Backoff:     cfg.Delivery.RetryBackoff,
+	}, notificationDomain.Locale(cfg.Notifications.DefaultLocale), cfg.Notifications.DefaultTimezone, cfg.Delivery.BatchSize, logger)
 	dashboardService := dashboard.NewService(patientRepo, appointmentRepo, queueRepo, doctorRepo, logger)
+	scheduleService := schedule.NewService(scheduleRepo, appointmentRepo, organizationRepo, userRepo, logger)
+	waitlistService := waitlist.NewService(waitlistRepo, appointmentRepo, notificationService, eventBus, transactor, cfg.Waitlist.HoldDuration, logger)
+	calendarService := calendar.NewService(calendarRepo, appointmentRepo, organizationRepo, userRepo, logger)
+	roomService := room.NewService(roomRepo, logger)
+	reminderService := reminder.NewService(notificationRepo, organizationRepo, notificationService, cfg.Reminders.Offsets, cfg.Reminders.Channels, logger)
+	notifierService := notifier.NewService(notificationService, appointmentRepo, queueRepo, userRepo, cfg.Notifications.Channels, logger)
+	displayService := display.NewService(displayRepo, organizationRepo, logger)
+	checkinService := checkin.NewService(checkinRepo, appointmentRepo, organizationRepo, queueRepo, checkinDomain.ArrivalWindow{
+		OpensBefore: cfg.CheckIn.OpensBefore,
+		ClosesAfter: cfg.CheckIn.ClosesAfter,
+	}, eventBus, transactor, logger)
+
+	// Real-time queue updates, shared between instances over Redis
+	queueHub := realtime.NewQueueHub(redis, logger)
+
+	// Event subscriptions
+	eventBus.Subscribe(context.Background(), "appointment.cancelled", waitlistService)
+	eventBus.Subscribe(context.Background(), "appointment.cancelled", reminderService)
+	eventBus.Subscribe(context.Background(), "appointment.scheduled", reminderService)
+	for _, eventType := range []string{
+		"appointment.scheduled",
+		"appointment.status_changed",
+		"appointment.cancelled",
+		queueDomain.EventCalled,
+		"user.created",
+	} {
+		eventBus.Subscribe(context.Background(), eventType, notifierService)
+	}
+	for _, eventType := range queueEventTypes {
+		eventBus.Subscribe(context.Background(), eventType, queueHub)
+	}
+
+	// Background jobs
+	jobRunner := jobs.NewRunner(logger)
+	jobRunner.Add(jobs.Job{
+		Name:     "waitlist-hold-expiry",
+		Interval: cfg.Waitlist.ExpiryInterval,
+		Run:      waitlistService.ExpireHolds,
+	})
+	jobRunner.Add(jobs.Job{
+		Name:     "appointment-no-show",
+		Interval: cfg.NoShow.CheckInterval,
+		Run:      noShowService.MarkNoShows,
+	})
+	jobRunner.Add(jobs.Job{
+		Name:     "appointment-confirmation-deadline",
+		Interval: cfg.NoShow.CheckInterval,
+		Run:      noShowService.EnforceConfirmations,
+	})
+	jobRunner.Add(jobs.Job{
+		Name:     "queue-day-close",
+		Interval: cfg.QueueClosing.CheckInterval,
+		Run:      queueService.CloseQueueDays,
+	})
+	jobRunner.Add(jobs.Job{
+		Name:     "notification-dispatch",
+		Interval: cfg.Reminders.DispatchInterval,
+		Run:      notificationService.DispatchDue,
+	})
+	jobRunner.Add(jobs.Job{
+		Name:     "notification-delivery",
+		Interval: cfg.Delivery.DispatchInterval,
+		Run:      notificationService.DeliverPending,
+	})
+	jobRunner.Add(jobs.Job{
+		Name:     "outbox-relay",
+		Interval: cfg.Outbox.RelayInterval,
+		Trigger:  outboxService.Woken(),
+		Run:      outboxService.Relay,
+	})
 	
 	// Handlers
 	userHandler := handlers.NewUserHandler(userService, validator, logger)
@@ -79,20 +193,89 @@ func New(
 	organizationsHandler := handlers.NewOrganizationHandler(organizationService, validator, logger)
 	appointmentsHandler := handlers.NewAppointmentHandler(appointmentService, validator, logger)
 	queueHandler := handlers.NewQueueHandler(queueService, validator, logger)
-	notificationHandler := handlers.NewNotificationHandler(notificationService, logger)
+	queueStreamHandler := handlers.NewQueueStreamHandler(queueHub, validator, logger)
+	displayHandler := handlers.NewDisplayHandler(displayService, validator, logger)
+	checkinHandler := handlers.NewCheckInHandler(checkinService, validator, logger)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, validator, logger)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardService, validator, logger)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService, validator, logger)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService, validator, logger)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, validator, logger)
+	roomHandler := handlers.NewRoomHandler(roomService, validator, logger)
+	noShowHandler := handlers.NewNoShowHandler(noShowService, validator, logger)
+	outboxHandler := handlers.NewOutboxHandler(outboxService, validator, logger)
 
 	// Setup middleware
 	setupMiddleware(app)
 	
 	// Setup routes
-	setupRoutes(app, userHandler, patientHandler, doctorsHandler, organizationsHandler, appointmentsHandler, queueHandler, notificationHandler, dashboardHandler)
+	setupRoutes(app, userHandler, patientHandler, doctorsHandler, organizationsHandler, appointmentsHandler, queueHandler, notificationHandler, dashboardHandler, scheduleHandler, waitlistHandler, calendarHandler, roomHandler, noShowHandler, queueStreamHandler, displayHandler, checkinHandler, outboxHandler)
 
 	return &Server{
-		app:    app,
-		config: cfg,
-		logger: logger,
+		app:      app,
+		config:   cfg.Server,
+		jobs:     jobRunner,
+		queueHub: queueHub,
+		logger:   logger,
+	}
+}
+
+// queueEventTypes are the types of queue.QueueEvent, all relayed to real-time subscribers
+var queueEventTypes = []string{
+	queueDomain.EventCreated,
+	queueDomain.EventCalled,
+	queueDomain.EventStarted,
+	queueDomain.EventCompleted,
+	queueDomain.EventSkipped,
+	queueDomain.EventHeld,
+	queueDomain.EventTransferred,
+	queueDomain.EventPositionsChanged,
+	queueDomain.EventPaused,
+	queueDomain.EventResumed,
+}
+
+// eventDecoders returns how the outbox relay reads back each type of published event.
+// Events of a type missing here end up in the dead letters.
+func eventDecoders() map[string]outbox.Decoder {
+	decoders := map[string]outbox.Decoder{
+		"appointment.scheduled":      outbox.DecoderFor[appointmentDomain.AppointmentScheduledEvent](),
+		"appointment.status_changed": outbox.DecoderFor[appointmentDomain.AppointmentStatusChangedEvent](),
+		"appointment.cancelled":      outbox.DecoderFor[appointmentDomain.AppointmentCancelledEvent](),
+		"user.created":               outbox.DecoderFor[userDomain.UserCreatedEvent](),
+		"user.updated":               outbox.DecoderFor[userDomain.UserUpdatedEvent](),
+		"user.deactivated":           outbox.DecoderFor[userDomain.UserDeactivatedEvent](),
+	}
+	for _, eventType := range queueEventTypes {
+		decoders[eventType] = outbox.DecoderFor[queueDomain.QueueEvent]()
+	}
+	return decoders
+}
+
+// newDeliveryChannels returns the external notification channels. Channels without
+// configuration get a fake that keeps their messages in the fake outbox instead.
+func newDeliveryChannels(cfg config.DeliveryConfig, logger logger.Logger) []notification.Channel {
+	fake := func(name string) notification.Channel {
+		logger.Warn(context.Background(), "Notification channel not configured, using fake delivery", "channel", name)
+		return delivery.NewFakeChannel(name, cfg.FakeOutbox, logger)
+	}
+
+	channels := make([]notification.Channel, 0, 3)
+	if cfg.SMTP.Host != "" {
+		channels = append(channels, delivery.NewEmailChannel(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From))
+	} else {
+		channels = append(channels, fake(notificationDomain.ChannelEmail))
+	}
+	if cfg.SMS.GatewayURL != "" {
+		channels = append(channels, delivery.NewSMSChannel(delivery.NewHTTPSMSProvider(cfg.SMS.GatewayURL, cfg.SMS.APIKey, cfg.SMS.Sender, cfg.SMS.Timeout)))
+	} else {
+		channels = append(channels, fake(notificationDomain.ChannelSMS))
+	}
+	if cfg.Webhook.URL != "" {
+		channels = append(channels, delivery.NewWebhookChannel(cfg.Webhook.URL, cfg.Webhook.Secret, cfg.Webhook.Timeout))
+	} else {
+		channels = append(channels, fake(notificationDomain.ChannelWebhook))
 	}
+	return channels
 }
 
 func setupMiddleware(app *fiber.App) {
@@ -127,7 +310,7 @@ func setupMiddleware(app *fiber.App) {
 	})
 }
 
-func setupRoutes(app *fiber.App, userHandler *handlers.UserHandler, patientHandler *handlers.PatientHandler, doctorsHandler *handlers.DoctorHandler, organizationsHandler *handlers.OrganizationHandler, appointmentsHandler *handlers.AppointmentHandler, queueHandler *handlers.QueueHandler, notificationHandler *handlers.NotificationHandler, dashboardHandler *handlers.DashboardHandler) {
+func setupRoutes(app *fiber.App, userHandler *handlers.UserHandler, patientHandler *handlers.PatientHandler, doctorsHandler *handlers.DoctorHandler, organizationsHandler *handlers.OrganizationHandler, appointmentsHandler *handlers.AppointmentHandler, queueHandler *handlers.QueueHandler, notificationHandler *handlers.NotificationHandler, dashboardHandler *handlers.DashboardHandler, scheduleHandler *handlers.ScheduleHandler, waitlistHandler *handlers.WaitlistHandler, calendarHandler *handlers.CalendarHandler, roomHandler *handlers.RoomHandler, noShowHandler *handlers.NoShowHandler, queueStreamHandler *handlers.QueueStreamHandler, displayHandler *handlers.DisplayHandler, checkinHandler *handlers.CheckInHandler, outboxHandler *handlers.OutboxHandler) {
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -158,6 +341,9 @@ func setupRoutes(app *fiber.App, userHandler *handlers.UserHandler, patientHandl
 	// Patient routes
 	patients := api.Group("/patients")
 	patients.Get("/", patientHandler.GetPatients)
+	patients.Get("/:id/calendar-feeds", middleware.AuthRequired(), calendarHandler.GetPatientFeeds)
+	patients.Post("/:id/calendar-feeds", middleware.AuthRequired(), calendarHandler.CreatePatientFeed)
+	patients.Get("/:id/no-shows", middleware.AuthRequired(), noShowHandler.GetPatientRecord)
 
 	// Doctor routes
 	doctors := api.Group("/doctors")
@@ -166,6 +352,12 @@ func setupRoutes(app *fiber.App, userHandler *handlers.UserHandler, patientHandl
 	doctors.Post("/", doctorsHandler.CreateDoctor)
 	doctors.Put("/:id", doctorsHandler.UpdateDoctor)
 	doctors.Delete("/:id", doctorsHandler.DeleteDoctor)
+	doctors.Get("/:id/slots", scheduleHandler.GetDoctorSlots)
+	doctors.Get("/:id/schedule", scheduleHandler.GetDoctorSchedule)
+	doctors.Post("/:id/schedule/templates", scheduleHandler.CreateDoctorTemplate)
+	doctors.Post("/:id/schedule/overrides", scheduleHandler.CreateDoctorOverride)
+	doctors.Get("/:id/calendar-feeds", middleware.AuthRequired(), calendarHandler.GetDoctorFeeds)
+	doctors.Post("/:id/calendar-feeds", middleware.AuthRequired(), calendarHandler.CreateDoctorFeed)
 
 	// Organization routes
 	organizations := api.Group("/organizations")
@@ -174,48 +366,130 @@ func setupRoutes(app *fiber.App, userHandler *handlers.UserHandler, patientHandl
 	organizations.Post("/", organizationsHandler.CreateOrganization)
 	organizations.Put("/:id", organizationsHandler.UpdateOrganization)
 	organizations.Delete("/:id", organizationsHandler.DeleteOrganization)
+	organizations.Get("/:id/schedule", scheduleHandler.GetOrganizationSchedule)
+	organizations.Post("/:id/schedule/templates", scheduleHandler.CreateOrganizationTemplate)
+	organizations.Post("/:id/schedule/overrides", scheduleHandler.CreateOrganizationOverride)
+	organizations.Get("/:id/no-show-policy", noShowHandler.GetPolicy)
+	organizations.Put("/:id/no-show-policy", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), noShowHandler.UpdatePolicy)
+	organizations.Get("/:id/queue-closing-policy", queueHandler.GetClosingPolicy)
+	organizations.Put("/:id/queue-closing-policy", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), queueHandler.UpdateClosingPolicy)
+	organizations.Get("/:id/queue-closures", middleware.AuthRequired(), middleware.RequireOwnOrganization("id"), queueHandler.GetClosures)
+	organizations.Get("/:id/notification-templates", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), notificationHandler.GetTemplates)
+	organizations.Put("/:id/notification-templates/:key/:locale", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), notificationHandler.UpdateTemplate)
+	organizations.Delete("/:id/notification-templates/:key/:locale", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), notificationHandler.DeleteTemplate)
+	organizations.Get("/:id/display-tokens", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), displayHandler.GetTokens)
+	organizations.Post("/:id/display-tokens", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOwnOrganization("id"), displayHandler.CreateToken)
+
+	// Schedule routes
+	schedules := api.Group("/schedules")
+	schedules.Put("/templates/:id", scheduleHandler.UpdateTemplate)
+	schedules.Delete("/templates/:id", scheduleHandler.DeleteTemplate)
+	schedules.Delete("/overrides/:id", scheduleHandler.DeleteOverride)
+
+	// Room routes
+	rooms := api.Group("/rooms")
+	rooms.Get("/", roomHandler.GetRooms)
+	rooms.Get("/occupancy", roomHandler.GetOccupancy) // Must be before /:id route
+	rooms.Get("/:id", roomHandler.GetRoom)
+	rooms.Post("/", roomHandler.CreateRoom)
+	rooms.Put("/:id", roomHandler.UpdateRoom)
+	rooms.Put("/:id/availability", roomHandler.UpdateAvailability)
+	rooms.Put("/:id/equipment", roomHandler.UpdateEquipment)
+	rooms.Delete("/:id", roomHandler.DeleteRoom)
 
 	// Appointment routes (temporarily without auth for development)
 	appointments := api.Group("/appointments")
 	appointments.Get("/", appointmentsHandler.GetAppointments)
-	appointments.Get("/:id", appointmentsHandler.GetAppointment)
-	appointments.Post("/", appointmentsHandler.CreateAppointment)
+	appointments.Get("/series/:seriesId", appointmentsHandler.GetAppointmentSeries) // Must be before /:id route
+	appointments.Get("/:id", middleware.OptionalAuth(), appointmentsHandler.GetAppointment)
+	appointments.Post("/", middleware.OptionalAuth(), appointmentsHandler.CreateAppointment)
 	appointments.Put("/:id", appointmentsHandler.UpdateAppointment)
 	appointments.Delete("/:id", appointmentsHandler.DeleteAppointment)
-	appointments.Put("/:id/status", appointmentsHandler.UpdateAppointmentStatus)
+	appointments.Put("/:id/status", middleware.AuthRequired(), appointmentsHandler.UpdateAppointmentStatus)
+	appointments.Post("/:id/reschedule", middleware.AuthRequired(), appointmentsHandler.RescheduleAppointment)
+	appointments.Get("/:id/history", appointmentsHandler.GetAppointmentHistory)
+	appointments.Post("/:id/deposit", appointmentsHandler.RecordDeposit)
+	appointments.Get("/:id/ics", calendarHandler.GetAppointmentICS)
+	appointments.Put("/:id/series", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), appointmentsHandler.UpdateAppointmentSeries)
+	appointments.Post("/:id/series/cancel", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), appointmentsHandler.CancelAppointmentSeries)
+
+	// Check-in routes; patients check themselves in at a kiosk with the code sent to them
+	checkIn := api.Group("/check-in")
+	checkIn.Post("/", checkinHandler.CheckInByCode)
+	checkIn.Post("/staff", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), middleware.RequireOrganization(), checkinHandler.CheckIn)
+	checkIn.Post("/walk-in", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), middleware.RequireOrganization(), checkinHandler.RegisterWalkIn)
+
+	// Calendar feed routes (feeds are authenticated by the token in the URL)
+	calendarRoutes := api.Group("/calendar")
+	calendarRoutes.Get("/feeds/:token.ics", calendarHandler.GetFeed)
+	calendarRoutes.Delete("/feeds/:id", middleware.AuthRequired(), calendarHandler.RevokeFeed)
+
+	// Waiting-room display routes (boards are authenticated by the token in the URL)
+	displayRoutes := api.Group("/display")
+	displayRoutes.Delete("/tokens/:id", middleware.AuthRequired(), middleware.RequireRole("admin"), displayHandler.RevokeToken)
+	displayRoutes.Get("/:token", displayHandler.GetBoard)
+
+	// Waitlist routes
+	waitlistRoutes := api.Group("/waitlist")
+	waitlistRoutes.Get("/", waitlistHandler.GetEntries)
+	waitlistRoutes.Post("/", waitlistHandler.CreateEntry)
+	waitlistRoutes.Get("/holds/:id", waitlistHandler.GetHold) // Must be before /:id route
+	waitlistRoutes.Post("/holds/:id/accept", middleware.AuthRequired(), waitlistHandler.AcceptHold)
+	waitlistRoutes.Post("/holds/:id/decline", middleware.AuthRequired(), waitlistHandler.DeclineHold)
+	waitlistRoutes.Get("/:id", waitlistHandler.GetEntry)
+	waitlistRoutes.Delete("/:id", waitlistHandler.CancelEntry)
 
 	// Queue routes
 	queues := api.Group("/queues")
 	queues.Get("/", queueHandler.GetQueues)
 	queues.Get("/patient/:patientId", queueHandler.GetPatientQueue) // Must be before /:id route
+	queues.Get("/state", queueHandler.GetQueueState)
+	queues.Get("/stream", queueStreamHandler.StreamEvents)
+	queues.Get("/ws", queueStreamHandler.UpgradeWebSocket, websocket.New(queueStreamHandler.StreamWebSocket))
 	queues.Get("/:id", queueHandler.GetQueue)
-	queues.Post("/", queueHandler.CreateQueue)
-	queues.Put("/:id", queueHandler.UpdateQueue)
-	queues.Delete("/:id", queueHandler.DeleteQueue)
-	queues.Post("/:id/actions", queueHandler.QueueAction)
+	queues.Post("/", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), queueHandler.CreateQueue)
+	queues.Put("/:id", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), queueHandler.UpdateQueue)
+	queues.Delete("/:id", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), middleware.RequireOrganization(), queueHandler.DeleteQueue)
+	queues.Post("/:id/actions", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), middleware.RequireOrganization(), queueHandler.QueueAction)
+	queues.Put("/:id/triage", middleware.AuthRequired(), middleware.RequireRole("admin", "doctor", "nurse"), middleware.RequireOrganization(), queueHandler.RetriageQueue)
+	queues.Get("/:id/triage-history", middleware.AuthRequired(), queueHandler.GetTriageHistory)
+	queues.Get("/:id/events", middleware.AuthRequired(), queueHandler.GetStatusHistory)
 
 	// Notification routes
 	notifications := api.Group("/notifications")
 	notifications.Get("/", middleware.AuthRequired(), notificationHandler.GetNotifications)
 	notifications.Get("/unread-count", middleware.AuthRequired(), notificationHandler.GetUnreadCount)
+	notifications.Get("/preferences", middleware.AuthRequired(), notificationHandler.GetPreferences)
+	notifications.Put("/preferences", middleware.AuthRequired(), notificationHandler.UpdatePreferences)
 	notifications.Put("/:id/read", middleware.AuthRequired(), notificationHandler.MarkAsRead)
 	notifications.Put("/:id/unread", middleware.AuthRequired(), notificationHandler.MarkAsUnread)
 	notifications.Put("/read-all", middleware.AuthRequired(), notificationHandler.MarkAllAsRead)
 	notifications.Delete("/:id", middleware.AuthRequired(), notificationHandler.DeleteNotification)
+	notifications.Get("/:id/deliveries", middleware.AuthRequired(), notificationHandler.GetDeliveries)
 
 	// Dashboard routes
 	dashboard := api.Group("/dashboard")
 	dashboard.Get("/summary", dashboardHandler.GetDashboardSummary)
+
+	// Outbox routes, for events the relay gave up on
+	outboxRoutes := api.Group("/admin/outbox")
+	outboxRoutes.Get("/dead-letters", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOrganization(), outboxHandler.GetDeadLetters)
+	outboxRoutes.Post("/dead-letters/:id/replay", middleware.AuthRequired(), middleware.RequireRole("admin"), middleware.RequireOrganization(), outboxHandler.ReplayDeadLetter)
 }
 
 func (s *Server) Start(ctx context.Context) error {
 	addr := fmt.Sprintf("%s:%s", s.config.Host, s.config.Port)
 	s.logger.Info(ctx, "🚀 Starting server", "address", addr)
+
+	s.jobs.Start(ctx)
+	s.queueHub.Start(ctx)
 	
 	return s.app.Listen(addr)
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info(ctx, "Shutting down server...")
+	s.jobs.Stop()
+	s.queueHub.Stop()
 	return s.app.ShutdownWithContext(ctx)
 }
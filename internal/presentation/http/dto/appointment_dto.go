@@ -2,22 +2,29 @@ package dto
 
 // AppointmentResponse represents an appointment in API responses
 type AppointmentResponse struct {
-	ID             string  `json:"id"`
-	PatientID      string  `json:"patientId"`
-	PatientName    string  `json:"patientName"`
-	DoctorID       string  `json:"doctorId"`
-	DoctorName     string  `json:"doctorName"`
-	OrganizationID string  `json:"organizationId"`
-	RoomID         *string `json:"roomId,omitempty"`
-	Date           string  `json:"date"`
-	StartTime      string  `json:"startTime"`
-	EndTime        string  `json:"endTime"`
-	Duration       int     `json:"duration"`
-	Status         string  `json:"status"`
-	Type           string  `json:"type"`
-	Notes          *string `json:"notes,omitempty"`
-	CreatedAt      string  `json:"createdAt"`
-	UpdatedAt      string  `json:"updatedAt"`
+	ID                   string  `json:"id"`
+	PatientID            string  `json:"patientId"`
+	PatientName          string  `json:"patientName"`
+	DoctorID             string  `json:"doctorId"`
+	DoctorName           string  `json:"doctorName"`
+	OrganizationID       string  `json:"organizationId"`
+	RoomID               *string `json:"roomId,omitempty"`
+	SeriesID             *string `json:"seriesId,omitempty"`
+	RescheduledFromID    *string `json:"rescheduledFromId,omitempty"`
+	Date                 string  `json:"date"`
+	StartTime            string  `json:"startTime"`
+	EndTime              string  `json:"endTime"`
+	Duration             int     `json:"duration"`
+	Status               string  `json:"status"`
+	Type                 string  `json:"type"`
+	Notes                *string `json:"notes,omitempty"`
+	ConfirmationRequired bool    `json:"confirmationRequired"`
+	DepositRequired      bool    `json:"depositRequired"`
+	DepositPaidAt        *string `json:"depositPaidAt,omitempty"`
+	CheckInCode          string  `json:"checkInCode,omitempty"` // only for the appointment's own patient
+	WalkIn               bool    `json:"walkIn"`
+	CreatedAt            string  `json:"createdAt"`
+	UpdatedAt            string  `json:"updatedAt"`
 }
 
 // CreateAppointmentRequest represents the request to create an appointment
@@ -32,6 +39,18 @@ type CreateAppointmentRequest struct {
 	Duration       int     `json:"duration" validate:"required,min=15,max=480"`
 	Type           string  `json:"type" validate:"required,oneof=consultation follow_up emergency routine_checkup"`
 	Notes          *string `json:"notes,omitempty"`
+
+	// Optional recurrence; when set the request creates a linked series
+	Recurrence    *RecurrenceRequest `json:"recurrence,omitempty"`
+	SkipConflicts bool               `json:"skipConflicts,omitempty"` // create the free occurrences and report the rest
+}
+
+// RecurrenceRequest describes how an appointment repeats (RRULE subset: FREQ, INTERVAL, COUNT, UNTIL)
+type RecurrenceRequest struct {
+	Frequency string  `json:"frequency" validate:"required,oneof=daily weekly monthly"`
+	Interval  int     `json:"interval,omitempty" validate:"omitempty,min=1,max=12"`
+	Count     *int    `json:"count,omitempty" validate:"omitempty,min=1,max=104"`
+	Until     *string `json:"until,omitempty"` // YYYY-MM-DD, inclusive
 }
 
 // UpdateAppointmentRequest represents the request to update an appointment
@@ -45,9 +64,100 @@ type UpdateAppointmentRequest struct {
 	RoomID    *string `json:"roomId,omitempty" validate:"omitempty,uuid"`
 }
 
+// RescheduleAppointmentRequest moves an appointment to a new slot. The original is
+// cancelled and the new appointment keeps a link to it.
+type RescheduleAppointmentRequest struct {
+	Date      string  `json:"date" validate:"required"`
+	StartTime string  `json:"startTime" validate:"required"`
+	EndTime   string  `json:"endTime" validate:"required"`
+	Duration  *int    `json:"duration,omitempty" validate:"omitempty,min=15,max=480"`
+	RoomID    *string `json:"roomId,omitempty" validate:"omitempty,uuid"`
+	Reason    *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// UpdateSeriesRequest represents an edit applied to one or more occurrences of a series
+type UpdateSeriesRequest struct {
+	Scope string `json:"scope" validate:"required,oneof=this following all"`
+	UpdateAppointmentRequest
+}
+
+// CancelSeriesRequest represents the cancellation of one or more occurrences of a series
+type CancelSeriesRequest struct {
+	Scope  string  `json:"scope" validate:"required,oneof=this following all"`
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
 // UpdateAppointmentStatusRequest represents the request to update appointment status
 type UpdateAppointmentStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=pending confirmed in_progress completed cancelled no_show"`
+	Status string  `json:"status" validate:"required,oneof=pending confirmed checked_in in_progress completed cancelled no_show"`
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// AppointmentStatusChangeResponse represents one entry of an appointment's status history
+type AppointmentStatusChangeResponse struct {
+	ID         string  `json:"id"`
+	FromStatus string  `json:"fromStatus"`
+	ToStatus   string  `json:"toStatus"`
+	ActorID    *string `json:"actorId,omitempty"`
+	Reason     *string `json:"reason,omitempty"`
+	ChangedAt  string  `json:"changedAt"`
+}
+
+// AppointmentConflict represents an existing appointment that overlaps a requested slot
+type AppointmentConflict struct {
+	AppointmentID string `json:"appointmentId"`
+	Resource      string `json:"resource"` // doctor, patient or room
+	Date          string `json:"date"`
+	StartTime     string `json:"startTime"`
+	EndTime       string `json:"endTime"`
+	Status        string `json:"status"`
+}
+
+// AppointmentConflictResponse represents a 409 response listing the conflicting appointments
+type AppointmentConflictResponse struct {
+	Success   bool                  `json:"success"`
+	Error     string                `json:"error"`
+	Message   string                `json:"message"`
+	Conflicts []AppointmentConflict `json:"conflicts"`
+}
+
+// AppointmentSeriesResponse represents a recurring series in API responses
+type AppointmentSeriesResponse struct {
+	ID             string  `json:"id"`
+	OrganizationID string  `json:"organizationId"`
+	PatientID      string  `json:"patientId"`
+	DoctorID       string  `json:"doctorId"`
+	Frequency      string  `json:"frequency"`
+	Interval       int     `json:"interval"`
+	Count          *int    `json:"count,omitempty"`
+	Until          *string `json:"until,omitempty"`
+	RRule          string  `json:"rrule"`
+	StartDate      string  `json:"startDate"`
+	CreatedAt      string  `json:"createdAt"`
+	UpdatedAt      string  `json:"updatedAt"`
+}
+
+// SeriesOccurrenceConflict lists the bookings that collide with one occurrence of a series
+type SeriesOccurrenceConflict struct {
+	Date      string                `json:"date"`
+	StartTime string                `json:"startTime"`
+	EndTime   string                `json:"endTime"`
+	Conflicts []AppointmentConflict `json:"conflicts"`
+}
+
+// AppointmentSeriesData represents a series together with its occurrences
+type AppointmentSeriesData struct {
+	Series             AppointmentSeriesResponse  `json:"series"`
+	Appointments       []AppointmentResponse      `json:"appointments"`
+	SkippedOccurrences []SeriesOccurrenceConflict `json:"skippedOccurrences,omitempty"`
+}
+
+// SeriesConflictResponse represents a 409 response listing conflicts per occurrence
+type SeriesConflictResponse struct {
+	Success     bool                       `json:"success"`
+	Error       string                     `json:"error"`
+	Message     string                     `json:"message"`
+	Occurrences []SeriesOccurrenceConflict `json:"occurrences"`
 }
 
 // AppointmentStats represents statistics about appointments
@@ -55,6 +165,7 @@ type AppointmentStats struct {
 	Total      int `json:"total"`
 	Pending    int `json:"pending"`
 	Confirmed  int `json:"confirmed"`
+	CheckedIn  int `json:"checkedIn"`
 	InProgress int `json:"inProgress"`
 	Completed  int `json:"completed"`
 	Cancelled  int `json:"cancelled"`
@@ -0,0 +1,20 @@
+package dto
+
+// CreateCalendarFeedRequest represents the request to issue a calendar feed token
+type CreateCalendarFeedRequest struct {
+	Label *string `json:"label,omitempty" validate:"omitempty,max=100"`
+}
+
+// CalendarFeedResponse represents a calendar feed token in API responses. Token
+// and URL are only set when the token is created.
+type CalendarFeedResponse struct {
+	ID         string  `json:"id"`
+	OwnerType  string  `json:"ownerType"`
+	OwnerID    string  `json:"ownerId"`
+	Label      *string `json:"label,omitempty"`
+	Token      string  `json:"token,omitempty"`
+	URL        string  `json:"url,omitempty"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty"`
+	RevokedAt  *string `json:"revokedAt,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+// KioskCheckInRequest checks a patient in by the check-in code sent to them, e.g.
+// scanned from a QR code at a kiosk
+type KioskCheckInRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+// CheckInRequest lets staff check a patient in by their appointment or their check-in
+// code
+type CheckInRequest struct {
+	AppointmentID string `json:"appointmentId,omitempty" validate:"required_without=Code,omitempty,uuid"`
+	Code          string `json:"code,omitempty" validate:"required_without=AppointmentID,omitempty,max=20"`
+	TriageLevel   int    `json:"triageLevel,omitempty" validate:"omitempty,min=1,max=5"`
+}
+
+// WalkInRequest registers a patient who arrived without an appointment. The
+// appointment is booked in the caller's organization, at the first time from now the
+// doctor and patient are free.
+type WalkInRequest struct {
+	PatientID   string  `json:"patientId" validate:"required,uuid"`
+	DoctorID    string  `json:"doctorId" validate:"required,uuid"`
+	Type        string  `json:"type,omitempty" validate:"omitempty,oneof=consultation follow_up emergency routine_checkup"`
+	Duration    int     `json:"duration,omitempty" validate:"omitempty,min=5,max=480"`
+	Notes       *string `json:"notes,omitempty"`
+	TriageLevel int     `json:"triageLevel,omitempty" validate:"omitempty,min=1,max=5"`
+}
+
+// CheckInData represents a checked-in appointment and the patient's place in the queue
+type CheckInData struct {
+	Appointment AppointmentResponse `json:"appointment"`
+	Queue       QueueResponse       `json:"queue"`
+}
+
+// KioskCheckInData is what a kiosk shows a patient who checked in: their ticket and
+// place in the queue
+type KioskCheckInData struct {
+	TicketNumber string `json:"ticketNumber"`
+	Position     int    `json:"position"`
+}
+
+// KioskCheckInResponse represents the response for a check-in at a kiosk
+type KioskCheckInResponse struct {
+	Success bool             `json:"success"`
+	Data    KioskCheckInData `json:"data"`
+	Message string           `json:"message"`
+}
+
+// CheckInResponse represents the response for a check-in
+type CheckInResponse struct {
+	Success bool        `json:"success"`
+	Data    CheckInData `json:"data"`
+	Message string      `json:"message"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// CreateDisplayTokenRequest represents the request to issue a display board token.
+// A scope limits the board to one doctor's, room's or service point's queue.
+type CreateDisplayTokenRequest struct {
+	Label     *string `json:"label,omitempty" validate:"omitempty,max=100"`
+	ScopeType string  `json:"scopeType,omitempty" validate:"omitempty,oneof=doctor room service_point"`
+	ScopeID   string  `json:"scopeId,omitempty" validate:"omitempty,max=100"`
+}
+
+// DisplayTokenResponse represents a display token in API responses. Token and URL
+// are only set when the token is created.
+type DisplayTokenResponse struct {
+	ID             string  `json:"id"`
+	OrganizationID string  `json:"organizationId"`
+	ScopeType      *string `json:"scopeType,omitempty"`
+	ScopeID        *string `json:"scopeId,omitempty"`
+	Label          *string `json:"label,omitempty"`
+	Token          string  `json:"token,omitempty"`
+	URL            string  `json:"url,omitempty"`
+	LastUsedAt     *string `json:"lastUsedAt,omitempty"`
+	RevokedAt      *string `json:"revokedAt,omitempty"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
+// DisplayBoardEntryResponse represents one ticket on a display board
+type DisplayBoardEntryResponse struct {
+	TicketNumber string  `json:"ticketNumber"`
+	RoomName     *string `json:"roomName,omitempty"`
+	Status       string  `json:"status"`
+}
+
+// DisplayBoardResponse represents a waiting-room display board
+type DisplayBoardResponse struct {
+	NowServing  []DisplayBoardEntryResponse `json:"nowServing"`
+	NextUp      []DisplayBoardEntryResponse `json:"nextUp"`
+	GeneratedAt string                      `json:"generatedAt"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+// UpdateNoShowPolicyRequest represents the request to replace an organization's no-show policy
+type UpdateNoShowPolicyRequest struct {
+	AutoMark              bool    `json:"autoMark"`
+	GracePeriodMinutes    *int    `json:"gracePeriodMinutes,omitempty" validate:"omitempty,min=0,max=1440"`
+	ConfirmationThreshold int     `json:"confirmationThreshold" validate:"min=0"`
+	ConfirmationLeadHours int     `json:"confirmationLeadHours" validate:"omitempty,min=1,max=168"`
+	DepositThreshold      int     `json:"depositThreshold" validate:"min=0"`
+	DepositAmount         float64 `json:"depositAmount" validate:"min=0"`
+}
+
+// NoShowPolicyResponse represents an organization's no-show policy in API responses
+type NoShowPolicyResponse struct {
+	OrganizationID        string  `json:"organizationId"`
+	AutoMark              bool    `json:"autoMark"`
+	GracePeriodMinutes    *int    `json:"gracePeriodMinutes,omitempty"`
+	ConfirmationThreshold int     `json:"confirmationThreshold"`
+	ConfirmationLeadHours int     `json:"confirmationLeadHours"`
+	DepositThreshold      int     `json:"depositThreshold"`
+	DepositAmount         float64 `json:"depositAmount"`
+}
+
+// PatientNoShowResponse represents a patient's no-show history at an organization
+type PatientNoShowResponse struct {
+	OrganizationID string  `json:"organizationId"`
+	PatientID      string  `json:"patientId"`
+	NoShowCount    int     `json:"noShowCount"`
+	LastNoShowAt   *string `json:"lastNoShowAt,omitempty"`
+	Requirement    string  `json:"requirement"`
+}
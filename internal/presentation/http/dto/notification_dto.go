@@ -23,6 +23,66 @@ type NotificationListResponse struct {
 	Pagination Pagination             `json:"pagination"`
 }
 
+// NotificationDeliveryResponse represents a notification's delivery on one external
+// channel in API responses
+type NotificationDeliveryResponse struct {
+	ID            string     `json:"id"`
+	Channel       string     `json:"channel"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// NotificationPreferencesRequest represents a request to update the current user's
+// notification preferences. Channels switches external channels (email, sms, webhook)
+// on or off per notification type.
+type NotificationPreferencesRequest struct {
+	Timezone   string                     `json:"timezone" validate:"required,timezone"`
+	QuietHours *QuietHoursRequest         `json:"quiet_hours,omitempty"`
+	Channels   map[string]map[string]bool `json:"channels,omitempty"`
+}
+
+// QuietHoursRequest represents a daily quiet period, which may span midnight
+type QuietHoursRequest struct {
+	Start string `json:"start" validate:"required,datetime=15:04"`
+	End   string `json:"end" validate:"required,datetime=15:04"`
+}
+
+// NotificationPreferencesResponse represents a user's notification preferences
+type NotificationPreferencesResponse struct {
+	Timezone   string                     `json:"timezone"`
+	QuietHours *QuietHoursRequest         `json:"quiet_hours,omitempty"`
+	Channels   map[string]map[string]bool `json:"channels"`
+	UpdatedAt  *time.Time                 `json:"updated_at,omitempty"`
+}
+
+// NotificationTemplateResponse represents a notification template in effect for an
+// organization
+type NotificationTemplateResponse struct {
+	Key        string     `json:"key"`
+	Locale     string     `json:"locale"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	SMSBody    string     `json:"sms_body,omitempty"`
+	EmailHTML  string     `json:"email_html,omitempty"`
+	Variables  []string   `json:"variables"`
+	Overridden bool       `json:"overridden"` // false for the built-in template
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateNotificationTemplateRequest represents a request to override a notification
+// template for an organization
+type UpdateNotificationTemplateRequest struct {
+	Title     string `json:"title" validate:"required,max=200"`
+	Body      string `json:"body" validate:"required,max=2000"`
+	SMSBody   string `json:"sms_body" validate:"omitempty,max=480"`
+	EmailHTML string `json:"email_html" validate:"omitempty,max=20000"`
+}
+
 // CreateNotificationRequest represents a request to create a notification
 type CreateNotificationRequest struct {
 	Title          string                 `json:"title" validate:"required"`
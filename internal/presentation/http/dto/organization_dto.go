@@ -2,38 +2,41 @@ package dto
 
 // OrganizationResponse represents an organization in API responses
 type OrganizationResponse struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Type        string  `json:"type"`
-	Address     string  `json:"address"`
-	Phone       string  `json:"phone"`
-	Email       string  `json:"email"`
-	Website     *string `json:"website,omitempty"`
-	Status      string  `json:"status"`
-	StaffCount  int     `json:"staffCount"`
-	CreatedAt   string  `json:"createdAt"`
-	UpdatedAt   string  `json:"updatedAt"`
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Address    string  `json:"address"`
+	Phone      string  `json:"phone"`
+	Email      string  `json:"email"`
+	Website    *string `json:"website,omitempty"`
+	Timezone   string  `json:"timezone,omitempty"`
+	Status     string  `json:"status"`
+	StaffCount int     `json:"staffCount"`
+	CreatedAt  string  `json:"createdAt"`
+	UpdatedAt  string  `json:"updatedAt"`
 }
 
 // CreateOrganizationRequest represents the request to create an organization
 type CreateOrganizationRequest struct {
-	Name    string  `json:"name" validate:"required,min=2,max=100"`
-	Type    string  `json:"type" validate:"required,oneof=hospital clinic urgent_care private_practice laboratory"`
-	Address string  `json:"address" validate:"required"`
-	Phone   string  `json:"phone" validate:"required"`
-	Email   string  `json:"email" validate:"required,email"`
-	Website *string `json:"website,omitempty"`
+	Name     string  `json:"name" validate:"required,min=2,max=100"`
+	Type     string  `json:"type" validate:"required,oneof=hospital clinic urgent_care private_practice laboratory"`
+	Address  string  `json:"address" validate:"required"`
+	Phone    string  `json:"phone" validate:"required"`
+	Email    string  `json:"email" validate:"required,email"`
+	Website  *string `json:"website,omitempty"`
+	Timezone string  `json:"timezone,omitempty"` // IANA zone of the hours and schedules, the server's own if empty
 }
 
 // UpdateOrganizationRequest represents the request to update an organization
 type UpdateOrganizationRequest struct {
-	Name    *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
-	Type    *string `json:"type,omitempty" validate:"omitempty,oneof=hospital clinic urgent_care private_practice laboratory"`
-	Address *string `json:"address,omitempty"`
-	Phone   *string `json:"phone,omitempty"`
-	Email   *string `json:"email,omitempty" validate:"omitempty,email"`
-	Website *string `json:"website,omitempty"`
-	Status  *string `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Type     *string `json:"type,omitempty" validate:"omitempty,oneof=hospital clinic urgent_care private_practice laboratory"`
+	Address  *string `json:"address,omitempty"`
+	Phone    *string `json:"phone,omitempty"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+	Website  *string `json:"website,omitempty"`
+	Status   *string `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+	Timezone *string `json:"timezone,omitempty"` // IANA zone of the hours and schedules
 }
 
 // OrganizationStats represents statistics about organizations
@@ -48,8 +51,8 @@ type OrganizationStats struct {
 // OrganizationsData represents the data structure for organizations list response
 type OrganizationsData struct {
 	Organizations []OrganizationResponse `json:"organizations"`
-	Pagination    Pagination            `json:"pagination"`
-	Stats         OrganizationStats     `json:"stats"`
+	Pagination    Pagination             `json:"pagination"`
+	Stats         OrganizationStats      `json:"stats"`
 }
 
 // OrganizationsResponse represents the response for organizations list
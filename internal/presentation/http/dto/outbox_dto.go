@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeadLetterResponse represents a domain event the outbox relay gave up on
+type DeadLetterResponse struct {
+	ID        string          `json:"id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	Delivered []string        `json:"delivered,omitempty"`
+	LastError string          `json:"last_error"`
+	CreatedAt time.Time       `json:"created_at"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+// DeadLetterListResponse represents a paginated list of dead letters
+type DeadLetterListResponse struct {
+	Data       []DeadLetterResponse `json:"data"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// OutboxMessageResponse represents a domain event waiting in the outbox
+type OutboxMessageResponse struct {
+	ID            string    `json:"id"`
+	EventType     string    `json:"event_type"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
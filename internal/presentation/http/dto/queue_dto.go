@@ -4,23 +4,35 @@ import "time"
 
 // QueueRequest represents the request to create/update a queue
 type QueueRequest struct {
-	AppointmentID     string `json:"appointment_id" validate:"required,uuid"`
-	OrganizationID    string `json:"organization_id" validate:"required,uuid"`
-	Position          int    `json:"position,omitempty"`
-	EstimatedWaitTime int    `json:"estimated_wait_time,omitempty"`
-	Status            string `json:"status,omitempty"`
+	AppointmentID  string `json:"appointment_id" validate:"required,uuid"`
+	OrganizationID string `json:"organization_id" validate:"required,uuid"`
+	ScopeType      string `json:"scope_type,omitempty" validate:"omitempty,oneof=doctor room service_point"`
+	ScopeID        string `json:"scope_id,omitempty" validate:"omitempty,max=100"`
+	TriageLevel    int    `json:"triage_level,omitempty" validate:"omitempty,min=1,max=5"`
+	Position       int    `json:"position,omitempty"`
+	Status         string `json:"status,omitempty"`
 }
 
 // QueueResponse represents a queue response
 type QueueResponse struct {
-	ID                string    `json:"id"`
-	AppointmentID     string    `json:"appointment_id"`
-	OrganizationID    string    `json:"organization_id"`
-	Position          int       `json:"position"`
-	EstimatedWaitTime int       `json:"estimated_wait_time"`
-	Status            string    `json:"status"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                string     `json:"id"`
+	AppointmentID     string     `json:"appointment_id"`
+	OrganizationID    string     `json:"organization_id"`
+	ScopeType         string     `json:"scope_type"`
+	ScopeID           string     `json:"scope_id"`
+	TriageLevel       int        `json:"triage_level"`
+	TicketNumber      string     `json:"ticket_number,omitempty"`
+	Position          int        `json:"position"`
+	EstimatedWaitTime int        `json:"estimated_wait_time"`
+	EstimatedWaitLow  int        `json:"estimated_wait_low"`
+	EstimatedWaitHigh int        `json:"estimated_wait_high"`
+	Status            string     `json:"status"`
+	CalledAt          *time.Time `json:"called_at,omitempty"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	CarriedOverTo     *time.Time `json:"carried_over_to,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // QueuesResponse represents the response for multiple queues
@@ -42,7 +54,32 @@ type QueueDetailResponse struct {
 
 // QueueActionRequest represents a request for queue actions
 type QueueActionRequest struct {
-	Action string `json:"action" validate:"required,oneof=call start complete cancel"`
+	Action string `json:"action" validate:"required,oneof=call_next start_consultation complete_consultation skip recall transfer hold release pause_queue resume_queue"`
+	// Queue to call from, pause or resume, defaulting to the calling doctor's queue and
+	// then to the queue of the entry the action is performed on; for transfer, the
+	// queue to move the entry to
+	ScopeType string `json:"scope_type,omitempty" validate:"omitempty,oneof=doctor room service_point"`
+	ScopeID   string `json:"scope_id,omitempty" validate:"omitempty,max=100"`
+	// Why a queue is paused
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// QueueStateResponse represents whether a queue is paused
+type QueueStateResponse struct {
+	OrganizationID string     `json:"organization_id"`
+	ScopeType      string     `json:"scope_type"`
+	ScopeID        string     `json:"scope_id"`
+	Paused         bool       `json:"paused"`
+	Reason         *string    `json:"reason,omitempty"`
+	ChangedBy      *string    `json:"changed_by,omitempty"`
+	ChangedAt      *time.Time `json:"changed_at,omitempty"`
+}
+
+// QueueStateDetailResponse represents the response for a queue's state
+type QueueStateDetailResponse struct {
+	Success bool               `json:"success"`
+	Data    QueueStateResponse `json:"data"`
+	Message string             `json:"message"`
 }
 
 // QueuePositionUpdate represents a queue position update
@@ -53,14 +90,23 @@ type QueuePositionUpdate struct {
 
 // PatientQueueResponse represents a queue response with enriched patient and appointment data
 type PatientQueueResponse struct {
-	ID                string    `json:"id"`
-	AppointmentID     string    `json:"appointment_id"`
-	OrganizationID    string    `json:"organization_id"`
-	Position          int       `json:"position"`
-	EstimatedWaitTime int       `json:"estimated_wait_time"`
-	Status            string    `json:"status"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                string     `json:"id"`
+	AppointmentID     string     `json:"appointment_id"`
+	OrganizationID    string     `json:"organization_id"`
+	ScopeType         string     `json:"scope_type"`
+	ScopeID           string     `json:"scope_id"`
+	TriageLevel       int        `json:"triage_level"`
+	TicketNumber      string     `json:"ticket_number,omitempty"`
+	Position          int        `json:"position"`
+	EstimatedWaitTime int        `json:"estimated_wait_time"`
+	EstimatedWaitLow  int        `json:"estimated_wait_low"`
+	EstimatedWaitHigh int        `json:"estimated_wait_high"`
+	Status            string     `json:"status"`
+	CalledAt          *time.Time `json:"called_at,omitempty"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 	
 	// Enriched data
 	PatientName    string `json:"patient_name"`
@@ -79,3 +125,75 @@ type PatientQueueDetailResponse struct {
 	Data    *PatientQueueResponse `json:"data"`
 	Message string                `json:"message"`
 }
+
+// RetriageRequest represents a request to change a queue entry's triage level
+type RetriageRequest struct {
+	TriageLevel int    `json:"triage_level" validate:"required,min=1,max=5"`
+	Reason      string `json:"reason" validate:"required,max=500"`
+}
+
+// TriageChangeResponse represents one entry of a queue entry's triage history
+type TriageChangeResponse struct {
+	ID        string    `json:"id"`
+	QueueID   string    `json:"queue_id"`
+	FromLevel int       `json:"from_level"`
+	ToLevel   int       `json:"to_level"`
+	Reason    string    `json:"reason"`
+	ActorID   *string   `json:"actor_id,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// StatusChangeResponse represents one entry of a queue entry's status history
+type StatusChangeResponse struct {
+	ID         string    `json:"id"`
+	QueueID    string    `json:"queue_id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    *string   `json:"actor_id,omitempty"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// StatusHistoryResponse represents the response for a queue entry's status history
+type StatusHistoryResponse struct {
+	Success bool                   `json:"success"`
+	Data    []StatusChangeResponse `json:"data"`
+	Message string                 `json:"message"`
+}
+
+// TriageHistoryResponse represents the response for a queue entry's triage history
+type TriageHistoryResponse struct {
+	Success bool                   `json:"success"`
+	Data    []TriageChangeResponse `json:"data"`
+	Message string                 `json:"message"`
+}
+
+// UpdateQueueClosingPolicyRequest represents the request to replace an organization's queue closing policy
+type UpdateQueueClosingPolicyRequest struct {
+	Enabled        bool   `json:"enabled"`
+	CloseTime      string `json:"close_time" validate:"required,datetime=15:04"`
+	LeftoverAction string `json:"leftover_action" validate:"required,oneof=cancel carry_over"`
+}
+
+// QueueClosingPolicyResponse represents an organization's queue closing policy in API responses
+type QueueClosingPolicyResponse struct {
+	OrganizationID string `json:"organization_id"`
+	Enabled        bool   `json:"enabled"`
+	CloseTime      string `json:"close_time"`
+	LeftoverAction string `json:"leftover_action"`
+}
+
+// QueueClosureResponse represents the summary of a closed queue day
+type QueueClosureResponse struct {
+	ID                 string    `json:"id"`
+	OrganizationID     string    `json:"organization_id"`
+	Day                string    `json:"day"`
+	LeftoverAction     string    `json:"leftover_action"`
+	Joined             int       `json:"joined"`
+	Completed          int       `json:"completed"`
+	Cancelled          int       `json:"cancelled"`
+	LeftoverCancelled  int       `json:"leftover_cancelled"`
+	CarriedOver        int       `json:"carried_over"`
+	InProgress         int       `json:"in_progress"`
+	AverageWaitMinutes int       `json:"average_wait_minutes"`
+	ClosedAt           time.Time `json:"closed_at"`
+}
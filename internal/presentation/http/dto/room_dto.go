@@ -0,0 +1,70 @@
+package dto
+
+// CreateRoomRequest represents the request to add a room
+type CreateRoomRequest struct {
+	OrganizationID string   `json:"organizationId" validate:"required,uuid"`
+	Name           string   `json:"name" validate:"required,min=1,max=255"`
+	Type           string   `json:"type" validate:"required,oneof=consultation examination procedure waiting office"`
+	Capacity       int      `json:"capacity" validate:"omitempty,min=1"`
+	IsAvailable    *bool    `json:"isAvailable,omitempty"`
+	Equipment      []string `json:"equipment,omitempty" validate:"omitempty,dive,max=100"`
+}
+
+// UpdateRoomRequest represents a partial room update
+type UpdateRoomRequest struct {
+	Name        *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Type        *string  `json:"type,omitempty" validate:"omitempty,oneof=consultation examination procedure waiting office"`
+	Capacity    *int     `json:"capacity,omitempty" validate:"omitempty,min=1"`
+	IsAvailable *bool    `json:"isAvailable,omitempty"`
+	Equipment   []string `json:"equipment,omitempty" validate:"omitempty,dive,max=100"`
+}
+
+// UpdateRoomAvailabilityRequest represents the request to open or close a room
+type UpdateRoomAvailabilityRequest struct {
+	IsAvailable *bool `json:"isAvailable" validate:"required"`
+}
+
+// UpdateRoomEquipmentRequest represents the request to replace a room's equipment list
+type UpdateRoomEquipmentRequest struct {
+	Equipment []string `json:"equipment" validate:"dive,max=100"`
+}
+
+// RoomResponse represents a room in API responses
+type RoomResponse struct {
+	ID             string   `json:"id"`
+	OrganizationID string   `json:"organizationId"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Capacity       int      `json:"capacity"`
+	IsAvailable    bool     `json:"isAvailable"`
+	Equipment      []string `json:"equipment"`
+	CreatedAt      string   `json:"createdAt"`
+	UpdatedAt      string   `json:"updatedAt"`
+}
+
+// RoomBookingResponse represents an appointment in a room's occupancy timeline
+type RoomBookingResponse struct {
+	AppointmentID string `json:"appointmentId"`
+	DoctorID      string `json:"doctorId"`
+	PatientID     string `json:"patientId"`
+	StartTime     string `json:"startTime"`
+	EndTime       string `json:"endTime"`
+	Status        string `json:"status"`
+}
+
+// RoomOccupancySegment represents a stretch of the day with a constant number of bookings
+type RoomOccupancySegment struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	Occupied  int    `json:"occupied"`
+	Capacity  int    `json:"capacity"`
+	Full      bool   `json:"full"`
+}
+
+// RoomOccupancyResponse represents one room's timeline for a day
+type RoomOccupancyResponse struct {
+	Room     RoomResponse           `json:"room"`
+	Date     string                 `json:"date"`
+	Bookings []RoomBookingResponse  `json:"bookings"`
+	Segments []RoomOccupancySegment `json:"segments"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+// ScheduleBreak represents a break inside a working window
+type ScheduleBreak struct {
+	StartTime string `json:"startTime" validate:"required"`
+	EndTime   string `json:"endTime" validate:"required"`
+}
+
+// ScheduleTemplateRequest represents the request to create or replace a weekly schedule template
+type ScheduleTemplateRequest struct {
+	DayOfWeek    *int            `json:"dayOfWeek" validate:"required,min=0,max=6"`
+	StartTime    string          `json:"startTime" validate:"required"`
+	EndTime      string          `json:"endTime" validate:"required"`
+	SlotDuration int             `json:"slotDuration" validate:"required,min=5,max=480"`
+	Breaks       []ScheduleBreak `json:"breaks,omitempty" validate:"dive"`
+	IsActive     *bool           `json:"isActive,omitempty"`
+}
+
+// ScheduleOverrideRequest represents the request to override the schedule for a single date
+type ScheduleOverrideRequest struct {
+	Date         string          `json:"date" validate:"required"`
+	IsAvailable  bool            `json:"isAvailable"`
+	StartTime    *string         `json:"startTime,omitempty"`
+	EndTime      *string         `json:"endTime,omitempty"`
+	SlotDuration *int            `json:"slotDuration,omitempty" validate:"omitempty,min=5,max=480"`
+	Breaks       []ScheduleBreak `json:"breaks,omitempty" validate:"dive"`
+	Reason       *string         `json:"reason,omitempty"`
+}
+
+// ScheduleTemplateResponse represents a schedule template in API responses
+type ScheduleTemplateResponse struct {
+	ID             string          `json:"id"`
+	OrganizationID string          `json:"organizationId"`
+	DoctorID       *string         `json:"doctorId,omitempty"`
+	DayOfWeek      int             `json:"dayOfWeek"`
+	StartTime      string          `json:"startTime"`
+	EndTime        string          `json:"endTime"`
+	SlotDuration   int             `json:"slotDuration"`
+	Breaks         []ScheduleBreak `json:"breaks"`
+	IsActive       bool            `json:"isActive"`
+	CreatedAt      string          `json:"createdAt"`
+	UpdatedAt      string          `json:"updatedAt"`
+}
+
+// ScheduleOverrideResponse represents a schedule override in API responses
+type ScheduleOverrideResponse struct {
+	ID             string          `json:"id"`
+	OrganizationID string          `json:"organizationId"`
+	DoctorID       *string         `json:"doctorId,omitempty"`
+	Date           string          `json:"date"`
+	IsAvailable    bool            `json:"isAvailable"`
+	StartTime      *string         `json:"startTime,omitempty"`
+	EndTime        *string         `json:"endTime,omitempty"`
+	SlotDuration   *int            `json:"slotDuration,omitempty"`
+	Breaks         []ScheduleBreak `json:"breaks"`
+	Reason         *string         `json:"reason,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	UpdatedAt      string          `json:"updatedAt"`
+}
+
+// ScheduleResponse represents the templates and overrides of a doctor or organization
+type ScheduleResponse struct {
+	Templates []ScheduleTemplateResponse `json:"templates"`
+	Overrides []ScheduleOverrideResponse `json:"overrides"`
+}
+
+// SlotResponse represents a bookable slot
+type SlotResponse struct {
+	Date      string `json:"date"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+// SlotsResponse represents the free slots of a doctor over a date range
+type SlotsResponse struct {
+	DoctorID string         `json:"doctorId"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Slots    []SlotResponse `json:"slots"`
+}
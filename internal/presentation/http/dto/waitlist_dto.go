@@ -0,0 +1,48 @@
+package dto
+
+// CreateWaitlistEntryRequest represents the request to put a patient on the waitlist
+type CreateWaitlistEntryRequest struct {
+	OrganizationID string  `json:"organizationId" validate:"required,uuid"`
+	PatientID      string  `json:"patientId" validate:"required,uuid"`
+	DoctorID       *string `json:"doctorId,omitempty" validate:"omitempty,uuid"`
+	Specialty      *string `json:"specialty,omitempty" validate:"omitempty,max=100"`
+	DateFrom       string  `json:"dateFrom" validate:"required"`
+	DateTo         string  `json:"dateTo" validate:"required"`
+	Notes          *string `json:"notes,omitempty"`
+}
+
+// WaitlistEntryResponse represents a waitlist entry in API responses
+type WaitlistEntryResponse struct {
+	ID             string  `json:"id"`
+	OrganizationID string  `json:"organizationId"`
+	PatientID      string  `json:"patientId"`
+	DoctorID       *string `json:"doctorId,omitempty"`
+	Specialty      *string `json:"specialty,omitempty"`
+	DateFrom       string  `json:"dateFrom"`
+	DateTo         string  `json:"dateTo"`
+	Status         string  `json:"status"`
+	Notes          *string `json:"notes,omitempty"`
+	CreatedAt      string  `json:"createdAt"`
+	UpdatedAt      string  `json:"updatedAt"`
+}
+
+// WaitlistHoldResponse represents a slot held for a waitlisted patient
+type WaitlistHoldResponse struct {
+	ID                  string  `json:"id"`
+	EntryID             string  `json:"entryId"`
+	PatientID           string  `json:"patientId"`
+	SourceAppointmentID string  `json:"sourceAppointmentId"`
+	OrganizationID      string  `json:"organizationId"`
+	DoctorID            string  `json:"doctorId"`
+	RoomID              *string `json:"roomId,omitempty"`
+	Date                string  `json:"date"`
+	StartTime           string  `json:"startTime"`
+	EndTime             string  `json:"endTime"`
+	Duration            int     `json:"duration"`
+	Type                string  `json:"type"`
+	Status              string  `json:"status"`
+	ExpiresAt           string  `json:"expiresAt"`
+	AppointmentID       *string `json:"appointmentId,omitempty"`
+	CreatedAt           string  `json:"createdAt"`
+	UpdatedAt           string  `json:"updatedAt"`
+}
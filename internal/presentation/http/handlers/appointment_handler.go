@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/room"
 	"medika-backend/internal/presentation/http/dto"
 	"medika-backend/pkg/logger"
 )
@@ -24,8 +27,17 @@ type AppointmentService interface {
 	GetAppointmentByID(ctx *fiber.Ctx, appointmentID string) (*appointment.Appointment, error)
 	CreateAppointment(ctx *fiber.Ctx, appointmentData *dto.CreateAppointmentRequest) (*appointment.Appointment, error)
 	UpdateAppointment(ctx *fiber.Ctx, appointmentID string, appointmentData *dto.UpdateAppointmentRequest) (*appointment.Appointment, error)
+	RescheduleAppointment(ctx *fiber.Ctx, appointmentID string, req *dto.RescheduleAppointmentRequest) (*appointment.Appointment, error)
 	DeleteAppointment(ctx *fiber.Ctx, appointmentID string) error
-	UpdateAppointmentStatus(ctx *fiber.Ctx, appointmentID string, status string) (*appointment.Appointment, error)
+	UpdateAppointmentStatus(ctx *fiber.Ctx, appointmentID string, status string, reason *string) (*appointment.Appointment, error)
+	GetAppointmentHistory(ctx *fiber.Ctx, appointmentID string) ([]*appointment.StatusChange, error)
+	RecordDeposit(ctx *fiber.Ctx, appointmentID string) (*appointment.Appointment, error)
+
+	// Recurring series
+	CreateAppointmentSeries(ctx *fiber.Ctx, appointmentData *dto.CreateAppointmentRequest) (*appointment.Series, []*appointment.Appointment, []appointment.OccurrenceConflict, error)
+	GetAppointmentSeries(ctx *fiber.Ctx, seriesID string) (*appointment.Series, []*appointment.Appointment, error)
+	UpdateSeriesOccurrences(ctx *fiber.Ctx, appointmentID string, scope appointment.SeriesScope, changes *dto.UpdateAppointmentRequest) ([]*appointment.Appointment, error)
+	CancelSeriesOccurrences(ctx *fiber.Ctx, appointmentID string, scope appointment.SeriesScope, reason *string) ([]*appointment.Appointment, error)
 }
 
 func NewAppointmentHandler(
@@ -86,22 +98,28 @@ func (h *AppointmentHandler) GetAppointments(c *fiber.Ctx) error {
 	appointmentResponses := make([]dto.AppointmentResponse, len(appointments))
 	for i, apt := range appointments {
 		appointmentResponses[i] = dto.AppointmentResponse{
-			ID:             apt.ID,
-			PatientID:      apt.PatientID,
-			PatientName:    "", // TODO: Fetch from patient entity
-			DoctorID:       apt.DoctorID,
-			DoctorName:     "", // TODO: Fetch from doctor entity
-			OrganizationID: apt.OrganizationID,
-			RoomID:         apt.RoomID,
-			Date:           apt.Date.Format("2006-01-02"),
-			StartTime:      apt.StartTime,
-			EndTime:        apt.EndTime,
-			Duration:       apt.Duration,
-			Status:         string(apt.Status),
-			Type:           apt.Type,
-			Notes:          apt.Notes,
-			CreatedAt:      apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:      apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:                   apt.ID,
+			PatientID:            apt.PatientID,
+			PatientName:          "", // TODO: Fetch from patient entity
+			DoctorID:             apt.DoctorID,
+			DoctorName:           "", // TODO: Fetch from doctor entity
+			OrganizationID:       apt.OrganizationID,
+			RoomID:               apt.RoomID,
+			SeriesID:             apt.SeriesID,
+			RescheduledFromID:    apt.RescheduledFromID,
+			Date:                 apt.Date.Format("2006-01-02"),
+			StartTime:            apt.StartTime,
+			EndTime:              apt.EndTime,
+			Duration:             apt.Duration,
+			Status:               string(apt.Status),
+			Type:                 apt.Type,
+			Notes:                apt.Notes,
+			ConfirmationRequired: apt.ConfirmationRequired,
+			DepositRequired:      apt.DepositRequired,
+			DepositPaidAt:        formatOptionalTime(apt.DepositPaidAt),
+			WalkIn:               apt.WalkIn,
+			CreatedAt:            apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:            apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
 	}
 
@@ -120,6 +138,7 @@ func (h *AppointmentHandler) GetAppointments(c *fiber.Ctx) error {
 				Total:      total,
 				Pending:    countAppointmentsByStatus(appointments, "pending"),
 				Confirmed:  countAppointmentsByStatus(appointments, "confirmed"),
+				CheckedIn:  countAppointmentsByStatus(appointments, "checked_in"),
 				InProgress: countAppointmentsByStatus(appointments, "in_progress"),
 				Completed:  countAppointmentsByStatus(appointments, "completed"),
 				Cancelled:  countAppointmentsByStatus(appointments, "cancelled"),
@@ -153,24 +172,30 @@ func (h *AppointmentHandler) GetAppointment(c *fiber.Ctx) error {
 
 	response := dto.SuccessResponse{
 		Success: true,
-		Data: dto.AppointmentResponse{
-			ID:             apt.ID,
-			PatientID:      apt.PatientID,
-			PatientName:    "", // TODO: Fetch from patient entity
-			DoctorID:       apt.DoctorID,
-			DoctorName:     "", // TODO: Fetch from doctor entity
-			OrganizationID: apt.OrganizationID,
-			RoomID:         apt.RoomID,
-			Date:           apt.Date.Format("2006-01-02"),
-			StartTime:      apt.StartTime,
-			EndTime:        apt.EndTime,
-			Duration:       apt.Duration,
-			Status:         string(apt.Status),
-			Type:           apt.Type,
-			Notes:          apt.Notes,
-			CreatedAt:      apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:      apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		Data: withPatientCheckInCode(c, apt, dto.AppointmentResponse{
+			ID:                   apt.ID,
+			PatientID:            apt.PatientID,
+			PatientName:          "", // TODO: Fetch from patient entity
+			DoctorID:             apt.DoctorID,
+			DoctorName:           "", // TODO: Fetch from doctor entity
+			OrganizationID:       apt.OrganizationID,
+			RoomID:               apt.RoomID,
+			SeriesID:             apt.SeriesID,
+			RescheduledFromID:    apt.RescheduledFromID,
+			Date:                 apt.Date.Format("2006-01-02"),
+			StartTime:            apt.StartTime,
+			EndTime:              apt.EndTime,
+			Duration:             apt.Duration,
+			Status:               string(apt.Status),
+			Type:                 apt.Type,
+			Notes:                apt.Notes,
+			ConfirmationRequired: apt.ConfirmationRequired,
+			DepositRequired:      apt.DepositRequired,
+			DepositPaidAt:        formatOptionalTime(apt.DepositPaidAt),
+			WalkIn:               apt.WalkIn,
+			CreatedAt:            apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:            apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}),
 		Message: "Appointment retrieved successfully",
 	}
 
@@ -194,9 +219,29 @@ func (h *AppointmentHandler) CreateAppointment(c *fiber.Ctx) error {
 		})
 	}
 
+	if req.Recurrence != nil {
+		return h.createAppointmentSeries(c, &req)
+	}
+
 	apt, err := h.appointmentService.CreateAppointment(c, &req)
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to create appointment", "error", err)
+
+		var conflictErr *appointment.ConflictError
+		if errors.As(err, &conflictErr) {
+			return respondAppointmentConflict(c, conflictErr)
+		}
+
+		if status, body, ok := roomBookingError(err); ok {
+			return c.Status(status).JSON(body)
+		}
+
+		if errors.Is(err, appointment.ErrInvalidTimeRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid appointment time",
+				Message: err.Error(),
+			})
+		}
 		
 		// Return 401 if organization ID is missing
 		if err.Error() == "organization ID is required" {
@@ -214,24 +259,30 @@ func (h *AppointmentHandler) CreateAppointment(c *fiber.Ctx) error {
 
 	response := dto.SuccessResponse{
 		Success: true,
-		Data: dto.AppointmentResponse{
-			ID:             apt.ID,
-			PatientID:      apt.PatientID,
-			PatientName:    "", // TODO: Fetch from patient entity
-			DoctorID:       apt.DoctorID,
-			DoctorName:     "", // TODO: Fetch from doctor entity
-			OrganizationID: apt.OrganizationID,
-			RoomID:         apt.RoomID,
-			Date:           apt.Date.Format("2006-01-02"),
-			StartTime:      apt.StartTime,
-			EndTime:        apt.EndTime,
-			Duration:       apt.Duration,
-			Status:         string(apt.Status),
-			Type:           apt.Type,
-			Notes:          apt.Notes,
-			CreatedAt:      apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:      apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		Data: withPatientCheckInCode(c, apt, dto.AppointmentResponse{
+			ID:                   apt.ID,
+			PatientID:            apt.PatientID,
+			PatientName:          "", // TODO: Fetch from patient entity
+			DoctorID:             apt.DoctorID,
+			DoctorName:           "", // TODO: Fetch from doctor entity
+			OrganizationID:       apt.OrganizationID,
+			RoomID:               apt.RoomID,
+			SeriesID:             apt.SeriesID,
+			RescheduledFromID:    apt.RescheduledFromID,
+			Date:                 apt.Date.Format("2006-01-02"),
+			StartTime:            apt.StartTime,
+			EndTime:              apt.EndTime,
+			Duration:             apt.Duration,
+			Status:               string(apt.Status),
+			Type:                 apt.Type,
+			Notes:                apt.Notes,
+			ConfirmationRequired: apt.ConfirmationRequired,
+			DepositRequired:      apt.DepositRequired,
+			DepositPaidAt:        formatOptionalTime(apt.DepositPaidAt),
+			WalkIn:               apt.WalkIn,
+			CreatedAt:            apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:            apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}),
 		Message: "Appointment created successfully",
 	}
 
@@ -266,31 +317,34 @@ func (h *AppointmentHandler) UpdateAppointment(c *fiber.Ctx) error {
 	apt, err := h.appointmentService.UpdateAppointment(c, appointmentID, &req)
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to update appointment", "error", err, "appointmentID", appointmentID)
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "Failed to update appointment",
-			Message: err.Error(),
-		})
+		return respondAppointmentChangeError(c, err, "Failed to update appointment")
 	}
 
 	response := dto.SuccessResponse{
 		Success: true,
 		Data: dto.AppointmentResponse{
-			ID:             apt.ID,
-			PatientID:      apt.PatientID,
-			PatientName:    "", // TODO: Fetch from patient entity
-			DoctorID:       apt.DoctorID,
-			DoctorName:     "", // TODO: Fetch from doctor entity
-			OrganizationID: apt.OrganizationID,
-			RoomID:         apt.RoomID,
-			Date:           apt.Date.Format("2006-01-02"),
-			StartTime:      apt.StartTime,
-			EndTime:        apt.EndTime,
-			Duration:       apt.Duration,
-			Status:         string(apt.Status),
-			Type:           apt.Type,
-			Notes:          apt.Notes,
-			CreatedAt:      apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:      apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:                   apt.ID,
+			PatientID:            apt.PatientID,
+			PatientName:          "", // TODO: Fetch from patient entity
+			DoctorID:             apt.DoctorID,
+			DoctorName:           "", // TODO: Fetch from doctor entity
+			OrganizationID:       apt.OrganizationID,
+			RoomID:               apt.RoomID,
+			SeriesID:             apt.SeriesID,
+			RescheduledFromID:    apt.RescheduledFromID,
+			Date:                 apt.Date.Format("2006-01-02"),
+			StartTime:            apt.StartTime,
+			EndTime:              apt.EndTime,
+			Duration:             apt.Duration,
+			Status:               string(apt.Status),
+			Type:                 apt.Type,
+			Notes:                apt.Notes,
+			ConfirmationRequired: apt.ConfirmationRequired,
+			DepositRequired:      apt.DepositRequired,
+			DepositPaidAt:        formatOptionalTime(apt.DepositPaidAt),
+			WalkIn:               apt.WalkIn,
+			CreatedAt:            apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:            apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		},
 		Message: "Appointment updated successfully",
 	}
@@ -298,6 +352,44 @@ func (h *AppointmentHandler) UpdateAppointment(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// POST /api/v1/appointments/:id/reschedule
+func (h *AppointmentHandler) RescheduleAppointment(c *fiber.Ctx) error {
+	appointmentID := c.Params("id")
+	if appointmentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Appointment ID is required",
+			Message: "Please provide a valid appointment ID",
+		})
+	}
+
+	var req dto.RescheduleAppointmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	apt, err := h.appointmentService.RescheduleAppointment(c, appointmentID, &req)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to reschedule appointment", "error", err, "appointmentID", appointmentID)
+		return respondAppointmentChangeError(c, err, "Failed to reschedule appointment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toAppointmentResponses([]*appointment.Appointment{apt})[0],
+		Message: "Appointment rescheduled successfully",
+	})
+}
+
 // DELETE /api/v1/appointments/:id
 func (h *AppointmentHandler) DeleteAppointment(c *fiber.Ctx) error {
 	appointmentID := c.Params("id")
@@ -347,9 +439,14 @@ func (h *AppointmentHandler) UpdateAppointmentStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	apt, err := h.appointmentService.UpdateAppointmentStatus(c, appointmentID, req.Status)
+	apt, err := h.appointmentService.UpdateAppointmentStatus(c, appointmentID, req.Status, req.Reason)
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to update appointment status", "error", err, "appointmentID", appointmentID)
+
+		if status, body, ok := statusTransitionError(err); ok {
+			return c.Status(status).JSON(body)
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error:   "Failed to update appointment status",
 			Message: err.Error(),
@@ -359,22 +456,28 @@ func (h *AppointmentHandler) UpdateAppointmentStatus(c *fiber.Ctx) error {
 	response := dto.SuccessResponse{
 		Success: true,
 		Data: dto.AppointmentResponse{
-			ID:             apt.ID,
-			PatientID:      apt.PatientID,
-			PatientName:    "", // TODO: Fetch from patient entity
-			DoctorID:       apt.DoctorID,
-			DoctorName:     "", // TODO: Fetch from doctor entity
-			OrganizationID: apt.OrganizationID,
-			RoomID:         apt.RoomID,
-			Date:           apt.Date.Format("2006-01-02"),
-			StartTime:      apt.StartTime,
-			EndTime:        apt.EndTime,
-			Duration:       apt.Duration,
-			Status:         string(apt.Status),
-			Type:           apt.Type,
-			Notes:          apt.Notes,
-			CreatedAt:      apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:      apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:                   apt.ID,
+			PatientID:            apt.PatientID,
+			PatientName:          "", // TODO: Fetch from patient entity
+			DoctorID:             apt.DoctorID,
+			DoctorName:           "", // TODO: Fetch from doctor entity
+			OrganizationID:       apt.OrganizationID,
+			RoomID:               apt.RoomID,
+			SeriesID:             apt.SeriesID,
+			RescheduledFromID:    apt.RescheduledFromID,
+			Date:                 apt.Date.Format("2006-01-02"),
+			StartTime:            apt.StartTime,
+			EndTime:              apt.EndTime,
+			Duration:             apt.Duration,
+			Status:               string(apt.Status),
+			Type:                 apt.Type,
+			Notes:                apt.Notes,
+			ConfirmationRequired: apt.ConfirmationRequired,
+			DepositRequired:      apt.DepositRequired,
+			DepositPaidAt:        formatOptionalTime(apt.DepositPaidAt),
+			WalkIn:               apt.WalkIn,
+			CreatedAt:            apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:            apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		},
 		Message: "Appointment status updated successfully",
 	}
@@ -382,7 +485,175 @@ func (h *AppointmentHandler) UpdateAppointmentStatus(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// GET /api/v1/appointments/:id/history
+func (h *AppointmentHandler) GetAppointmentHistory(c *fiber.Ctx) error {
+	appointmentID := c.Params("id")
+	if appointmentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Appointment ID is required",
+			Message: "Please provide a valid appointment ID",
+		})
+	}
+
+	history, err := h.appointmentService.GetAppointmentHistory(c, appointmentID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get appointment history", "error", err, "appointmentID", appointmentID)
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Appointment not found",
+			Message: err.Error(),
+		})
+	}
+
+	entries := make([]dto.AppointmentStatusChangeResponse, len(history))
+	for i, change := range history {
+		entries[i] = dto.AppointmentStatusChangeResponse{
+			ID:         change.ID,
+			FromStatus: string(change.FromStatus),
+			ToStatus:   string(change.ToStatus),
+			ActorID:    change.ActorID,
+			Reason:     change.Reason,
+			ChangedAt:  change.ChangedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    entries,
+		Message: "Appointment history retrieved successfully",
+	})
+}
+
+// POST /api/v1/appointments/:id/deposit
+func (h *AppointmentHandler) RecordDeposit(c *fiber.Ctx) error {
+	appointmentID := c.Params("id")
+	if err := h.validator.Var(appointmentID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid appointment ID",
+			Message: "Please provide a valid appointment ID",
+		})
+	}
+
+	apt, err := h.appointmentService.RecordDeposit(c, appointmentID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to record appointment deposit", "error", err, "appointmentID", appointmentID)
+		return respondAppointmentChangeError(c, err, "Failed to record appointment deposit")
+	}
+
+	response := dto.SuccessResponse{
+		Success: true,
+		Data:    toAppointmentResponses([]*appointment.Appointment{apt})[0],
+		Message: "Appointment deposit recorded successfully",
+	}
+
+	return c.JSON(response)
+}
+
 // Helper functions
+
+// withPatientCheckInCode adds the appointment's check-in code to resp when the caller
+// is its patient. Nobody else sees it, as the code alone checks the patient in.
+func withPatientCheckInCode(c *fiber.Ctx, apt *appointment.Appointment, resp dto.AppointmentResponse) dto.AppointmentResponse {
+	if userID, _ := c.Locals("user_id").(string); userID != "" && userID == apt.PatientID {
+		resp.CheckInCode = apt.CheckInCode
+	}
+	return resp
+}
+
+// statusTransitionError maps lifecycle violations to 422 and lost races to 409.
+// It returns false when err is not a status transition error.
+func statusTransitionError(err error) (int, dto.ErrorResponse, bool) {
+	var transitionErr *appointment.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		return fiber.StatusUnprocessableEntity, dto.ErrorResponse{Error: "Invalid status transition", Message: transitionErr.Error()}, true
+	}
+	if errors.Is(err, appointment.ErrStatusChanged) {
+		return fiber.StatusConflict, dto.ErrorResponse{Error: "Appointment status changed", Message: err.Error()}, true
+	}
+	if errors.Is(err, appointment.ErrDepositRequired) || errors.Is(err, appointment.ErrDepositNotRequired) {
+		return fiber.StatusUnprocessableEntity, dto.ErrorResponse{Error: "Deposit policy violation", Message: err.Error()}, true
+	}
+	return 0, dto.ErrorResponse{}, false
+}
+
+// formatOptionalTime formats t for API responses, keeping nil as nil
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z07:00")
+	return &formatted
+}
+
+// roomBookingError maps an unknown room to 400 and a room that cannot take the
+// appointment to 422. It returns false when err is not a room error.
+func roomBookingError(err error) (int, dto.ErrorResponse, bool) {
+	if errors.Is(err, room.ErrRoomNotFound) {
+		return fiber.StatusBadRequest, dto.ErrorResponse{Error: "Invalid room", Message: err.Error()}, true
+	}
+
+	if errors.Is(err, room.ErrRoomUnavailable) || errors.Is(err, room.ErrRoomNotBookable) || errors.Is(err, room.ErrRoomOtherOrg) {
+		return fiber.StatusUnprocessableEntity, dto.ErrorResponse{Error: "Room cannot be booked", Message: err.Error()}, true
+	}
+
+	return 0, dto.ErrorResponse{}, false
+}
+
+// respondAppointmentChangeError maps errors from editing or rescheduling an appointment
+func respondAppointmentChangeError(c *fiber.Ctx, err error, message string) error {
+	var conflictErr *appointment.ConflictError
+	if errors.As(err, &conflictErr) {
+		return respondAppointmentConflict(c, conflictErr)
+	}
+
+	if errors.Is(err, appointment.ErrAppointmentClosed) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	if status, body, ok := statusTransitionError(err); ok {
+		return c.Status(status).JSON(body)
+	}
+
+	if status, body, ok := roomBookingError(err); ok {
+		return c.Status(status).JSON(body)
+	}
+
+	if errors.Is(err, appointment.ErrInvalidTimeRange) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func respondAppointmentConflict(c *fiber.Ctx, conflictErr *appointment.ConflictError) error {
+	conflicts := make([]dto.AppointmentConflict, len(conflictErr.Conflicts))
+	for i, conflict := range conflictErr.Conflicts {
+		conflicts[i] = dto.AppointmentConflict{
+			AppointmentID: conflict.AppointmentID,
+			Resource:      string(conflict.Resource),
+			Date:          conflict.Date.Format("2006-01-02"),
+			StartTime:     conflict.StartTime,
+			EndTime:       conflict.EndTime,
+			Status:        string(conflict.Status),
+		}
+	}
+
+	return c.Status(fiber.StatusConflict).JSON(dto.AppointmentConflictResponse{
+		Success:   false,
+		Error:     "Appointment conflict",
+		Message:   conflictErr.Error(),
+		Conflicts: conflicts,
+	})
+}
+
 func countAppointmentsByStatus(appointments []*appointment.Appointment, status string) int {
 	count := 0
 	for _, apt := range appointments {
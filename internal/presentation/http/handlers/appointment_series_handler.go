@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/presentation/http/dto"
+)
+
+// GET /api/v1/appointments/series/:seriesId
+func (h *AppointmentHandler) GetAppointmentSeries(c *fiber.Ctx) error {
+	seriesID := c.Params("seriesId")
+	if err := h.validator.Var(seriesID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid series ID",
+			Message: "Series ID must be a valid UUID",
+		})
+	}
+
+	series, occurrences, err := h.appointmentService.GetAppointmentSeries(c, seriesID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get appointment series", "error", err, "seriesID", seriesID)
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Appointment series not found",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data: dto.AppointmentSeriesData{
+			Series:       toSeriesResponse(series),
+			Appointments: toAppointmentResponses(occurrences),
+		},
+		Message: "Appointment series retrieved successfully",
+	})
+}
+
+// PUT /api/v1/appointments/:id/series
+func (h *AppointmentHandler) UpdateAppointmentSeries(c *fiber.Ctx) error {
+	appointmentID := c.Params("id")
+	if appointmentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Appointment ID is required",
+			Message: "Please provide a valid appointment ID",
+		})
+	}
+
+	var req dto.UpdateSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	scope, err := appointment.ParseSeriesScope(req.Scope)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid scope",
+			Message: err.Error(),
+		})
+	}
+
+	updated, err := h.appointmentService.UpdateSeriesOccurrences(c, appointmentID, scope, &req.UpdateAppointmentRequest)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to update appointment series", "error", err, "appointmentID", appointmentID)
+		return h.respondSeriesError(c, err, "Failed to update appointment series")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toAppointmentResponses(updated),
+		Message: "Appointment series updated successfully",
+	})
+}
+
+// POST /api/v1/appointments/:id/series/cancel
+func (h *AppointmentHandler) CancelAppointmentSeries(c *fiber.Ctx) error {
+	appointmentID := c.Params("id")
+	if appointmentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Appointment ID is required",
+			Message: "Please provide a valid appointment ID",
+		})
+	}
+
+	var req dto.CancelSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	scope, err := appointment.ParseSeriesScope(req.Scope)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid scope",
+			Message: err.Error(),
+		})
+	}
+
+	cancelled, err := h.appointmentService.CancelSeriesOccurrences(c, appointmentID, scope, req.Reason)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to cancel appointment series", "error", err, "appointmentID", appointmentID)
+		return h.respondSeriesError(c, err, "Failed to cancel appointment series")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toAppointmentResponses(cancelled),
+		Message: "Appointment series cancelled successfully",
+	})
+}
+
+// createAppointmentSeries handles POST /api/v1/appointments when a recurrence rule is present
+func (h *AppointmentHandler) createAppointmentSeries(c *fiber.Ctx, req *dto.CreateAppointmentRequest) error {
+	series, created, skipped, err := h.appointmentService.CreateAppointmentSeries(c, req)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to create appointment series", "error", err)
+		return h.respondSeriesError(c, err, "Failed to create appointment series")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data: dto.AppointmentSeriesData{
+			Series:             toSeriesResponse(series),
+			Appointments:       toAppointmentResponses(created),
+			SkippedOccurrences: toOccurrenceConflicts(skipped),
+		},
+		Message: "Appointment series created successfully",
+	})
+}
+
+func (h *AppointmentHandler) respondSeriesError(c *fiber.Ctx, err error, message string) error {
+	var seriesErr *appointment.SeriesConflictError
+	if errors.As(err, &seriesErr) {
+		return c.Status(fiber.StatusConflict).JSON(dto.SeriesConflictResponse{
+			Success:     false,
+			Error:       "Appointment series conflict",
+			Message:     seriesErr.Error(),
+			Occurrences: toOccurrenceConflicts(seriesErr.Occurrences),
+		})
+	}
+
+	var conflictErr *appointment.ConflictError
+	if errors.As(err, &conflictErr) {
+		return respondAppointmentConflict(c, conflictErr)
+	}
+
+	if status, body, ok := statusTransitionError(err); ok {
+		return c.Status(status).JSON(body)
+	}
+
+	if status, body, ok := roomBookingError(err); ok {
+		return c.Status(status).JSON(body)
+	}
+
+	for _, target := range []error{
+		appointment.ErrInvalidTimeRange,
+		appointment.ErrInvalidFrequency,
+		appointment.ErrInvalidInterval,
+		appointment.ErrMissingRecurrenceEnd,
+		appointment.ErrUntilBeforeStart,
+		appointment.ErrTooManyOccurrences,
+		appointment.ErrNotInSeries,
+		appointment.ErrSeriesDateChange,
+	} {
+		if errors.Is(err, target) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   message,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func toAppointmentResponses(appointments []*appointment.Appointment) []dto.AppointmentResponse {
+	responses := make([]dto.AppointmentResponse, len(appointments))
+	for i, apt := range appointments {
+		responses[i] = dto.AppointmentResponse{
+			ID:                   apt.ID,
+			PatientID:            apt.PatientID,
+			DoctorID:             apt.DoctorID,
+			OrganizationID:       apt.OrganizationID,
+			RoomID:               apt.RoomID,
+			SeriesID:             apt.SeriesID,
+			RescheduledFromID:    apt.RescheduledFromID,
+			Date:                 apt.Date.Format("2006-01-02"),
+			StartTime:            apt.StartTime,
+			EndTime:              apt.EndTime,
+			Duration:             apt.Duration,
+			Status:               string(apt.Status),
+			Type:                 apt.Type,
+			Notes:                apt.Notes,
+			ConfirmationRequired: apt.ConfirmationRequired,
+			DepositRequired:      apt.DepositRequired,
+			DepositPaidAt:        formatOptionalTime(apt.DepositPaidAt),
+			WalkIn:               apt.WalkIn,
+			CreatedAt:            apt.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:            apt.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+	return responses
+}
+
+func toSeriesResponse(series *appointment.Series) dto.AppointmentSeriesResponse {
+	var until *string
+	if series.Rule.Until != nil {
+		formatted := series.Rule.Until.Format("2006-01-02")
+		until = &formatted
+	}
+
+	return dto.AppointmentSeriesResponse{
+		ID:             series.ID,
+		OrganizationID: series.OrganizationID,
+		PatientID:      series.PatientID,
+		DoctorID:       series.DoctorID,
+		Frequency:      string(series.Rule.Frequency),
+		Interval:       series.Rule.Interval,
+		Count:          series.Rule.Count,
+		Until:          until,
+		RRule:          series.Rule.String(),
+		StartDate:      series.StartDate.Format("2006-01-02"),
+		CreatedAt:      series.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      series.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func toOccurrenceConflicts(occurrences []appointment.OccurrenceConflict) []dto.SeriesOccurrenceConflict {
+	result := make([]dto.SeriesOccurrenceConflict, len(occurrences))
+	for i, occurrence := range occurrences {
+		conflicts := make([]dto.AppointmentConflict, len(occurrence.Conflicts))
+		for j, conflict := range occurrence.Conflicts {
+			conflicts[j] = dto.AppointmentConflict{
+				AppointmentID: conflict.AppointmentID,
+				Resource:      string(conflict.Resource),
+				Date:          conflict.Date.Format("2006-01-02"),
+				StartTime:     conflict.StartTime,
+				EndTime:       conflict.EndTime,
+				Status:        string(conflict.Status),
+			}
+		}
+		result[i] = dto.SeriesOccurrenceConflict{
+			Date:      occurrence.Date.Format("2006-01-02"),
+			StartTime: occurrence.StartTime,
+			EndTime:   occurrence.EndTime,
+			Conflicts: conflicts,
+		}
+	}
+	return result
+}
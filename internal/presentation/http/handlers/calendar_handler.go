@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	calendarApp "medika-backend/internal/application/calendar"
+	"medika-backend/internal/domain/calendar"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/ical"
+	"medika-backend/pkg/logger"
+)
+
+type CalendarHandler struct {
+	calendarService CalendarService
+	validator       *validator.Validate
+	logger          logger.Logger
+}
+
+// CalendarService interface for dependency injection
+type CalendarService interface {
+	CreateFeedToken(ctx context.Context, caller calendarApp.Caller, ownerType calendar.OwnerType, ownerID string, label *string) (*calendar.FeedToken, string, error)
+	GetFeedTokens(ctx context.Context, caller calendarApp.Caller, ownerType calendar.OwnerType, ownerID string) ([]*calendar.FeedToken, error)
+	RevokeFeedToken(ctx context.Context, caller calendarApp.Caller, id string) error
+	GetFeed(ctx context.Context, secret string) (*ical.Calendar, error)
+	GetAppointmentCalendar(ctx context.Context, id string) (*ical.Calendar, error)
+}
+
+func NewCalendarHandler(
+	calendarService CalendarService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// POST /api/v1/doctors/:id/calendar-feeds
+func (h *CalendarHandler) CreateDoctorFeed(c *fiber.Ctx) error {
+	return h.createFeed(c, calendar.OwnerTypeDoctor)
+}
+
+// POST /api/v1/patients/:id/calendar-feeds
+func (h *CalendarHandler) CreatePatientFeed(c *fiber.Ctx) error {
+	return h.createFeed(c, calendar.OwnerTypePatient)
+}
+
+// GET /api/v1/doctors/:id/calendar-feeds
+func (h *CalendarHandler) GetDoctorFeeds(c *fiber.Ctx) error {
+	return h.getFeeds(c, calendar.OwnerTypeDoctor)
+}
+
+// GET /api/v1/patients/:id/calendar-feeds
+func (h *CalendarHandler) GetPatientFeeds(c *fiber.Ctx) error {
+	return h.getFeeds(c, calendar.OwnerTypePatient)
+}
+
+func (h *CalendarHandler) createFeed(c *fiber.Ctx, ownerType calendar.OwnerType) error {
+	ownerID := c.Params("id")
+	if err := h.validator.Var(ownerID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   fmt.Sprintf("Invalid %s ID", ownerType),
+			Message: "ID must be a valid UUID",
+		})
+	}
+
+	var req dto.CreateCalendarFeedRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid JSON format",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	token, secret, err := h.calendarService.CreateFeedToken(c.Context(), feedCaller(c), ownerType, ownerID, req.Label)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to create calendar feed", "error", err, "ownerType", ownerType, "ownerID", ownerID)
+		return h.respondCalendarError(c, err, "Failed to create calendar feed")
+	}
+
+	response := toCalendarFeedResponse(token)
+	response.Token = secret
+	response.URL = c.BaseURL() + "/api/v1/calendar/feeds/" + secret + ".ics"
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Calendar feed created successfully. Store the URL now; it is not shown again.",
+	})
+}
+
+func (h *CalendarHandler) getFeeds(c *fiber.Ctx, ownerType calendar.OwnerType) error {
+	ownerID := c.Params("id")
+	if err := h.validator.Var(ownerID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   fmt.Sprintf("Invalid %s ID", ownerType),
+			Message: "ID must be a valid UUID",
+		})
+	}
+
+	tokens, err := h.calendarService.GetFeedTokens(c.Context(), feedCaller(c), ownerType, ownerID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get calendar feeds", "error", err, "ownerType", ownerType, "ownerID", ownerID)
+		return h.respondCalendarError(c, err, "Failed to get calendar feeds")
+	}
+
+	responses := make([]dto.CalendarFeedResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = toCalendarFeedResponse(token)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    responses,
+		Message: "Calendar feeds retrieved successfully",
+	})
+}
+
+// DELETE /api/v1/calendar/feeds/:id
+func (h *CalendarHandler) RevokeFeed(c *fiber.Ctx) error {
+	feedID := c.Params("id")
+	if err := h.validator.Var(feedID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid calendar feed ID",
+			Message: "Calendar feed ID must be a valid UUID",
+		})
+	}
+
+	if err := h.calendarService.RevokeFeedToken(c.Context(), feedCaller(c), feedID); err != nil {
+		h.logger.Error(c.Context(), "Failed to revoke calendar feed", "error", err, "feedID", feedID)
+		return h.respondCalendarError(c, err, "Failed to revoke calendar feed")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Calendar feed revoked successfully",
+	})
+}
+
+// GET /api/v1/calendar/feeds/:token.ics
+// The token in the URL is the only credential, since calendar apps cannot send
+// an Authorization header.
+func (h *CalendarHandler) GetFeed(c *fiber.Ctx) error {
+	secret := c.Params("token")
+	if err := h.validator.Var(secret, "hexadecimal"); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Calendar feed not found",
+			Message: calendar.ErrFeedNotFound.Error(),
+		})
+	}
+
+	cal, err := h.calendarService.GetFeed(c.Context(), secret)
+	if err != nil {
+		if !errors.Is(err, calendar.ErrFeedNotFound) {
+			h.logger.Error(c.Context(), "Failed to render calendar feed", "error", err)
+		}
+		return h.respondCalendarError(c, err, "Failed to render calendar feed")
+	}
+
+	c.Set(fiber.HeaderContentType, ical.MIMEType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(cal.Bytes())
+}
+
+// GET /api/v1/appointments/:id/ics
+func (h *CalendarHandler) GetAppointmentICS(c *fiber.Ctx) error {
+	appointmentID := c.Params("id")
+	if err := h.validator.Var(appointmentID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid appointment ID",
+			Message: "Appointment ID must be a valid UUID",
+		})
+	}
+
+	cal, err := h.calendarService.GetAppointmentCalendar(c.Context(), appointmentID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to export appointment", "error", err, "appointmentID", appointmentID)
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Appointment not found",
+			Message: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, ical.MIMEType)
+	c.Attachment("appointment-" + appointmentID + ".ics")
+	return c.Send(cal.Bytes())
+}
+
+func (h *CalendarHandler) respondCalendarError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, calendar.ErrFeedNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Calendar feed not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, calendar.ErrInvalidOwner):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, calendar.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, calendar.ErrAlreadyRevoked):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+// feedCaller returns the authenticated user managing feeds
+func feedCaller(c *fiber.Ctx) calendarApp.Caller {
+	userID, _ := c.Locals("user_id").(string)
+	role, _ := c.Locals("role").(string)
+	organizationID, _ := c.Locals("organization_id").(string)
+	return calendarApp.Caller{
+		UserID:         userID,
+		Role:           role,
+		OrganizationID: organizationID,
+	}
+}
+
+func toCalendarFeedResponse(token *calendar.FeedToken) dto.CalendarFeedResponse {
+	response := dto.CalendarFeedResponse{
+		ID:        token.ID,
+		OwnerType: string(token.OwnerType),
+		OwnerID:   token.OwnerID,
+		Label:     token.Label,
+		CreatedAt: token.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if token.LastUsedAt != nil {
+		lastUsedAt := token.LastUsedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.LastUsedAt = &lastUsedAt
+	}
+	if token.RevokedAt != nil {
+		revokedAt := token.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.RevokedAt = &revokedAt
+	}
+
+	return response
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/checkin"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type CheckInHandler struct {
+	checkInService CheckInService
+	validator      *validator.Validate
+	logger         logger.Logger
+}
+
+// CheckInService interface for dependency injection
+type CheckInService interface {
+	CheckIn(ctx context.Context, organizationID, appointmentID, code string, triageLevel queue.TriageLevel, actorID *string) (*checkin.Result, error)
+	RegisterWalkIn(ctx context.Context, apt *appointment.Appointment, triageLevel queue.TriageLevel, actorID *string) (*checkin.Result, error)
+}
+
+func NewCheckInHandler(
+	checkInService CheckInService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *CheckInHandler {
+	return &CheckInHandler{
+		checkInService: checkInService,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+// POST /api/v1/check-in
+// Public, for kiosks: the code is all a patient can check in with, and they only learn
+// their ticket and place in the queue
+func (h *CheckInHandler) CheckInByCode(c *fiber.Ctx) error {
+	var req dto.KioskCheckInRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	result, err := h.checkInService.CheckIn(c.Context(), "", "", req.Code, 0, nil)
+	if err != nil {
+		return h.respondCheckInError(c, err, "Failed to check in")
+	}
+
+	return c.JSON(dto.KioskCheckInResponse{
+		Success: true,
+		Data: dto.KioskCheckInData{
+			TicketNumber: result.Entry.TicketNumber,
+			Position:     result.Entry.Position,
+		},
+		Message: "Checked in successfully",
+	})
+}
+
+// POST /api/v1/check-in/staff
+func (h *CheckInHandler) CheckIn(c *fiber.Ctx) error {
+	var req dto.CheckInRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	organizationID, _ := c.Locals("organization_id").(string)
+	result, err := h.checkInService.CheckIn(c.Context(), organizationID, req.AppointmentID, req.Code, queue.TriageLevel(req.TriageLevel), actorFromLocals(c))
+	if err != nil {
+		return h.respondCheckInError(c, err, "Failed to check in")
+	}
+
+	return c.JSON(dto.CheckInResponse{
+		Success: true,
+		Data:    toCheckInData(result),
+		Message: "Checked in successfully",
+	})
+}
+
+// POST /api/v1/check-in/walk-in
+func (h *CheckInHandler) RegisterWalkIn(c *fiber.Ctx) error {
+	var req dto.WalkInRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	// Walk-ins are booked in the organization of the staff member registering them
+	organizationID, _ := c.Locals("organization_id").(string)
+	apt := &appointment.Appointment{
+		PatientID:      req.PatientID,
+		DoctorID:       req.DoctorID,
+		OrganizationID: organizationID,
+		Duration:       req.Duration,
+		Type:           req.Type,
+		Notes:          req.Notes,
+	}
+	if apt.Type == "" {
+		apt.Type = "consultation"
+	}
+
+	result, err := h.checkInService.RegisterWalkIn(c.Context(), apt, queue.TriageLevel(req.TriageLevel), actorFromLocals(c))
+	if err != nil {
+		return h.respondCheckInError(c, err, "Failed to register walk-in")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CheckInResponse{
+		Success: true,
+		Data:    toCheckInData(result),
+		Message: "Walk-in registered successfully",
+	})
+}
+
+func (h *CheckInHandler) respondCheckInError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, checkin.ErrAppointmentNotFound), errors.Is(err, checkin.ErrCodeNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, checkin.ErrAlreadyCheckedIn):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, checkin.ErrNotToday), errors.Is(err, checkin.ErrTooEarly), errors.Is(err, checkin.ErrTooLate):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse{
+			Error:   "Outside the arrival window",
+			Message: err.Error(),
+		})
+	case errors.Is(err, checkin.ErrNoWalkInSlot):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, queue.ErrInvalidTriageLevel):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	var conflictErr *appointment.ConflictError
+	if errors.As(err, &conflictErr) {
+		return respondAppointmentConflict(c, conflictErr)
+	}
+	if status, body, ok := statusTransitionError(err); ok {
+		return c.Status(status).JSON(body)
+	}
+
+	h.logger.Error(c.Context(), message, "error", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func toCheckInData(result *checkin.Result) dto.CheckInData {
+	entry := result.Entry
+	return dto.CheckInData{
+		Appointment: toAppointmentResponses([]*appointment.Appointment{result.Appointment})[0],
+		Queue: dto.QueueResponse{
+			ID:                entry.ID,
+			AppointmentID:     entry.AppointmentID,
+			OrganizationID:    entry.OrganizationID,
+			ScopeType:         string(entry.ScopeType),
+			ScopeID:           entry.ScopeID,
+			TriageLevel:       int(entry.TriageLevel),
+			TicketNumber:      entry.TicketNumber,
+			Position:          entry.Position,
+			EstimatedWaitTime: entry.EstimatedWaitTime,
+			EstimatedWaitLow:  entry.EstimatedWaitLow,
+			EstimatedWaitHigh: entry.EstimatedWaitHigh,
+			Status:            entry.Status,
+			CalledAt:          entry.CalledAt,
+			StartedAt:         entry.StartedAt,
+			CompletedAt:       entry.CompletedAt,
+			CarriedOverTo:     entry.CarriedOverTo,
+			CreatedAt:         entry.CreatedAt,
+			UpdatedAt:         entry.UpdatedAt,
+		},
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"medika-backend/internal/domain/display"
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type DisplayHandler struct {
+	displayService DisplayService
+	validator      *validator.Validate
+	logger         logger.Logger
+}
+
+// DisplayService interface for dependency injection
+type DisplayService interface {
+	CreateToken(ctx context.Context, organizationID string, scope *queue.Scope, label *string) (*display.Token, string, error)
+	GetTokens(ctx context.Context, organizationID string) ([]*display.Token, error)
+	RevokeToken(ctx context.Context, organizationID, id string) error
+	GetBoard(ctx context.Context, secret string) (*display.Board, error)
+}
+
+func NewDisplayHandler(
+	displayService DisplayService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *DisplayHandler {
+	return &DisplayHandler{
+		displayService: displayService,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+// POST /api/v1/organizations/:id/display-tokens
+func (h *DisplayHandler) CreateToken(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Organization ID must be a valid UUID",
+		})
+	}
+
+	var req dto.CreateDisplayTokenRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid JSON format",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	var scope *queue.Scope
+	if req.ScopeType != "" || req.ScopeID != "" {
+		scope = &queue.Scope{Type: queue.ScopeType(req.ScopeType), ID: req.ScopeID}
+	}
+
+	token, secret, err := h.displayService.CreateToken(c.Context(), organizationID, scope, req.Label)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to create display token", "error", err, "organizationID", organizationID)
+		return h.respondDisplayError(c, err, "Failed to create display token")
+	}
+
+	response := toDisplayTokenResponse(token)
+	response.Token = secret
+	response.URL = c.BaseURL() + "/api/v1/display/" + secret
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Display token created successfully. Store the URL now; it is not shown again.",
+	})
+}
+
+// GET /api/v1/organizations/:id/display-tokens
+func (h *DisplayHandler) GetTokens(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Organization ID must be a valid UUID",
+		})
+	}
+
+	tokens, err := h.displayService.GetTokens(c.Context(), organizationID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get display tokens", "error", err, "organizationID", organizationID)
+		return h.respondDisplayError(c, err, "Failed to get display tokens")
+	}
+
+	responses := make([]dto.DisplayTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = toDisplayTokenResponse(token)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    responses,
+		Message: "Display tokens retrieved successfully",
+	})
+}
+
+// DELETE /api/v1/display/tokens/:id
+func (h *DisplayHandler) RevokeToken(c *fiber.Ctx) error {
+	tokenID := c.Params("id")
+	if err := h.validator.Var(tokenID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid display token ID",
+			Message: "Display token ID must be a valid UUID",
+		})
+	}
+
+	organizationID, _ := c.Locals("organization_id").(string)
+	if err := h.displayService.RevokeToken(c.Context(), organizationID, tokenID); err != nil {
+		h.logger.Error(c.Context(), "Failed to revoke display token", "error", err, "tokenID", tokenID)
+		return h.respondDisplayError(c, err, "Failed to revoke display token")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Display token revoked successfully",
+	})
+}
+
+// GET /api/v1/display/:token
+// The token in the URL is the only credential, so the board carries ticket numbers
+// and room names only, never patient details.
+func (h *DisplayHandler) GetBoard(c *fiber.Ctx) error {
+	secret := c.Params("token")
+	if err := h.validator.Var(secret, "hexadecimal"); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Display not found",
+			Message: display.ErrTokenNotFound.Error(),
+		})
+	}
+
+	board, err := h.displayService.GetBoard(c.Context(), secret)
+	if err != nil {
+		if !errors.Is(err, display.ErrTokenNotFound) {
+			h.logger.Error(c.Context(), "Failed to build display board", "error", err)
+		}
+		return h.respondDisplayError(c, err, "Failed to build display board")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data: dto.DisplayBoardResponse{
+			NowServing:  toDisplayBoardEntries(board.NowServing),
+			NextUp:      toDisplayBoardEntries(board.NextUp),
+			GeneratedAt: board.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		},
+		Message: "Display board retrieved successfully",
+	})
+}
+
+func (h *DisplayHandler) respondDisplayError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, display.ErrTokenNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Display not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, display.ErrOrganizationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, queue.ErrInvalidScope):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, display.ErrAlreadyRevoked):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func toDisplayTokenResponse(token *display.Token) dto.DisplayTokenResponse {
+	response := dto.DisplayTokenResponse{
+		ID:             token.ID,
+		OrganizationID: token.OrganizationID,
+		Label:          token.Label,
+		CreatedAt:      token.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if token.Scope != nil {
+		scopeType := string(token.Scope.Type)
+		response.ScopeType = &scopeType
+		response.ScopeID = &token.Scope.ID
+	}
+	if token.LastUsedAt != nil {
+		lastUsedAt := token.LastUsedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.LastUsedAt = &lastUsedAt
+	}
+	if token.RevokedAt != nil {
+		revokedAt := token.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.RevokedAt = &revokedAt
+	}
+
+	return response
+}
+
+func toDisplayBoardEntries(entries []display.BoardEntry) []dto.DisplayBoardEntryResponse {
+	responses := make([]dto.DisplayBoardEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = dto.DisplayBoardEntryResponse{
+			TicketNumber: entry.TicketNumber,
+			RoomName:     entry.RoomName,
+			Status:       entry.Status,
+		}
+	}
+	return responses
+}
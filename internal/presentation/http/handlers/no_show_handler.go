@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	appnoshow "medika-backend/internal/application/noshow"
+	"medika-backend/internal/domain/noshow"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type NoShowHandler struct {
+	noShowService NoShowService
+	validator     *validator.Validate
+	logger        logger.Logger
+}
+
+// NoShowService interface for dependency injection
+type NoShowService interface {
+	GetPolicy(ctx context.Context, organizationID string) (*noshow.Policy, error)
+	UpdatePolicy(ctx context.Context, policy *noshow.Policy) error
+	GetPatientRecord(ctx context.Context, organizationID, patientID string) (*noshow.PatientRecord, noshow.Requirement, error)
+}
+
+func NewNoShowHandler(
+	noShowService NoShowService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *NoShowHandler {
+	return &NoShowHandler{
+		noShowService: noShowService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// GET /api/v1/organizations/:id/no-show-policy
+func (h *NoShowHandler) GetPolicy(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	policy, err := h.noShowService.GetPolicy(c.Context(), organizationID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get no-show policy", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get no-show policy",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toNoShowPolicyResponse(policy),
+		Message: "No-show policy retrieved successfully",
+	})
+}
+
+// PUT /api/v1/organizations/:id/no-show-policy
+func (h *NoShowHandler) UpdatePolicy(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	var req dto.UpdateNoShowPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	policy := &noshow.Policy{
+		OrganizationID:        organizationID,
+		AutoMark:              req.AutoMark,
+		GracePeriodMinutes:    req.GracePeriodMinutes,
+		ConfirmationThreshold: req.ConfirmationThreshold,
+		ConfirmationLeadHours: req.ConfirmationLeadHours,
+		DepositThreshold:      req.DepositThreshold,
+		DepositAmount:         req.DepositAmount,
+	}
+	if policy.ConfirmationLeadHours == 0 {
+		policy.ConfirmationLeadHours = noshow.DefaultConfirmationLeadHours
+	}
+
+	if err := h.noShowService.UpdatePolicy(c.Context(), policy); err != nil {
+		h.logger.Error(c.Context(), "Failed to update no-show policy", "error", err, "organizationID", organizationID)
+		if appnoshow.IsValidationError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid no-show policy",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to update no-show policy",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toNoShowPolicyResponse(policy),
+		Message: "No-show policy updated successfully",
+	})
+}
+
+// GET /api/v1/patients/:id/no-shows?organizationId=...
+func (h *NoShowHandler) GetPatientRecord(c *fiber.Ctx) error {
+	patientID := c.Params("id")
+	if err := h.validator.Var(patientID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Please provide a valid patient ID",
+		})
+	}
+
+	organizationID := organizationFromRequest(c)
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "organizationId must be a valid UUID",
+		})
+	}
+
+	// Patients see their own record, staff those kept by their organization
+	userID, _ := c.Locals("user_id").(string)
+	role, _ := c.Locals("role").(string)
+	userOrgID, _ := c.Locals("organization_id").(string)
+	isStaff := role == "admin" || role == "doctor" || role == "nurse"
+	if userID != patientID && (!isStaff || userOrgID != organizationID) {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   "Access denied",
+			Message: "You can only view your own no-show record or those of your organization's patients",
+		})
+	}
+
+	record, requirement, err := h.noShowService.GetPatientRecord(c.Context(), organizationID, patientID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get patient no-show record", "error", err, "patientID", patientID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get patient no-show record",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data: dto.PatientNoShowResponse{
+			OrganizationID: record.OrganizationID,
+			PatientID:      record.PatientID,
+			NoShowCount:    record.NoShowCount,
+			LastNoShowAt:   formatOptionalTime(record.LastNoShowAt),
+			Requirement:    string(requirement),
+		},
+		Message: "Patient no-show record retrieved successfully",
+	})
+}
+
+func toNoShowPolicyResponse(policy *noshow.Policy) dto.NoShowPolicyResponse {
+	return dto.NoShowPolicyResponse{
+		OrganizationID:        policy.OrganizationID,
+		AutoMark:              policy.AutoMark,
+		GracePeriodMinutes:    policy.GracePeriodMinutes,
+		ConfirmationThreshold: policy.ConfirmationThreshold,
+		ConfirmationLeadHours: policy.ConfirmationLeadHours,
+		DepositThreshold:      policy.DepositThreshold,
+		DepositAmount:         policy.DepositAmount,
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"medika-backend/internal/application/notification"
@@ -9,17 +10,20 @@ import (
 	"medika-backend/internal/presentation/http/dto"
 	"medika-backend/pkg/logger"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 type NotificationHandler struct {
 	notificationService *notification.Service
+	validator           *validator.Validate
 	logger              logger.Logger
 }
 
-func NewNotificationHandler(notificationService *notification.Service, logger logger.Logger) *NotificationHandler {
+func NewNotificationHandler(notificationService *notification.Service, validator *validator.Validate, logger logger.Logger) *NotificationHandler {
 	return &NotificationHandler{
 		notificationService: notificationService,
+		validator:           validator,
 		logger:              logger,
 	}
 }
@@ -187,6 +191,270 @@ func (h *NotificationHandler) DeleteNotification(c *fiber.Ctx) error {
 	})
 }
 
+// GetDeliveries handles GET /api/v1/notifications/:id/deliveries
+func (h *NotificationHandler) GetDeliveries(c *fiber.Ctx) error {
+	notificationID := notificationDomain.NotificationID(c.Params("id"))
+
+	deliveries, err := h.notificationService.GetDeliveries(c.Context(), notificationID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get notification deliveries", "error", err, "notification_id", notificationID.String())
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: "Failed to get notification deliveries",
+		})
+	}
+
+	deliveryDTOs := make([]dto.NotificationDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveryDTOs[i] = dto.NotificationDeliveryResponse{
+			ID:            delivery.ID,
+			Channel:       delivery.Channel,
+			Status:        string(delivery.Status),
+			Attempts:      delivery.Attempts,
+			LastError:     delivery.LastError,
+			NextAttemptAt: delivery.NextAttemptAt,
+			SentAt:        delivery.SentAt,
+			CreatedAt:     delivery.CreatedAt,
+			UpdatedAt:     delivery.UpdatedAt,
+		}
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    deliveryDTOs,
+	})
+}
+
+// GetPreferences handles GET /api/v1/notifications/preferences
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	preferences, err := h.notificationService.GetPreferences(c.Context(), userID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get notification preferences", "error", err, "user_id", userID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: "Failed to get notification preferences",
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toNotificationPreferencesResponse(preferences),
+	})
+}
+
+// UpdatePreferences handles PUT /api/v1/notifications/preferences
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req dto.NotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	preferences := &notificationDomain.Preferences{
+		UserID:   userID,
+		Timezone: req.Timezone,
+	}
+	if req.QuietHours != nil {
+		preferences.QuietHours = &notificationDomain.QuietHours{
+			Start: req.QuietHours.Start,
+			End:   req.QuietHours.End,
+		}
+	}
+	if len(req.Channels) > 0 {
+		preferences.Channels = make(map[notificationDomain.NotificationType]map[string]bool, len(req.Channels))
+		for notificationType, channels := range req.Channels {
+			preferences.Channels[notificationDomain.NotificationType(notificationType)] = channels
+		}
+	}
+
+	if err := h.notificationService.UpdatePreferences(c.Context(), preferences); err != nil {
+		if notification.IsPreferencesError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid notification preferences",
+				Message: err.Error(),
+			})
+		}
+		h.logger.Error(c.Context(), "Failed to update notification preferences", "error", err, "user_id", userID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: "Failed to update notification preferences",
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toNotificationPreferencesResponse(preferences),
+		Message: "Notification preferences updated",
+	})
+}
+
+func toNotificationPreferencesResponse(preferences *notificationDomain.Preferences) dto.NotificationPreferencesResponse {
+	resp := dto.NotificationPreferencesResponse{
+		Timezone: preferences.Timezone,
+		Channels: make(map[string]map[string]bool, len(preferences.Channels)),
+	}
+	if preferences.QuietHours != nil {
+		resp.QuietHours = &dto.QuietHoursRequest{
+			Start: preferences.QuietHours.Start,
+			End:   preferences.QuietHours.End,
+		}
+	}
+	for notificationType, channels := range preferences.Channels {
+		resp.Channels[string(notificationType)] = channels
+	}
+	if !preferences.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &preferences.UpdatedAt
+	}
+	return resp
+}
+
+// GET /api/v1/organizations/:id/notification-templates
+func (h *NotificationHandler) GetTemplates(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	templates, err := h.notificationService.GetTemplates(c.Context(), organizationID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get notification templates", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get notification templates",
+			Message: err.Error(),
+		})
+	}
+
+	templateDTOs := make([]dto.NotificationTemplateResponse, len(templates))
+	for i, tmpl := range templates {
+		templateDTOs[i] = toNotificationTemplateResponse(tmpl)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    templateDTOs,
+		Message: "Notification templates retrieved successfully",
+	})
+}
+
+// PUT /api/v1/organizations/:id/notification-templates/:key/:locale
+func (h *NotificationHandler) UpdateTemplate(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	var req dto.UpdateNotificationTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	tmpl := &notificationDomain.Template{
+		Key:            notificationDomain.TemplateKey(c.Params("key")),
+		Locale:         notificationDomain.Locale(c.Params("locale")),
+		OrganizationID: &organizationID,
+		Title:          req.Title,
+		Body:           req.Body,
+		SMSBody:        req.SMSBody,
+		EmailHTML:      req.EmailHTML,
+	}
+
+	if err := h.notificationService.SaveTemplate(c.Context(), tmpl); err != nil {
+		if notification.IsTemplateError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid notification template",
+				Message: err.Error(),
+			})
+		}
+		h.logger.Error(c.Context(), "Failed to update notification template", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to update notification template",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toNotificationTemplateResponse(tmpl),
+		Message: "Notification template updated successfully",
+	})
+}
+
+// DELETE /api/v1/organizations/:id/notification-templates/:key/:locale
+func (h *NotificationHandler) DeleteTemplate(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	key := notificationDomain.TemplateKey(c.Params("key"))
+	locale := notificationDomain.Locale(c.Params("locale"))
+
+	if err := h.notificationService.DeleteTemplate(c.Context(), organizationID, key, locale); err != nil {
+		if errors.Is(err, notificationDomain.ErrTemplateNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "Notification template not overridden",
+				Message: err.Error(),
+			})
+		}
+		h.logger.Error(c.Context(), "Failed to delete notification template", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to delete notification template",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Notification template reset to the built-in template",
+	})
+}
+
+func toNotificationTemplateResponse(tmpl *notificationDomain.Template) dto.NotificationTemplateResponse {
+	resp := dto.NotificationTemplateResponse{
+		Key:        string(tmpl.Key),
+		Locale:     string(tmpl.Locale),
+		Title:      tmpl.Title,
+		Body:       tmpl.Body,
+		SMSBody:    tmpl.SMSBody,
+		EmailHTML:  tmpl.EmailHTML,
+		Variables:  notificationDomain.TemplateVariables[tmpl.Key],
+		Overridden: tmpl.OrganizationID != nil,
+	}
+	if !tmpl.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &tmpl.UpdatedAt
+	}
+	return resp
+}
+
 // parseNotificationFilters parses query parameters into notification filters
 func (h *NotificationHandler) parseNotificationFilters(c *fiber.Ctx) notificationDomain.NotificationFilters {
 	filters := notificationDomain.NotificationFilters{}
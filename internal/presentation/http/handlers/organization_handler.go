@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -89,6 +90,7 @@ func (h *OrganizationHandler) GetOrganizations(c *fiber.Ctx) error {
 			Phone:     org.Phone,
 			Email:     org.Email,
 			Website:   org.Website,
+			Timezone:  org.Timezone,
 			Status:    getOrganizationStatus(org.IsActive),
 			StaffCount: 0, // TODO: Implement staff count calculation
 			CreatedAt: org.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -150,6 +152,7 @@ func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
 			Phone:     org.Phone,
 			Email:     org.Email,
 			Website:   org.Website,
+			Timezone:  org.Timezone,
 			Status:    getOrganizationStatus(org.IsActive),
 			StaffCount: 0, // TODO: Implement staff count calculation
 			CreatedAt: org.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -178,6 +181,12 @@ func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
 		})
 	}
 
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(invalidTimezoneResponse(req.Timezone))
+		}
+	}
+
 	org, err := h.organizationService.CreateOrganization(c, &req)
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to create organization", "error", err)
@@ -197,6 +206,7 @@ func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
 			Phone:     org.Phone,
 			Email:     org.Email,
 			Website:   org.Website,
+			Timezone:  org.Timezone,
 			Status:    getOrganizationStatus(org.IsActive),
 			StaffCount: 0, // TODO: Implement staff count calculation
 			CreatedAt: org.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -233,6 +243,12 @@ func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
 		})
 	}
 
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(invalidTimezoneResponse(*req.Timezone))
+		}
+	}
+
 	org, err := h.organizationService.UpdateOrganization(c, organizationID, &req)
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to update organization", "error", err, "organizationID", organizationID)
@@ -252,6 +268,7 @@ func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
 			Phone:     org.Phone,
 			Email:     org.Email,
 			Website:   org.Website,
+			Timezone:  org.Timezone,
 			Status:    getOrganizationStatus(org.IsActive),
 			StaffCount: 0, // TODO: Implement staff count calculation
 			CreatedAt: org.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -305,6 +322,13 @@ func getOrganizationStatus(isActive bool) string {
 	return "inactive"
 }
 
+func invalidTimezoneResponse(timezone string) dto.ErrorResponse {
+	return dto.ErrorResponse{
+		Error:   "Invalid timezone",
+		Message: "Unknown IANA time zone: " + timezone,
+	}
+}
+
 func buildTypeStats(organizations []*organization.Organization) map[string]int {
 	stats := make(map[string]int)
 	for _, org := range organizations {
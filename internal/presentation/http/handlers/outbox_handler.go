@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"medika-backend/internal/domain/outbox"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type OutboxHandler struct {
+	outboxService OutboxService
+	validator     *validator.Validate
+	logger        logger.Logger
+}
+
+// OutboxService interface for dependency injection
+type OutboxService interface {
+	GetDeadLetters(ctx context.Context, organizationID, eventType string, limit, offset int) ([]*outbox.DeadLetter, error)
+	CountDeadLetters(ctx context.Context, organizationID, eventType string) (int, error)
+	Replay(ctx context.Context, organizationID, id string) (*outbox.Message, error)
+}
+
+func NewOutboxHandler(
+	outboxService OutboxService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService: outboxService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// GET /api/v1/admin/outbox/dead-letters
+// Lists the dead letters about the caller's organization
+// Optional query parameters: event_type, limit and page
+func (h *OutboxHandler) GetDeadLetters(c *fiber.Ctx) error {
+	organizationID := c.Locals("organization_id").(string)
+	eventType := c.Query("event_type")
+
+	limit, err := strconv.Atoi(c.Query("limit", "30"))
+	if err != nil || limit <= 0 {
+		limit = 30
+	}
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	deadLetters, err := h.outboxService.GetDeadLetters(c.Context(), organizationID, eventType, limit, (page-1)*limit)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get dead letters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get dead letters",
+			Message: err.Error(),
+		})
+	}
+
+	total, err := h.outboxService.CountDeadLetters(c.Context(), organizationID, eventType)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to count dead letters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to count dead letters",
+			Message: err.Error(),
+		})
+	}
+
+	data := make([]dto.DeadLetterResponse, len(deadLetters))
+	for i, deadLetter := range deadLetters {
+		data[i] = dto.DeadLetterResponse{
+			ID:        deadLetter.ID,
+			EventType: deadLetter.EventType,
+			Payload:   deadLetter.Payload,
+			Attempts:  deadLetter.Attempts,
+			Delivered: deadLetter.Delivered,
+			LastError: deadLetter.LastError,
+			CreatedAt: deadLetter.CreatedAt,
+			FailedAt:  deadLetter.FailedAt,
+		}
+	}
+
+	return c.JSON(dto.DeadLetterListResponse{
+		Data: data,
+		Pagination: dto.Pagination{
+			Page:       page,
+			TotalPages: (total + limit - 1) / limit,
+			Total:      total,
+			Limit:      limit,
+		},
+	})
+}
+
+// POST /api/v1/admin/outbox/dead-letters/:id/replay
+// The event goes back to the outbox and is published again with fresh attempts. Only
+// dead letters about the caller's organization can be replayed.
+func (h *OutboxHandler) ReplayDeadLetter(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.validator.Var(id, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid dead letter ID",
+			Message: "Dead letter ID must be a valid UUID",
+		})
+	}
+
+	msg, err := h.outboxService.Replay(c.Context(), c.Locals("organization_id").(string), id)
+	if errors.Is(err, outbox.ErrDeadLetterNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Dead letter not found",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to replay dead letter",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data: dto.OutboxMessageResponse{
+			ID:            msg.ID,
+			EventType:     msg.EventType,
+			NextAttemptAt: msg.NextAttemptAt,
+		},
+		Message: "Dead letter queued for replay",
+	})
+}
@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
+	appqueue "medika-backend/internal/application/queue"
 	"medika-backend/internal/domain/queue"
 	"medika-backend/internal/presentation/http/dto"
 	"medika-backend/pkg/logger"
@@ -22,14 +25,30 @@ type QueueHandler struct {
 type QueueService interface {
 	GetQueuesByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*queue.PatientQueue, error)
 	CountQueuesByOrganization(ctx context.Context, organizationID string) (int, error)
-	CreateQueue(ctx context.Context, q *queue.PatientQueue) error
+	GetQueuesByScope(ctx context.Context, organizationID string, scope queue.Scope, limit, offset int) ([]*queue.PatientQueue, error)
+	CountQueuesByScope(ctx context.Context, organizationID string, scope queue.Scope) (int, error)
+	CreateQueue(ctx context.Context, q *queue.PatientQueue, actorID *string) error
 	GetQueue(ctx context.Context, id string) (*queue.PatientQueue, error)
 	GetPatientQueue(ctx context.Context, patientID string) (*queue.PatientQueueWithDetails, error)
-	UpdateQueue(ctx context.Context, q *queue.PatientQueue) error
-	DeleteQueue(ctx context.Context, id string) error
-	CallNextPatient(ctx context.Context, organizationID string) (*queue.PatientQueue, error)
-	StartPatientConsultation(ctx context.Context, queueID string) (*queue.PatientQueue, error)
-	CompletePatientConsultation(ctx context.Context, queueID string) (*queue.PatientQueue, error)
+	UpdateQueue(ctx context.Context, q *queue.PatientQueue, actorID *string) error
+	CancelQueue(ctx context.Context, id string, actorID *string) (*queue.PatientQueue, error)
+	CallNextPatient(ctx context.Context, organizationID string, scope queue.Scope, actorID *string) (*queue.PatientQueue, error)
+	StartPatientConsultation(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error)
+	CompletePatientConsultation(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error)
+	RetriageQueue(ctx context.Context, queueID string, level queue.TriageLevel, reason string, actorID *string) (*queue.PatientQueue, error)
+	GetTriageHistory(ctx context.Context, queueID string) ([]*queue.TriageChange, error)
+	GetStatusHistory(ctx context.Context, queueID string) ([]*queue.StatusChange, error)
+	SkipPatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error)
+	RecallPatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error)
+	HoldPatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error)
+	ReleasePatient(ctx context.Context, queueID string, actorID *string) (*queue.PatientQueue, error)
+	TransferPatient(ctx context.Context, queueID string, to queue.Scope, actorID *string) (*queue.PatientQueue, error)
+	GetQueueState(ctx context.Context, organizationID string, scope queue.Scope) (*queue.State, error)
+	PauseQueue(ctx context.Context, organizationID string, scope queue.Scope, reason, actorID *string) (*queue.State, error)
+	ResumeQueue(ctx context.Context, organizationID string, scope queue.Scope, actorID *string) (*queue.State, error)
+	GetClosingPolicy(ctx context.Context, organizationID string) (*queue.ClosingPolicy, error)
+	UpdateClosingPolicy(ctx context.Context, policy *queue.ClosingPolicy) error
+	GetClosures(ctx context.Context, organizationID string, limit, offset int) ([]*queue.Closure, error)
 }
 
 func NewQueueHandler(
@@ -50,6 +69,11 @@ func (h *QueueHandler) GetQueues(c *fiber.Ctx) error {
 	limitStr := c.Query("limit", "10")
 	pageStr := c.Query("page", "1")
 	organizationID := c.Query("organizationId", "")
+	scope := queue.Scope{
+		Type: queue.ScopeType(c.Query("scopeType", "")),
+		ID:   c.Query("scopeId", ""),
+	}
+	scoped := scope.Type != "" || scope.ID != ""
 	
 	// Validate organizationId if provided
 	if organizationID != "" {
@@ -60,6 +84,14 @@ func (h *QueueHandler) GetQueues(c *fiber.Ctx) error {
 			})
 		}
 	}
+
+	// A single queue is only meaningful within one organization
+	if scoped && (organizationID == "" || !scope.IsValid()) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue scope",
+			Message: "organizationId, scopeType (doctor, room or service_point) and scopeId are required to filter by queue",
+		})
+	}
 	
 	// Parse pagination parameters
 	limit, err := strconv.Atoi(limitStr)
@@ -75,7 +107,12 @@ func (h *QueueHandler) GetQueues(c *fiber.Ctx) error {
 	offset := (page - 1) * limit
 	
 	// Get queues from service
-	queues, err := h.queueService.GetQueuesByOrganization(c.Context(), organizationID, limit, offset)
+	var queues []*queue.PatientQueue
+	if scoped {
+		queues, err = h.queueService.GetQueuesByScope(c.Context(), organizationID, scope, limit, offset)
+	} else {
+		queues, err = h.queueService.GetQueuesByOrganization(c.Context(), organizationID, limit, offset)
+	}
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to get queues", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
@@ -85,7 +122,12 @@ func (h *QueueHandler) GetQueues(c *fiber.Ctx) error {
 	}
 	
 	// Get total count for pagination
-	total, err := h.queueService.CountQueuesByOrganization(c.Context(), organizationID)
+	var total int
+	if scoped {
+		total, err = h.queueService.CountQueuesByScope(c.Context(), organizationID, scope)
+	} else {
+		total, err = h.queueService.CountQueuesByOrganization(c.Context(), organizationID)
+	}
 	if err != nil {
 		h.logger.Error(c.Context(), "Failed to count queues", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
@@ -101,9 +143,19 @@ func (h *QueueHandler) GetQueues(c *fiber.Ctx) error {
 			ID:                q.ID,
 			AppointmentID:     q.AppointmentID,
 			OrganizationID:    q.OrganizationID,
+			ScopeType:         string(q.ScopeType),
+			ScopeID:           q.ScopeID,
+			TriageLevel:       int(q.TriageLevel),
+			TicketNumber:      q.TicketNumber,
 			Position:          q.Position,
 			EstimatedWaitTime: q.EstimatedWaitTime,
+			EstimatedWaitLow:  q.EstimatedWaitLow,
+			EstimatedWaitHigh: q.EstimatedWaitHigh,
 			Status:            q.Status,
+			CalledAt:          q.CalledAt,
+			StartedAt:         q.StartedAt,
+			CompletedAt:       q.CompletedAt,
+			CarriedOverTo:     q.CarriedOverTo,
 			CreatedAt:         q.CreatedAt,
 			UpdatedAt:         q.UpdatedAt,
 		}
@@ -157,9 +209,19 @@ func (h *QueueHandler) GetQueue(c *fiber.Ctx) error {
 		ID:                queue.ID,
 		AppointmentID:     queue.AppointmentID,
 		OrganizationID:    queue.OrganizationID,
+		ScopeType:         string(queue.ScopeType),
+		ScopeID:           queue.ScopeID,
+		TriageLevel:       int(queue.TriageLevel),
+		TicketNumber:      queue.TicketNumber,
 		Position:          queue.Position,
 		EstimatedWaitTime: queue.EstimatedWaitTime,
+		EstimatedWaitLow:  queue.EstimatedWaitLow,
+		EstimatedWaitHigh: queue.EstimatedWaitHigh,
 		Status:            queue.Status,
+		CalledAt:          queue.CalledAt,
+		StartedAt:         queue.StartedAt,
+		CompletedAt:       queue.CompletedAt,
+		CarriedOverTo:     queue.CarriedOverTo,
 		CreatedAt:         queue.CreatedAt,
 		UpdatedAt:         queue.UpdatedAt,
 	}
@@ -211,9 +273,18 @@ func (h *QueueHandler) GetPatientQueue(c *fiber.Ctx) error {
 		ID:                patientQueue.ID,
 		AppointmentID:     patientQueue.AppointmentID,
 		OrganizationID:    patientQueue.OrganizationID,
+		ScopeType:         string(patientQueue.ScopeType),
+		ScopeID:           patientQueue.ScopeID,
+		TriageLevel:       int(patientQueue.TriageLevel),
+		TicketNumber:      patientQueue.TicketNumber,
 		Position:          patientQueue.Position,
 		EstimatedWaitTime: patientQueue.EstimatedWaitTime,
+		EstimatedWaitLow:  patientQueue.EstimatedWaitLow,
+		EstimatedWaitHigh: patientQueue.EstimatedWaitHigh,
 		Status:            patientQueue.Status,
+		CalledAt:          patientQueue.CalledAt,
+		StartedAt:         patientQueue.StartedAt,
+		CompletedAt:       patientQueue.CompletedAt,
 		CreatedAt:         patientQueue.CreatedAt,
 		UpdatedAt:         patientQueue.UpdatedAt,
 		PatientName:       patientQueue.PatientName,
@@ -256,13 +327,21 @@ func (h *QueueHandler) CreateQueue(c *fiber.Ctx) error {
 	
 	// Convert to domain model
 	queue := &queue.PatientQueue{
-		AppointmentID:     request.AppointmentID,
-		OrganizationID:    request.OrganizationID,
-		EstimatedWaitTime: request.EstimatedWaitTime,
+		AppointmentID:  request.AppointmentID,
+		OrganizationID: request.OrganizationID,
+		ScopeType:      queue.ScopeType(request.ScopeType),
+		ScopeID:        request.ScopeID,
+		TriageLevel:    queue.TriageLevel(request.TriageLevel),
 	}
 	
 	// Create queue
-	if err := h.queueService.CreateQueue(c.Context(), queue); err != nil {
+	if err := h.queueService.CreateQueue(c.Context(), queue, actorFromLocals(c)); err != nil {
+		if isInvalidQueueScope(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid queue scope",
+				Message: "The queue's doctor, room or service point could not be determined",
+			})
+		}
 		h.logger.Error(c.Context(), "Failed to create queue", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error:   "Failed to create queue",
@@ -275,9 +354,19 @@ func (h *QueueHandler) CreateQueue(c *fiber.Ctx) error {
 		ID:                queue.ID,
 		AppointmentID:     queue.AppointmentID,
 		OrganizationID:    queue.OrganizationID,
+		ScopeType:         string(queue.ScopeType),
+		ScopeID:           queue.ScopeID,
+		TriageLevel:       int(queue.TriageLevel),
+		TicketNumber:      queue.TicketNumber,
 		Position:          queue.Position,
 		EstimatedWaitTime: queue.EstimatedWaitTime,
+		EstimatedWaitLow:  queue.EstimatedWaitLow,
+		EstimatedWaitHigh: queue.EstimatedWaitHigh,
 		Status:            queue.Status,
+		CalledAt:          queue.CalledAt,
+		StartedAt:         queue.StartedAt,
+		CompletedAt:       queue.CompletedAt,
+		CarriedOverTo:     queue.CarriedOverTo,
 		CreatedAt:         queue.CreatedAt,
 		UpdatedAt:         queue.UpdatedAt,
 	}
@@ -328,11 +417,10 @@ func (h *QueueHandler) UpdateQueue(c *fiber.Ctx) error {
 
 	// Update queue fields
 	existingQueue.Position = req.Position
-	existingQueue.EstimatedWaitTime = req.EstimatedWaitTime
 	existingQueue.Status = req.Status
 
 	// Update queue
-	if err := h.queueService.UpdateQueue(c.Context(), existingQueue); err != nil {
+	if err := h.queueService.UpdateQueue(c.Context(), existingQueue, actorFromLocals(c)); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error:   "Failed to update queue",
 			Message: err.Error(),
@@ -344,9 +432,19 @@ func (h *QueueHandler) UpdateQueue(c *fiber.Ctx) error {
 		ID:                existingQueue.ID,
 		AppointmentID:     existingQueue.AppointmentID,
 		OrganizationID:    existingQueue.OrganizationID,
+		ScopeType:         string(existingQueue.ScopeType),
+		ScopeID:           existingQueue.ScopeID,
+		TriageLevel:       int(existingQueue.TriageLevel),
+		TicketNumber:      existingQueue.TicketNumber,
 		Position:          existingQueue.Position,
 		EstimatedWaitTime: existingQueue.EstimatedWaitTime,
+		EstimatedWaitLow:  existingQueue.EstimatedWaitLow,
+		EstimatedWaitHigh: existingQueue.EstimatedWaitHigh,
 		Status:            existingQueue.Status,
+		CalledAt:          existingQueue.CalledAt,
+		StartedAt:         existingQueue.StartedAt,
+		CompletedAt:       existingQueue.CompletedAt,
+		CarriedOverTo:     existingQueue.CarriedOverTo,
 		CreatedAt:         existingQueue.CreatedAt,
 		UpdatedAt:         existingQueue.UpdatedAt,
 	}
@@ -361,6 +459,7 @@ func (h *QueueHandler) UpdateQueue(c *fiber.Ctx) error {
 }
 
 // DELETE /api/v1/queues/:id
+// Cancels the entry rather than deleting it, so its status history is kept
 func (h *QueueHandler) DeleteQueue(c *fiber.Ctx) error {
 	queueID := c.Params("id")
 	if queueID == "" {
@@ -371,25 +470,29 @@ func (h *QueueHandler) DeleteQueue(c *fiber.Ctx) error {
 	}
 
 	// Check if queue exists
-	_, err := h.queueService.GetQueue(c.Context(), queueID)
-	if err != nil {
+	if _, err := h.getOwnQueue(c, queueID); err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 			Error:   "Queue not found",
 			Message: err.Error(),
 		})
 	}
 
-	// Delete queue
-	if err := h.queueService.DeleteQueue(c.Context(), queueID); err != nil {
+	if _, err := h.queueService.CancelQueue(c.Context(), queueID, actorFromLocals(c)); err != nil {
+		if errors.Is(err, queue.ErrInvalidTransition) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "Failed to cancel queue",
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "Failed to delete queue",
+			Error:   "Failed to cancel queue",
 			Message: err.Error(),
 		})
 	}
 
 	response := dto.SuccessResponse{
 		Success: true,
-		Message: "Queue deleted successfully",
+		Message: "Queue cancelled successfully",
 	}
 
 	return c.JSON(response)
@@ -421,32 +524,72 @@ func (h *QueueHandler) QueueAction(c *fiber.Ctx) error {
 		})
 	}
 
+	existingQueue, err := h.getOwnQueue(c, queueID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Queue not found",
+			Message: err.Error(),
+		})
+	}
+
 	var updatedQueue *queue.PatientQueue
-	var err error
 
 	// Perform action based on type
 	switch req.Action {
 	case "call_next":
-		// Get organization ID from queue
-		existingQueue, getErr := h.queueService.GetQueue(c.Context(), queueID)
-		if getErr != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "Queue not found",
-				Message: getErr.Error(),
+		scope, scopeErr := callScope(c, req, existingQueue)
+		if scopeErr != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid queue scope",
+				Message: scopeErr.Error(),
 			})
 		}
-		updatedQueue, err = h.queueService.CallNextPatient(c.Context(), existingQueue.OrganizationID)
+		updatedQueue, err = h.queueService.CallNextPatient(c.Context(), existingQueue.OrganizationID, scope, actorFromLocals(c))
 	case "start_consultation":
-		updatedQueue, err = h.queueService.StartPatientConsultation(c.Context(), queueID)
+		updatedQueue, err = h.queueService.StartPatientConsultation(c.Context(), queueID, actorFromLocals(c))
 	case "complete_consultation":
-		updatedQueue, err = h.queueService.CompletePatientConsultation(c.Context(), queueID)
+		updatedQueue, err = h.queueService.CompletePatientConsultation(c.Context(), queueID, actorFromLocals(c))
+	case "skip":
+		updatedQueue, err = h.queueService.SkipPatient(c.Context(), queueID, actorFromLocals(c))
+	case "recall":
+		updatedQueue, err = h.queueService.RecallPatient(c.Context(), queueID, actorFromLocals(c))
+	case "hold":
+		updatedQueue, err = h.queueService.HoldPatient(c.Context(), queueID, actorFromLocals(c))
+	case "release":
+		updatedQueue, err = h.queueService.ReleasePatient(c.Context(), queueID, actorFromLocals(c))
+	case "transfer":
+		if req.ScopeType == "" || req.ScopeID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid queue scope",
+				Message: "scope_type and scope_id of the queue to transfer to are required",
+			})
+		}
+		to := queue.Scope{Type: queue.ScopeType(req.ScopeType), ID: req.ScopeID}
+		updatedQueue, err = h.queueService.TransferPatient(c.Context(), queueID, to, actorFromLocals(c))
+	case "pause_queue", "resume_queue":
+		return h.changeQueueState(c, queueID, req)
 	default:
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
 			Error:   "Invalid action",
-			Message: "Action must be one of: call_next, start_consultation, complete_consultation",
+			Message: "Action must be one of: call_next, start_consultation, complete_consultation, skip, recall, transfer, hold, release, pause_queue, resume_queue",
 		})
 	}
 
+	if errors.Is(err, queue.ErrQueueEmpty) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Queue is empty",
+			Message: "No patients are waiting in this queue",
+		})
+	}
+	if status, body, ok := queueActionError(err); ok {
+		return c.Status(status).JSON(body)
+	}
+	if isInvalidQueueScope(err) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue scope",
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error:   "Failed to perform action",
@@ -459,9 +602,19 @@ func (h *QueueHandler) QueueAction(c *fiber.Ctx) error {
 		ID:                updatedQueue.ID,
 		AppointmentID:     updatedQueue.AppointmentID,
 		OrganizationID:    updatedQueue.OrganizationID,
+		ScopeType:         string(updatedQueue.ScopeType),
+		ScopeID:           updatedQueue.ScopeID,
+		TriageLevel:       int(updatedQueue.TriageLevel),
+		TicketNumber:      updatedQueue.TicketNumber,
 		Position:          updatedQueue.Position,
 		EstimatedWaitTime: updatedQueue.EstimatedWaitTime,
+		EstimatedWaitLow:  updatedQueue.EstimatedWaitLow,
+		EstimatedWaitHigh: updatedQueue.EstimatedWaitHigh,
 		Status:            updatedQueue.Status,
+		CalledAt:          updatedQueue.CalledAt,
+		StartedAt:         updatedQueue.StartedAt,
+		CompletedAt:       updatedQueue.CompletedAt,
+		CarriedOverTo:     updatedQueue.CarriedOverTo,
 		CreatedAt:         updatedQueue.CreatedAt,
 		UpdatedAt:         updatedQueue.UpdatedAt,
 	}
@@ -474,3 +627,470 @@ func (h *QueueHandler) QueueAction(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+// changeQueueState pauses or resumes the queue picked as for call_next
+func (h *QueueHandler) changeQueueState(c *fiber.Ctx, queueID string, req dto.QueueActionRequest) error {
+	existingQueue, err := h.queueService.GetQueue(c.Context(), queueID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Queue not found",
+			Message: err.Error(),
+		})
+	}
+
+	scope, err := callScope(c, req, existingQueue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue scope",
+			Message: err.Error(),
+		})
+	}
+
+	var state *queue.State
+	if req.Action == "pause_queue" {
+		var reason *string
+		if req.Reason != "" {
+			reason = &req.Reason
+		}
+		state, err = h.queueService.PauseQueue(c.Context(), existingQueue.OrganizationID, scope, reason, actorFromLocals(c))
+	} else {
+		state, err = h.queueService.ResumeQueue(c.Context(), existingQueue.OrganizationID, scope, actorFromLocals(c))
+	}
+	if isInvalidQueueScope(err) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue scope",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to change queue state", "error", err, "queueID", queueID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to perform action",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.QueueStateDetailResponse{
+		Success: true,
+		Data:    toQueueStateResponse(state),
+		Message: "Action performed successfully",
+	})
+}
+
+// GET /api/v1/queues/state
+func (h *QueueHandler) GetQueueState(c *fiber.Ctx) error {
+	organizationID := c.Query("organizationId")
+	if organizationID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Missing organization ID",
+			Message: "organizationId query parameter is required",
+		})
+	}
+
+	scope := queue.Scope{Type: queue.ScopeType(c.Query("scopeType")), ID: c.Query("scopeId")}
+	state, err := h.queueService.GetQueueState(c.Context(), organizationID, scope)
+	if isInvalidQueueScope(err) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue scope",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get queue state", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get queue state",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.QueueStateDetailResponse{
+		Success: true,
+		Data:    toQueueStateResponse(state),
+		Message: "Queue state retrieved successfully",
+	})
+}
+
+// GET /api/v1/organizations/:id/queue-closing-policy
+func (h *QueueHandler) GetClosingPolicy(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	policy, err := h.queueService.GetClosingPolicy(c.Context(), organizationID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get queue closing policy", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get queue closing policy",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toQueueClosingPolicyResponse(policy),
+		Message: "Queue closing policy retrieved successfully",
+	})
+}
+
+// PUT /api/v1/organizations/:id/queue-closing-policy
+func (h *QueueHandler) UpdateClosingPolicy(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	var req dto.UpdateQueueClosingPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	policy := &queue.ClosingPolicy{
+		OrganizationID: organizationID,
+		Enabled:        req.Enabled,
+		CloseTime:      req.CloseTime,
+		LeftoverAction: queue.LeftoverAction(req.LeftoverAction),
+	}
+
+	if err := h.queueService.UpdateClosingPolicy(c.Context(), policy); err != nil {
+		if appqueue.IsClosingPolicyError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid queue closing policy",
+				Message: err.Error(),
+			})
+		}
+		h.logger.Error(c.Context(), "Failed to update queue closing policy", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to update queue closing policy",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toQueueClosingPolicyResponse(policy),
+		Message: "Queue closing policy updated successfully",
+	})
+}
+
+// GET /api/v1/organizations/:id/queue-closures?limit=30&page=1
+func (h *QueueHandler) GetClosures(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Please provide a valid organization ID",
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "30"))
+	if err != nil || limit <= 0 {
+		limit = 30
+	}
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	closures, err := h.queueService.GetClosures(c.Context(), organizationID, limit, (page-1)*limit)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get queue closures", "error", err, "organizationID", organizationID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get queue closures",
+			Message: err.Error(),
+		})
+	}
+
+	data := make([]dto.QueueClosureResponse, len(closures))
+	for i, closure := range closures {
+		data[i] = dto.QueueClosureResponse{
+			ID:                 closure.ID,
+			OrganizationID:     closure.OrganizationID,
+			Day:                closure.Day.Format("2006-01-02"),
+			LeftoverAction:     string(closure.LeftoverAction),
+			Joined:             closure.Joined,
+			Completed:          closure.Completed,
+			Cancelled:          closure.Cancelled,
+			LeftoverCancelled:  closure.LeftoverCancelled,
+			CarriedOver:        closure.CarriedOver,
+			InProgress:         closure.InProgress,
+			AverageWaitMinutes: closure.AverageWaitMinutes,
+			ClosedAt:           closure.ClosedAt,
+		}
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    data,
+		Message: "Queue closures retrieved successfully",
+	})
+}
+
+// PUT /api/v1/queues/:id/triage
+func (h *QueueHandler) RetriageQueue(c *fiber.Ctx) error {
+	queueID := c.Params("id")
+	if queueID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue ID",
+			Message: "Queue ID is required",
+		})
+	}
+
+	var req dto.RetriageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	if _, err := h.getOwnQueue(c, queueID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Queue not found",
+			Message: err.Error(),
+		})
+	}
+
+	updatedQueue, err := h.queueService.RetriageQueue(c.Context(), queueID, queue.TriageLevel(req.TriageLevel), req.Reason, actorFromLocals(c))
+	if err != nil {
+		if status, body, ok := triageError(err); ok {
+			return c.Status(status).JSON(body)
+		}
+		h.logger.Error(c.Context(), "Failed to re-triage queue", "error", err, "queueID", queueID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to re-triage queue",
+			Message: err.Error(),
+		})
+	}
+
+	queueResponse := dto.QueueResponse{
+		ID:                updatedQueue.ID,
+		AppointmentID:     updatedQueue.AppointmentID,
+		OrganizationID:    updatedQueue.OrganizationID,
+		ScopeType:         string(updatedQueue.ScopeType),
+		ScopeID:           updatedQueue.ScopeID,
+		TriageLevel:       int(updatedQueue.TriageLevel),
+		TicketNumber:      updatedQueue.TicketNumber,
+		Position:          updatedQueue.Position,
+		EstimatedWaitTime: updatedQueue.EstimatedWaitTime,
+		EstimatedWaitLow:  updatedQueue.EstimatedWaitLow,
+		EstimatedWaitHigh: updatedQueue.EstimatedWaitHigh,
+		Status:            updatedQueue.Status,
+		CalledAt:          updatedQueue.CalledAt,
+		StartedAt:         updatedQueue.StartedAt,
+		CompletedAt:       updatedQueue.CompletedAt,
+		CarriedOverTo:     updatedQueue.CarriedOverTo,
+		CreatedAt:         updatedQueue.CreatedAt,
+		UpdatedAt:         updatedQueue.UpdatedAt,
+	}
+
+	return c.JSON(dto.QueueDetailResponse{
+		Success: true,
+		Data:    queueResponse,
+		Message: "Queue entry re-triaged successfully",
+	})
+}
+
+// GET /api/v1/queues/:id/triage-history
+func (h *QueueHandler) GetTriageHistory(c *fiber.Ctx) error {
+	queueID := c.Params("id")
+	if queueID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue ID",
+			Message: "Queue ID is required",
+		})
+	}
+
+	history, err := h.queueService.GetTriageHistory(c.Context(), queueID)
+	if err != nil {
+		if status, body, ok := triageError(err); ok {
+			return c.Status(status).JSON(body)
+		}
+		h.logger.Error(c.Context(), "Failed to get triage history", "error", err, "queueID", queueID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get triage history",
+			Message: err.Error(),
+		})
+	}
+
+	entries := make([]dto.TriageChangeResponse, len(history))
+	for i, change := range history {
+		entries[i] = dto.TriageChangeResponse{
+			ID:        change.ID,
+			QueueID:   change.QueueID,
+			FromLevel: int(change.FromLevel),
+			ToLevel:   int(change.ToLevel),
+			Reason:    change.Reason,
+			ActorID:   change.ActorID,
+			ChangedAt: change.ChangedAt,
+		}
+	}
+
+	return c.JSON(dto.TriageHistoryResponse{
+		Success: true,
+		Data:    entries,
+		Message: "Triage history retrieved successfully",
+	})
+}
+
+// GET /api/v1/queues/:id/events
+func (h *QueueHandler) GetStatusHistory(c *fiber.Ctx) error {
+	queueID := c.Params("id")
+	if queueID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid queue ID",
+			Message: "Queue ID is required",
+		})
+	}
+
+	history, err := h.queueService.GetStatusHistory(c.Context(), queueID)
+	if err != nil {
+		if errors.Is(err, queue.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "Queue not found",
+				Message: err.Error(),
+			})
+		}
+		h.logger.Error(c.Context(), "Failed to get queue status history", "error", err, "queueID", queueID)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to get queue status history",
+			Message: err.Error(),
+		})
+	}
+
+	entries := make([]dto.StatusChangeResponse, len(history))
+	for i, change := range history {
+		entries[i] = dto.StatusChangeResponse{
+			ID:         change.ID,
+			QueueID:    change.QueueID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			ActorID:    change.ActorID,
+			ChangedAt:  change.ChangedAt,
+		}
+	}
+
+	return c.JSON(dto.StatusHistoryResponse{
+		Success: true,
+		Data:    entries,
+		Message: "Queue status history retrieved successfully",
+	})
+}
+
+// triageError returns the status and body for errors a triage request can be
+// rejected with, and false for unexpected errors
+func triageError(err error) (int, dto.ErrorResponse, bool) {
+	switch {
+	case errors.Is(err, queue.ErrNotFound):
+		return fiber.StatusNotFound, dto.ErrorResponse{Error: "Queue not found", Message: err.Error()}, true
+	case errors.Is(err, queue.ErrNotWaiting):
+		return fiber.StatusConflict, dto.ErrorResponse{Error: "Queue entry not waiting", Message: err.Error()}, true
+	case errors.Is(err, queue.ErrInvalidTriageLevel), errors.Is(err, queue.ErrTriageReasonRequired):
+		return fiber.StatusBadRequest, dto.ErrorResponse{Error: "Invalid triage", Message: err.Error()}, true
+	}
+	return 0, dto.ErrorResponse{}, false
+}
+
+// queueActionError returns the status and body for errors a queue action can be
+// rejected with, and false for unexpected errors
+func queueActionError(err error) (int, dto.ErrorResponse, bool) {
+	switch {
+	case errors.Is(err, queue.ErrNotFound):
+		return fiber.StatusNotFound, dto.ErrorResponse{Error: "Queue not found", Message: err.Error()}, true
+	case errors.Is(err, queue.ErrInvalidTransition):
+		return fiber.StatusConflict, dto.ErrorResponse{Error: "Invalid queue action", Message: err.Error()}, true
+	case errors.Is(err, queue.ErrQueuePaused):
+		return fiber.StatusConflict, dto.ErrorResponse{Error: "Queue is paused", Message: err.Error()}, true
+	case errors.Is(err, queue.ErrSameScope), errors.Is(err, queue.ErrUnknownScope):
+		return fiber.StatusBadRequest, dto.ErrorResponse{Error: "Invalid queue scope", Message: err.Error()}, true
+	}
+	return 0, dto.ErrorResponse{}, false
+}
+
+func toQueueStateResponse(state *queue.State) dto.QueueStateResponse {
+	return dto.QueueStateResponse{
+		OrganizationID: state.OrganizationID,
+		ScopeType:      string(state.Scope.Type),
+		ScopeID:        state.Scope.ID,
+		Paused:         state.Paused,
+		Reason:         state.Reason,
+		ChangedBy:      state.ChangedBy,
+		ChangedAt:      state.ChangedAt,
+	}
+}
+
+func toQueueClosingPolicyResponse(policy *queue.ClosingPolicy) dto.QueueClosingPolicyResponse {
+	return dto.QueueClosingPolicyResponse{
+		OrganizationID: policy.OrganizationID,
+		Enabled:        policy.Enabled,
+		CloseTime:      policy.CloseTime,
+		LeftoverAction: string(policy.LeftoverAction),
+	}
+}
+
+// callScope picks the queue call_next, pause_queue and resume_queue act on: an explicit scope in the request,
+// else the calling doctor's own queue, else, for other staff, the queue of the entry acted on
+func callScope(c *fiber.Ctx, req dto.QueueActionRequest, entry *queue.PatientQueue) (queue.Scope, error) {
+	if req.ScopeType != "" {
+		return queue.Scope{Type: queue.ScopeType(req.ScopeType), ID: req.ScopeID}, nil
+	}
+	if role, _ := c.Locals("role").(string); role == "doctor" {
+		userID, _ := c.Locals("user_id").(string)
+		if userID == "" {
+			return queue.Scope{}, fmt.Errorf("%w: the calling doctor is unknown; pass scope_type and scope_id", queue.ErrInvalidScope)
+		}
+		return queue.Scope{Type: queue.ScopeDoctor, ID: userID}, nil
+	}
+	return entry.Scope(), nil
+}
+
+// getOwnQueue returns the queue entry with the given ID if it belongs to the caller's
+// organization; other organizations' entries are reported as not found
+func (h *QueueHandler) getOwnQueue(c *fiber.Ctx, queueID string) (*queue.PatientQueue, error) {
+	q, err := h.queueService.GetQueue(c.Context(), queueID)
+	if err != nil {
+		return nil, err
+	}
+	if organizationID, _ := c.Locals("organization_id").(string); q.OrganizationID != organizationID {
+		return nil, queue.ErrNotFound
+	}
+	return q, nil
+}
+
+// actorFromLocals returns the authenticated user, if any, for the queue's audit records
+func actorFromLocals(c *fiber.Ctx) *string {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return nil
+	}
+	return &userID
+}
+
+// isInvalidQueueScope reports whether err means the queue scope was missing or malformed
+func isInvalidQueueScope(err error) bool {
+	return errors.Is(err, queue.ErrInvalidScope)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"medika-backend/internal/domain/queue"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+// streamHeartbeatInterval keeps idle streams from being closed by proxies and lets
+// dropped clients be noticed
+const streamHeartbeatInterval = 15 * time.Second
+
+type QueueStreamHandler struct {
+	queueStream QueueStream
+	validator   *validator.Validate
+	logger      logger.Logger
+}
+
+// QueueStream interface for dependency injection
+type QueueStream interface {
+	Subscribe(organizationID string, scope *queue.Scope) (<-chan queue.QueueEvent, func())
+}
+
+func NewQueueStreamHandler(
+	queueStream QueueStream,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *QueueStreamHandler {
+	return &QueueStreamHandler{
+		queueStream: queueStream,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// GET /api/v1/queues/stream
+func (h *QueueStreamHandler) StreamEvents(c *fiber.Ctx) error {
+	organizationID, scope, err := h.streamFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid stream filter",
+			Message: err.Error(),
+		})
+	}
+
+	events, unsubscribe := h.queueStream.Subscribe(organizationID, scope)
+	conn := c.Context().Conn()
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		// The server's write timeout covers a whole response; keep pushing it back so
+		// it does not cut the stream
+		flush := func() error {
+			if err := conn.SetWriteDeadline(time.Now().Add(2 * streamHeartbeatInterval)); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		// Open the stream right away rather than on the first event
+		fmt.Fprint(w, ": connected\n\n")
+		if err := flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				payload, err := json.Marshal(event)
+				if err != nil {
+					h.logger.Error(context.Background(), "Failed to encode queue event", "error", err, "type", event.Type)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			// A failed flush means the client has gone away
+			if err := flush(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return nil
+}
+
+// UpgradeWebSocket validates a queue WebSocket request before it is upgraded
+func (h *QueueStreamHandler) UpgradeWebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(dto.ErrorResponse{
+			Error:   "WebSocket upgrade required",
+			Message: "Use GET /api/v1/queues/stream for Server-Sent Events",
+		})
+	}
+
+	organizationID, scope, err := h.streamFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid stream filter",
+			Message: err.Error(),
+		})
+	}
+
+	c.Locals("queue_organization_id", organizationID)
+	c.Locals("queue_scope", scope)
+	return c.Next()
+}
+
+// GET /api/v1/queues/ws
+func (h *QueueStreamHandler) StreamWebSocket(conn *websocket.Conn) {
+	organizationID, _ := conn.Locals("queue_organization_id").(string)
+	scope, _ := conn.Locals("queue_scope").(*queue.Scope)
+
+	events, unsubscribe := h.queueStream.Subscribe(organizationID, scope)
+	defer unsubscribe()
+
+	// Clients only listen, but reading is how a disconnect is noticed
+	ws := conn.Conn
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+				return
+			}
+			if err := ws.WriteJSON(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamFilter reads the organization a stream is for and, optionally, the one queue
+// within it
+func (h *QueueStreamHandler) streamFilter(c *fiber.Ctx) (string, *queue.Scope, error) {
+	organizationID := c.Query("organizationId", "")
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return "", nil, errors.New("organizationId must be a valid UUID")
+	}
+
+	scope := queue.Scope{
+		Type: queue.ScopeType(c.Query("scopeType", "")),
+		ID:   c.Query("scopeId", ""),
+	}
+	if scope.Type == "" && scope.ID == "" {
+		return organizationID, nil, nil
+	}
+	if !scope.IsValid() {
+		return "", nil, errors.New("scopeType (doctor, room or service_point) and scopeId must be given together")
+	}
+
+	return organizationID, &scope, nil
+}
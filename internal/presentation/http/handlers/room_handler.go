@@ -0,0 +1,453 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	approom "medika-backend/internal/application/room"
+	"medika-backend/internal/domain/room"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type RoomHandler struct {
+	roomService RoomService
+	validator   *validator.Validate
+	logger      logger.Logger
+}
+
+// RoomService interface for dependency injection
+type RoomService interface {
+	CreateRoom(ctx context.Context, rm *room.Room) error
+	GetRoom(ctx context.Context, id string) (*room.Room, error)
+	GetRooms(ctx context.Context, organizationID string, filter room.Filter, limit, offset int) ([]*room.Room, error)
+	UpdateRoom(ctx context.Context, rm *room.Room) error
+	SetAvailability(ctx context.Context, id string, available bool) (*room.Room, error)
+	SetEquipment(ctx context.Context, id string, equipment []string) (*room.Room, error)
+	DeleteRoom(ctx context.Context, id string) error
+	GetOccupancy(ctx context.Context, organizationID string, date time.Time) ([]*room.Occupancy, error)
+}
+
+func NewRoomHandler(
+	roomService RoomService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *RoomHandler {
+	return &RoomHandler{
+		roomService: roomService,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// GET /api/v1/rooms?organizationId=...&type=consultation&available=true&equipment=ECG,Defibrillator
+func (h *RoomHandler) GetRooms(c *fiber.Ctx) error {
+	organizationID := organizationFromRequest(c)
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "organizationId must be a valid UUID",
+		})
+	}
+
+	roomType := c.Query("type", "")
+	if roomType != "" && !room.Type(roomType).IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid room type",
+			Message: "type must be one of consultation, examination, procedure, waiting, office",
+		})
+	}
+
+	filter := room.Filter{
+		Type:          room.Type(roomType),
+		AvailableOnly: c.QueryBool("available", false),
+	}
+	if equipment := c.Query("equipment", ""); equipment != "" {
+		for _, item := range strings.Split(equipment, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				filter.Equipment = append(filter.Equipment, item)
+			}
+		}
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	rooms, err := h.roomService.GetRooms(c.Context(), organizationID, filter, limit, (page-1)*limit)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get rooms", "error", err)
+		return h.respondRoomError(c, err, "Failed to get rooms")
+	}
+
+	responses := make([]dto.RoomResponse, len(rooms))
+	for i, rm := range rooms {
+		responses[i] = toRoomResponse(rm)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    responses,
+		Message: "Rooms retrieved successfully",
+	})
+}
+
+// GET /api/v1/rooms/occupancy?organizationId=...&date=2024-01-01
+func (h *RoomHandler) GetOccupancy(c *fiber.Ctx) error {
+	organizationID := organizationFromRequest(c)
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "organizationId must be a valid UUID",
+		})
+	}
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid date",
+				Message: "date must be in YYYY-MM-DD format",
+			})
+		}
+		date = parsed
+	}
+
+	occupancy, err := h.roomService.GetOccupancy(c.Context(), organizationID, date)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get room occupancy", "error", err)
+		return h.respondRoomError(c, err, "Failed to get room occupancy")
+	}
+
+	responses := make([]dto.RoomOccupancyResponse, len(occupancy))
+	for i, o := range occupancy {
+		responses[i] = toRoomOccupancyResponse(o, date)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    responses,
+		Message: "Room occupancy retrieved successfully",
+	})
+}
+
+// GET /api/v1/rooms/:id
+func (h *RoomHandler) GetRoom(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if err := h.validator.Var(roomID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid room ID",
+			Message: "Room ID must be a valid UUID",
+		})
+	}
+
+	rm, err := h.roomService.GetRoom(c.Context(), roomID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get room", "error", err, "roomID", roomID)
+		return h.respondRoomError(c, err, "Failed to get room")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toRoomResponse(rm),
+		Message: "Room retrieved successfully",
+	})
+}
+
+// POST /api/v1/rooms
+func (h *RoomHandler) CreateRoom(c *fiber.Ctx) error {
+	var req dto.CreateRoomRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	rm := &room.Room{
+		OrganizationID: req.OrganizationID,
+		Name:           req.Name,
+		Type:           room.Type(req.Type),
+		Capacity:       req.Capacity,
+		IsAvailable:    true,
+		Equipment:      req.Equipment,
+	}
+	if rm.Capacity == 0 {
+		rm.Capacity = 1
+	}
+	if req.IsAvailable != nil {
+		rm.IsAvailable = *req.IsAvailable
+	}
+
+	if err := h.roomService.CreateRoom(c.Context(), rm); err != nil {
+		h.logger.Error(c.Context(), "Failed to create room", "error", err)
+		return h.respondRoomError(c, err, "Failed to create room")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toRoomResponse(rm),
+		Message: "Room created successfully",
+	})
+}
+
+// PUT /api/v1/rooms/:id
+func (h *RoomHandler) UpdateRoom(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if err := h.validator.Var(roomID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid room ID",
+			Message: "Room ID must be a valid UUID",
+		})
+	}
+
+	var req dto.UpdateRoomRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	rm, err := h.roomService.GetRoom(c.Context(), roomID)
+	if err != nil {
+		return h.respondRoomError(c, err, "Failed to update room")
+	}
+
+	applyRoomRequest(rm, &req)
+
+	if err := h.roomService.UpdateRoom(c.Context(), rm); err != nil {
+		h.logger.Error(c.Context(), "Failed to update room", "error", err, "roomID", roomID)
+		return h.respondRoomError(c, err, "Failed to update room")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toRoomResponse(rm),
+		Message: "Room updated successfully",
+	})
+}
+
+// PUT /api/v1/rooms/:id/availability
+func (h *RoomHandler) UpdateAvailability(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if err := h.validator.Var(roomID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid room ID",
+			Message: "Room ID must be a valid UUID",
+		})
+	}
+
+	var req dto.UpdateRoomAvailabilityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	rm, err := h.roomService.SetAvailability(c.Context(), roomID, *req.IsAvailable)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to update room availability", "error", err, "roomID", roomID)
+		return h.respondRoomError(c, err, "Failed to update room availability")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toRoomResponse(rm),
+		Message: "Room availability updated successfully",
+	})
+}
+
+// PUT /api/v1/rooms/:id/equipment
+func (h *RoomHandler) UpdateEquipment(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if err := h.validator.Var(roomID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid room ID",
+			Message: "Room ID must be a valid UUID",
+		})
+	}
+
+	var req dto.UpdateRoomEquipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	rm, err := h.roomService.SetEquipment(c.Context(), roomID, req.Equipment)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to update room equipment", "error", err, "roomID", roomID)
+		return h.respondRoomError(c, err, "Failed to update room equipment")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toRoomResponse(rm),
+		Message: "Room equipment updated successfully",
+	})
+}
+
+// DELETE /api/v1/rooms/:id
+func (h *RoomHandler) DeleteRoom(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if err := h.validator.Var(roomID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid room ID",
+			Message: "Room ID must be a valid UUID",
+		})
+	}
+
+	if err := h.roomService.DeleteRoom(c.Context(), roomID); err != nil {
+		h.logger.Error(c.Context(), "Failed to delete room", "error", err, "roomID", roomID)
+		return h.respondRoomError(c, err, "Failed to delete room")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Room deleted successfully",
+	})
+}
+
+// organizationFromRequest reads the organizationId query parameter, falling back to
+// the caller's organization
+func organizationFromRequest(c *fiber.Ctx) string {
+	if organizationID := c.Query("organizationId", ""); organizationID != "" {
+		return organizationID
+	}
+	organizationID, _ := c.Locals("organization_id").(string)
+	return organizationID
+}
+
+func (h *RoomHandler) respondRoomError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, room.ErrRoomNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Room not found",
+			Message: err.Error(),
+		})
+	case approom.IsValidationError(err):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case errors.Is(err, room.ErrRoomHasAppointments):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func applyRoomRequest(rm *room.Room, req *dto.UpdateRoomRequest) {
+	if req.Name != nil {
+		rm.Name = *req.Name
+	}
+	if req.Type != nil {
+		rm.Type = room.Type(*req.Type)
+	}
+	if req.Capacity != nil {
+		rm.Capacity = *req.Capacity
+	}
+	if req.IsAvailable != nil {
+		rm.IsAvailable = *req.IsAvailable
+	}
+	if req.Equipment != nil {
+		rm.Equipment = req.Equipment
+	}
+}
+
+func toRoomResponse(rm *room.Room) dto.RoomResponse {
+	return dto.RoomResponse{
+		ID:             rm.ID,
+		OrganizationID: rm.OrganizationID,
+		Name:           rm.Name,
+		Type:           string(rm.Type),
+		Capacity:       rm.Capacity,
+		IsAvailable:    rm.IsAvailable,
+		Equipment:      rm.Equipment,
+		CreatedAt:      rm.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      rm.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func toRoomOccupancyResponse(o *room.Occupancy, date time.Time) dto.RoomOccupancyResponse {
+	bookings := make([]dto.RoomBookingResponse, len(o.Bookings))
+	for i, b := range o.Bookings {
+		bookings[i] = dto.RoomBookingResponse{
+			AppointmentID: b.AppointmentID,
+			DoctorID:      b.DoctorID,
+			PatientID:     b.PatientID,
+			StartTime:     b.StartTime,
+			EndTime:       b.EndTime,
+			Status:        b.Status,
+		}
+	}
+
+	segments := make([]dto.RoomOccupancySegment, len(o.Segments))
+	for i, s := range o.Segments {
+		segments[i] = dto.RoomOccupancySegment{
+			StartTime: s.StartTime,
+			EndTime:   s.EndTime,
+			Occupied:  s.Occupied,
+			Capacity:  s.Capacity,
+			Full:      s.Occupied >= s.Capacity,
+		}
+	}
+
+	return dto.RoomOccupancyResponse{
+		Room:     toRoomResponse(o.Room),
+		Date:     date.Format("2006-01-02"),
+		Bookings: bookings,
+		Segments: segments,
+	}
+}
@@ -0,0 +1,499 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	appschedule "medika-backend/internal/application/schedule"
+	"medika-backend/internal/domain/schedule"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type ScheduleHandler struct {
+	scheduleService ScheduleService
+	validator       *validator.Validate
+	logger          logger.Logger
+}
+
+// ScheduleService interface for dependency injection
+type ScheduleService interface {
+	GetAvailableSlots(ctx context.Context, doctorID string, from, to time.Time) ([]schedule.Slot, error)
+	GetDoctorSchedule(ctx context.Context, doctorID string, from, to time.Time) ([]*schedule.Template, []*schedule.Override, error)
+	GetOrganizationSchedule(ctx context.Context, organizationID string, from, to time.Time) ([]*schedule.Template, []*schedule.Override, error)
+	CreateDoctorTemplate(ctx context.Context, doctorID string, t *schedule.Template) error
+	CreateOrganizationTemplate(ctx context.Context, organizationID string, t *schedule.Template) error
+	GetTemplate(ctx context.Context, id string) (*schedule.Template, error)
+	UpdateTemplate(ctx context.Context, t *schedule.Template) error
+	DeleteTemplate(ctx context.Context, id string) error
+	CreateDoctorOverride(ctx context.Context, doctorID string, o *schedule.Override) error
+	CreateOrganizationOverride(ctx context.Context, organizationID string, o *schedule.Override) error
+	DeleteOverride(ctx context.Context, id string) error
+}
+
+func NewScheduleHandler(
+	scheduleService ScheduleService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// GET /api/v1/doctors/:id/slots?from=2024-01-01&to=2024-01-07
+func (h *ScheduleHandler) GetDoctorSlots(c *fiber.Ctx) error {
+	doctorID := c.Params("id")
+	if err := h.validator.Var(doctorID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid doctor ID",
+			Message: "Doctor ID must be a valid UUID",
+		})
+	}
+
+	from, to, err := parseDateRange(c, 6)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid date range",
+			Message: err.Error(),
+		})
+	}
+
+	slots, err := h.scheduleService.GetAvailableSlots(c.Context(), doctorID, from, to)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get available slots", "error", err)
+		return h.respondScheduleError(c, err, "Failed to get available slots")
+	}
+
+	slotResponses := make([]dto.SlotResponse, len(slots))
+	for i, slot := range slots {
+		slotResponses[i] = dto.SlotResponse{
+			Date:      slot.Date.Format("2006-01-02"),
+			StartTime: slot.StartTime,
+			EndTime:   slot.EndTime,
+		}
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data: dto.SlotsResponse{
+			DoctorID: doctorID,
+			From:     from.Format("2006-01-02"),
+			To:       to.Format("2006-01-02"),
+			Slots:    slotResponses,
+		},
+		Message: "Available slots retrieved successfully",
+	})
+}
+
+// GET /api/v1/doctors/:id/schedule
+func (h *ScheduleHandler) GetDoctorSchedule(c *fiber.Ctx) error {
+	doctorID := c.Params("id")
+	if err := h.validator.Var(doctorID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid doctor ID",
+			Message: "Doctor ID must be a valid UUID",
+		})
+	}
+
+	from, to, err := parseDateRange(c, 30)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid date range",
+			Message: err.Error(),
+		})
+	}
+
+	templates, overrides, err := h.scheduleService.GetDoctorSchedule(c.Context(), doctorID, from, to)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get doctor schedule", "error", err)
+		return h.respondScheduleError(c, err, "Failed to get doctor schedule")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toScheduleResponse(templates, overrides),
+		Message: "Doctor schedule retrieved successfully",
+	})
+}
+
+// GET /api/v1/organizations/:id/schedule
+func (h *ScheduleHandler) GetOrganizationSchedule(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	if err := h.validator.Var(organizationID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "Organization ID must be a valid UUID",
+		})
+	}
+
+	from, to, err := parseDateRange(c, 30)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid date range",
+			Message: err.Error(),
+		})
+	}
+
+	templates, overrides, err := h.scheduleService.GetOrganizationSchedule(c.Context(), organizationID, from, to)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get organization schedule", "error", err)
+		return h.respondScheduleError(c, err, "Failed to get organization schedule")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toScheduleResponse(templates, overrides),
+		Message: "Organization schedule retrieved successfully",
+	})
+}
+
+// POST /api/v1/doctors/:id/schedule/templates
+func (h *ScheduleHandler) CreateDoctorTemplate(c *fiber.Ctx) error {
+	return h.createTemplate(c, "doctor", h.scheduleService.CreateDoctorTemplate)
+}
+
+// POST /api/v1/organizations/:id/schedule/templates
+func (h *ScheduleHandler) CreateOrganizationTemplate(c *fiber.Ctx) error {
+	return h.createTemplate(c, "organization", h.scheduleService.CreateOrganizationTemplate)
+}
+
+// PUT /api/v1/schedules/templates/:id
+func (h *ScheduleHandler) UpdateTemplate(c *fiber.Ctx) error {
+	templateID := c.Params("id")
+	if err := h.validator.Var(templateID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid template ID",
+			Message: "Template ID must be a valid UUID",
+		})
+	}
+
+	var req dto.ScheduleTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	template, err := h.scheduleService.GetTemplate(c.Context(), templateID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Template not found",
+			Message: err.Error(),
+		})
+	}
+
+	applyTemplateRequest(template, &req)
+
+	if err := h.scheduleService.UpdateTemplate(c.Context(), template); err != nil {
+		h.logger.Error(c.Context(), "Failed to update schedule template", "error", err)
+		return h.respondScheduleError(c, err, "Failed to update schedule template")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toTemplateResponse(template),
+		Message: "Schedule template updated successfully",
+	})
+}
+
+// DELETE /api/v1/schedules/templates/:id
+func (h *ScheduleHandler) DeleteTemplate(c *fiber.Ctx) error {
+	templateID := c.Params("id")
+	if err := h.validator.Var(templateID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid template ID",
+			Message: "Template ID must be a valid UUID",
+		})
+	}
+
+	if err := h.scheduleService.DeleteTemplate(c.Context(), templateID); err != nil {
+		h.logger.Error(c.Context(), "Failed to delete schedule template", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to delete schedule template",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Schedule template deleted successfully",
+	})
+}
+
+// POST /api/v1/doctors/:id/schedule/overrides
+func (h *ScheduleHandler) CreateDoctorOverride(c *fiber.Ctx) error {
+	return h.createOverride(c, "doctor", h.scheduleService.CreateDoctorOverride)
+}
+
+// POST /api/v1/organizations/:id/schedule/overrides
+func (h *ScheduleHandler) CreateOrganizationOverride(c *fiber.Ctx) error {
+	return h.createOverride(c, "organization", h.scheduleService.CreateOrganizationOverride)
+}
+
+// DELETE /api/v1/schedules/overrides/:id
+func (h *ScheduleHandler) DeleteOverride(c *fiber.Ctx) error {
+	overrideID := c.Params("id")
+	if err := h.validator.Var(overrideID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid override ID",
+			Message: "Override ID must be a valid UUID",
+		})
+	}
+
+	if err := h.scheduleService.DeleteOverride(c.Context(), overrideID); err != nil {
+		h.logger.Error(c.Context(), "Failed to delete schedule override", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to delete schedule override",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Schedule override deleted successfully",
+	})
+}
+
+func (h *ScheduleHandler) createTemplate(
+	c *fiber.Ctx,
+	owner string,
+	create func(ctx context.Context, ownerID string, t *schedule.Template) error,
+) error {
+	ownerID := c.Params("id")
+	if err := h.validator.Var(ownerID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid " + owner + " ID",
+			Message: "ID must be a valid UUID",
+		})
+	}
+
+	var req dto.ScheduleTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	template := &schedule.Template{IsActive: true}
+	applyTemplateRequest(template, &req)
+
+	if err := create(c.Context(), ownerID, template); err != nil {
+		h.logger.Error(c.Context(), "Failed to create schedule template", "error", err)
+		return h.respondScheduleError(c, err, "Failed to create schedule template")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toTemplateResponse(template),
+		Message: "Schedule template created successfully",
+	})
+}
+
+func (h *ScheduleHandler) createOverride(
+	c *fiber.Ctx,
+	owner string,
+	create func(ctx context.Context, ownerID string, o *schedule.Override) error,
+) error {
+	ownerID := c.Params("id")
+	if err := h.validator.Var(ownerID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid " + owner + " ID",
+			Message: "ID must be a valid UUID",
+		})
+	}
+
+	var req dto.ScheduleOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid date format",
+			Message: "Date must be in YYYY-MM-DD format",
+		})
+	}
+
+	override := &schedule.Override{
+		Date:         date,
+		IsAvailable:  req.IsAvailable,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		SlotDuration: req.SlotDuration,
+		Breaks:       toDomainBreaks(req.Breaks),
+		Reason:       req.Reason,
+	}
+
+	if err := create(c.Context(), ownerID, override); err != nil {
+		h.logger.Error(c.Context(), "Failed to create schedule override", "error", err)
+		return h.respondScheduleError(c, err, "Failed to create schedule override")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toOverrideResponse(override),
+		Message: "Schedule override created successfully",
+	})
+}
+
+func (h *ScheduleHandler) respondScheduleError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case schedule.IsValidationError(err),
+		errors.Is(err, appschedule.ErrInvalidDateRange),
+		errors.Is(err, appschedule.ErrDateRangeTooLong),
+		errors.Is(err, appschedule.ErrTemplateOwnerChanged):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid schedule",
+			Message: err.Error(),
+		})
+	case errors.Is(err, appschedule.ErrNotADoctor),
+		errors.Is(err, appschedule.ErrDoctorNotInOrg):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Doctor not found",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+// parseDateRange reads the from/to query parameters, defaulting to today and
+// the given number of days after from
+func parseDateRange(c *fiber.Ctx, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("'from' must be in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, defaultDays)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("'to' must be in YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, appschedule.ErrInvalidDateRange
+	}
+
+	return from, to, nil
+}
+
+func applyTemplateRequest(t *schedule.Template, req *dto.ScheduleTemplateRequest) {
+	t.DayOfWeek = *req.DayOfWeek
+	t.StartTime = req.StartTime
+	t.EndTime = req.EndTime
+	t.SlotDuration = req.SlotDuration
+	t.Breaks = toDomainBreaks(req.Breaks)
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+}
+
+func toDomainBreaks(breaks []dto.ScheduleBreak) []schedule.Break {
+	result := make([]schedule.Break, len(breaks))
+	for i, b := range breaks {
+		result[i] = schedule.Break{StartTime: b.StartTime, EndTime: b.EndTime}
+	}
+	return result
+}
+
+func toBreakResponses(breaks []schedule.Break) []dto.ScheduleBreak {
+	result := make([]dto.ScheduleBreak, len(breaks))
+	for i, b := range breaks {
+		result[i] = dto.ScheduleBreak{StartTime: b.StartTime, EndTime: b.EndTime}
+	}
+	return result
+}
+
+func toScheduleResponse(templates []*schedule.Template, overrides []*schedule.Override) dto.ScheduleResponse {
+	response := dto.ScheduleResponse{
+		Templates: make([]dto.ScheduleTemplateResponse, len(templates)),
+		Overrides: make([]dto.ScheduleOverrideResponse, len(overrides)),
+	}
+	for i, t := range templates {
+		response.Templates[i] = toTemplateResponse(t)
+	}
+	for i, o := range overrides {
+		response.Overrides[i] = toOverrideResponse(o)
+	}
+	return response
+}
+
+func toTemplateResponse(t *schedule.Template) dto.ScheduleTemplateResponse {
+	return dto.ScheduleTemplateResponse{
+		ID:             t.ID,
+		OrganizationID: t.OrganizationID,
+		DoctorID:       t.DoctorID,
+		DayOfWeek:      t.DayOfWeek,
+		StartTime:      t.StartTime,
+		EndTime:        t.EndTime,
+		SlotDuration:   t.SlotDuration,
+		Breaks:         toBreakResponses(t.Breaks),
+		IsActive:       t.IsActive,
+		CreatedAt:      t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      t.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func toOverrideResponse(o *schedule.Override) dto.ScheduleOverrideResponse {
+	return dto.ScheduleOverrideResponse{
+		ID:             o.ID,
+		OrganizationID: o.OrganizationID,
+		DoctorID:       o.DoctorID,
+		Date:           o.Date.Format("2006-01-02"),
+		IsAvailable:    o.IsAvailable,
+		StartTime:      o.StartTime,
+		EndTime:        o.EndTime,
+		SlotDuration:   o.SlotDuration,
+		Breaks:         toBreakResponses(o.Breaks),
+		Reason:         o.Reason,
+		CreatedAt:      o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
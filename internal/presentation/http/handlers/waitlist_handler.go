@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	appwaitlist "medika-backend/internal/application/waitlist"
+	"medika-backend/internal/domain/appointment"
+	"medika-backend/internal/domain/waitlist"
+	"medika-backend/internal/presentation/http/dto"
+	"medika-backend/pkg/logger"
+)
+
+type WaitlistHandler struct {
+	waitlistService WaitlistService
+	validator       *validator.Validate
+	logger          logger.Logger
+}
+
+// WaitlistService interface for dependency injection
+type WaitlistService interface {
+	CreateEntry(ctx context.Context, entry *waitlist.Entry) error
+	GetEntry(ctx context.Context, id string) (*waitlist.Entry, error)
+	GetEntries(ctx context.Context, organizationID string, status waitlist.EntryStatus, limit, offset int) ([]*waitlist.Entry, error)
+	CancelEntry(ctx context.Context, id string) error
+	GetHold(ctx context.Context, id string) (*waitlist.Hold, error)
+	AcceptHold(ctx context.Context, holdID string) (*appointment.Appointment, error)
+	DeclineHold(ctx context.Context, holdID string) error
+}
+
+func NewWaitlistHandler(
+	waitlistService WaitlistService,
+	validator *validator.Validate,
+	logger logger.Logger,
+) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// POST /api/v1/waitlist
+func (h *WaitlistHandler) CreateEntry(c *fiber.Ctx) error {
+	var req dto.CreateWaitlistEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid JSON format",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	}
+
+	dateFrom, err := time.ParseInLocation("2006-01-02", req.DateFrom, time.Local)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid date",
+			Message: "dateFrom must be in YYYY-MM-DD format",
+		})
+	}
+
+	dateTo, err := time.ParseInLocation("2006-01-02", req.DateTo, time.Local)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid date",
+			Message: "dateTo must be in YYYY-MM-DD format",
+		})
+	}
+
+	entry := &waitlist.Entry{
+		OrganizationID: req.OrganizationID,
+		PatientID:      req.PatientID,
+		DoctorID:       req.DoctorID,
+		Specialty:      req.Specialty,
+		DateFrom:       dateFrom,
+		DateTo:         dateTo,
+		Notes:          req.Notes,
+	}
+
+	if err := h.waitlistService.CreateEntry(c.Context(), entry); err != nil {
+		h.logger.Error(c.Context(), "Failed to create waitlist entry", "error", err)
+		return h.respondWaitlistError(c, err, "Failed to create waitlist entry")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toWaitlistEntryResponse(entry),
+		Message: "Waitlist entry created successfully",
+	})
+}
+
+// GET /api/v1/waitlist?organizationId=...&status=waiting
+func (h *WaitlistHandler) GetEntries(c *fiber.Ctx) error {
+	organizationID := c.Query("organizationId", "")
+	if organizationID == "" {
+		if userOrgID, ok := c.Locals("organization_id").(string); ok {
+			organizationID = userOrgID
+		}
+	}
+	if err := h.validator.Var(organizationID, "required,uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid organization ID",
+			Message: "organizationId must be a valid UUID",
+		})
+	}
+
+	status := c.Query("status", "")
+	if err := h.validator.Var(status, "omitempty,oneof=waiting offered booked cancelled"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid status",
+			Message: "status must be one of waiting, offered, booked, cancelled",
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	entries, err := h.waitlistService.GetEntries(c.Context(), organizationID, waitlist.EntryStatus(status), limit, (page-1)*limit)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get waitlist entries", "error", err)
+		return h.respondWaitlistError(c, err, "Failed to get waitlist entries")
+	}
+
+	responses := make([]dto.WaitlistEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = toWaitlistEntryResponse(entry)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    responses,
+		Message: "Waitlist entries retrieved successfully",
+	})
+}
+
+// GET /api/v1/waitlist/:id
+func (h *WaitlistHandler) GetEntry(c *fiber.Ctx) error {
+	entryID := c.Params("id")
+	if err := h.validator.Var(entryID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid waitlist entry ID",
+			Message: "Waitlist entry ID must be a valid UUID",
+		})
+	}
+
+	entry, err := h.waitlistService.GetEntry(c.Context(), entryID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get waitlist entry", "error", err, "entryID", entryID)
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Waitlist entry not found",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toWaitlistEntryResponse(entry),
+		Message: "Waitlist entry retrieved successfully",
+	})
+}
+
+// DELETE /api/v1/waitlist/:id
+func (h *WaitlistHandler) CancelEntry(c *fiber.Ctx) error {
+	entryID := c.Params("id")
+	if err := h.validator.Var(entryID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid waitlist entry ID",
+			Message: "Waitlist entry ID must be a valid UUID",
+		})
+	}
+
+	if err := h.waitlistService.CancelEntry(c.Context(), entryID); err != nil {
+		h.logger.Error(c.Context(), "Failed to cancel waitlist entry", "error", err, "entryID", entryID)
+		return h.respondWaitlistError(c, err, "Failed to cancel waitlist entry")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Waitlist entry cancelled successfully",
+	})
+}
+
+// GET /api/v1/waitlist/holds/:id
+func (h *WaitlistHandler) GetHold(c *fiber.Ctx) error {
+	holdID := c.Params("id")
+	if err := h.validator.Var(holdID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid hold ID",
+			Message: "Hold ID must be a valid UUID",
+		})
+	}
+
+	hold, err := h.waitlistService.GetHold(c.Context(), holdID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to get waitlist hold", "error", err, "holdID", holdID)
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Waitlist hold not found",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toWaitlistHoldResponse(hold),
+		Message: "Waitlist hold retrieved successfully",
+	})
+}
+
+// POST /api/v1/waitlist/holds/:id/accept
+func (h *WaitlistHandler) AcceptHold(c *fiber.Ctx) error {
+	holdID := c.Params("id")
+	if err := h.validator.Var(holdID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid hold ID",
+			Message: "Hold ID must be a valid UUID",
+		})
+	}
+
+	hold, err := h.waitlistService.GetHold(c.Context(), holdID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Waitlist hold not found",
+			Message: err.Error(),
+		})
+	}
+	if !canAnswerHold(c, hold) {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   "Access denied",
+			Message: "Only the held patient or their organization's staff can answer this hold",
+		})
+	}
+
+	apt, err := h.waitlistService.AcceptHold(c.Context(), hold.ID)
+	if err != nil {
+		h.logger.Error(c.Context(), "Failed to accept waitlist hold", "error", err, "holdID", holdID)
+		return h.respondWaitlistError(c, err, "Failed to accept waitlist hold")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Success: true,
+		Data:    toAppointmentResponses([]*appointment.Appointment{apt})[0],
+		Message: "Appointment booked from waitlist successfully",
+	})
+}
+
+// POST /api/v1/waitlist/holds/:id/decline
+func (h *WaitlistHandler) DeclineHold(c *fiber.Ctx) error {
+	holdID := c.Params("id")
+	if err := h.validator.Var(holdID, "uuid"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid hold ID",
+			Message: "Hold ID must be a valid UUID",
+		})
+	}
+
+	hold, err := h.waitlistService.GetHold(c.Context(), holdID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "Waitlist hold not found",
+			Message: err.Error(),
+		})
+	}
+	if !canAnswerHold(c, hold) {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   "Access denied",
+			Message: "Only the held patient or their organization's staff can answer this hold",
+		})
+	}
+
+	if err := h.waitlistService.DeclineHold(c.Context(), hold.ID); err != nil {
+		h.logger.Error(c.Context(), "Failed to decline waitlist hold", "error", err, "holdID", holdID)
+		return h.respondWaitlistError(c, err, "Failed to decline waitlist hold")
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Waitlist hold declined successfully",
+	})
+}
+
+// canAnswerHold reports whether the caller is the held patient or staff of the held
+// slot's organization
+func canAnswerHold(c *fiber.Ctx, hold *waitlist.Hold) bool {
+	userID, _ := c.Locals("user_id").(string)
+	if userID != "" && userID == hold.PatientID {
+		return true
+	}
+	role, _ := c.Locals("role").(string)
+	organizationID, _ := c.Locals("organization_id").(string)
+	isStaff := role == "admin" || role == "doctor" || role == "nurse"
+	return isStaff && organizationID != "" && organizationID == hold.Slot.OrganizationID
+}
+
+func (h *WaitlistHandler) respondWaitlistError(c *fiber.Ctx, err error, message string) error {
+	var conflictErr *appointment.ConflictError
+	if errors.As(err, &conflictErr) {
+		return respondAppointmentConflict(c, conflictErr)
+	}
+
+	if appwaitlist.IsClientError(err) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func toWaitlistEntryResponse(entry *waitlist.Entry) dto.WaitlistEntryResponse {
+	return dto.WaitlistEntryResponse{
+		ID:             entry.ID,
+		OrganizationID: entry.OrganizationID,
+		PatientID:      entry.PatientID,
+		DoctorID:       entry.DoctorID,
+		Specialty:      entry.Specialty,
+		DateFrom:       entry.DateFrom.Format("2006-01-02"),
+		DateTo:         entry.DateTo.Format("2006-01-02"),
+		Status:         string(entry.Status),
+		Notes:          entry.Notes,
+		CreatedAt:      entry.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      entry.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func toWaitlistHoldResponse(hold *waitlist.Hold) dto.WaitlistHoldResponse {
+	return dto.WaitlistHoldResponse{
+		ID:                  hold.ID,
+		EntryID:             hold.EntryID,
+		PatientID:           hold.PatientID,
+		SourceAppointmentID: hold.Slot.SourceAppointmentID,
+		OrganizationID:      hold.Slot.OrganizationID,
+		DoctorID:            hold.Slot.DoctorID,
+		RoomID:              hold.Slot.RoomID,
+		Date:                hold.Slot.Date.Format("2006-01-02"),
+		StartTime:           hold.Slot.StartTime,
+		EndTime:             hold.Slot.EndTime,
+		Duration:            hold.Slot.Duration,
+		Type:                hold.Slot.Type,
+		Status:              string(hold.Status),
+		ExpiresAt:           hold.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		AppointmentID:       hold.AppointmentID,
+		CreatedAt:           hold.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:           hold.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
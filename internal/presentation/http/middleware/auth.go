@@ -65,6 +65,18 @@ func AuthRequired() fiber.Handler {
 	}
 }
 
+// OptionalAuth middleware; requests without an Authorization header go through as
+// anonymous, the others are authenticated as by AuthRequired
+func OptionalAuth() fiber.Handler {
+	authRequired := AuthRequired()
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			return c.Next()
+		}
+		return authRequired(c)
+	}
+}
+
 // RequireRole middleware
 func RequireRole(allowedRoles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -101,3 +113,18 @@ func RequireOrganization() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// RequireOwnOrganization middleware; the organization named by the route parameter
+// must be the caller's own
+func RequireOwnOrganization(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		orgID, _ := c.Locals("organization_id").(string)
+		if orgID == "" || orgID != c.Params(param) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Organization access required",
+			})
+		}
+
+		return c.Next()
+	}
+}
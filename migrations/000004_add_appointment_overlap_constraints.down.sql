@@ -0,0 +1,4 @@
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_patient_no_overlap;
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_doctor_no_overlap;
+DROP INDEX IF EXISTS idx_appointments_doctor_date;
+DROP INDEX IF EXISTS idx_appointments_room;
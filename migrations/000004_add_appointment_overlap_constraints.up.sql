@@ -0,0 +1,31 @@
+-- Prevent double-booking of doctors and patients at the database level.
+-- btree_gist lets the exclusion constraints combine uuid equality with range overlap.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_room ON appointments(room_id);
+CREATE INDEX IF NOT EXISTS idx_appointments_doctor_date ON appointments(doctor_id, date);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'appointments_doctor_no_overlap') THEN
+        ALTER TABLE appointments ADD CONSTRAINT appointments_doctor_no_overlap
+            EXCLUDE USING gist (
+                doctor_id WITH =,
+                tsrange(date + start_time, date + end_time) WITH &&
+            ) WHERE (status NOT IN ('cancelled', 'no_show'));
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'appointments_patient_no_overlap') THEN
+        ALTER TABLE appointments ADD CONSTRAINT appointments_patient_no_overlap
+            EXCLUDE USING gist (
+                patient_id WITH =,
+                tsrange(date + start_time, date + end_time) WITH &&
+            ) WHERE (status NOT IN ('cancelled', 'no_show'));
+    END IF;
+EXCEPTION
+    WHEN exclusion_violation THEN
+        -- Without the constraints concurrent bookings could overlap, so do not go on
+        RAISE EXCEPTION 'appointments overlap constraints not added: existing bookings overlap'
+            USING HINT = 'Cancel or move the overlapping appointments, then run the migrations again';
+END
+$$;
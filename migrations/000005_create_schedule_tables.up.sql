@@ -0,0 +1,46 @@
+-- Weekly working-hour templates per doctor, or per organization when doctor_id is NULL
+CREATE TABLE IF NOT EXISTS schedule_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    doctor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    slot_duration INTEGER NOT NULL CHECK (slot_duration BETWEEN 5 AND 480),
+    breaks JSONB DEFAULT '[]',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT schedule_templates_hours_check CHECK (end_time > start_time)
+);
+
+-- Date-specific exceptions: days off, holidays or different hours
+CREATE TABLE IF NOT EXISTS schedule_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    doctor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    is_available BOOLEAN NOT NULL,
+    start_time TIME,
+    end_time TIME,
+    slot_duration INTEGER CHECK (slot_duration BETWEEN 5 AND 480),
+    breaks JSONB DEFAULT '[]',
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT schedule_overrides_hours_check CHECK (
+        NOT is_available OR (start_time IS NOT NULL AND end_time IS NOT NULL AND end_time > start_time)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_templates_doctor ON schedule_templates(doctor_id, day_of_week);
+CREATE INDEX IF NOT EXISTS idx_schedule_templates_organization ON schedule_templates(organization_id, day_of_week);
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_doctor_date ON schedule_overrides(doctor_id, date);
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_organization_date ON schedule_overrides(organization_id, date);
+
+DROP TRIGGER IF EXISTS update_schedule_templates_updated_at ON schedule_templates;
+CREATE TRIGGER update_schedule_templates_updated_at BEFORE UPDATE ON schedule_templates FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+DROP TRIGGER IF EXISTS update_schedule_overrides_updated_at ON schedule_overrides;
+CREATE TRIGGER update_schedule_overrides_updated_at BEFORE UPDATE ON schedule_overrides FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
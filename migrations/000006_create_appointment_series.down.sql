@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_appointments_series;
+ALTER TABLE appointments DROP COLUMN IF EXISTS series_id;
+
+DROP TRIGGER IF EXISTS update_appointment_series_updated_at ON appointment_series;
+DROP TABLE IF EXISTS appointment_series;
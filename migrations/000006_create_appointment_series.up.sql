@@ -0,0 +1,25 @@
+-- Recurring appointment series; each occurrence is a regular row in appointments
+CREATE TABLE IF NOT EXISTS appointment_series (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    doctor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    repeat_interval INTEGER NOT NULL DEFAULT 1 CHECK (repeat_interval >= 1),
+    occurrence_count INTEGER CHECK (occurrence_count >= 1),
+    until_date DATE,
+    rrule TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT appointment_series_end_check CHECK (occurrence_count IS NOT NULL OR until_date IS NOT NULL)
+);
+
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES appointment_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_series ON appointments(series_id, date);
+CREATE INDEX IF NOT EXISTS idx_appointment_series_patient ON appointment_series(patient_id);
+
+DROP TRIGGER IF EXISTS update_appointment_series_updated_at ON appointment_series;
+CREATE TRIGGER update_appointment_series_updated_at BEFORE UPDATE ON appointment_series FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_appointments_rescheduled_from;
+ALTER TABLE appointments DROP COLUMN IF EXISTS rescheduled_from_id;
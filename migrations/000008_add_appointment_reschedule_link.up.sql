@@ -0,0 +1,4 @@
+-- A rescheduled appointment points at the appointment it replaced
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS rescheduled_from_id UUID REFERENCES appointments(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_rescheduled_from ON appointments(rescheduled_from_id);
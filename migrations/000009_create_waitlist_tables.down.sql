@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_waitlist_holds_updated_at ON waitlist_holds;
+DROP TRIGGER IF EXISTS update_waitlist_entries_updated_at ON waitlist_entries;
+
+DROP TABLE IF EXISTS waitlist_holds;
+DROP TABLE IF EXISTS waitlist_entries;
+
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS specialization;
@@ -0,0 +1,52 @@
+-- Doctors' specialty, used to match waitlist entries that are not tied to one doctor
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS specialization VARCHAR(100);
+
+-- Patients waiting for an earlier slot with a doctor, or any doctor of a specialty
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    doctor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    specialty VARCHAR(100),
+    date_from DATE NOT NULL,
+    date_to DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'offered', 'booked', 'cancelled')),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT waitlist_entries_target_check CHECK (doctor_id IS NOT NULL OR specialty IS NOT NULL),
+    CONSTRAINT waitlist_entries_range_check CHECK (date_to >= date_from)
+);
+
+-- Slots freed by cancellations, held for one waitlisted patient until expires_at
+CREATE TABLE IF NOT EXISTS waitlist_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL REFERENCES waitlist_entries(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_appointment_id UUID NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    doctor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    room_id UUID REFERENCES rooms(id) ON DELETE SET NULL,
+    date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    duration INTEGER NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'accepted', 'declined', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    appointment_id UUID REFERENCES appointments(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_doctor ON waitlist_entries(organization_id, doctor_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_specialty ON waitlist_entries(organization_id, specialty, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_waitlist_holds_source ON waitlist_holds(source_appointment_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_holds_expiry ON waitlist_holds(expires_at) WHERE status = 'active';
+
+DROP TRIGGER IF EXISTS update_waitlist_entries_updated_at ON waitlist_entries;
+CREATE TRIGGER update_waitlist_entries_updated_at BEFORE UPDATE ON waitlist_entries FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_waitlist_holds_updated_at ON waitlist_holds;
+CREATE TRIGGER update_waitlist_holds_updated_at BEFORE UPDATE ON waitlist_holds FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
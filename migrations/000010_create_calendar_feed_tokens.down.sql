@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_calendar_feed_tokens_updated_at ON calendar_feed_tokens;
+
+DROP TABLE IF EXISTS calendar_feed_tokens;
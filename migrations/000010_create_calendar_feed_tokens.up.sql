@@ -0,0 +1,17 @@
+-- Revocable tokens for subscribable per-doctor and per-patient iCalendar feeds
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('doctor', 'patient')),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(100),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feed_tokens_owner ON calendar_feed_tokens(owner_type, owner_id);
+
+DROP TRIGGER IF EXISTS update_calendar_feed_tokens_updated_at ON calendar_feed_tokens;
+CREATE TRIGGER update_calendar_feed_tokens_updated_at BEFORE UPDATE ON calendar_feed_tokens FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
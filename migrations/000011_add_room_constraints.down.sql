@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_appointments_room_date;
+DROP INDEX IF EXISTS idx_rooms_equipment;
+DROP INDEX IF EXISTS idx_rooms_organization;
+
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_capacity_check;
+ALTER TABLE rooms ALTER COLUMN is_available DROP NOT NULL;
@@ -0,0 +1,10 @@
+-- Rooms are either bookable or not; treat unset availability as available
+UPDATE rooms SET is_available = true WHERE is_available IS NULL;
+ALTER TABLE rooms ALTER COLUMN is_available SET NOT NULL;
+
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_capacity_check;
+ALTER TABLE rooms ADD CONSTRAINT rooms_capacity_check CHECK (capacity >= 1);
+
+CREATE INDEX IF NOT EXISTS idx_rooms_organization ON rooms(organization_id, name);
+CREATE INDEX IF NOT EXISTS idx_rooms_equipment ON rooms USING gin(equipment);
+CREATE INDEX IF NOT EXISTS idx_appointments_room_date ON appointments(room_id, date) WHERE room_id IS NOT NULL;
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS update_patient_no_show_stats_updated_at ON patient_no_show_stats;
+DROP TRIGGER IF EXISTS update_no_show_policies_updated_at ON no_show_policies;
+
+DROP INDEX IF EXISTS idx_appointments_open_status_date;
+
+ALTER TABLE appointments DROP COLUMN IF EXISTS deposit_paid_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS deposit_required;
+ALTER TABLE appointments DROP COLUMN IF EXISTS confirmation_required;
+
+DROP TABLE IF EXISTS patient_no_show_stats;
+DROP TABLE IF EXISTS no_show_policies;
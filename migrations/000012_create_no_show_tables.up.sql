@@ -0,0 +1,35 @@
+-- Per-organization no-show policy; organizations without a row use the defaults
+CREATE TABLE IF NOT EXISTS no_show_policies (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    auto_mark BOOLEAN NOT NULL DEFAULT TRUE,
+    grace_period_minutes INTEGER CHECK (grace_period_minutes >= 0),
+    confirmation_threshold INTEGER NOT NULL DEFAULT 0 CHECK (confirmation_threshold >= 0),
+    confirmation_lead_hours INTEGER NOT NULL DEFAULT 24 CHECK (confirmation_lead_hours >= 1),
+    deposit_threshold INTEGER NOT NULL DEFAULT 0 CHECK (deposit_threshold >= 0),
+    deposit_amount DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (deposit_amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Running no-show count per patient and organization
+CREATE TABLE IF NOT EXISTS patient_no_show_stats (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    no_show_count INTEGER NOT NULL DEFAULT 0,
+    last_no_show_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, patient_id)
+);
+
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS confirmation_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS deposit_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS deposit_paid_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_open_status_date ON appointments(status, date) WHERE status IN ('pending', 'confirmed');
+
+DROP TRIGGER IF EXISTS update_no_show_policies_updated_at ON no_show_policies;
+CREATE TRIGGER update_no_show_policies_updated_at BEFORE UPDATE ON no_show_policies FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_patient_no_show_stats_updated_at ON patient_no_show_stats;
+CREATE TRIGGER update_patient_no_show_stats_updated_at BEFORE UPDATE ON patient_no_show_stats FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
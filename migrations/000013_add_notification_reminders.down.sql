@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notifications_due;
+DROP INDEX IF EXISTS idx_notifications_appointment;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS appointment_id;
@@ -0,0 +1,5 @@
+-- Appointment reminders are scheduled notifications linked to their appointment
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS appointment_id UUID REFERENCES appointments(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_appointment ON notifications(appointment_id) WHERE appointment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications(scheduled_for) WHERE sent_at IS NULL AND scheduled_for IS NOT NULL;
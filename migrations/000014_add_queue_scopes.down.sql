@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_queues_scope_position;
+
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_scope_type_check;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS scope_id;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS scope_type;
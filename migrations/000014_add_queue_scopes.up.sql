@@ -0,0 +1,37 @@
+-- Queues are kept per doctor, room or service point instead of per organization.
+-- Existing entries are moved into queues once, when the scope columns are added:
+-- every migration runs again on every start.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'patient_queues' AND column_name = 'scope_type'
+    ) THEN
+        ALTER TABLE patient_queues ADD COLUMN scope_type VARCHAR(20);
+        ALTER TABLE patient_queues ADD COLUMN scope_id VARCHAR(100);
+
+        -- Existing entries join the queue of their appointment's doctor
+        UPDATE patient_queues pq
+        SET scope_type = 'doctor', scope_id = a.doctor_id::text
+        FROM appointments a
+        WHERE a.id = pq.appointment_id;
+
+        -- Positions were organization-wide; renumber them per queue
+        UPDATE patient_queues pq
+        SET position = ranked.new_position, estimated_wait_time = (ranked.new_position - 1) * 15
+        FROM (
+            SELECT id, ROW_NUMBER() OVER (PARTITION BY organization_id, scope_type, scope_id ORDER BY position ASC, created_at ASC) AS new_position
+            FROM patient_queues
+            WHERE status = 'waiting'
+        ) AS ranked
+        WHERE pq.id = ranked.id;
+
+        ALTER TABLE patient_queues ALTER COLUMN scope_type SET NOT NULL;
+        ALTER TABLE patient_queues ALTER COLUMN scope_id SET NOT NULL;
+    END IF;
+END $$;
+
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_scope_type_check;
+ALTER TABLE patient_queues ADD CONSTRAINT patient_queues_scope_type_check CHECK (scope_type IN ('doctor', 'room', 'service_point'));
+
+CREATE INDEX IF NOT EXISTS idx_queues_scope_position ON patient_queues(organization_id, scope_type, scope_id, status, position);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS queue_triage_history;
+
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_triage_level_check;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS triage_level;
@@ -0,0 +1,18 @@
+-- ESI-style triage levels for queue entries: 1 is resuscitation, 5 is non-urgent
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS triage_level SMALLINT NOT NULL DEFAULT 5;
+
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_triage_level_check;
+ALTER TABLE patient_queues ADD CONSTRAINT patient_queues_triage_level_check CHECK (triage_level BETWEEN 1 AND 5);
+
+-- Audit trail of re-triage decisions
+CREATE TABLE IF NOT EXISTS queue_triage_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    queue_id UUID NOT NULL REFERENCES patient_queues(id) ON DELETE CASCADE,
+    from_level SMALLINT NOT NULL,
+    to_level SMALLINT NOT NULL,
+    reason TEXT NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_triage_history_queue ON queue_triage_history(queue_id, changed_at);
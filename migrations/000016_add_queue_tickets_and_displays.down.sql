@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS display_tokens;
+DROP TABLE IF EXISTS queue_ticket_counters;
+
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS ticket_number;
@@ -0,0 +1,34 @@
+-- Human-readable ticket numbers such as A-014, restarting every day
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS ticket_number VARCHAR(10);
+
+-- One counter per queue and day; the prefix letter is handed out in the order the
+-- organization's queues issue their first ticket of the day
+CREATE TABLE IF NOT EXISTS queue_ticket_counters (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    scope_type VARCHAR(20) NOT NULL,
+    scope_id VARCHAR(100) NOT NULL,
+    day DATE NOT NULL DEFAULT CURRENT_DATE,
+    prefix VARCHAR(3) NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (organization_id, scope_type, scope_id, day)
+);
+
+-- Revocable tokens for unauthenticated waiting-room display boards
+CREATE TABLE IF NOT EXISTS display_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    scope_type VARCHAR(20) CHECK (scope_type IN ('doctor', 'room', 'service_point')),
+    scope_id VARCHAR(100),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(100),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((scope_type IS NULL) = (scope_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_display_tokens_organization ON display_tokens(organization_id);
+
+DROP TRIGGER IF EXISTS update_display_tokens_updated_at ON display_tokens;
+CREATE TRIGGER update_display_tokens_updated_at BEFORE UPDATE ON display_tokens FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_patient_queues_completed;
+
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS estimated_wait_high;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS estimated_wait_low;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS started_at;
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS called_at;
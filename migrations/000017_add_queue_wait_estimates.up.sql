@@ -0,0 +1,12 @@
+-- When each queue entry was called, started and completed; completed consultations
+-- feed the wait estimates
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS called_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE;
+
+-- 80% range around estimated_wait_time, in minutes
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS estimated_wait_low INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS estimated_wait_high INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_patient_queues_completed ON patient_queues(organization_id, completed_at)
+    WHERE completed_at IS NOT NULL;
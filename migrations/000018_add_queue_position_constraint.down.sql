@@ -0,0 +1 @@
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_waiting_position_excl;
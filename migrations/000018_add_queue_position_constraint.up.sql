@@ -0,0 +1,17 @@
+-- Close any gaps or duplicates left by concurrent check-ins before enforcing positions
+UPDATE patient_queues pq
+SET position = ranked.new_position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY organization_id, scope_type, scope_id ORDER BY position ASC, created_at ASC) AS new_position
+    FROM patient_queues
+    WHERE status = 'waiting'
+) AS ranked
+WHERE pq.id = ranked.id AND pq.position <> ranked.new_position;
+
+-- No two waiting patients of a queue share a position. Checked at commit, since
+-- renumbering moves positions through each other within a single statement.
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_waiting_position_excl;
+ALTER TABLE patient_queues ADD CONSTRAINT patient_queues_waiting_position_excl
+    EXCLUDE USING btree (organization_id WITH =, scope_type WITH =, scope_id WITH =, position WITH =)
+    WHERE (status = 'waiting')
+    DEFERRABLE INITIALLY DEFERRED;
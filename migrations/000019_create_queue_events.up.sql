@@ -0,0 +1,26 @@
+-- Log of every status change of a queue entry, for wait-time and throughput reporting
+CREATE TABLE IF NOT EXISTS queue_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    queue_id UUID NOT NULL REFERENCES patient_queues(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_events_queue ON queue_events(queue_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_queue_events_organization ON queue_events(organization_id, changed_at);
+
+-- Start the log of existing entries from the timestamps they already carry
+INSERT INTO queue_events (queue_id, organization_id, from_status, to_status, changed_at)
+SELECT pq.id, pq.organization_id, t.from_status, t.to_status, t.changed_at
+FROM patient_queues pq
+CROSS JOIN LATERAL (VALUES
+    (NULL, 'waiting', pq.created_at),
+    ('waiting', 'called', pq.called_at),
+    (CASE WHEN pq.called_at IS NULL THEN 'waiting' ELSE 'called' END, 'in_progress', pq.started_at),
+    (CASE WHEN pq.started_at IS NULL THEN 'called' ELSE 'in_progress' END, 'completed', pq.completed_at)
+) AS t(from_status, to_status, changed_at)
+WHERE t.changed_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM queue_events e WHERE e.queue_id = pq.id);
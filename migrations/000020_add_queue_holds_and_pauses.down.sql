@@ -0,0 +1,6 @@
+UPDATE patient_queues SET status = 'waiting' WHERE status IN ('skipped', 'on_hold');
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_status_check;
+ALTER TABLE patient_queues ADD CONSTRAINT patient_queues_status_check
+    CHECK (status IN ('waiting', 'called', 'in_progress', 'completed', 'cancelled'));
+
+DROP TABLE IF EXISTS queue_states;
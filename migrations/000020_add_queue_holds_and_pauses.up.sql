@@ -0,0 +1,16 @@
+-- Queues that have been paused, e.g. for a doctor's break. Queues without a row run.
+CREATE TABLE IF NOT EXISTS queue_states (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    scope_type VARCHAR(20) NOT NULL,
+    scope_id VARCHAR(100) NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, scope_type, scope_id)
+);
+
+-- Skipped patients can be recalled and patients on hold released, so both keep their entry
+ALTER TABLE patient_queues DROP CONSTRAINT IF EXISTS patient_queues_status_check;
+ALTER TABLE patient_queues ADD CONSTRAINT patient_queues_status_check
+    CHECK (status IN ('waiting', 'called', 'in_progress', 'completed', 'cancelled', 'skipped', 'on_hold'));
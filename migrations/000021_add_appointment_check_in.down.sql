@@ -0,0 +1,10 @@
+UPDATE appointments SET status = 'confirmed' WHERE status = 'checked_in';
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_status_check;
+ALTER TABLE appointments ADD CONSTRAINT appointments_status_check
+    CHECK (status IN ('pending', 'confirmed', 'in_progress', 'completed', 'cancelled', 'no_show'));
+
+ALTER TABLE appointments DROP COLUMN IF EXISTS walk_in;
+
+DROP INDEX IF EXISTS idx_appointments_check_in_code;
+ALTER TABLE appointments DROP COLUMN IF EXISTS check_in_code;
+DROP FUNCTION IF EXISTS generate_check_in_code();
@@ -0,0 +1,24 @@
+-- Check-in codes use Crockford's base32 alphabet, which leaves out I, L, O and U so
+-- a code read off a screen or paper cannot be mistyped into another one
+CREATE OR REPLACE FUNCTION generate_check_in_code() RETURNS VARCHAR AS $$
+DECLARE
+    alphabet CONSTANT TEXT := '0123456789ABCDEFGHJKMNPQRSTVWXYZ';
+    code TEXT := '';
+BEGIN
+    FOR i IN 1..8 LOOP
+        code := code || substr(alphabet, 1 + floor(random() * 32)::INTEGER, 1);
+    END LOOP;
+    RETURN code;
+END;
+$$ LANGUAGE plpgsql VOLATILE;
+
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS check_in_code VARCHAR(8) DEFAULT generate_check_in_code();
+UPDATE appointments SET check_in_code = generate_check_in_code() WHERE check_in_code IS NULL;
+ALTER TABLE appointments ALTER COLUMN check_in_code SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointments_check_in_code ON appointments(check_in_code);
+
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS walk_in BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_status_check;
+ALTER TABLE appointments ADD CONSTRAINT appointments_status_check
+    CHECK (status IN ('pending', 'confirmed', 'checked_in', 'in_progress', 'completed', 'cancelled', 'no_show'));
@@ -0,0 +1,3 @@
+ALTER TABLE patient_queues DROP COLUMN IF EXISTS carried_over_to;
+DROP TABLE IF EXISTS queue_closures;
+DROP TABLE IF EXISTS queue_closing_policies;
@@ -0,0 +1,31 @@
+-- When each organization's queues close for the day and what happens to the patients
+-- still waiting. Organizations without a row use the configured defaults.
+CREATE TABLE IF NOT EXISTS queue_closing_policies (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    close_time VARCHAR(5) NOT NULL,
+    leftover_action VARCHAR(20) NOT NULL CHECK (leftover_action IN ('cancel', 'carry_over')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Summary of each closed queue day
+CREATE TABLE IF NOT EXISTS queue_closures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    leftover_action VARCHAR(20) NOT NULL,
+    joined INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0,
+    cancelled INTEGER NOT NULL DEFAULT 0,
+    leftover_cancelled INTEGER NOT NULL DEFAULT 0,
+    carried_over INTEGER NOT NULL DEFAULT 0,
+    in_progress INTEGER NOT NULL DEFAULT 0,
+    average_wait_minutes INTEGER NOT NULL DEFAULT 0,
+    closed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, day)
+);
+
+-- Entries carried over keep their arrival time, so the queue day they now belong to is
+-- kept apart from it
+ALTER TABLE patient_queues ADD COLUMN IF NOT EXISTS carried_over_to DATE;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notification_templates;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS email_html;
+ALTER TABLE notifications DROP COLUMN IF EXISTS sms_body;
+ALTER TABLE notifications DROP COLUMN IF EXISTS template;
@@ -0,0 +1,21 @@
+-- Notifications rendered from a template keep the template they came from and its
+-- SMS and HTML email bodies
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS template VARCHAR(50);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS sms_body TEXT;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_html TEXT;
+
+-- An organization's overrides of the built-in notification templates, per event and
+-- locale
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    sms_body TEXT,
+    email_html TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, key, locale)
+);
@@ -0,0 +1,6 @@
+UPDATE notification_deliveries SET status = 'failed' WHERE status = 'skipped';
+ALTER TABLE notification_deliveries DROP CONSTRAINT IF EXISTS notification_deliveries_status_check;
+ALTER TABLE notification_deliveries ADD CONSTRAINT notification_deliveries_status_check
+    CHECK (status IN ('pending', 'sent', 'failed'));
+
+DROP TABLE IF EXISTS notification_preferences;
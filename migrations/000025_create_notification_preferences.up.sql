@@ -0,0 +1,17 @@
+-- A user's notification preferences: external channels switched off per notification
+-- type, and quiet hours in the user's timezone. Users without a row receive everything.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL,
+    quiet_start VARCHAR(5),
+    quiet_end VARCHAR(5),
+    channels JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT notification_preferences_quiet_hours_check CHECK ((quiet_start IS NULL) = (quiet_end IS NULL))
+);
+
+-- Deliveries the recipient switched off are skipped rather than attempted
+ALTER TABLE notification_deliveries DROP CONSTRAINT IF EXISTS notification_deliveries_status_check;
+ALTER TABLE notification_deliveries ADD CONSTRAINT notification_deliveries_status_check
+    CHECK (status IN ('pending', 'sent', 'failed', 'skipped'));
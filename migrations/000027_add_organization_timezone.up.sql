@@ -0,0 +1,3 @@
+-- The IANA time zone an organization's business hours and schedules are in. Without
+-- one they are in the server's zone.
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS increment_appointments_revision ON appointments;
+DROP FUNCTION IF EXISTS increment_revision_column();
+ALTER TABLE appointments DROP COLUMN IF EXISTS revision;
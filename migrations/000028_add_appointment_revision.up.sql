@@ -0,0 +1,30 @@
+-- Counts the changes made to an appointment. Calendar feeds publish it as the event's
+-- SEQUENCE, so calendar apps pick up every change, including new times.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'appointments' AND column_name = 'revision'
+    ) THEN
+        ALTER TABLE appointments ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+
+        -- Start from the status changes, which the feeds published as the sequence
+        -- before, without touching updated_at
+        ALTER TABLE appointments DISABLE TRIGGER update_appointments_updated_at;
+        UPDATE appointments a SET revision = (
+            SELECT COUNT(*) FROM appointment_status_history h WHERE h.appointment_id = a.id
+        );
+        ALTER TABLE appointments ENABLE TRIGGER update_appointments_updated_at;
+    END IF;
+END $$;
+
+CREATE OR REPLACE FUNCTION increment_revision_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.revision = OLD.revision + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS increment_appointments_revision ON appointments;
+CREATE TRIGGER increment_appointments_revision BEFORE UPDATE ON appointments FOR EACH ROW EXECUTE FUNCTION increment_revision_column();
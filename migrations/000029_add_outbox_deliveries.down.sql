@@ -0,0 +1,2 @@
+ALTER TABLE outbox_dead_letters DROP COLUMN IF EXISTS delivered;
+ALTER TABLE outbox DROP COLUMN IF EXISTS delivered;
@@ -0,0 +1,4 @@
+-- The subscribers an event was delivered to. When it is published again after a
+-- failure, only the others get it.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS delivered TEXT[];
+ALTER TABLE outbox_dead_letters ADD COLUMN IF NOT EXISTS delivered TEXT[];
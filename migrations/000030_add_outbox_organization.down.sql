@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_outbox_dead_letters_organization;
+ALTER TABLE outbox_dead_letters DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE outbox DROP COLUMN IF EXISTS organization_id;
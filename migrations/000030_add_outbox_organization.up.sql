@@ -0,0 +1,16 @@
+-- The organization an event is about, so each organization's admins see and replay
+-- only their own dead letters. Events about no organization have none.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE outbox_dead_letters ADD COLUMN IF NOT EXISTS organization_id UUID;
+
+-- Appointment events carry it on the appointment, queue events at the top level
+UPDATE outbox
+SET organization_id = COALESCE(payload->'Appointment'->>'organizationId', payload->>'organization_id')::uuid
+WHERE organization_id IS NULL
+  AND COALESCE(payload->'Appointment'->>'organizationId', payload->>'organization_id') <> '';
+UPDATE outbox_dead_letters
+SET organization_id = COALESCE(payload->'Appointment'->>'organizationId', payload->>'organization_id')::uuid
+WHERE organization_id IS NULL
+  AND COALESCE(payload->'Appointment'->>'organizationId', payload->>'organization_id') <> '';
+
+CREATE INDEX IF NOT EXISTS idx_outbox_dead_letters_organization ON outbox_dead_letters(organization_id, failed_at DESC);
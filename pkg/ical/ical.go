@@ -0,0 +1,151 @@
+// Package ical writes iCalendar (RFC 5545) documents
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Method is the iTIP method of a calendar (RFC 5546)
+type Method string
+
+const (
+	MethodPublish Method = "PUBLISH"
+	MethodRequest Method = "REQUEST"
+	MethodCancel  Method = "CANCEL"
+)
+
+// Status is the status of an event
+type Status string
+
+const (
+	StatusTentative Status = "TENTATIVE"
+	StatusConfirmed Status = "CONFIRMED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// MIMEType is the content type of an encoded calendar
+const MIMEType = "text/calendar; charset=utf-8"
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string
+	Method Method
+	// Name is the display name suggested to calendar clients
+	Name   string
+	Events []*Event
+}
+
+// Event is a VEVENT component. Times are written in UTC.
+type Event struct {
+	UID          string
+	Sequence     int
+	Stamp        time.Time
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	Status       Status
+	Created      time.Time
+	LastModified time.Time
+}
+
+// Bytes encodes the calendar as an RFC 5545 document
+func (c *Calendar) Bytes() []byte {
+	var w writer
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD", string(c.Method))
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		e.write(&w)
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func (e *Event) write(w *writer) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", escape(e.UID))
+	w.line("DTSTAMP", formatTime(e.Stamp))
+	w.line("DTSTART", formatTime(e.Start))
+	w.line("DTEND", formatTime(e.End))
+	w.line("SEQUENCE", fmt.Sprint(e.Sequence))
+	if e.Status != "" {
+		w.line("STATUS", string(e.Status))
+	}
+	w.line("SUMMARY", escape(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION", escape(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION", escape(e.Location))
+	}
+	if !e.Created.IsZero() {
+		w.line("CREATED", formatTime(e.Created))
+	}
+	if !e.LastModified.IsZero() {
+		w.line("LAST-MODIFIED", formatTime(e.LastModified))
+	}
+	w.line("END", "VEVENT")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escape escapes a TEXT value (RFC 5545 section 3.3.11)
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it at 75 octets without splitting a
+// UTF-8 sequence (RFC 5545 section 3.1)
+func (w *writer) line(name, value string) {
+	s := name + ":" + value
+	limit := maxLineOctets
+
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}